@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// Finding eh um padrao fraco encontrado por Audit ou AuditModulus
+type Finding struct {
+	Check  string // nome da verificacao, ex.: "roca"
+	Detail string
+}
+
+func (f Finding) String() string {
+	return f.Check + ": " + f.Detail
+}
+
+// rocaPrimes sao os primos pequenos que dividem o M usado pela biblioteca
+// RSALib vulneravel ao ROCA (CVE-2017-15361). Os primos gerados por ela
+// tem a forma p = k*M + (65537^a mod M), de modo que p mod r pertence ao
+// subgrupo gerado por 65537 modulo cada r; o mesmo vale para o modulo n = pq.
+var rocaPrimes = []int{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67,
+	71, 73, 79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139,
+	149, 151, 157, 163, 167,
+}
+
+// rocaSubgroups[i] marca as potencias de 65537 modulo rocaPrimes[i]
+var rocaSubgroups = func() [][]bool {
+	subgroups := make([][]bool, len(rocaPrimes))
+	for i, r := range rocaPrimes {
+		member := make([]bool, r)
+		for x := 1; !member[x]; x = x * (65537 % r) % r {
+			member[x] = true
+		}
+		subgroups[i] = member
+	}
+	return subgroups
+}()
+
+// ROCAFingerprint informa se n (primo ou modulo) tem a estrutura dos primos
+// gerados pela RSALib vulneravel ao ataque ROCA. A chance de um valor
+// aleatorio apresentar a impressao digital eh desprezivel.
+func ROCAFingerprint(n *big.Int) bool {
+	if n.Sign() <= 0 {
+		return false
+	}
+	r := new(big.Int)
+	for i, p := range rocaPrimes {
+		r.Mod(n, big.NewInt(int64(p)))
+		if !rocaSubgroups[i][r.Int64()] {
+			return false
+		}
+	}
+	return true
+}
+
+// minHammingBits eh o menor tamanho em que o peso de Hamming eh verificado;
+// abaixo dele pesos extremos acontecem ao acaso
+const minHammingBits = 64
+
+// hammingFinding verifica se n tem poucos bits 1 ou poucos bits 0, como
+// numeros de forma especial (2^k +- c). Para um valor aleatorio de b bits o
+// peso fica em torno de b/2; menos de b/8 ou mais de 7b/8 indica estrutura.
+func hammingFinding(n *big.Int) *Finding {
+	size := n.BitLen()
+	if size < minHammingBits {
+		return nil
+	}
+	weight := 0
+	for _, w := range n.Bits() {
+		weight += bits.OnesCount(uint(w))
+	}
+	if weight < size/8 || weight > size-size/8 {
+		return &Finding{
+			Check:  "hamming",
+			Detail: fmt.Sprintf("peso de Hamming %d em %d bits indica forma especial", weight, size),
+		}
+	}
+	return nil
+}
+
+// Audit procura em um primo gerado padroes fracos conhecidos: a impressao
+// digital do ROCA e um peso de Hamming extremo. Retorna nil se nada for
+// encontrado. As verificacoes de suavidade de p - 1 e p + 1 ficam em
+// SmoothnessReport, pois dependem de um limite escolhido pelo usuario.
+func Audit(p *big.Int) []Finding {
+	var findings []Finding
+	if ROCAFingerprint(p) {
+		findings = append(findings, Finding{Check: "roca", Detail: "estrutura dos primos vulneraveis ao ROCA (CVE-2017-15361)"})
+	}
+	if f := hammingFinding(p); f != nil {
+		findings = append(findings, *f)
+	}
+	return findings
+}
+
+// fermatSteps eh o numero de iteracoes do metodo de Fermat em AuditModulus
+const fermatSteps = 1 << 10
+
+// AuditModulus procura em um modulo n = pq os padroes de Audit e tambem
+// fatores proximos demais, que o metodo de Fermat encontra em poucas
+// iteracoes a partir de sqrt(n)
+func AuditModulus(n *big.Int) []Finding {
+	findings := Audit(n)
+	if p := fermatFactor(n, fermatSteps); p != nil {
+		findings = append(findings, Finding{
+			Check:  "fermat",
+			Detail: fmt.Sprintf("fatores proximos demais: %s divide o modulo", p),
+		})
+	}
+	return findings
+}
+
+// fermatFactor tenta escrever n = a^2 - b^2 = (a-b)(a+b) com a a partir de
+// ceil(sqrt(n)), retornando o fator a-b ou nil apos steps tentativas
+func fermatFactor(n *big.Int, steps int) *big.Int {
+	if n.Sign() <= 0 || n.Bit(0) == 0 {
+		return nil
+	}
+	a := new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(a, a).Cmp(n) < 0 {
+		a.Add(a, big.NewInt(1))
+	}
+	b2, b := new(big.Int), new(big.Int)
+	for i := 0; i < steps; i++ {
+		b2.Mul(a, a).Sub(b2, n)
+		b.Sqrt(b2)
+		if new(big.Int).Mul(b, b).Cmp(b2) == 0 {
+			p := new(big.Int).Sub(a, b)
+			if p.Cmp(big.NewInt(1)) > 0 {
+				return p
+			}
+			return nil
+		}
+		a.Add(a, big.NewInt(1))
+	}
+	return nil
+}
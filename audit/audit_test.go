@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// rocaPrime constroi um primo com a estrutura da RSALib:
+// p = k*M + (65537^a mod M), com M o produto de rocaPrimes
+func rocaPrime() *big.Int {
+	m := big.NewInt(2)
+	for _, r := range rocaPrimes {
+		m.Mul(m, big.NewInt(int64(r)))
+	}
+	for {
+		a, _ := rand.Int(rand.Reader, m)
+		k, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
+		p := new(big.Int).Exp(big.NewInt(65537), a, m)
+		p.Add(p, k.Mul(k, m))
+		if p.ProbablyPrime(20) {
+			return p
+		}
+	}
+}
+
+func TestROCAFingerprint(t *testing.T) {
+	p, q := rocaPrime(), rocaPrime()
+	if !ROCAFingerprint(p) || !ROCAFingerprint(new(big.Int).Mul(p, q)) {
+		t.Fatal("primo com estrutura ROCA nao detectado")
+	}
+	if findings := Audit(p); len(findings) == 0 || findings[0].Check != "roca" {
+		t.Errorf("Audit = %v", findings)
+	}
+
+	for i := 0; i < 20; i++ {
+		r, _ := rand.Prime(rand.Reader, 512)
+		if findings := Audit(r); findings != nil {
+			t.Errorf("primo aleatorio %s reprovado: %v", r, findings)
+		}
+	}
+}
+
+func TestAuditWeakPatterns(t *testing.T) {
+	// 2^127 - 1 tem todos os bits ligados
+	m := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	if findings := Audit(m); len(findings) != 1 || findings[0].Check != "hamming" {
+		t.Errorf("Audit(2^127 - 1) = %v", findings)
+	}
+
+	// Primos consecutivos formam um modulo fatoravel pelo metodo de Fermat
+	p, _ := rand.Prime(rand.Reader, 256)
+	q := new(big.Int).Add(p, big.NewInt(2))
+	for !q.ProbablyPrime(20) {
+		q.Add(q, big.NewInt(2))
+	}
+	findings := AuditModulus(new(big.Int).Mul(p, q))
+	if len(findings) != 1 || findings[0].Check != "fermat" {
+		t.Fatalf("AuditModulus = %v", findings)
+	}
+
+	r, _ := rand.Prime(rand.Reader, 256)
+	if findings := AuditModulus(new(big.Int).Mul(p, r)); findings != nil {
+		t.Errorf("modulo aleatorio reprovado: %v", findings)
+	}
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"math/big"
+)
+
+// BatchGCD calcula, para cada valor, o mdc com o produto de todos os
+// outros, pelo algoritmo de Bernstein: uma arvore de produtos seguida de
+// uma arvore de restos. Um resultado maior que 1 indica que o valor
+// compartilha um fator com algum outro da lista; para modulos RSA gerados
+// independentemente, isso so acontece se a fonte de entropia falhou.
+func BatchGCD(values []*big.Int) []*big.Int {
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Arvore de produtos: tree[0] sao os valores e a raiz eh o produto total
+	tree := [][]*big.Int{values}
+	for level := values; len(level) > 1; {
+		next := make([]*big.Int, (len(level)+1)/2)
+		for i := range next {
+			if 2*i+1 < len(level) {
+				next[i] = new(big.Int).Mul(level[2*i], level[2*i+1])
+			} else {
+				next[i] = level[2*i]
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+
+	// Arvore de restos: descemos calculando P mod x^2 para cada no x
+	rests := tree[len(tree)-1]
+	for depth := len(tree) - 2; depth >= 0; depth-- {
+		level := tree[depth]
+		next := make([]*big.Int, len(level))
+		for i, x := range level {
+			sq := new(big.Int).Mul(x, x)
+			next[i] = sq.Mod(rests[i/2], sq)
+		}
+		rests = next
+	}
+
+	// mdc((P mod x^2) / x, x) = mdc(P/x, x)
+	gcds := make([]*big.Int, len(values))
+	for i, x := range values {
+		q := new(big.Int).Quo(rests[i], x)
+		gcds[i] = q.GCD(nil, nil, q, x)
+	}
+	return gcds
+}
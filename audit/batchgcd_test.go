@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestBatchGCD(t *testing.T) {
+	primes := make([]*big.Int, 8)
+	for i := range primes {
+		primes[i], _ = rand.Prime(rand.Reader, 128)
+	}
+	mul := func(a, b int) *big.Int { return new(big.Int).Mul(primes[a], primes[b]) }
+
+	// Os modulos 1 e 4 compartilham primes[2]; os demais sao independentes
+	moduli := []*big.Int{mul(0, 1), mul(2, 3), mul(4, 5), mul(6, 7), mul(2, 0)}
+	gcds := BatchGCD(moduli)
+	want := []*big.Int{primes[0], primes[2], big.NewInt(1), big.NewInt(1), moduli[4]}
+	for i := range want {
+		if gcds[i].Cmp(want[i]) != 0 {
+			t.Errorf("BatchGCD[%d] = %s, esperado %s", i, gcds[i], want[i])
+		}
+	}
+
+	if gcds := BatchGCD(moduli[:1]); gcds[0].Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("BatchGCD de um unico valor = %s", gcds[0])
+	}
+}
@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Params sao os numeros de um bloco PEM gerado por outra ferramenta, como
+// o openssl dhparam ou o openssl genrsa, lidos por ParsePEM
+type Params struct {
+	Type string // tipo do bloco PEM, ex.: "DH PARAMETERS"
+
+	// Parametros de Diffie-Hellman: o primo p, o gerador g e, no formato
+	// X9.42, a ordem q do subgrupo gerado por g (nil no PKCS#3, em que p
+	// deve ser um primo seguro)
+	P, G, Q *big.Int
+
+	// Chaves RSA: o modulo n e, nas chaves privadas, seus fatores primos
+	N      *big.Int
+	Primes []*big.Int
+}
+
+// DH informa se os parametros sao de Diffie-Hellman
+func (p Params) DH() bool {
+	return p.P != nil
+}
+
+// dhParams eh o DHParameter do PKCS#3, produzido pelo openssl dhparam
+type dhParams struct {
+	P, G               *big.Int
+	PrivateValueLength int `asn1:"optional"`
+}
+
+// x942Params eh o DomainParameters da ANSI X9.42 (RFC 3279)
+type x942Params struct {
+	P, G, Q    *big.Int
+	J          *big.Int      `asn1:"optional"`
+	Validation asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs1PrivateKey eh o RSAPrivateKey do PKCS#1 (RFC 8017). Ele eh lido
+// aqui, e nao com x509.ParsePKCS1PrivateKey, porque esta rejeita chaves
+// inconsistentes, justamente as que interessam a uma auditoria.
+type pkcs1PrivateKey struct {
+	Version         int
+	N, E, D, P, Q   *big.Int
+	Dp, Dq, Qinv    *big.Int
+	OtherPrimeInfos []pkcs1OtherPrime `asn1:"optional"`
+}
+
+type pkcs1OtherPrime struct {
+	R, Exponent, Coefficient *big.Int
+}
+
+// pkcs8 eh o PrivateKeyInfo do PKCS#8 (RFC 5208)
+type pkcs8 struct {
+	Version    int
+	Algorithm  asn1.RawValue
+	PrivateKey []byte
+	Attributes asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// algorithmIdentifier eh o AlgorithmIdentifier do X.509
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+var oidRSA = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+
+// ParsePEM le todos os blocos PEM de data e extrai os numeros de cada um.
+// Sao aceitos parametros de Diffie-Hellman ("DH PARAMETERS" do PKCS#3 e
+// "X9.42 DH PARAMETERS"), chaves RSA no PKCS#1 ("RSA PRIVATE KEY" e "RSA
+// PUBLIC KEY") e chaves RSA no PKCS#8 e no X.509 ("PRIVATE KEY" e "PUBLIC
+// KEY"). Blocos cifrados ou de outros tipos sao um erro.
+func ParsePEM(data []byte) ([]Params, error) {
+	var all []Params
+	for i := 1; ; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		params, err := parseBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("audit: bloco %d (%s): %w", i, block.Type, err)
+		}
+		all = append(all, params)
+	}
+	if len(all) == 0 {
+		return nil, errors.New("audit: nenhum bloco PEM encontrado")
+	}
+	return all, nil
+}
+
+// parseBlock extrai os numeros de um bloco PEM
+func parseBlock(block *pem.Block) (Params, error) {
+	if _, ok := block.Headers["DEK-Info"]; ok {
+		return Params{}, errors.New("bloco cifrado")
+	}
+	params := Params{Type: block.Type}
+	var err error
+	switch block.Type {
+	case "DH PARAMETERS":
+		var dh dhParams
+		if err = unmarshal(block.Bytes, &dh); err == nil {
+			params.P, params.G = dh.P, dh.G
+		}
+	case "X9.42 DH PARAMETERS":
+		var dh x942Params
+		if err = unmarshal(block.Bytes, &dh); err == nil {
+			params.P, params.G, params.Q = dh.P, dh.G, dh.Q
+		}
+	case "RSA PRIVATE KEY":
+		params.N, params.Primes, err = parsePKCS1Private(block.Bytes)
+	case "RSA PUBLIC KEY":
+		var key rsa.PublicKey
+		if err = unmarshal(block.Bytes, &key); err == nil {
+			params.N = key.N
+		}
+	case "PRIVATE KEY":
+		var key pkcs8
+		var alg algorithmIdentifier
+		if err = unmarshal(block.Bytes, &key); err != nil {
+			break
+		}
+		if err = unmarshal(key.Algorithm.FullBytes, &alg); err != nil {
+			break
+		}
+		if !alg.Algorithm.Equal(oidRSA) {
+			return Params{}, fmt.Errorf("algoritmo %s nao suportado", alg.Algorithm)
+		}
+		params.N, params.Primes, err = parsePKCS1Private(key.PrivateKey)
+	case "PUBLIC KEY":
+		var key any
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			break
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return Params{}, fmt.Errorf("chave %T nao suportada", key)
+		}
+		params.N = rsaKey.N
+	default:
+		return Params{}, errors.New("tipo de bloco nao suportado")
+	}
+	if err != nil {
+		return Params{}, err
+	}
+	for _, n := range []*big.Int{params.P, params.G, params.Q, params.N} {
+		if n != nil && n.Sign() <= 0 {
+			return Params{}, errors.New("valor nao positivo")
+		}
+	}
+	return params, nil
+}
+
+// parsePKCS1Private retorna o modulo e os fatores primos de uma chave
+// privada no PKCS#1, inclusive os adicionais das chaves com mais de dois
+func parsePKCS1Private(der []byte) (*big.Int, []*big.Int, error) {
+	var key pkcs1PrivateKey
+	if err := unmarshal(der, &key); err != nil {
+		return nil, nil, err
+	}
+	primes := []*big.Int{key.P, key.Q}
+	for _, other := range key.OtherPrimeInfos {
+		primes = append(primes, other.R)
+	}
+	for _, p := range primes {
+		if p.Sign() <= 0 {
+			return nil, nil, errors.New("fator nao positivo")
+		}
+	}
+	return key.N, primes, nil
+}
+
+// unmarshal decodifica der em v, recusando bytes que sobrem no final
+func unmarshal(der []byte, v any) error {
+	rest, err := asn1.Unmarshal(der, v)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return errors.New("dados extras apos a estrutura ASN.1")
+	}
+	return nil
+}
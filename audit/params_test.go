@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+)
+
+// openSSLDHParams foi gerado com openssl dhparam 512
+const openSSLDHParams = `-----BEGIN DH PARAMETERS-----
+MEYCQQDVpz8QERhxLgpkHdn6veODUDCV0Nn9+rdWTf6YDLNtRQrfpLtpLuQaAa5t
+Z6Mw5DBfatQS8H69+N2dg9+oFM+vAgEC
+-----END DH PARAMETERS-----
+`
+
+func encodePEM(t *testing.T, kind string, v any) []byte {
+	t.Helper()
+	der, err := asn1.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der})
+}
+
+func TestParsePEMDH(t *testing.T) {
+	params, err := ParsePEM([]byte(openSSLDHParams))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 1 || !params[0].DH() || params[0].Q != nil {
+		t.Fatalf("ParsePEM = %+v", params)
+	}
+	p := params[0].P
+	if p.BitLen() != 512 || params[0].G.Int64() != 2 || !p.ProbablyPrime(20) {
+		t.Errorf("p = %s, g = %s", p, params[0].G)
+	}
+
+	// X9.42: p = 23, g = 4 e q = 11
+	data := encodePEM(t, "X9.42 DH PARAMETERS", x942Params{P: big.NewInt(23), G: big.NewInt(4), Q: big.NewInt(11)})
+	if params, err = ParsePEM(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := params[0]; got.P.Int64() != 23 || got.G.Int64() != 4 || got.Q.Int64() != 11 {
+		t.Errorf("X9.42 = %+v", got)
+	}
+}
+
+func TestParsePEMRSA(t *testing.T) {
+	p, _ := rand.Prime(rand.Reader, 256)
+	q, _ := rand.Prime(rand.Reader, 256)
+	n := new(big.Int).Mul(p, q)
+	one := big.NewInt(1)
+	private := pkcs1PrivateKey{N: n, E: big.NewInt(65537), D: one, P: p, Q: q, Dp: one, Dq: one, Qinv: one}
+	pkcs1 := encodePEM(t, "RSA PRIVATE KEY", private)
+	der, _ := asn1.Marshal(private)
+	algorithm, _ := asn1.Marshal(algorithmIdentifier{Algorithm: oidRSA, Parameters: asn1.NullRawValue})
+	pkcs8 := encodePEM(t, "PRIVATE KEY", pkcs8{Algorithm: asn1.RawValue{FullBytes: algorithm}, PrivateKey: der})
+	public := encodePEM(t, "RSA PUBLIC KEY", rsa.PublicKey{N: n, E: 65537})
+	pkix, err := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: n, E: 65537})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spki := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix})
+
+	// Todos os blocos no mesmo arquivo, como em um pacote de chaves
+	var data []byte
+	for _, block := range [][]byte{pkcs1, pkcs8, public, spki} {
+		data = append(data, block...)
+	}
+	params, err := ParsePEM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 4 {
+		t.Fatalf("%d blocos lidos", len(params))
+	}
+	for i, got := range params {
+		if got.DH() || got.N.Cmp(n) != 0 {
+			t.Errorf("bloco %d (%s): %+v", i+1, got.Type, got)
+		}
+		wantPrimes := i < 2
+		if (len(got.Primes) == 2) != wantPrimes || (wantPrimes && (got.Primes[0].Cmp(p) != 0 || got.Primes[1].Cmp(q) != 0)) {
+			t.Errorf("bloco %d (%s): fatores %v", i+1, got.Type, got.Primes)
+		}
+	}
+
+	// Uma chave inconsistente, com um fator composto, ainda eh lida
+	private.P = new(big.Int).Mul(p, big.NewInt(3))
+	if params, err := ParsePEM(encodePEM(t, "RSA PRIVATE KEY", private)); err != nil || params[0].Primes[0].Cmp(private.P) != 0 {
+		t.Errorf("chave inconsistente: %v, %v", params, err)
+	}
+}
+
+func TestParsePEMErrors(t *testing.T) {
+	encrypted := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-128-CBC,00"}, Bytes: []byte{0}})
+	negative := encodePEM(t, "DH PARAMETERS", dhParams{P: big.NewInt(-23), G: big.NewInt(5)})
+	for name, data := range map[string][]byte{
+		"sem PEM":  []byte("23\n"),
+		"tipo":     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{0}}),
+		"cifrado":  encrypted,
+		"negativo": negative,
+		"ASN.1":    pem.EncodeToMemory(&pem.Block{Type: "DH PARAMETERS", Bytes: []byte{0x30, 0x01}}),
+	} {
+		if _, err := ParsePEM(data); err == nil {
+			t.Errorf("%s: nenhum erro", name)
+		}
+	}
+}
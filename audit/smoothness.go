@@ -0,0 +1,101 @@
+// O pacote audit verifica a qualidade de primos e modulos gerados,
+// procurando estruturas que os tornem vulneraveis a ataques conhecidos.
+package audit
+
+import (
+	"PrimeNumGenerator/numutil"
+	"math/big"
+)
+
+// Factor eh um fator primo pequeno e seu expoente
+type Factor struct {
+	Prime    int
+	Exponent int
+}
+
+// Factorization eh a fatoracao parcial de um numero pelos primos ate um
+// limite: Factors traz os fatores pequenos e Cofactor a parte que sobrou
+type Factorization struct {
+	Factors  []Factor
+	Cofactor *big.Int
+}
+
+// Largest retorna o maior fator pequeno encontrado (1 se nenhum)
+func (f Factorization) Largest() int {
+	if len(f.Factors) == 0 {
+		return 1
+	}
+	return f.Factors[len(f.Factors)-1].Prime
+}
+
+// Smoothness eh o resultado de SmoothnessReport para um primo p
+type Smoothness struct {
+	Bound  int
+	PMinus Factorization // fatoracao parcial de p - 1
+	PPlus  Factorization // fatoracao parcial de p + 1
+}
+
+// weak informa se a fatoracao eh suave o bastante para os dois estagios do
+// ataque: o cofator eh 1 ou um unico primo menor que bound^2
+func (s Smoothness) weak(f Factorization) bool {
+	if f.Cofactor.Cmp(big.NewInt(1)) == 0 {
+		return true
+	}
+	b := big.NewInt(int64(s.Bound))
+	return f.Cofactor.Cmp(b.Mul(b, b)) <= 0 && f.Cofactor.ProbablyPrime(20)
+}
+
+// PollardVulnerable informa se p - 1 eh suave, tornando o produto de p por
+// outro primo fatoravel pelo metodo p - 1 de Pollard com limite Bound
+func (s Smoothness) PollardVulnerable() bool {
+	return s.weak(s.PMinus)
+}
+
+// WilliamsVulnerable informa se p + 1 eh suave, tornando o produto de p por
+// outro primo fatoravel pelo metodo p + 1 de Williams com limite Bound
+func (s Smoothness) WilliamsVulnerable() bool {
+	return s.weak(s.PPlus)
+}
+
+// Vulnerable informa se p eh vulneravel a algum dos dois ataques
+func (s Smoothness) Vulnerable() bool {
+	return s.PollardVulnerable() || s.WilliamsVulnerable()
+}
+
+// SmoothnessReport retira de p - 1 e de p + 1 os fatores primos ate bound e
+// relata o que foi encontrado
+func SmoothnessReport(p *big.Int, bound int) Smoothness {
+	primes := numutil.PrimesUpTo(bound)
+	one := big.NewInt(1)
+	return Smoothness{
+		Bound:  bound,
+		PMinus: trialFactor(new(big.Int).Sub(p, one), primes),
+		PPlus:  trialFactor(new(big.Int).Add(p, one), primes),
+	}
+}
+
+// trialFactor divide n por cada um dos primos
+func trialFactor(n *big.Int, primes []int) Factorization {
+	rest := new(big.Int).Set(n)
+	var factors []Factor
+	q, r := new(big.Int), new(big.Int)
+	for _, p := range primes {
+		if rest.Cmp(big.NewInt(1)) <= 0 {
+			break
+		}
+		bp := big.NewInt(int64(p))
+		exp := 0
+		for {
+			q.QuoRem(rest, bp, r)
+			if r.Sign() != 0 {
+				break
+			}
+			rest.Set(q)
+			exp++
+		}
+		if exp > 0 {
+			factors = append(factors, Factor{Prime: p, Exponent: exp})
+		}
+	}
+	return Factorization{Factors: factors, Cofactor: rest}
+}
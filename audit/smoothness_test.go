@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSmoothnessReport(t *testing.T) {
+	// p = 2^4 * 3 * 5 * 13^3 + 1 = 527281 eh primo e p - 1 eh 13-suave
+	p := big.NewInt(527281)
+	s := SmoothnessReport(p, 100)
+	if !s.PollardVulnerable() || s.PMinus.Largest() != 13 || s.PMinus.Cofactor.Int64() != 1 {
+		t.Fatalf("p - 1 = %+v", s.PMinus)
+	}
+	want := []Factor{{2, 4}, {3, 1}, {5, 1}, {13, 3}}
+	for i, f := range want {
+		if s.PMinus.Factors[i] != f {
+			t.Fatalf("fator %d = %+v, esperado %+v", i, s.PMinus.Factors[i], f)
+		}
+	}
+
+	// p + 1 = 2 * 7 * 37663: com limite 200 sobra o primo 37663 < 200^2,
+	// alcancado pelo segundo estagio; com limite 100 ele esta fora de alcance
+	if s.WilliamsVulnerable() || s.PPlus.Cofactor.Int64() != 37663 {
+		t.Errorf("p + 1 = %+v", s.PPlus)
+	}
+	if !SmoothnessReport(p, 200).WilliamsVulnerable() {
+		t.Error("p + 1 nao considerado suave com limite 200")
+	}
+
+	// Primo de Mersenne 2^127 - 1: p + 1 = 2^127
+	m := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	if s := SmoothnessReport(m, 1000); !s.WilliamsVulnerable() || s.PPlus.Factors[0] != (Factor{2, 127}) {
+		t.Errorf("2^127 - 1: %+v", s.PPlus)
+	}
+}
@@ -0,0 +1,204 @@
+// O pacote auditlog mantem um log de auditoria apenas acrescentado dos
+// primos emitidos pelo servidor. Cada entrada guarda o hash da anterior,
+// formando uma cadeia: alterar, remover ou reordenar qualquer entrada
+// quebra a cadeia a partir dela. Opcionalmente, o hash de cada entrada eh
+// assinado com Ed25519, permitindo provar depois quem emitiu cada primo.
+package auditlog
+
+import (
+	"PrimeNumGenerator/clock"
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry eh um evento de geracao registrado no log
+type Entry struct {
+	Seq    uint64    `json:"seq"`
+	Time   time.Time `json:"time"`
+	Event  string    `json:"event"` // ex.: "generate"
+	Test   string    `json:"test,omitempty"`
+	Bits   int       `json:"bits,omitempty"`
+	Number string    `json:"number,omitempty"`
+	Params string    `json:"params,omitempty"` // parametros da geracao, em texto livre
+	Prev   string    `json:"prev"`             // hash da entrada anterior, em hexadecimal
+
+	Hash      string `json:"hash"`                // SHA-256 da entrada sem Hash e Signature
+	Signature string `json:"signature,omitempty"` // assinatura Ed25519 de Hash
+}
+
+// genesis eh o valor de Prev da primeira entrada
+var genesis = strings.Repeat("0", 2*sha256.Size)
+
+// digest calcula o hash da entrada, sem os campos Hash e Signature
+func (e Entry) digest() ([]byte, error) {
+	e.Hash, e.Signature = "", ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// Log eh um log de auditoria aberto para acrescentar entradas. Pode ser
+// usado por varias goroutines ao mesmo tempo.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	key  ed25519.PrivateKey
+	seq  uint64
+	prev string
+}
+
+// Open abre (ou cria) o log em path e continua a cadeia a partir da ultima
+// entrada. Se key nao for nil, as novas entradas sao assinadas com ela.
+func Open(path string, key ed25519.PrivateKey) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{f: f, key: key, prev: genesis}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("auditlog: entrada %d invalida: %w", l.seq+1, err)
+		}
+		l.seq, l.prev = e.Seq, e.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append completa e grava a entrada e: Seq, Time (se zero), Prev, Hash e
+// Signature sao preenchidos pelo log. Retorna a entrada gravada.
+func (l *Log) Append(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return Entry{}, errors.New("auditlog: log fechado")
+	}
+
+	e.Seq = l.seq + 1
+	if e.Time.IsZero() {
+		e.Time = clock.Now().UTC()
+	}
+	e.Prev = l.prev
+	sum, err := e.digest()
+	if err != nil {
+		return Entry{}, err
+	}
+	e.Hash = hex.EncodeToString(sum)
+	e.Signature = ""
+	if l.key != nil {
+		e.Signature = hex.EncodeToString(ed25519.Sign(l.key, sum))
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, err
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return Entry{}, err
+	}
+	if err := l.f.Sync(); err != nil {
+		return Entry{}, err
+	}
+	l.seq, l.prev = e.Seq, e.Hash
+	return e, nil
+}
+
+// Close fecha o log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
+
+// Verify confere a cadeia de hashes do log lido de r e, se pub nao for nil,
+// a assinatura de cada entrada. Retorna o numero de entradas verificadas ou
+// um erro apontando a primeira entrada invalida.
+func Verify(r io.Reader, pub ed25519.PublicKey) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	prev, count := genesis, 0
+	for scanner.Scan() {
+		count++
+		var e Entry
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&e); err != nil {
+			return count - 1, fmt.Errorf("auditlog: entrada %d invalida: %w", count, err)
+		}
+		if e.Seq != uint64(count) || e.Prev != prev {
+			return count - 1, fmt.Errorf("auditlog: entrada %d fora da cadeia", count)
+		}
+		sum, err := e.digest()
+		if err != nil {
+			return count - 1, err
+		}
+		if e.Hash != hex.EncodeToString(sum) {
+			return count - 1, fmt.Errorf("auditlog: hash da entrada %d nao confere", count)
+		}
+		if pub != nil {
+			sig, err := hex.DecodeString(e.Signature)
+			if err != nil || !ed25519.Verify(pub, sum, sig) {
+				return count - 1, fmt.Errorf("auditlog: assinatura da entrada %d invalida", count)
+			}
+		}
+		prev = e.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// LoadKey le uma chave privada Ed25519 gravada por SaveKey: a semente de 32
+// bytes em hexadecimal
+func LoadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("auditlog: chave invalida")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// SaveKey grava a semente da chave privada key em path, em hexadecimal
+func SaveKey(path string, key ed25519.PrivateKey) error {
+	return os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0o600)
+}
+
+// ParsePublicKey decodifica uma chave publica Ed25519 em hexadecimal
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	pub, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("auditlog: chave publica invalida")
+	}
+	return ed25519.PublicKey(pub), nil
+}
@@ -0,0 +1,54 @@
+package auditlog
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogChainAndSignatures(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Append(Entry{Event: "generate", Test: "miller-rabin", Bits: 64, Number: "18446744073709551557"})
+	l.Close()
+
+	// Reabrimos para conferir que a cadeia continua apos reinicializar
+	l, err = Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := l.Append(Entry{Event: "generate", Test: "fermat", Bits: 32, Number: "4294967291"})
+	l.Close()
+	if err != nil || e.Seq != 2 {
+		t.Fatalf("Append = %+v, %v", e, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if n, err := Verify(bytes.NewReader(data), pub); n != 2 || err != nil {
+		t.Fatalf("Verify = %d, %v", n, err)
+	}
+
+	// Trocar o primo emitido quebra o hash
+	tampered := strings.Replace(string(data), "4294967291", "4294967279", 1)
+	if _, err := Verify(strings.NewReader(tampered), nil); err == nil {
+		t.Error("log adulterado aceito")
+	}
+	// Remover a primeira entrada quebra a cadeia
+	lines := strings.SplitAfter(string(data), "\n")
+	if _, err := Verify(strings.NewReader(lines[1]), nil); err == nil {
+		t.Error("log truncado aceito")
+	}
+	// Outra chave nao valida as assinaturas
+	other, _, _ := ed25519.GenerateKey(nil)
+	if _, err := Verify(bytes.NewReader(data), other); err == nil {
+		t.Error("assinatura aceita com outra chave")
+	}
+}
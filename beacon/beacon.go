@@ -0,0 +1,208 @@
+// O pacote beacon implementa um farol de aleatoriedade verificavel sobre o
+//
+//	Blum Blum Shub.
+//
+// O farol publica o modulo n (sem conhecer sua fatoracao) e, para cada
+// segmento, um compromisso SHA-256 com a semente x_0 do segmento. As saidas
+// sao os numeros gerados pelo BBS a partir dessa semente. Ao fim de cada
+// segmento a semente eh revelada (prova de estado), permitindo que qualquer
+// pessoa recalcule e confira todas as saidas do segmento, e uma nova
+// semente eh sorteada para o segmento seguinte, cujo compromisso ja foi
+// publicado. Revelar x_0 so depois de encerrado o segmento impede que as
+// saidas sejam previstas antes de serem emitidas.
+package beacon
+
+import (
+	"PrimeNumGenerator/prng"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// maxProofs eh o numero de provas de segmentos encerrados mantidas em memoria
+const maxProofs = 1024
+
+// Commitment eh o compromisso publicado no inicio de um segmento
+type Commitment struct {
+	Segment    uint64 `json:"segment"`
+	Modulus    string `json:"modulus"`    // n em hexadecimal
+	Commitment string `json:"commitment"` // SHA-256(segmento || n || x_0) em hexadecimal
+}
+
+// Output eh uma saida do farol
+type Output struct {
+	Segment uint64 `json:"segment"`
+	Index   int    `json:"index"`
+	Value   string `json:"value"` // numero gerado, em hexadecimal
+}
+
+// Proof revela a semente de um segmento encerrado
+type Proof struct {
+	Segment uint64 `json:"segment"`
+	Seed    string `json:"seed"` // x_0 em hexadecimal
+	Outputs int    `json:"outputs"`
+}
+
+// Beacon eh o farol. Eh seguro para uso concorrente.
+type Beacon struct {
+	mu      sync.Mutex
+	bbs     *prng.BlumBlumShub
+	entropy prng.Entropy
+	period  int
+	bits    int
+	segment uint64
+	index   int
+	seed    *big.Int
+	proofs  map[uint64]Proof
+	oldest  uint64
+	modulus *big.Int
+}
+
+// New cria um farol com modulo de modulusBits bits, saidas de outputBits
+// bits e segmentos de period saidas. Os fatores de n sao descartados
+// assim que o modulo eh gerado.
+func New(modulusBits, outputBits, period int, e prng.Entropy) (*Beacon, error) {
+	if period < 1 {
+		return nil, errors.New("beacon: periodo deve ser positivo")
+	}
+	bbs, err := prng.NewBBSPublicOnly(modulusBits, e)
+	if err != nil {
+		return nil, err
+	}
+	// Recriamos o gerador com o tamanho de saida pedido; o estado 4 eh
+	// provisorio e eh substituido pela semente do primeiro segmento
+	public, err := prng.NewBBSFromPublic(bbs.Modulus(), big.NewInt(4), outputBits)
+	if err != nil {
+		return nil, err
+	}
+	b := &Beacon{
+		bbs:     public,
+		entropy: e,
+		period:  period,
+		bits:    outputBits,
+		proofs:  make(map[uint64]Proof),
+		modulus: bbs.Modulus(),
+	}
+	if b.seed, err = b.bbs.Reseed(e); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// commit calcula o compromisso com a semente x0 do segmento
+func commit(segment uint64, n, x0 *big.Int) string {
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], segment)
+	h.Write(buf[:])
+	h.Write(n.Bytes())
+	h.Write(x0.Bytes())
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Commitment retorna o compromisso do segmento atual
+func (b *Beacon) Commitment() Commitment {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.commitment()
+}
+
+func (b *Beacon) commitment() Commitment {
+	return Commitment{
+		Segment:    b.segment,
+		Modulus:    b.modulus.Text(16),
+		Commitment: commit(b.segment, b.modulus, b.seed),
+	}
+}
+
+// Next emite a proxima saida. Quando ela encerra um segmento, tambem
+// retorna a prova do segmento encerrado.
+func (b *Beacon) Next() (Output, *Proof, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := Output{
+		Segment: b.segment,
+		Index:   b.index,
+		Value:   b.bbs.Next().Text(16),
+	}
+	b.index++
+	if b.index < b.period {
+		return out, nil, nil
+	}
+
+	// Encerramos o segmento: revelamos a semente e sorteamos a proxima
+	proof := Proof{Segment: b.segment, Seed: b.seed.Text(16), Outputs: b.index}
+	seed, err := b.bbs.Reseed(b.entropy)
+	if err != nil {
+		return Output{}, nil, err
+	}
+	b.proofs[proof.Segment] = proof
+	for len(b.proofs) > maxProofs {
+		delete(b.proofs, b.oldest)
+		b.oldest++
+	}
+	b.seed = seed
+	b.segment++
+	b.index = 0
+	return out, &proof, nil
+}
+
+// Proof retorna a prova de um segmento ja encerrado
+func (b *Beacon) Proof(segment uint64) (Proof, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.proofs[segment]
+	return p, ok
+}
+
+// Verify confere se as saidas outputs do segmento foram produzidas pela
+// semente revelada em proof e se essa semente corresponde ao compromisso c,
+// publicado antes das saidas. bits eh o tamanho em bits de cada saida.
+//
+// A prova vem de fora, entao proof.Outputs so limita os indices aceitos:
+// a sequencia eh recalculada apenas ate a maior saida conferida.
+func Verify(c Commitment, proof Proof, outputs []Output, bits int) error {
+	n, ok := new(big.Int).SetString(c.Modulus, 16)
+	if !ok {
+		return errors.New("beacon: modulo invalido")
+	}
+	x0, ok := new(big.Int).SetString(proof.Seed, 16)
+	if !ok {
+		return errors.New("beacon: semente invalida")
+	}
+	if proof.Segment != c.Segment {
+		return errors.New("beacon: prova e compromisso de segmentos diferentes")
+	}
+	if proof.Outputs < 1 {
+		return fmt.Errorf("beacon: prova com %d saidas", proof.Outputs)
+	}
+	if commit(c.Segment, n, x0) != c.Commitment {
+		return errors.New("beacon: semente nao corresponde ao compromisso")
+	}
+
+	bbs, err := prng.NewBBSFromPublic(n, x0, bits)
+	if err != nil {
+		return err
+	}
+	last := -1
+	for _, out := range outputs {
+		if out.Segment != proof.Segment || out.Index < 0 || out.Index >= proof.Outputs {
+			return fmt.Errorf("beacon: saida %d/%d fora do segmento", out.Segment, out.Index)
+		}
+		last = max(last, out.Index)
+	}
+	expected := make([]string, last+1)
+	for i := range expected {
+		expected[i] = bbs.Next().Text(16)
+	}
+	for _, out := range outputs {
+		if expected[out.Index] != out.Value {
+			return fmt.Errorf("beacon: saida %d nao confere", out.Index)
+		}
+	}
+	return nil
+}
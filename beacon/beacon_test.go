@@ -0,0 +1,103 @@
+package beacon
+
+import (
+	"PrimeNumGenerator/prng"
+	"math"
+	"testing"
+)
+
+func TestBeaconVerify(t *testing.T) {
+	b, err := New(128, 32, 4, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := b.Commitment()
+	var outputs []Output
+	var proof *Proof
+	for proof == nil {
+		var out Output
+		out, proof, err = b.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, out)
+	}
+	if len(outputs) != 4 {
+		t.Fatalf("segmento com %d saidas, esperado 4", len(outputs))
+	}
+	if err := Verify(c, *proof, outputs, 32); err != nil {
+		t.Fatal(err)
+	}
+	if stored, ok := b.Proof(c.Segment); !ok || stored != *proof {
+		t.Error("prova do segmento encerrado nao foi guardada")
+	}
+
+	// A prova nao escolhe quanto trabalho Verify faz: uma contagem absurda
+	// nao eh alocada, e as saidas alem dela sao recusadas
+	huge := *proof
+	huge.Outputs = math.MaxInt
+	if err := Verify(c, huge, outputs[:2], 32); err != nil {
+		t.Errorf("prova com contagem grande: %v", err)
+	}
+	for _, n := range []int{0, -1, 2} {
+		bad := *proof
+		bad.Outputs = n
+		if Verify(c, bad, outputs, 32) == nil {
+			t.Errorf("prova com %d saidas aceita", n)
+		}
+	}
+
+	// Uma saida adulterada deve ser rejeitada
+	outputs[1].Value = "0"
+	if err := Verify(c, *proof, outputs, 32); err == nil {
+		t.Error("saida adulterada aceita")
+	}
+
+	// A prova de um segmento nao vale para o compromisso de outro
+	if next := b.Commitment(); Verify(next, *proof, nil, 32) == nil {
+		t.Error("prova aceita para outro segmento")
+	}
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	b, err := New(128, 32, 4, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Avancamos ate o meio do segundo segmento
+	for i := 0; i < 6; i++ {
+		if _, _, err := b.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := b.Checkpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := Restore(data, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Commitment() != b.Commitment() {
+		t.Fatal("compromisso do farol restaurado difere do original")
+	}
+	if p, ok := restored.Proof(0); !ok || p.Seed == "" {
+		t.Error("prova do primeiro segmento perdida no checkpoint")
+	}
+	for i := 0; i < 2; i++ {
+		want, _, _ := b.Next()
+		got, _, err := restored.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("saida %d: %+v, esperado %+v", i, got, want)
+		}
+	}
+
+	if _, err := Restore([]byte(`{"period":0}`), prng.Entropy{}); err == nil {
+		t.Error("checkpoint invalido aceito")
+	}
+}
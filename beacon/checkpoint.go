@@ -0,0 +1,89 @@
+package beacon
+
+import (
+	"PrimeNumGenerator/prng"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// checkpoint eh o estado serializado do farol. O gerador so contem o modulo
+// publico e o estado atual, pois os fatores de n sao descartados em New.
+type checkpoint struct {
+	Generator []byte  `json:"generator"` // prng.BlumBlumShub.MarshalBinary
+	Period    int     `json:"period"`
+	Bits      int     `json:"bits"`
+	Segment   uint64  `json:"segment"`
+	Index     int     `json:"index"`
+	Seed      string  `json:"seed"` // x_0 do segmento atual em hexadecimal
+	Proofs    []Proof `json:"proofs"`
+	Oldest    uint64  `json:"oldest"`
+}
+
+// Checkpoint serializa o estado do farol, para que ele continue do mesmo
+// ponto apos reiniciar o processo (veja Restore). O resultado contem a
+// semente do segmento atual e deve ser guardado em sigilo.
+func (b *Beacon) Checkpoint() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	generator, err := b.bbs.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	c := checkpoint{
+		Generator: generator,
+		Period:    b.period,
+		Bits:      b.bits,
+		Segment:   b.segment,
+		Index:     b.index,
+		Seed:      b.seed.Text(16),
+		Oldest:    b.oldest,
+	}
+	for _, p := range b.proofs {
+		c.Proofs = append(c.Proofs, p)
+	}
+	sort.Slice(c.Proofs, func(i, j int) bool { return c.Proofs[i].Segment < c.Proofs[j].Segment })
+	return json.Marshal(c)
+}
+
+// Restore recria um farol a partir de um estado gerado por Checkpoint. As
+// sementes dos proximos segmentos sao sorteadas a partir da entropia e.
+func Restore(data []byte, e prng.Entropy) (*Beacon, error) {
+	var c checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Period < 1 || c.Index < 0 || c.Index >= c.Period {
+		return nil, errors.New("beacon: checkpoint com posicao invalida")
+	}
+	seed, ok := new(big.Int).SetString(c.Seed, 16)
+	if !ok {
+		return nil, errors.New("beacon: checkpoint com semente invalida")
+	}
+	bbs := new(prng.BlumBlumShub)
+	if err := bbs.UnmarshalBinary(c.Generator); err != nil {
+		return nil, err
+	}
+	if bbs.HasPrivate() {
+		return nil, errors.New("beacon: checkpoint contem os fatores do modulo")
+	}
+
+	b := &Beacon{
+		bbs:     bbs,
+		entropy: e,
+		period:  c.Period,
+		bits:    c.Bits,
+		segment: c.Segment,
+		index:   c.Index,
+		seed:    seed,
+		proofs:  make(map[uint64]Proof, len(c.Proofs)),
+		oldest:  c.Oldest,
+		modulus: bbs.Modulus(),
+	}
+	for _, p := range c.Proofs {
+		b.proofs[p.Segment] = p
+	}
+	return b, nil
+}
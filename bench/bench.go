@@ -0,0 +1,160 @@
+// O pacote bench mede as operacoes centrais do projeto (saida do LFG, bit
+// do BBS e iteracao do Miller-Rabin) e compara as medicoes com uma base
+// gravada em JSON, para acompanhar o desempenho entre versoes.
+package bench
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Operation eh uma operacao medida. Setup prepara os dados, fora da
+// medicao, e retorna a funcao de benchmark.
+type Operation struct {
+	Name  string
+	Setup func() (func(b *testing.B), error)
+}
+
+// Operations retorna as operacoes medidas com numeros de bits bits
+func Operations(bits int) []Operation {
+	return []Operation{
+		{Name: "lfg-next", Setup: func() (func(b *testing.B), error) {
+			lfg, err := prng.NewLFGWithSecurity(10, 7, 10, bits, prng.Strict)
+			if err != nil {
+				return nil, err
+			}
+			return func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					lfg.Next()
+				}
+			}, nil
+		}},
+		{Name: "bbs-bit", Setup: func() (func(b *testing.B), error) {
+			bbs, err := prng.NewBBSWithSecurity(bits, prng.Strict)
+			if err != nil {
+				return nil, err
+			}
+			return func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					bbs.NextBit()
+				}
+			}, nil
+		}},
+		{Name: "mr-round", Setup: func() (func(b *testing.B), error) {
+			// Com n primo, cada chamada executa exatamente uma iteracao
+			n, err := rand.Prime(rand.Reader, bits)
+			if err != nil {
+				return nil, err
+			}
+			return func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					pta.MillerRabinTest(n, 1)
+				}
+			}, nil
+		}},
+	}
+}
+
+// Result eh a medicao de uma operacao
+type Result struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+}
+
+// Run mede as operacoes ops, uma de cada vez
+func Run(ops []Operation) ([]Result, error) {
+	results := make([]Result, 0, len(ops))
+	for _, op := range ops {
+		f, err := op.Setup()
+		if err != nil {
+			return nil, fmt.Errorf("bench: %s: %w", op.Name, err)
+		}
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			f(b)
+		})
+		if r.N == 0 {
+			return nil, fmt.Errorf("bench: %s nao executou", op.Name)
+		}
+		results = append(results, Result{
+			Name:        op.Name,
+			NsPerOp:     float64(r.T.Nanoseconds()) / float64(r.N),
+			AllocsPerOp: r.AllocsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+		})
+	}
+	return results, nil
+}
+
+// Baseline eh o arquivo com as medicoes de referencia
+type Baseline struct {
+	Time      time.Time `json:"time"`
+	GoVersion string    `json:"go_version"`
+	Bits      int       `json:"bits"`
+	Results   []Result  `json:"results"`
+}
+
+// NewBaseline cria uma base com os resultados results, medidos agora
+func NewBaseline(bits int, results []Result) Baseline {
+	return Baseline{Time: clock.Now().UTC(), GoVersion: runtime.Version(), Bits: bits, Results: results}
+}
+
+// Load le a base gravada em path
+func Load(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Baseline{}, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Baseline{}, fmt.Errorf("bench: base invalida em %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save grava a base em path
+func (b Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Change compara a medicao de uma operacao com a da base
+type Change struct {
+	Name    string
+	Base    float64 // ns/op na base; 0 se a operacao nao estava nela
+	Current float64 // ns/op agora
+	// Percent eh a variacao do tempo: positiva eh regressao, negativa eh
+	// melhora; NaN se a operacao nao estava na base
+	Percent float64
+}
+
+// Compare compara os resultados results com os da base
+func (b Baseline) Compare(results []Result) []Change {
+	base := make(map[string]float64, len(b.Results))
+	for _, r := range b.Results {
+		base[r.Name] = r.NsPerOp
+	}
+	changes := make([]Change, len(results))
+	for i, r := range results {
+		c := Change{Name: r.Name, Base: base[r.Name], Current: r.NsPerOp, Percent: math.NaN()}
+		if c.Base > 0 {
+			c.Percent = (c.Current - c.Base) / c.Base * 100
+		}
+		changes[i] = c
+	}
+	return changes
+}
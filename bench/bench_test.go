@@ -0,0 +1,37 @@
+package bench
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	base := NewBaseline(2048, []Result{{Name: "lfg-next", NsPerOp: 100}, {Name: "bbs-bit", NsPerOp: 400}})
+	changes := base.Compare([]Result{
+		{Name: "lfg-next", NsPerOp: 125},
+		{Name: "bbs-bit", NsPerOp: 300},
+		{Name: "mr-round", NsPerOp: 1000},
+	})
+	if changes[0].Percent != 25 || changes[1].Percent != -25 {
+		t.Errorf("variacoes %f e %f, esperado 25 e -25", changes[0].Percent, changes[1].Percent)
+	}
+	if !math.IsNaN(changes[2].Percent) || changes[2].Base != 0 {
+		t.Errorf("operacao fora da base: %+v", changes[2])
+	}
+}
+
+func TestBaselineSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	base := NewBaseline(512, []Result{{Name: "mr-round", NsPerOp: 1234.5, AllocsPerOp: 3, BytesPerOp: 96}})
+	if err := base.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bits != 512 || got.GoVersion != base.GoVersion || len(got.Results) != 1 || got.Results[0] != base.Results[0] {
+		t.Errorf("base lida %+v, gravada %+v", got, base)
+	}
+}
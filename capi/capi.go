@@ -0,0 +1,212 @@
+// O programa capi expoe os geradores e os testes de primalidade por uma ABI
+// C minima, para uso a partir de C, Python (ctypes/cffi) e outras
+// linguagens. Compile a biblioteca compartilhada com:
+//
+//	go build -buildmode=c-shared -o libprimegen.so ./capi
+//
+// O que gera tambem o cabecalho libprimegen.h. Todas as funcoes retornam um
+// codigo: zero ou positivo em caso de sucesso e negativo em caso de erro
+// (veja pg_strerror). Os numeros sao trocados como texto em hexadecimal,
+// terminado em zero.
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+enum {
+	PG_OK = 0,
+	PG_EINVAL = -1,   // argumento invalido
+	PG_ERANGE = -2,   // buffer de saida pequeno demais
+	PG_EENTROPY = -3, // falha da fonte de entropia
+	PG_EHANDLE = -4,  // handle de gerador invalido
+};
+
+static const char *pg_errors[] = {
+	"ok",
+	"argumento invalido",
+	"buffer de saida pequeno demais",
+	"falha da fonte de entropia",
+	"handle de gerador invalido",
+};
+
+static const char *pg_strerror_impl(int code) {
+	if (code > 0) {
+		code = 0;
+	}
+	if (code < PG_EHANDLE) {
+		return "erro desconhecido";
+	}
+	return pg_errors[-code];
+}
+*/
+import "C"
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"math/big"
+	"sync"
+	"unsafe"
+)
+
+// maxBits limita o tamanho dos numeros pedidos pela ABI
+const maxBits = 1 << 16
+
+// cfg eh a configuracao dos testes: crypto/rand no nivel Strict
+var cfg = pta.Config{Security: prng.Strict}
+
+// handleEntry serializa o uso de um gerador por varias threads do C
+type handleEntry struct {
+	mu sync.Mutex
+	g  prng.Generator
+}
+
+// Os handles sao indices em um mapa, e nao ponteiros Go: um handle invalido
+// vindo do C resulta em PG_EHANDLE em vez de corromper a memoria
+var (
+	handlesMu  sync.Mutex
+	handles    = map[uint64]*handleEntry{}
+	nextHandle uint64
+)
+
+func newHandle(g prng.Generator) C.int64_t {
+	handlesMu.Lock()
+	defer handlesMu.Unlock()
+	nextHandle++
+	handles[nextHandle] = &handleEntry{g: g}
+	return C.int64_t(nextHandle)
+}
+
+// writeHex copia n em hexadecimal, terminado em zero, para out
+func writeHex(n *big.Int, out *C.char, size C.size_t) C.int {
+	if out == nil {
+		return C.PG_EINVAL
+	}
+	s := n.Text(16)
+	if C.size_t(len(s)+1) > size {
+		return C.PG_ERANGE
+	}
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(out)), len(s)+1)
+	copy(buf, s)
+	buf[len(s)] = 0
+	return C.PG_OK
+}
+
+//export pg_strerror
+func pg_strerror(code C.int) *C.char {
+	return (*C.char)(unsafe.Pointer(C.pg_strerror_impl(code)))
+}
+
+//export pg_generate_prime
+func pg_generate_prime(bits C.int, outHex *C.char, size C.size_t) C.int {
+	if bits < 2 || bits > maxBits {
+		return C.PG_EINVAL
+	}
+	candidate, err := cfg.Entropy().Bits(int(bits))
+	if err != nil {
+		return C.PG_EENTROPY
+	}
+	res, err := pta.MillerRabin(candidate, int(bits), cfg)
+	if err != nil {
+		return C.PG_EENTROPY
+	}
+	return writeHex(res.Number, outHex, size)
+}
+
+// pg_is_prime retorna 1 se o numero em hexadecimal eh provavelmente primo
+// e 0 se for composto. rounds <= 0 escolhe o numero de iteracoes pelo
+// tamanho do numero.
+//
+//export pg_is_prime
+func pg_is_prime(hex *C.char, rounds C.int) C.int {
+	if hex == nil {
+		return C.PG_EINVAL
+	}
+	n, ok := new(big.Int).SetString(C.GoString(hex), 16)
+	if !ok {
+		return C.PG_EINVAL
+	}
+	// Com rounds <= 0, o pta escolhe as iteracoes pelo tamanho de n
+	c := cfg
+	c.Rounds = int(rounds)
+	test, _ := pta.Get("miller-rabin")
+	res := test.IsPrime(n, c)
+	if res.Err != nil {
+		return C.PG_EENTROPY
+	}
+	if res.Prime {
+		return 1
+	}
+	return 0
+}
+
+// pg_bbs_new cria um gerador Blum Blum Shub de bits bits e retorna seu
+// handle (positivo), que deve ser liberado com pg_generator_free. bits deve
+// ser pelo menos prng.MinBBSBits (13).
+//
+//export pg_bbs_new
+func pg_bbs_new(bits C.int) C.int64_t {
+	if bits < prng.MinBBSBits || bits > maxBits {
+		return C.PG_EINVAL
+	}
+	g, err := prng.NewBBSWithEntropy(int(bits), cfg.Entropy())
+	if err != nil {
+		return C.PG_EENTROPY
+	}
+	return newHandle(g)
+}
+
+// pg_lfg_new cria um Lagged Fibonacci Generator de bits bits com os
+// parametros j < k e retorna seu handle, como pg_bbs_new. O gerador ja
+// vem aquecido com prng.DefaultWarmup(k) valores descartados.
+//
+//export pg_lfg_new
+func pg_lfg_new(j, k, bits C.int) C.int64_t {
+	if bits < 2 || bits > maxBits || j <= 0 || j >= k || k > 1<<12 {
+		return C.PG_EINVAL
+	}
+	g, err := prng.NewLFGWithEntropy(int(k), int(j), int(k), int(bits), cfg.Entropy())
+	if err != nil {
+		return C.PG_EENTROPY
+	}
+	g.Discard(prng.DefaultWarmup(int(k)))
+	return newHandle(g)
+}
+
+// pg_generator_next escreve em out_hex o proximo numero do gerador
+//
+//export pg_generator_next
+func pg_generator_next(handle C.int64_t, outHex *C.char, size C.size_t) C.int {
+	handlesMu.Lock()
+	h, ok := handles[uint64(handle)]
+	handlesMu.Unlock()
+	if !ok {
+		return C.PG_EHANDLE
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return writeHex(h.g.Next(), outHex, size)
+}
+
+// pg_generator_free libera o gerador, apagando seu estado da memoria
+//
+//export pg_generator_free
+func pg_generator_free(handle C.int64_t) C.int {
+	handlesMu.Lock()
+	h, ok := handles[uint64(handle)]
+	delete(handles, uint64(handle))
+	handlesMu.Unlock()
+	if !ok {
+		return C.PG_EHANDLE
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if w, ok := h.g.(interface{ Wipe() }); ok {
+		w.Wipe()
+	}
+	return C.PG_OK
+}
+
+// main eh exigido pelo -buildmode=c-shared, mas nunca eh executado
+func main() {}
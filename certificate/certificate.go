@@ -0,0 +1,130 @@
+// O pacote certificate produz e verifica certificados de primalidade: ao
+// contrario dos testes probabilisticos do pacote pta, um certificado eh
+// uma prova de que o numero eh primo, que qualquer um pode conferir com
+// bem menos trabalho do que foi preciso para produzi-la.
+//
+// Os certificados sao produzidos pelo metodo de Atkin-Morain (ECPP, prova
+// de primalidade por curvas elipticas; veja Prove) e verificados por
+// Verify. Cada passo reduz a prova de que N eh primo a de que um primo q
+// menor eh primo, ate chegar a um numero de no maximo 64 bits, cuja
+// primalidade eh verificada diretamente.
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// SmallBits eh o tamanho, em bits, ate o qual a primalidade eh verificada
+// diretamente, sem passos: big.Int.ProbablyPrime eh exato abaixo de 2^64
+const SmallBits = 64
+
+// ErrInvalid indica que o certificado nao prova a primalidade do numero
+var ErrInvalid = errors.New("certificate: certificado invalido")
+
+// Certificate prova que N eh primo. Os passos formam uma cadeia: o N do
+// primeiro eh o do certificado, o N de cada um dos demais eh o Q do
+// anterior e o Q do ultimo (ou N, sem passos) tem no maximo SmallBits bits.
+type Certificate struct {
+	N     *big.Int `json:"n"`
+	Steps []Step   `json:"steps"`
+}
+
+// Step eh um passo de Atkin-Morain: a curva y^2 = x^3 + Ax + B modulo N,
+// com M pontos se N for primo, e um ponto P = (X, Y) dela. Se M = kQ com
+// Q primo, Q > (N^(1/4) + 1)^2, kP != O e Q(kP) = O, entao N eh primo
+// (teorema de Goldwasser-Kilian): um fator primo p <= sqrt(N) de N daria
+// a kP ordem Q modulo p, e Q passaria do limite de Hasse para #E(F_p).
+type Step struct {
+	N *big.Int `json:"n"`
+	D int64    `json:"d"` // discriminante da multiplicacao complexa (informativo)
+	A *big.Int `json:"a"`
+	B *big.Int `json:"b"`
+	M *big.Int `json:"m"`
+	Q *big.Int `json:"q"`
+	X *big.Int `json:"x"`
+	Y *big.Int `json:"y"`
+}
+
+// Verify confere o certificado, retornando nil se ele provar que c.N eh
+// primo ou um erro que envolve ErrInvalid
+func (c *Certificate) Verify() error {
+	if c.N == nil {
+		return fmt.Errorf("%w: numero ausente", ErrInvalid)
+	}
+	n := c.N
+	for i, s := range c.Steps {
+		if s.N == nil || s.N.Cmp(n) != 0 {
+			return fmt.Errorf("%w: o passo %d nao continua a cadeia", ErrInvalid, i+1)
+		}
+		if err := s.Verify(); err != nil {
+			return fmt.Errorf("passo %d: %w", i+1, err)
+		}
+		n = s.Q
+	}
+	if n.BitLen() > SmallBits {
+		return fmt.Errorf("%w: a cadeia termina em um numero de %d bits", ErrInvalid, n.BitLen())
+	}
+	if !n.ProbablyPrime(0) {
+		return fmt.Errorf("%w: %s nao eh primo", ErrInvalid, n)
+	}
+	return nil
+}
+
+// Verify confere um passo isolado: se ele valer e s.Q for primo, s.N eh
+// primo
+func (s Step) Verify() error {
+	for _, v := range []*big.Int{s.N, s.A, s.B, s.M, s.Q, s.X, s.Y} {
+		if v == nil || v.Sign() < 0 {
+			return fmt.Errorf("%w: campo ausente ou negativo", ErrInvalid)
+		}
+	}
+	n := s.N
+	if new(big.Int).GCD(nil, nil, n, big.NewInt(6)).Cmp(big.NewInt(1)) != 0 || n.Cmp(big.NewInt(5)) < 0 {
+		return fmt.Errorf("%w: N deve ser coprimo com 6", ErrInvalid)
+	}
+	for _, v := range []*big.Int{s.A, s.B, s.X, s.Y} {
+		if v.Cmp(n) >= 0 {
+			return fmt.Errorf("%w: coordenadas e coeficientes devem ser menores que N", ErrInvalid)
+		}
+	}
+	e := curve{n: n, a: s.A, b: s.B}
+	if new(big.Int).GCD(nil, nil, e.discriminant(), n).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("%w: curva singular", ErrInvalid)
+	}
+	p := point{s.X, s.Y}
+	if !e.onCurve(p) {
+		return fmt.Errorf("%w: o ponto nao esta na curva", ErrInvalid)
+	}
+
+	if s.Q.Sign() == 0 {
+		return fmt.Errorf("%w: Q nulo", ErrInvalid)
+	}
+	k, r := new(big.Int).QuoRem(s.M, s.Q, new(big.Int))
+	if r.Sign() != 0 {
+		return fmt.Errorf("%w: Q nao divide M", ErrInvalid)
+	}
+	// N^(1/4) < r + 1, com r = piso(N^(1/4)), entao basta Q > (r + 2)^2
+	bound := new(big.Int).Sqrt(new(big.Int).Sqrt(n))
+	bound.Add(bound, big.NewInt(2))
+	if s.Q.Cmp(bound.Mul(bound, bound)) <= 0 {
+		return fmt.Errorf("%w: Q nao passa de (N^(1/4) + 1)^2", ErrInvalid)
+	}
+
+	u, err := e.mul(k, p)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if u.infinity() {
+		return fmt.Errorf("%w: kP eh o ponto no infinito", ErrInvalid)
+	}
+	v, err := e.mul(s.Q, u)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if !v.infinity() {
+		return fmt.Errorf("%w: MP nao eh o ponto no infinito", ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package certificate
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestHilbertPolynomial(t *testing.T) {
+	cases := []struct {
+		d    int64
+		want []string // coeficientes, do termo constante ao dominante
+	}{
+		{-3, []string{"0", "1"}},
+		{-4, []string{"-1728", "1"}},
+		{-7, []string{"3375", "1"}},
+		{-163, []string{"262537412640768000", "1"}},
+		{-15, []string{"-121287375", "191025", "1"}},
+		{-23, []string{"12771880859375", "-5151296875", "3491750", "1"}},
+	}
+	for _, c := range cases {
+		h, err := HilbertPolynomial(c.d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(h) != len(c.want) {
+			t.Fatalf("H_%d com grau %d, esperado %d", c.d, len(h)-1, len(c.want)-1)
+		}
+		for i, w := range c.want {
+			if h[i].String() != w {
+				t.Errorf("H_%d: coeficiente %d = %s, esperado %s", c.d, i, h[i], w)
+			}
+		}
+	}
+	// O grau eh o numero de classes: h(-71) = 7
+	if h, err := HilbertPolynomial(-71); err != nil || len(h) != 8 {
+		t.Errorf("H_-71 com %d coeficientes (%v)", len(h), err)
+	}
+}
+
+// nextPrime retorna o menor primo provavel >= n
+func nextPrime(n *big.Int) *big.Int {
+	p := new(big.Int).SetBit(n, 0, 1)
+	for !p.ProbablyPrime(20) {
+		p.Add(p, big.NewInt(2))
+	}
+	return p
+}
+
+func TestProve(t *testing.T) {
+	for _, bits := range []int{40, 80, 160, 384} {
+		p := nextPrime(new(big.Int).Lsh(big.NewInt(3), uint(bits-2)))
+		c, err := Prove(p)
+		if err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		if err := c.Verify(); err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		if (bits <= SmallBits) != (len(c.Steps) == 0) {
+			t.Errorf("%d bits: %d passos", bits, len(c.Steps))
+		}
+
+		// O certificado sobrevive a uma ida e volta em JSON
+		data, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Certificate
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Fatal(err)
+		}
+		if err := back.Verify(); err != nil {
+			t.Errorf("%d bits, depois do JSON: %v", bits, err)
+		}
+	}
+
+	composite := new(big.Int).Mul(nextPrime(big.NewInt(1<<40)), nextPrime(big.NewInt(1<<50)))
+	if _, err := Prove(composite); !errors.Is(err, ErrComposite) {
+		t.Errorf("composto: erro %v", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	p := nextPrime(new(big.Int).Lsh(big.NewInt(1), 200))
+	c, err := Prove(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := big.NewInt(1)
+	tamper := []struct {
+		name string
+		edit func(c *Certificate)
+	}{
+		{"outro N", func(c *Certificate) { c.N = new(big.Int).Add(c.N, big.NewInt(2)) }},
+		{"Q nao divide M", func(c *Certificate) { c.Steps[0].M.Add(c.Steps[0].M, one) }},
+		{"outra curva", func(c *Certificate) { c.Steps[0].B.Add(c.Steps[0].B, one) }},
+		{"Q pequeno", func(c *Certificate) { c.Steps[0].Q = big.NewInt(3) }},
+		{"ponto fora da curva", func(c *Certificate) { c.Steps[0].Y.Add(c.Steps[0].Y, one) }},
+		{"cadeia partida", func(c *Certificate) { c.Steps = c.Steps[:len(c.Steps)-1] }},
+		{"N composto", func(c *Certificate) {
+			n := new(big.Int).Mul(c.N, big.NewInt(5))
+			c.N, c.Steps[0].N = n, n
+		}},
+	}
+	for _, tc := range tamper {
+		data, _ := json.Marshal(c)
+		var bad Certificate
+		json.Unmarshal(data, &bad)
+		tc.edit(&bad)
+		if err := bad.Verify(); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: erro %v", tc.name, err)
+		}
+	}
+	if err := c.Verify(); err != nil {
+		t.Errorf("o original deixou de valer: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+// Esse arquivo traz a aritmetica de curvas elipticas modulo N usada para
+//  construir e verificar os certificados. N pode ser composto: toda divisao
+//  por um valor nao inversivel modulo N eh um erro, o que garante que cada
+//  ponto calculado vale modulo todos os primos que dividem N.
+
+package certificate
+
+import (
+	"errors"
+	"math/big"
+)
+
+// errNotInvertible indica que uma divisao por um valor nao inversivel
+// modulo N foi necessaria: N eh composto
+var errNotInvertible = errors.New("certificate: divisao por valor nao inversivel modulo N")
+
+// curve eh a curva y^2 = x^3 + ax + b modulo n
+type curve struct {
+	n, a, b *big.Int
+}
+
+// point eh um ponto afim; x nil representa o ponto no infinito
+type point struct {
+	x, y *big.Int
+}
+
+func (p point) infinity() bool {
+	return p.x == nil
+}
+
+// onCurve informa se p satisfaz a equacao da curva
+func (c curve) onCurve(p point) bool {
+	if p.infinity() {
+		return true
+	}
+	y2 := new(big.Int).Mul(p.y, p.y)
+	return y2.Mod(y2, c.n).Cmp(c.rhs(p.x)) == 0
+}
+
+// rhs calcula x^3 + ax + b mod n
+func (c curve) rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Add(r, c.a).Mul(r, x).Add(r, c.b)
+	return r.Mod(r, c.n)
+}
+
+// discriminant calcula 4a^3 + 27b^2 mod n
+func (c curve) discriminant() *big.Int {
+	a3 := new(big.Int).Exp(c.a, big.NewInt(3), c.n)
+	a3.Mul(a3, big.NewInt(4))
+	b2 := new(big.Int).Mul(c.b, c.b)
+	b2.Mul(b2, big.NewInt(27))
+	return a3.Add(a3, b2).Mod(a3, c.n)
+}
+
+// add retorna p + q. Com n composto, p e q podem coincidir modulo um
+// fator de n e nao modulo outro; esses casos aparecem como divisoes por
+// valores nao inversiveis ou como pontos com o mesmo x e y distintos, e
+// sao recusados.
+func (c curve) add(p, q point) (point, error) {
+	switch {
+	case p.infinity():
+		return q, nil
+	case q.infinity():
+		return p, nil
+	}
+	var num, den *big.Int
+	if p.x.Cmp(q.x) == 0 {
+		sum := new(big.Int).Add(p.y, q.y)
+		if sum.Mod(sum, c.n).Sign() == 0 {
+			return point{}, nil
+		}
+		if p.y.Cmp(q.y) != 0 {
+			return point{}, errNotInvertible
+		}
+		// Duplicacao: lambda = (3x^2 + a) / 2y
+		num = new(big.Int).Mul(p.x, p.x)
+		num.Mul(num, big.NewInt(3)).Add(num, c.a)
+		den = new(big.Int).Lsh(p.y, 1)
+	} else {
+		num = new(big.Int).Sub(q.y, p.y)
+		den = new(big.Int).Sub(q.x, p.x)
+	}
+	// big.Int.ModInverse eh bem mais rapido que numutil.ModInverse, e as
+	// inversoes dominam o tempo das multiplicacoes por escalar
+	inv := new(big.Int).ModInverse(den.Mod(den, c.n), c.n)
+	if inv == nil {
+		return point{}, errNotInvertible
+	}
+	lambda := num.Mul(num, inv)
+	lambda.Mod(lambda, c.n)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, p.x).Sub(x, q.x).Mod(x, c.n)
+	y := new(big.Int).Sub(p.x, x)
+	y.Mul(y, lambda).Sub(y, p.y).Mod(y, c.n)
+	return point{x, y}, nil
+}
+
+// mul retorna k*p pelo metodo de duplicar e somar
+func (c curve) mul(k *big.Int, p point) (point, error) {
+	result := point{}
+	var err error
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		if result, err = c.add(result, result); err != nil {
+			return point{}, err
+		}
+		if k.Bit(i) == 1 {
+			if result, err = c.add(result, p); err != nil {
+				return point{}, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// jacobian eh um ponto em coordenadas jacobianas, (X/Z^2, Y/Z^3) em
+// coordenadas afins; Z = 0 representa o ponto no infinito
+type jacobian struct {
+	x, y, z *big.Int
+}
+
+// fastMul retorna k*p como mul, mas em coordenadas jacobianas, com uma
+// unica inversao no final. As formulas supoem que n eh primo, entao fastMul
+// serve apenas para construir os certificados: a verificacao usa mul, que
+// recusa as divisoes que so falham modulo um fator de n.
+func (c curve) fastMul(k *big.Int, p point) (point, error) {
+	r := jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	if p.infinity() {
+		return point{}, nil
+	}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = c.double(r)
+		if k.Bit(i) == 1 {
+			r = c.addAffine(r, p)
+		}
+	}
+	if r.z.Sign() == 0 {
+		return point{}, nil
+	}
+	zinv := new(big.Int).ModInverse(r.z, c.n)
+	if zinv == nil {
+		return point{}, errNotInvertible
+	}
+	z2 := new(big.Int).Mul(zinv, zinv)
+	z2.Mod(z2, c.n)
+	x := new(big.Int).Mul(r.x, z2)
+	y := z2.Mul(z2, zinv).Mul(z2, r.y)
+	return point{x.Mod(x, c.n), y.Mod(y, c.n)}, nil
+}
+
+// double retorna 2p: com s = 4xy^2 e m = 3x^2 + az^4, x' = m^2 - 2s,
+// y' = m(s - x') - 8y^4 e z' = 2yz
+func (c curve) double(p jacobian) jacobian {
+	if p.z.Sign() == 0 || p.y.Sign() == 0 {
+		return jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	y2 := new(big.Int).Mul(p.y, p.y)
+	y2.Mod(y2, c.n)
+	s := new(big.Int).Mul(p.x, y2)
+	s.Lsh(s, 2).Mod(s, c.n)
+	z2 := new(big.Int).Mul(p.z, p.z)
+	z2.Mod(z2, c.n)
+	m := new(big.Int).Mul(z2, z2)
+	m.Mod(m, c.n).Mul(m, c.a)
+	x2 := new(big.Int).Mul(p.x, p.x)
+	m.Add(m, x2.Mul(x2, big.NewInt(3))).Mod(m, c.n)
+
+	x := new(big.Int).Mul(m, m)
+	x.Sub(x, new(big.Int).Lsh(s, 1)).Mod(x, c.n)
+	y := s.Sub(s, x)
+	y.Mul(y, m)
+	y4 := y2.Mul(y2, y2)
+	y.Sub(y, y4.Lsh(y4, 3)).Mod(y, c.n)
+	z := new(big.Int).Mul(p.y, p.z)
+	z.Lsh(z, 1).Mod(z, c.n)
+	return jacobian{x, y, z}
+}
+
+// addAffine retorna p + q, com q afim e finito: com u = q.x z^2,
+// s = q.y z^3, h = u - x e r = s - y, x' = r^2 - h^3 - 2xh^2,
+// y' = r(xh^2 - x') - yh^3 e z' = zh
+func (c curve) addAffine(p jacobian, q point) jacobian {
+	if p.z.Sign() == 0 {
+		return jacobian{new(big.Int).Set(q.x), new(big.Int).Set(q.y), big.NewInt(1)}
+	}
+	z2 := new(big.Int).Mul(p.z, p.z)
+	z2.Mod(z2, c.n)
+	u := new(big.Int).Mul(q.x, z2)
+	u.Mod(u, c.n)
+	s := z2.Mul(z2, p.z).Mod(z2, c.n)
+	s.Mul(s, q.y).Mod(s, c.n)
+	h := u.Sub(u, p.x).Mod(u, c.n)
+	r := s.Sub(s, p.y).Mod(s, c.n)
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.double(p)
+		}
+		return jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	h2 := new(big.Int).Mul(h, h)
+	h2.Mod(h2, c.n)
+	h3 := new(big.Int).Mul(h2, h)
+	h3.Mod(h3, c.n)
+	xh2 := h2.Mul(h2, p.x).Mod(h2, c.n)
+
+	x := new(big.Int).Mul(r, r)
+	x.Sub(x, h3).Sub(x, new(big.Int).Lsh(xh2, 1)).Mod(x, c.n)
+	y := xh2.Sub(xh2, x).Mul(xh2, r)
+	y.Sub(y, h3.Mul(h3, p.y)).Mod(y, c.n)
+	z := new(big.Int).Mul(p.z, h)
+	return jacobian{x, y, z.Mod(z, c.n)}
+}
@@ -0,0 +1,393 @@
+// Esse arquivo produz certificados pelo metodo de Atkin-Morain (ECPP).
+
+package certificate
+
+import (
+	"PrimeNumGenerator/numutil"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"slices"
+	"sync"
+)
+
+const (
+	// MaxBits eh o maior tamanho, em bits, aceito por Prove: em 2048 bits a
+	// busca ja leva cerca de um minuto
+	MaxBits = 2048
+
+	// maxDiscriminant e maxClassNumber limitam os discriminantes usados;
+	// o grau dos polinomios de classe eh o numero de classes
+	maxDiscriminant = 20000
+	maxClassNumber  = 48
+
+	// smoothBound eh o limite dos primos retirados da ordem M para obter
+	// o cofator Q
+	smoothBound = 1 << 20
+
+	// maxNodes limita os numeros visitados pela busca de Prove
+	maxNodes = 20000
+)
+
+var (
+	// ErrComposite indica que o numero passado a Prove eh composto
+	ErrComposite = errors.New("certificate: numero composto")
+	// ErrNoCertificate indica que Prove desistiu sem encontrar um
+	// certificado para um numero que parece primo
+	ErrNoCertificate = errors.New("certificate: nenhum certificado encontrado")
+)
+
+// Prove produz um certificado de que n eh primo, com n de no maximo
+// MaxBits bits, ou retorna ErrComposite se n for composto.
+//
+// Cada passo segue Atkin e Morain: para um discriminante fundamental
+// d < 0 com 4N = u^2 + |d|v^2 (encontrados pelo algoritmo de Cornacchia),
+// as curvas com multiplicacao complexa por d tem N + 1 ± u pontos (mais as
+// torcoes de d = -3 e d = -4); se uma dessas ordens for M = kQ, com k
+// formado pelos primos abaixo de 2^20 e Q primo provavel acima de
+// (N^(1/4) + 1)^2, a prova passa a ser a de Q. A curva eh construida com uma
+// raiz do polinomio de classe H_d modulo N (veja HilbertPolynomial) so
+// depois que a prova de Q termina: se Q nao levar a um certificado, a
+// busca volta e tenta outra ordem.
+//
+// Os discriminantes tem |d| ate 20000 e numero de classes ate 48, do menor
+// numero de classes para o maior, o que basta para os tamanhos ate
+// MaxBits. Com a mesma entrada, o certificado eh sempre o mesmo.
+func Prove(n *big.Int) (*Certificate, error) {
+	if n.Sign() <= 0 || n.BitLen() > MaxBits {
+		return nil, fmt.Errorf("certificate: apenas numeros positivos de ate %d bits", MaxBits)
+	}
+	if !n.ProbablyPrime(20) {
+		return nil, ErrComposite
+	}
+	p := prover{}
+	steps, err := p.prove(n)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{N: new(big.Int).Set(n), Steps: append([]Step{}, steps...)}, nil
+}
+
+// prover guarda o estado da busca em profundidade de Prove
+type prover struct {
+	nodes int
+}
+
+// prove retorna os passos que provam que o primo provavel n eh primo
+func (p *prover) prove(n *big.Int) ([]Step, error) {
+	if n.BitLen() <= SmallBits {
+		return nil, nil
+	}
+	if p.nodes++; p.nodes > maxNodes {
+		return nil, ErrNoCertificate
+	}
+	var sieve *orderSieve
+	for _, d := range discriminants() {
+		if numutil.Jacobi(big.NewInt(d), n) != 1 {
+			continue
+		}
+		u, v, ok := cornacchia(d, n)
+		if !ok {
+			continue
+		}
+		if sieve == nil {
+			sieve = newOrderSieve(n)
+		}
+		var found []candidate
+		for _, t := range traces(d, u, v) {
+			found = append(found, sieve.candidates(t)...)
+		}
+		for _, c := range found {
+			m, q := c.m, c.q
+			rest, err := p.prove(q)
+			if errors.Is(err, ErrNoCertificate) && p.nodes <= maxNodes {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			step, err := curveStep(d, n, m, q)
+			if errors.Is(err, errNotInvertible) {
+				return nil, ErrComposite
+			}
+			if err != nil {
+				continue
+			}
+			return append([]Step{step}, rest...), nil
+		}
+	}
+	return nil, ErrNoCertificate
+}
+
+// discriminants retorna os discriminantes fundamentais usados por Prove,
+// ordenados pelo numero de classes e depois pelo valor absoluto
+var discriminants = sync.OnceValue(func() []int64 {
+	type entry struct{ d, h int64 }
+	var list []entry
+	for d := int64(-3); d >= -maxDiscriminant; d-- {
+		if !fundamental(d) {
+			continue
+		}
+		if h := int64(len(reducedForms(d))); h <= maxClassNumber {
+			list = append(list, entry{d, h})
+		}
+	}
+	slices.SortStableFunc(list, func(a, b entry) int {
+		return int(a.h - b.h)
+	})
+	ds := make([]int64, len(list))
+	for i, e := range list {
+		ds[i] = e.d
+	}
+	return ds
+})
+
+// cornacchia resolve 4n = u^2 + |d|v^2 para o primo n, com (d/n) = 1, pelo
+// algoritmo de Cornacchia modificado (Cohen, algoritmo 1.5.3)
+func cornacchia(d int64, n *big.Int) (u, v *big.Int, ok bool) {
+	dd := big.NewInt(d)
+	x0, err := numutil.SqrtMod(dd, n)
+	if err != nil {
+		return nil, nil, false
+	}
+	// x0 deve ter a paridade de d
+	if x0.Bit(0) != uint(d&1) {
+		x0.Sub(n, x0)
+	}
+	a, b := new(big.Int).Lsh(n, 1), x0
+	limit := new(big.Int).Sqrt(new(big.Int).Lsh(n, 2))
+	for b.Cmp(limit) > 0 {
+		a, b = b, a.Mod(a, b)
+	}
+	// c = (4n - b^2)/|d| precisa ser um quadrado v^2
+	c := new(big.Int).Lsh(n, 2)
+	c.Sub(c, new(big.Int).Mul(b, b))
+	c, r := c.QuoRem(c, big.NewInt(-d), new(big.Int))
+	if r.Sign() != 0 || c.Sign() < 0 {
+		return nil, nil, false
+	}
+	v = new(big.Int).Sqrt(c)
+	if new(big.Int).Mul(v, v).Cmp(c) != 0 {
+		return nil, nil, false
+	}
+	return b, v, true
+}
+
+// traces retorna os tracos t das curvas com multiplicacao complexa por d
+// modulo n, a menos do sinal: as ordens sao n + 1 ± t
+func traces(d int64, u, v *big.Int) []*big.Int {
+	switch d {
+	case -4:
+		// 4n = u^2 + 4v^2: as torcoes quarticas tem tracos ±u e ±2v
+		return []*big.Int{u, new(big.Int).Lsh(v, 1)}
+	case -3:
+		// As torcoes sexticas tem tracos ±u, ±(u + 3v)/2 e ±(u - 3v)/2
+		v3 := new(big.Int).Mul(v, big.NewInt(3))
+		return []*big.Int{u,
+			new(big.Int).Rsh(new(big.Int).Add(u, v3), 1),
+			new(big.Int).Quo(new(big.Int).Sub(u, v3), big.NewInt(2))}
+	}
+	return []*big.Int{u}
+}
+
+// primeGroup eh um grupo de primos abaixo de smoothBound com produto menor
+// que 2^63
+type primeGroup struct {
+	primes  []uint64
+	product uint64
+}
+
+// smallPrimes agrupa os primos abaixo de smoothBound: o resto de um numero
+// pelo produto de cada grupo eh calculado uma vez, e os primos do grupo sao
+// testados nesse resto
+var smallPrimes = sync.OnceValue(func() []primeGroup {
+	var groups []primeGroup
+	g := primeGroup{product: 1}
+	for _, p := range numutil.PrimesUpTo(smoothBound) {
+		if g.product > (1<<63)/uint64(p) {
+			groups = append(groups, g)
+			g = primeGroup{product: 1}
+		}
+		g.primes = append(g.primes, uint64(p))
+		g.product *= uint64(p)
+	}
+	return append(groups, g)
+})
+
+// residues retorna os restos de x >= 0 pelos produtos dos grupos de
+// smallPrimes, palavra a palavra, sem alocar um big.Int por grupo
+func residues(x *big.Int) []uint64 {
+	groups := smallPrimes()
+	rems := make([]uint64, len(groups))
+	words := x.Bits()
+	for i, g := range groups {
+		rem := uint64(0)
+		for j := len(words) - 1; j >= 0; j-- {
+			if bits.UintSize == 64 {
+				rem = bits.Rem64(rem, uint64(words[j]), g.product)
+			} else {
+				rem = bits.Rem64(rem>>32, rem<<32|uint64(words[j]), g.product)
+			}
+		}
+		rems[i] = rem
+	}
+	return rems
+}
+
+// candidate eh uma ordem m = kQ com Q primo provavel que serve a um passo
+type candidate struct {
+	m, q *big.Int
+}
+
+// orderSieve procura candidatos entre as ordens n + 1 ± t de um mesmo n.
+// Os restos de n + 1 pelos grupos de primos sao calculados uma vez; os de
+// cada ordem saem dos restos de t, que tem metade dos bits.
+type orderSieve struct {
+	n, n1, bound *big.Int
+	rems         []uint64
+}
+
+func newOrderSieve(n *big.Int) *orderSieve {
+	n1 := new(big.Int).Add(n, big.NewInt(1))
+	// (n^(1/4) + 1)^2 < (piso(n^(1/4)) + 2)^2
+	bound := new(big.Int).Sqrt(new(big.Int).Sqrt(n))
+	bound.Add(bound, big.NewInt(2))
+	return &orderSieve{n: n, n1: n1, bound: bound.Mul(bound, bound), rems: residues(n1)}
+}
+
+// candidates retira das ordens n + 1 - t e n + 1 + t os fatores abaixo de
+// smoothBound e retorna as que deixam um cofator Q primo provavel, menor
+// que n e maior que (n^(1/4) + 1)^2
+func (s *orderSieve) candidates(t *big.Int) []candidate {
+	trems := residues(new(big.Int).Abs(t))
+	if t.Sign() < 0 {
+		t = new(big.Int).Neg(t)
+		for i, g := range smallPrimes() {
+			trems[i] = (g.product - trems[i]) % g.product
+		}
+	}
+	var found []candidate
+	for _, sign := range []int{-1, 1} {
+		m := new(big.Int).Add(s.n1, t)
+		if sign < 0 {
+			m.Sub(s.n1, t)
+		}
+		q := new(big.Int).Set(m)
+		r := new(big.Int)
+		for i, g := range smallPrimes() {
+			// Resto de m pelo produto do grupo; as somas cabem em 64 bits
+			rem := (s.rems[i] + trems[i]) % g.product
+			if sign < 0 {
+				rem = (s.rems[i] + g.product - trems[i]) % g.product
+			}
+			for _, p := range g.primes {
+				if rem%p != 0 {
+					continue
+				}
+				bp := new(big.Int).SetUint64(p)
+				for r.Mod(q, bp).Sign() == 0 {
+					q.Quo(q, bp)
+				}
+			}
+		}
+		if q.Cmp(s.bound) > 0 && q.Cmp(s.n) < 0 && q.ProbablyPrime(20) {
+			found = append(found, candidate{m, q})
+		}
+	}
+	return found
+}
+
+// curveStep constroi o passo de n: uma curva com m pontos e um ponto P
+// com (m/q)P != O e mP = O
+func curveStep(d int64, n, m, q *big.Int) (Step, error) {
+	var j *big.Int
+	switch d {
+	case -3:
+		j = big.NewInt(0)
+	case -4:
+		j = big.NewInt(1728)
+	default:
+		h, err := HilbertPolynomial(d)
+		if err != nil {
+			return Step{}, err
+		}
+		if j, err = findRoot(reduce(h, n), n); err != nil {
+			return Step{}, err
+		}
+	}
+
+	k := new(big.Int).Quo(m, q)
+	for c := int64(1); c <= 64; c++ {
+		e, ok := twist(j, c, n)
+		if !ok {
+			continue
+		}
+		for x, tries := int64(0), 0; tries < 8; x++ {
+			pt, ok := e.pointAt(big.NewInt(x))
+			if !ok {
+				continue
+			}
+			tries++
+			u, err := e.fastMul(k, pt)
+			if err != nil {
+				return Step{}, err
+			}
+			if u.infinity() {
+				continue
+			}
+			v, err := e.fastMul(q, u)
+			if err != nil {
+				return Step{}, err
+			}
+			if !v.infinity() {
+				// A ordem nao eh m: outra torcao
+				break
+			}
+			return Step{N: new(big.Int).Set(n), D: d, A: e.a, B: e.b, M: m, Q: q, X: pt.x, Y: pt.y}, nil
+		}
+	}
+	return Step{}, errors.New("certificate: nenhuma curva com a ordem esperada")
+}
+
+// twist retorna a curva de invariante j modulo n associada a c: para
+// j = 0, y^2 = x^3 + c; para j = 1728, y^2 = x^3 + cx; nos demais casos,
+// y^2 = x^3 + 3kc^2 x + 2kc^3 com k = j/(1728 - j). Variar c percorre as
+// torcoes da curva.
+func twist(j *big.Int, c int64, n *big.Int) (curve, bool) {
+	cc := big.NewInt(c)
+	var e curve
+	switch {
+	case j.Sign() == 0:
+		e = curve{n: n, a: new(big.Int), b: cc}
+	case j.Cmp(big.NewInt(1728)) == 0:
+		e = curve{n: n, a: cc, b: new(big.Int)}
+	default:
+		den := new(big.Int).Sub(big.NewInt(1728), j)
+		inv, err := numutil.ModInverse(den, n)
+		if err != nil {
+			return curve{}, false
+		}
+		k := inv.Mul(inv, j)
+		a := new(big.Int).Mul(cc, cc)
+		a.Mul(a, k).Mul(a, big.NewInt(3)).Mod(a, n)
+		b := new(big.Int).Exp(cc, big.NewInt(3), nil)
+		b.Mul(b, k).Lsh(b, 1).Mod(b, n)
+		e = curve{n: n, a: a, b: b}
+	}
+	return e, e.discriminant().Sign() != 0
+}
+
+// pointAt retorna um ponto da curva com abscissa x, se houver um que nao
+// seja de ordem 2
+func (c curve) pointAt(x *big.Int) (point, bool) {
+	r := c.rhs(x)
+	if numutil.Jacobi(r, c.n) != 1 {
+		return point{}, false
+	}
+	y, err := numutil.SqrtMod(r, c.n)
+	if err != nil {
+		return point{}, false
+	}
+	return point{new(big.Int).Set(x), y}, true
+}
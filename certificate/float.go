@@ -0,0 +1,135 @@
+// Esse arquivo traz a aritmetica complexa de alta precisao usada no calculo
+//  dos polinomios de classe de Hilbert.
+
+package certificate
+
+import "math/big"
+
+// complexFloat eh um numero complexo com partes em big.Float
+type complexFloat struct {
+	re, im *big.Float
+}
+
+func newComplex(prec uint) complexFloat {
+	return complexFloat{new(big.Float).SetPrec(prec), new(big.Float).SetPrec(prec)}
+}
+
+func (z complexFloat) set(w complexFloat) complexFloat {
+	z.re.Set(w.re)
+	z.im.Set(w.im)
+	return z
+}
+
+func (z complexFloat) add(x, y complexFloat) complexFloat {
+	z.re.Add(x.re, y.re)
+	z.im.Add(x.im, y.im)
+	return z
+}
+
+func (z complexFloat) sub(x, y complexFloat) complexFloat {
+	z.re.Sub(x.re, y.re)
+	z.im.Sub(x.im, y.im)
+	return z
+}
+
+// mul calcula z = x*y; z pode ser x ou y
+func (z complexFloat) mul(x, y complexFloat) complexFloat {
+	prec := z.re.Prec()
+	ac := new(big.Float).SetPrec(prec).Mul(x.re, y.re)
+	bd := new(big.Float).SetPrec(prec).Mul(x.im, y.im)
+	ad := new(big.Float).SetPrec(prec).Mul(x.re, y.im)
+	bc := new(big.Float).SetPrec(prec).Mul(x.im, y.re)
+	z.re.Sub(ac, bd)
+	z.im.Add(ad, bc)
+	return z
+}
+
+// quo calcula z = x/y; z pode ser x ou y
+func (z complexFloat) quo(x, y complexFloat) complexFloat {
+	prec := z.re.Prec()
+	norm := new(big.Float).SetPrec(prec).Mul(y.re, y.re)
+	norm.Add(norm, new(big.Float).SetPrec(prec).Mul(y.im, y.im))
+	conj := newComplex(prec)
+	conj.re.Set(y.re)
+	conj.im.Neg(y.im)
+	z.mul(x, conj)
+	z.re.Quo(z.re, norm)
+	z.im.Quo(z.im, norm)
+	return z
+}
+
+// scale calcula z = x*f para um real f
+func (z complexFloat) scale(x complexFloat, f *big.Float) complexFloat {
+	z.re.Mul(x.re, f)
+	z.im.Mul(x.im, f)
+	return z
+}
+
+// negligible informa se |x| < 2^-prec
+func negligible(x *big.Float, prec uint) bool {
+	return x.Sign() == 0 || x.MantExp(nil) < -int(prec)
+}
+
+// piFloat calcula pi com prec bits pela formula de Machin:
+// pi = 16 arctan(1/5) - 4 arctan(1/239)
+func piFloat(prec uint) *big.Float {
+	w := prec + 32
+	a := arctanInv(5, w)
+	a.Mul(a, big.NewFloat(16))
+	b := arctanInv(239, w)
+	b.Mul(b, big.NewFloat(4))
+	return a.Sub(a, b).SetPrec(prec)
+}
+
+// arctanInv calcula arctan(1/x) = sum (-1)^k / ((2k+1) x^(2k+1))
+func arctanInv(x int64, prec uint) *big.Float {
+	x2 := new(big.Float).SetPrec(prec).SetInt64(x * x)
+	power := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), new(big.Float).SetInt64(x))
+	sum := new(big.Float).SetPrec(prec).Set(power)
+	term := new(big.Float).SetPrec(prec)
+	for k := int64(1); ; k++ {
+		power.Quo(power, x2)
+		term.Quo(power, new(big.Float).SetInt64(2*k+1))
+		if negligible(term, prec) {
+			return sum
+		}
+		if k%2 == 1 {
+			sum.Sub(sum, term)
+		} else {
+			sum.Add(sum, term)
+		}
+	}
+}
+
+// cexp calcula e^z com prec bits: a serie de Taylor eh somada para
+// z/2^s, pequeno, e o resultado eh elevado ao quadrado s vezes
+func cexp(z complexFloat, prec uint) complexFloat {
+	s := 8
+	for _, part := range []*big.Float{z.re, z.im} {
+		if part.Sign() != 0 {
+			s = max(s, part.MantExp(nil)+8)
+		}
+	}
+	w := prec + uint(s) + 32
+	small := newComplex(w)
+	small.re.SetMantExp(z.re, -s)
+	small.im.SetMantExp(z.im, -s)
+
+	sum := newComplex(w)
+	sum.re.SetInt64(1)
+	term := newComplex(w).set(sum)
+	for n := int64(1); ; n++ {
+		term.mul(term, small)
+		f := new(big.Float).SetPrec(w).SetInt64(n)
+		term.re.Quo(term.re, f)
+		term.im.Quo(term.im, f)
+		if negligible(term.re, w) && negligible(term.im, w) {
+			break
+		}
+		sum.add(sum, term)
+	}
+	for range s {
+		sum.mul(sum, sum)
+	}
+	return sum
+}
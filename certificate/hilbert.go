@@ -0,0 +1,240 @@
+// Esse arquivo calcula os polinomios de classe de Hilbert, cujas raizes
+//  modulo N sao os invariantes j das curvas com multiplicacao complexa
+//  usadas pelo ECPP.
+
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+)
+
+// form eh a forma quadratica binaria ax^2 + bxy + cy^2
+type form struct {
+	a, b, c int64
+}
+
+// reducedForms retorna as formas reduzidas primitivas de discriminante d
+// (d < 0, d ≡ 0 ou 1 mod 4): |b| <= a <= c, com b >= 0 se |b| = a ou a = c.
+// O numero delas eh o numero de classes h(d).
+func reducedForms(d int64) []form {
+	var forms []form
+	for a := int64(1); 3*a*a <= -d; a++ {
+		for b := -a + 1; b <= a; b++ {
+			if (b*b-d)%(4*a) != 0 {
+				continue
+			}
+			c := (b*b - d) / (4 * a)
+			if c < a || (b < 0 && a == c) || gcd(gcd(a, abs(b)), c) != 1 {
+				continue
+			}
+			forms = append(forms, form{a, b, c})
+		}
+	}
+	return forms
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// fundamental informa se d < 0 eh um discriminante fundamental: d ≡ 1
+// (mod 4) livre de quadrados, ou d = 4m com m ≡ 2 ou 3 (mod 4) livre de
+// quadrados
+func fundamental(d int64) bool {
+	switch m := -d; {
+	case m%4 == 3:
+		return squarefree(m)
+	case m%4 == 0 && (m/4)%4 != 3:
+		// -m/4 ≡ 2 ou 3 (mod 4)
+		return squarefree(m / 4)
+	}
+	return false
+}
+
+func squarefree(m int64) bool {
+	for p := int64(2); p*p <= m; p++ {
+		if m%(p*p) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	hilbertMu    sync.Mutex
+	hilbertCache = make(map[int64][]*big.Int)
+)
+
+// HilbertPolynomial retorna os coeficientes, do termo constante ao
+// dominante, do polinomio de classe de Hilbert H_d(x) = prod (x - j(tau)),
+// com tau = (-b + sqrt(d))/2a para cada forma reduzida (a, b, c) de
+// discriminante d < 0. H_d tem coeficientes inteiros e grau h(d); modulo um
+// primo N com 4N = u^2 + |d|v^2, ele se decompoe em fatores lineares, e
+// suas raizes sao os invariantes j das curvas com N + 1 ± u pontos.
+//
+// Os valores j(tau) sao calculados com numeros complexos de precisao
+// suficiente para que o arredondamento dos coeficientes seja exato, o que
+// eh conferido; os polinomios ficam em cache.
+func HilbertPolynomial(d int64) ([]*big.Int, error) {
+	if d >= 0 || (d%4 != 0 && d%4 != -3) {
+		return nil, fmt.Errorf("certificate: discriminante invalido: %d", d)
+	}
+	hilbertMu.Lock()
+	defer hilbertMu.Unlock()
+	if h, ok := hilbertCache[d]; ok {
+		return h, nil
+	}
+
+	// |j(tau)| ~ e^(pi sqrt|d|/a), entao os coeficientes tem no maximo
+	// cerca de sum (pi sqrt|d|/a)/ln 2 bits
+	forms := reducedForms(d)
+	estimate := 64.0
+	for _, f := range forms {
+		estimate += math.Pi*math.Sqrt(float64(-d))/float64(f.a)/math.Ln2 + 16
+	}
+	for prec := uint(estimate); prec < 1<<16; prec *= 2 {
+		if h, ok := hilbertAt(d, forms, prec); ok {
+			hilbertCache[d] = h
+			return h, nil
+		}
+	}
+	return nil, errors.New("certificate: precisao insuficiente para o polinomio de classe")
+}
+
+// hilbertAt calcula H_d com prec bits de precisao e informa se os
+// coeficientes ficaram proximos o bastante de inteiros
+func hilbertAt(d int64, forms []form, prec uint) ([]*big.Int, bool) {
+	pi := piFloat(prec)
+	sqrtD := new(big.Float).SetPrec(prec).SetInt64(-d)
+	sqrtD.Sqrt(sqrtD)
+
+	// poly comeca em 1 e eh multiplicado por (x - j) para cada forma
+	poly := []complexFloat{newComplex(prec)}
+	poly[0].re.SetInt64(1)
+	for _, f := range forms {
+		j := jInvariant(f, pi, sqrtD, prec)
+		next := make([]complexFloat, len(poly)+1)
+		for i := range next {
+			next[i] = newComplex(prec)
+		}
+		for i, c := range poly {
+			next[i+1].add(next[i+1], c)
+			next[i].sub(next[i], newComplex(prec).mul(c, j))
+		}
+		poly = next
+	}
+
+	tolerance := new(big.Float).SetMantExp(big.NewFloat(1), -32)
+	coeffs := make([]*big.Int, len(poly))
+	for i, c := range poly {
+		r := round(c.re)
+		diff := new(big.Float).SetPrec(prec).SetInt(r)
+		diff.Sub(diff, c.re).Abs(diff)
+		if diff.Cmp(tolerance) > 0 || new(big.Float).Abs(c.im).Cmp(tolerance) > 0 {
+			return nil, false
+		}
+		coeffs[i] = r
+	}
+	return coeffs, true
+}
+
+// round arredonda x para o inteiro mais proximo
+func round(x *big.Float) *big.Int {
+	y := new(big.Float).SetPrec(x.Prec()+1).Add(x, big.NewFloat(0.5))
+	r, _ := y.Int(nil)
+	// Int trunca em direcao a zero; queremos o piso
+	if y.Sign() < 0 && !y.IsInt() {
+		r.Sub(r, big.NewInt(1))
+	}
+	return r
+}
+
+// jInvariant calcula j(tau) para tau = (-b + sqrt(d))/2a, com
+// q = e^(2 pi i tau), j = E4^3/Delta, E4 = 1 + 240 sum sigma3(n) q^n e
+// Delta = q prod (1 - q^n)^24, o produto calculado pela serie pentagonal
+// de Euler: prod (1 - q^n) = sum (-1)^k q^(k(3k-1)/2), com k inteiro
+func jInvariant(f form, pi, sqrtD *big.Float, prec uint) complexFloat {
+	w := prec + 32
+	// 2 pi i tau = -pi sqrt|d|/a - i pi b/a
+	z := newComplex(w)
+	z.re.Mul(pi, sqrtD)
+	z.re.Quo(z.re, new(big.Float).SetInt64(f.a)).Neg(z.re)
+	z.im.Mul(pi, new(big.Float).SetInt64(f.b))
+	z.im.Quo(z.im, new(big.Float).SetInt64(f.a)).Neg(z.im)
+	q := cexp(z, w)
+
+	// |q|^n < 2^-w a partir de n = terms
+	decay, _ := z.re.Float64()
+	terms := int(float64(w)*math.Ln2/-decay) + 2
+	powers := make([]complexFloat, terms+1)
+	powers[0] = newComplex(w)
+	powers[0].re.SetInt64(1)
+	for n := 1; n <= terms; n++ {
+		powers[n] = newComplex(w).mul(powers[n-1], q)
+	}
+
+	e4 := newComplex(w).set(powers[0])
+	for n := 1; n <= terms; n++ {
+		coef := new(big.Float).SetPrec(w).SetInt64(240 * sigma3(int64(n)))
+		e4.add(e4, newComplex(w).scale(powers[n], coef))
+	}
+
+	eta := newComplex(w).set(powers[0])
+	for k := 1; ; k++ {
+		e1, e2 := k*(3*k-1)/2, k*(3*k+1)/2
+		if e1 > terms {
+			break
+		}
+		for _, e := range []int{e1, e2} {
+			if e > terms {
+				continue
+			}
+			if k%2 == 1 {
+				eta.sub(eta, powers[e])
+			} else {
+				eta.add(eta, powers[e])
+			}
+		}
+	}
+	// Delta = q eta^24
+	delta := newComplex(w).set(eta)
+	for _, sq := range []bool{false, true, true, true} {
+		// eta^3, depois ao quadrado tres vezes: eta^24
+		if !sq {
+			delta.mul(delta, eta)
+			delta.mul(delta, eta)
+			continue
+		}
+		delta.mul(delta, delta)
+	}
+	delta.mul(delta, q)
+
+	j := newComplex(w).mul(e4, e4)
+	j.mul(j, e4)
+	return j.quo(j, delta)
+}
+
+// sigma3 retorna a soma dos cubos dos divisores de n
+func sigma3(n int64) int64 {
+	s := int64(0)
+	for d := int64(1); d <= n; d++ {
+		if n%d == 0 {
+			s += d * d * d
+		}
+	}
+	return s
+}
@@ -0,0 +1,179 @@
+// Esse arquivo encontra raizes de polinomios modulo um primo N, para obter
+//  os invariantes j a partir dos polinomios de classe.
+
+package certificate
+
+import (
+	"PrimeNumGenerator/numutil"
+	"errors"
+	"math/big"
+)
+
+// poly eh um polinomio modulo N, com os coeficientes do termo constante
+// ao dominante e sem zeros a esquerda (o polinomio nulo eh vazio)
+type poly []*big.Int
+
+func (p poly) degree() int {
+	return len(p) - 1
+}
+
+// trim remove os coeficientes dominantes nulos
+func (p poly) trim() poly {
+	for len(p) > 0 && p[len(p)-1].Sign() == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// reduce retorna os coeficientes de c modulo n
+func reduce(c []*big.Int, n *big.Int) poly {
+	p := make(poly, len(c))
+	for i, x := range c {
+		p[i] = new(big.Int).Mod(x, n)
+	}
+	return p.trim()
+}
+
+// polyMod retorna a mod b, para b nao nulo
+func polyMod(a, b poly, n *big.Int) (poly, error) {
+	inv, err := numutil.ModInverse(b[len(b)-1], n)
+	if err != nil {
+		return nil, err
+	}
+	r := make(poly, len(a))
+	for i, x := range a {
+		r[i] = new(big.Int).Set(x)
+	}
+	t := new(big.Int)
+	for r = r.trim(); len(r) >= len(b); r = r.trim() {
+		// Elimina o termo dominante de r com um multiplo de b
+		f := new(big.Int).Mul(r[len(r)-1], inv)
+		f.Mod(f, n)
+		shift := len(r) - len(b)
+		for i, x := range b {
+			t.Mul(f, x)
+			r[shift+i].Sub(r[shift+i], t).Mod(r[shift+i], n)
+		}
+	}
+	return r, nil
+}
+
+// polyMulMod retorna a*b mod f
+func polyMulMod(a, b, f poly, n *big.Int) (poly, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, nil
+	}
+	prod := make(poly, len(a)+len(b)-1)
+	for i := range prod {
+		prod[i] = new(big.Int)
+	}
+	t := new(big.Int)
+	for i, x := range a {
+		for j, y := range b {
+			prod[i+j].Add(prod[i+j], t.Mul(x, y))
+		}
+	}
+	for _, c := range prod {
+		c.Mod(c, n)
+	}
+	return polyMod(prod, f, n)
+}
+
+// polyGCD retorna o mdc monico de a e b
+func polyGCD(a, b poly, n *big.Int) (poly, error) {
+	for len(b) > 0 {
+		r, err := polyMod(a, b, n)
+		if err != nil {
+			return nil, err
+		}
+		a, b = b, r
+	}
+	if len(a) == 0 {
+		return a, nil
+	}
+	inv, err := numutil.ModInverse(a[len(a)-1], n)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range a {
+		c.Mul(c, inv).Mod(c, n)
+	}
+	return a, nil
+}
+
+// findRoot retorna uma raiz modulo o primo n do polinomio f, que deve se
+// decompor em fatores lineares distintos, como os polinomios de classe
+// quando 4n = u^2 + |d|v^2. A cada passo, mdc((x + a)^((n-1)/2) - 1, f)
+// separa as raizes r com r + a residuo quadratico das demais, e o fator
+// menor eh mantido, ate restar um fator linear.
+func findRoot(f poly, n *big.Int) (*big.Int, error) {
+	half := new(big.Int).Rsh(n, 1)
+	for a := int64(0); f.degree() > 1; a++ {
+		if a == 256 {
+			return nil, errors.New("certificate: o polinomio de classe nao se decompoe")
+		}
+		// (x + a)^((n-1)/2) mod f, por quadrados sucessivos
+		base := poly{big.NewInt(a), big.NewInt(1)}
+		power := poly{big.NewInt(1)}
+		var err error
+		for i := half.BitLen() - 1; i >= 0; i-- {
+			if power, err = polyMulMod(power, power, f, n); err != nil {
+				return nil, err
+			}
+			if half.Bit(i) == 1 {
+				if power, err = polyMulMod(power, base, f, n); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if len(power) == 0 {
+			continue
+		}
+		power[0].Sub(power[0], big.NewInt(1)).Mod(power[0], n)
+		g, err := polyGCD(f, power.trim(), n)
+		if err != nil {
+			return nil, err
+		}
+		if g.degree() < 1 || g.degree() == f.degree() {
+			continue
+		}
+		if rest, err := polyQuo(f, g, n); err == nil && rest.degree() < g.degree() {
+			g = rest
+		}
+		f = g
+	}
+	if f.degree() != 1 {
+		return nil, errors.New("certificate: o polinomio de classe nao tem raizes")
+	}
+	// f = f1 x + f0: a raiz eh -f0/f1
+	inv, err := numutil.ModInverse(f[1], n)
+	if err != nil {
+		return nil, err
+	}
+	root := new(big.Int).Neg(f[0])
+	root.Mul(root, inv)
+	return root.Mod(root, n), nil
+}
+
+// polyQuo retorna o quociente exato de a por b
+func polyQuo(a, b poly, n *big.Int) (poly, error) {
+	inv, err := numutil.ModInverse(b[len(b)-1], n)
+	if err != nil {
+		return nil, err
+	}
+	r := make(poly, len(a))
+	for i, x := range a {
+		r[i] = new(big.Int).Set(x)
+	}
+	q := make(poly, len(a)-len(b)+1)
+	t := new(big.Int)
+	for shift := len(q) - 1; shift >= 0; shift-- {
+		f := new(big.Int).Mul(r[shift+len(b)-1], inv)
+		q[shift] = f.Mod(f, n)
+		for i, x := range b {
+			t.Mul(f, x)
+			r[shift+i].Sub(r[shift+i], t).Mod(r[shift+i], n)
+		}
+	}
+	return q.trim(), nil
+}
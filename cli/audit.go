@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"PrimeNumGenerator/audit"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// ErrAuditFailed indica que o subcomando audit encontrou algum problema
+var ErrAuditFailed = errors.New("auditoria encontrou problemas")
+
+// auditEntry eh um valor lido pelo subcomando audit e sua origem
+type auditEntry struct {
+	value *big.Int
+	where string
+}
+
+// Audit implementa o subcomando audit, que le primos ou modulos (um por
+// linha, como em parseNumber) dos arquivos informados
+// ou da entrada padrao e procura fatores compartilhados entre eles com o
+// mdc em lote, alem dos padroes fracos de audit.AuditModulus
+func Audit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	patterns := fs.Bool("patterns", true, "verifica tambem os padroes fracos de cada valor (ROCA, peso de Hamming, Fermat)")
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	var entries []auditEntry
+	read := func(r io.Reader, name string) error {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			n, err := parseNumber(text)
+			if err != nil || n.Sign() <= 0 {
+				return fmt.Errorf("%s:%d: valor invalido %q", name, line, text)
+			}
+			entries = append(entries, auditEntry{n, fmt.Sprintf("%s:%d", name, line)})
+		}
+		return scanner.Err()
+	}
+
+	if fs.NArg() == 0 {
+		if err := read(os.Stdin, "stdin"); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = read(f, name)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	values := make([]*big.Int, len(entries))
+	for i, e := range entries {
+		values[i] = e.value
+	}
+
+	fmt.Printf("Auditando %d valores\n", len(values))
+	problems := 0
+	var shared []int
+	for i, g := range audit.BatchGCD(values) {
+		if g.Cmp(big.NewInt(1)) != 0 {
+			shared = append(shared, i)
+		}
+	}
+	// Apenas os valores sinalizados sao comparados dois a dois, para dizer
+	// com quem cada um compartilha fatores
+	for a := 0; a < len(shared); a++ {
+		for b := a + 1; b < len(shared); b++ {
+			i, j := shared[a], shared[b]
+			g := new(big.Int).GCD(nil, nil, values[i], values[j])
+			if g.Cmp(big.NewInt(1)) == 0 {
+				continue
+			}
+			problems++
+			if values[i].Cmp(values[j]) == 0 {
+				fmt.Printf("- %s e %s: valores repetidos\n", entries[i].where, entries[j].where)
+			} else {
+				fmt.Printf("- %s e %s: fator compartilhado %s\n", entries[i].where, entries[j].where, g)
+			}
+		}
+	}
+
+	if *patterns {
+		for _, e := range entries {
+			for _, f := range audit.AuditModulus(e.value) {
+				problems++
+				fmt.Printf("- %s: %s\n", e.where, f)
+			}
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("%d problema(s) encontrado(s)\n", problems)
+		return ErrAuditFailed
+	}
+	fmt.Println("Nenhum problema encontrado")
+	return nil
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"PrimeNumGenerator/audit"
+	"PrimeNumGenerator/group"
+	"PrimeNumGenerator/pta"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+)
+
+// AuditFile implementa o subcomando audit-file, que le parametros de
+// Diffie-Hellman e chaves RSA gerados por outras ferramentas (arquivos PEM
+// do openssl dhparam, genrsa, genpkey etc.) e verifica os primos e modulos
+// que eles contem: o Miller-Rabin em cada primo, a exigencia de primo
+// seguro (ou a ordem do subgrupo, no X9.42), a suavidade de p - 1 e p + 1 e
+// os padroes fracos de audit.Audit e audit.AuditModulus
+func AuditFile(args []string) error {
+	fs := flag.NewFlagSet("audit-file", flag.ExitOnError)
+	bound := fs.Int("smoothness-bound", 1<<16, "limite da verificacao de suavidade de p-1 e p+1 (0 desativa)")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	if *bound < 0 {
+		return Usagef("-smoothness-bound nao pode ser negativo")
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	mr, err := pta.Get("miller-rabin")
+	if err != nil {
+		return err
+	}
+	a := fileAuditor{test: mr, cfg: TestConfig(e), bound: *bound}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	for _, name := range names {
+		var data []byte
+		if name == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return err
+		}
+		all, err := audit.ParsePEM(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for i, params := range all {
+			where := fmt.Sprintf("%s, bloco %d (%s)", name, i+1, params.Type)
+			if err := a.audit(where, params); err != nil {
+				return err
+			}
+		}
+	}
+
+	if a.problems > 0 {
+		fmt.Printf("%d problema(s) encontrado(s)\n", a.problems)
+		return ErrAuditFailed
+	}
+	fmt.Println("Nenhum problema encontrado")
+	return nil
+}
+
+// fileAuditor acumula os problemas encontrados por AuditFile
+type fileAuditor struct {
+	test     pta.PrimalityTest
+	cfg      pta.Config
+	bound    int
+	problems int
+}
+
+// report registra e exibe um problema
+func (a *fileAuditor) report(format string, args ...any) {
+	a.problems++
+	fmt.Printf("  - %s\n", fmt.Sprintf(format, args...))
+}
+
+// audit verifica os numeros de um bloco
+func (a *fileAuditor) audit(where string, params audit.Params) error {
+	one := big.NewInt(1)
+	if params.DH() {
+		p, g, q := params.P, params.G, params.Q
+		if g.BitLen() <= 64 {
+			fmt.Printf("%s: p de %d bits, g = %s\n", where, p.BitLen(), g)
+		} else {
+			fmt.Printf("%s: p de %d bits, g de %d bits\n", where, p.BitLen(), g.BitLen())
+		}
+		prime, err := a.prime("p", p)
+		if err != nil {
+			return err
+		}
+		pMinus := new(big.Int).Sub(p, one)
+		if g.Cmp(one) <= 0 || g.Cmp(pMinus) >= 0 {
+			a.report("g fora do intervalo 1 < g < p - 1")
+		}
+		if q == nil {
+			if prime && !group.IsSafePrime(p) {
+				a.report("p não é um primo seguro: (p - 1)/2 é composto")
+			}
+			return nil
+		}
+		fmt.Printf("  q de %d bits\n", q.BitLen())
+		if _, err := a.prime("q", q); err != nil {
+			return err
+		}
+		if new(big.Int).Mod(pMinus, q).Sign() != 0 {
+			a.report("q não divide p - 1")
+		} else if new(big.Int).Exp(g, q, p).Cmp(one) != 0 {
+			a.report("g não gera o subgrupo de ordem q")
+		}
+		return nil
+	}
+
+	n := params.N
+	fmt.Printf("%s: módulo de %d bits\n", where, n.BitLen())
+	for _, f := range audit.AuditModulus(n) {
+		a.report("n: %s", f)
+	}
+	if len(params.Primes) == 0 {
+		return nil
+	}
+	product := big.NewInt(1)
+	for i, p := range params.Primes {
+		if _, err := a.prime(primeName(i), p); err != nil {
+			return err
+		}
+		product.Mul(product, p)
+	}
+	if product.Cmp(n) != 0 {
+		a.report("o produto dos fatores da chave não é o módulo")
+	}
+	return nil
+}
+
+// prime verifica um numero que deveria ser primo, informando se ele passou
+// pelo Miller-Rabin
+func (a *fileAuditor) prime(name string, p *big.Int) (bool, error) {
+	res := a.test.IsPrime(p, a.cfg)
+	if res.Err != nil {
+		return false, res.Err
+	}
+	if !res.Prime {
+		if res.Witness != nil {
+			a.report("%s é composto (testemunha %s)", name, res.Witness)
+		} else {
+			a.report("%s é composto", name)
+		}
+		return false, nil
+	}
+	for _, f := range audit.Audit(p) {
+		a.report("%s: %s", name, f)
+	}
+	if a.bound > 0 {
+		s := audit.SmoothnessReport(p, a.bound)
+		if s.PollardVulnerable() {
+			a.report("%s - 1 é suave em relação a %d (Pollard p - 1)", name, a.bound)
+		}
+		if s.WilliamsVulnerable() {
+			a.report("%s + 1 é suave em relação a %d (Williams p + 1)", name, a.bound)
+		}
+	}
+	return true, nil
+}
+
+// primeName da nome ao i-esimo fator de uma chave RSA: p, q e, nas chaves
+// com mais de dois fatores, r3, r4...
+func primeName(i int) string {
+	switch i {
+	case 0:
+		return "p"
+	case 1:
+		return "q"
+	}
+	return fmt.Sprintf("r%d", i+1)
+}
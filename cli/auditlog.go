@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"PrimeNumGenerator/auditlog"
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// AuditLog implementa o subcomando auditlog, com as acoes keygen (gera a
+// chave que assina o log de auditoria do servidor) e verify (confere a
+// cadeia de hashes e as assinaturas de um log)
+func AuditLog(args []string) error {
+	if len(args) == 0 {
+		return Usagef("use: auditlog keygen -out arquivo | auditlog verify [-pub chave] log")
+	}
+	switch args[0] {
+	case "keygen":
+		fs := flag.NewFlagSet("auditlog keygen", flag.ExitOnError)
+		out := fs.String("out", "", "arquivo em que a chave privada sera gravada")
+		fs.Parse(args[1:])
+		if *out == "" {
+			return Usagef("informe o arquivo da chave com -out")
+		}
+
+		pub, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			return err
+		}
+		if err := auditlog.SaveKey(*out, key); err != nil {
+			return err
+		}
+		fmt.Printf("Chave pública: %s\n", hex.EncodeToString(pub))
+		return nil
+	case "verify":
+		fs := flag.NewFlagSet("auditlog verify", flag.ExitOnError)
+		pubHex := fs.String("pub", "", "chave publica, em hexadecimal, que deve ter assinado as entradas")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return Usagef("informe o arquivo do log")
+		}
+
+		var pub ed25519.PublicKey
+		if *pubHex != "" {
+			var err error
+			if pub, err = auditlog.ParsePublicKey(*pubHex); err != nil {
+				return err
+			}
+		}
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		n, err := auditlog.Verify(f, pub)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Log íntegro: %d entrada(s) verificada(s)\n", n)
+		return nil
+	default:
+		return Usagef("acao desconhecida %q: use keygen ou verify", args[0])
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/distrib"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/tune"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Auto implementa o subcomando auto, que mede a maquina, escolhe os
+// parametros da geracao (veja tune.Choose), informa a escolha e gera o
+// primo com eles
+func Auto(args []string) error {
+	fs := flag.NewFlagSet("auto", flag.ExitOnError)
+	bits := fs.Int("bits", 2048, "tamanho em bits do primo gerado")
+	errorBits := fs.Int("error", 80, "probabilidade de erro aceita, 2^-N")
+	dryRun := fs.Bool("dry-run", false, "apenas informa os parametros escolhidos, sem gerar o primo")
+	timeout := fs.Duration("timeout", 0, "prazo da busca (0 = sem prazo)")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	resumeFlags := AddResumeFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	if *bits < 2 {
+		return Usagef("-bits deve ser pelo menos 2")
+	}
+	if *errorBits < 1 {
+		return Usagef("-error deve ser pelo menos 1")
+	}
+
+	fmt.Printf("Medindo a máquina para números de %d bits...\n", *bits)
+	m, err := tune.Probe(*bits)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("- %d CPUs, exponenciação modular: %s, quadrado modular: %s\n", m.CPUs, m.ModExp, m.Square)
+	plan := tune.Choose(*bits, *errorBits, m)
+	fmt.Printf("\nParâmetros escolhidos (erro de no máximo 2^-%d):\n", plan.ErrorBits)
+	for _, reason := range plan.Reasons {
+		fmt.Printf("- %s\n", reason)
+	}
+	fmt.Printf("- Tempo estimado com um worker: %s\n", plan.Estimate.Round(time.Millisecond))
+	if *dryRun {
+		return nil
+	}
+
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	cfg := TestConfig(e)
+	cfg.Rounds = plan.Rounds
+	cfg.Prescreen = plan.Prescreen
+	c := distrib.Coordinator{Window: plan.Window, Test: "miller-rabin", Config: cfg}
+	for i := 0; i < plan.Workers; i++ {
+		c.Workers = append(c.Workers, distrib.Local{Config: cfg})
+	}
+
+	// Ao retomar, a janela eh a da busca gravada, mesmo que a medicao
+	// de agora sugira outra
+	p, err := resumeFlags.Load(&c, *bits)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		g, err := newGenerator(plan.Generator, *bits, e)
+		if err != nil {
+			return err
+		}
+		p = c.NewProgress(prng.ExactBits(g, *bits))
+		p.Bits = *bits
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	inicio := clock.Now()
+	s, err := resumeFlags.Search(ctx, &c, p)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nPrimo encontrado (%d bits): %s\n", s.Prime.BitLen(), s.Prime)
+	elapsed := clock.Since(inicio)
+	fmt.Printf("Candidatos testados: %d, tempo: %s\n", s.Tested, elapsed)
+	return out.Write(ctx, searchRecord("auto", c, s, elapsed))
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BBSRecover implementa o subcomando bbs-recover, que demonstra o papel da
+// fatoracao na seguranca do Blum Blum Shub: o estado capturado depois de
+// -steps bits eh um residuo quadratico que, sem p e q, nao se distingue de
+// um pseudo-quadrado e nao pode ser recuado; com p e q, as raizes
+// quadradas principais levam de volta a semente e a toda a saida anterior
+func BBSRecover(args []string) error {
+	fs := flag.NewFlagSet("bbs-recover", flag.ExitOnError)
+	bits := fs.Int("bits", 128, "tamanho em bits do modulo n (16 a 4096)")
+	steps := fs.Int("steps", 64, "bits gerados antes da captura do estado (1 a 4096)")
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 16 || *bits > 4096 {
+		return Usagef("-bits deve estar entre 16 e 4096")
+	}
+	if *steps < 1 || *steps > 4096 {
+		return Usagef("-steps deve estar entre 1 e 4096")
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	bbs, err := prng.NewBBSWithEntropy(*bits, e)
+	if err != nil {
+		return err
+	}
+	defer bbs.Wipe()
+	n, seed := bbs.Modulus(), bbs.State()
+	fmt.Printf("Módulo n de %d bits: %s\n", n.BitLen(), n)
+	fmt.Printf("Semente x_0 = %s\n", seed)
+
+	output := make([]uint, *steps)
+	for i := range output {
+		output[i] = bbs.NextBit()
+	}
+	captured := bbs.State()
+	fmt.Printf("Saída: %s\n", bitString(output))
+	fmt.Printf("Estado capturado x_%d = %s\n", *steps, captured)
+
+	// Sem os fatores, o estado e o pseudo-quadrado n - x tem o mesmo
+	// simbolo de Jacobi e nao podem ser distinguidos
+	public, err := prng.NewBBSFromPublic(n, captured, *bits)
+	if err != nil {
+		return err
+	}
+	pseudo := new(big.Int).Sub(n, captured)
+	fmt.Println("\nSem a fatoração de n:")
+	fmt.Printf("- x_%d: %s\n", *steps, public.Residuosity(captured))
+	fmt.Printf("- n - x_%d: %s\n", *steps, public.Residuosity(pseudo))
+	if _, err := public.Rewind(1); !errors.Is(err, prng.ErrNoPrivate) {
+		return fmt.Errorf("bbs-recover: gerador sem fatores recuou: %v", err)
+	}
+	fmt.Println("- recuar o gerador exige os fatores p e q")
+
+	fmt.Println("\nCom a fatoração de n:")
+	fmt.Printf("- x_%d: %s\n", *steps, bbs.Residuosity(captured))
+	fmt.Printf("- n - x_%d: %s\n", *steps, bbs.Residuosity(pseudo))
+	recovered, err := bbs.Rewind(uint64(*steps))
+	if err != nil {
+		return err
+	}
+	replay := make([]uint, *steps)
+	for i := range replay {
+		replay[i] = bbs.NextBit()
+	}
+	fmt.Printf("- semente recuperada por %d raízes quadradas principais: %s\n", *steps, recovered)
+	fmt.Printf("- saída reconstruída: %s\n", bitString(replay))
+	if recovered.Cmp(seed) != 0 || bitString(replay) != bitString(output) {
+		return errors.New("bbs-recover: a saida reconstruida difere da original")
+	}
+	fmt.Println("\nA semente e toda a saída anterior foram recuperadas a partir de um único estado.")
+	return nil
+}
+
+// bitString escreve os bits em sequencia, como "0110"
+func bitString(bits []uint) string {
+	var b strings.Builder
+	for _, bit := range bits {
+		b.WriteByte(byte('0' + bit))
+	}
+	return b.String()
+}
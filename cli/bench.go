@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"PrimeNumGenerator/bench"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+)
+
+// ErrBenchRegression indica que alguma operacao ficou mais lenta que a
+// base alem do limite de -threshold
+var ErrBenchRegression = errors.New("regressao de desempenho acima do limite")
+
+// Bench implementa o subcomando bench, que mede as operacoes centrais e as
+// compara com a base gravada em -baseline
+func Bench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	baseline := fs.String("baseline", "", "arquivo JSON com a base; criado com as medicoes se nao existir")
+	bits := fs.Int("bits", 2048, "tamanho em bits dos numeros usados nas medicoes")
+	update := fs.Bool("update", false, "grava as medicoes como a nova base")
+	threshold := fs.Float64("threshold", 0, "falha se alguma operacao ficar mais lenta que a base alem dessa porcentagem (0 apenas informa)")
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	if *bits < 16 {
+		return Usagef("-bits deve ser pelo menos 16")
+	}
+	if *threshold < 0 {
+		return Usagef("-threshold nao pode ser negativo")
+	}
+
+	var base *bench.Baseline
+	if *baseline != "" {
+		b, err := bench.Load(*baseline)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			*update = true // primeira execucao: as medicoes viram a base
+		case err != nil:
+			return err
+		case b.Bits != *bits:
+			return Usagef("a base foi medida com %d bits; use -bits %d ou -update", b.Bits, b.Bits)
+		default:
+			base = &b
+		}
+	} else if *update {
+		return Usagef("informe o arquivo da base com -baseline")
+	}
+
+	fmt.Printf("Medindo as operações com números de %d bits...\n", *bits)
+	results, err := bench.Run(bench.Operations(*bits))
+	if err != nil {
+		return err
+	}
+
+	regressed := false
+	if base == nil {
+		for _, r := range results {
+			fmt.Printf("%-10s %14.1f ns/op %6d alocações/op\n", r.Name, r.NsPerOp, r.AllocsPerOp)
+		}
+	} else {
+		fmt.Printf("Comparando com a base de %s (%s):\n", base.Time.Local().Format("2006-01-02 15:04"), base.GoVersion)
+		for _, c := range base.Compare(results) {
+			switch {
+			case math.IsNaN(c.Percent):
+				fmt.Printf("%-10s %14.1f ns/op  (fora da base)\n", c.Name, c.Current)
+			case c.Percent > 0:
+				fmt.Printf("%-10s %14.1f ns/op  %+7.1f%% (regressão)\n", c.Name, c.Current, c.Percent)
+				regressed = regressed || (*threshold > 0 && c.Percent > *threshold)
+			default:
+				fmt.Printf("%-10s %14.1f ns/op  %+7.1f%% (melhora)\n", c.Name, c.Current, c.Percent)
+			}
+		}
+	}
+
+	if *update {
+		if err := bench.NewBaseline(*bits, results).Save(*baseline); err != nil {
+			return err
+		}
+		fmt.Printf("Base gravada em %s\n", *baseline)
+	}
+	if regressed {
+		return ErrBenchRegression
+	}
+	return nil
+}
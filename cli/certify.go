@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"PrimeNumGenerator/certificate"
+	"PrimeNumGenerator/clock"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Certify implementa o subcomando certify, que produz um certificado de
+// primalidade (ECPP, veja certificate.Prove) para um numero ou expressao,
+// em JSON, ou, com -verify, confere um certificado gravado. Um certificado
+// invalido leva ao codigo de saida ExitProblems.
+func Certify(args []string) error {
+	fs := flag.NewFlagSet("certify", flag.ExitOnError)
+	out := fs.String("out", "", "grava o certificado nesse arquivo (saida padrao se vazio)")
+	verify := fs.String("verify", "", "confere o certificado desse arquivo em vez de produzir um")
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	if (*verify == "") == (fs.NArg() == 0) || fs.NArg() > 1 {
+		return Usagef("use: certify [-out arquivo] numero | certify -verify arquivo")
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	if *verify != "" {
+		data, err := os.ReadFile(*verify)
+		if err != nil {
+			return err
+		}
+		var c certificate.Certificate
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("%s: %w", *verify, err)
+		}
+		if err := c.Verify(); err != nil {
+			return fmt.Errorf("%s: %w", *verify, err)
+		}
+		fmt.Printf("Certificado válido: %s (%d bits) é primo, com %d passo(s)\n", c.N, c.N.BitLen(), len(c.Steps))
+		return nil
+	}
+
+	n, err := parseNumber(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if n.BitLen() > certificate.MaxBits {
+		return Usagef("apenas numeros de ate %d bits", certificate.MaxBits)
+	}
+	inicio := clock.Now()
+	c, err := certificate.Prove(n)
+	if errors.Is(err, certificate.ErrComposite) {
+		return fmt.Errorf("%w: %s", ErrComposite, n)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = fmt.Printf("%s\n", data)
+		return err
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Certificado de %d bits com %d passo(s) gravado em %s (%s)\n", n.BitLen(), len(c.Steps), *out, clock.Since(inicio).Round(time.Millisecond))
+	return nil
+}
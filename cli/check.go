@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/pta"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ErrComposite indica, no modo -assert do subcomando check, que o numero
+// eh composto
+var ErrComposite = errors.New("numero composto")
+
+// ErrTimeout indica que o teste nao terminou dentro do prazo
+var ErrTimeout = errors.New("prazo esgotado")
+
+// Check implementa o subcomando check, que testa se um numero (ou uma
+// expressao como 2^127-1), informado como argumento ou na entrada padrao,
+// eh primo. Com -assert, o codigo de saida indica o veredito, para uso em
+// scripts: 0 se o numero for provavelmente primo, ExitComposite se for
+// composto, ExitUsage se nao for um numero e ExitTimeout se o teste passar
+// de -timeout. Com -f, testa cada linha de um arquivo (veja checkBatch).
+func Check(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	testName := fs.String("test", "miller-rabin", "teste de primalidade: "+strings.Join(pta.Names(), ", "))
+	rounds := fs.Int("rounds", 0, "iteracoes do teste (0 = escolhidas pelo tamanho)")
+	assert := fs.Bool("assert", false, "sai com codigo 0 apenas se o numero (ou todos os de -f) for primo (veja a documentacao dos codigos)")
+	timeout := fs.Duration("timeout", 0, "prazo do teste de cada numero (0 = sem prazo)")
+	file := fs.String("f", "", "testa cada linha desse arquivo (- para a entrada padrao) e escreve os vereditos em JSONL")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "numeros testados ao mesmo tempo com -f")
+	entropyFlags := AddEntropyFlags(fs)
+	formatFlags := AddFormatFlags(fs)
+	fs.Parse(args)
+
+	if fs.NArg() > 1 || (*file != "" && fs.NArg() > 0) {
+		return Usagef("use: check [opcoes] [numero] | check [opcoes] -f arquivo")
+	}
+	if *workers < 1 {
+		return Usagef("-workers deve ser positivo")
+	}
+	test, err := pta.Get(*testName)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	format, err := formatFlags.Format()
+	if err != nil {
+		return err
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	cfg := TestConfig(e)
+	cfg.Rounds = *rounds
+
+	if *file != "" {
+		in := os.Stdin
+		if *file != "-" {
+			if in, err = os.Open(*file); err != nil {
+				return err
+			}
+			defer in.Close()
+		}
+		sum, err := checkBatch(in, os.Stdout, test, cfg, *timeout, *workers)
+		if err != nil || !*assert {
+			return err
+		}
+		switch {
+		case sum.failed > 0:
+			return fmt.Errorf("check: %d numero(s) nao testado(s)", sum.failed)
+		case sum.invalid > 0:
+			return Usagef("%d linha(s) sem um numero valido", sum.invalid)
+		case sum.timeouts > 0:
+			return fmt.Errorf("%w: %d numero(s)", ErrTimeout, sum.timeouts)
+		case sum.composites > 0:
+			return fmt.Errorf("%w: %d de %d numero(s)", ErrComposite, sum.composites, sum.lines)
+		}
+		return nil
+	}
+
+	text := fs.Arg(0)
+	if text == "" || text == "-" {
+		data, err := io.ReadAll(bufio.NewReader(os.Stdin))
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	n, err := parseNumber(text)
+	if err != nil {
+		return err
+	}
+
+	res, err := runTest(test, n, cfg, *timeout, nil)
+	if err != nil {
+		return err
+	}
+	if res.Prime {
+		fmt.Printf("%s é provavelmente primo (%s, %d iterações, probabilidade de erro de no máximo %.3g)\n", format.Int(n, 10), test.Name(), res.Rounds, 1-res.Confidence)
+		return nil
+	}
+	if res.Witness != nil {
+		fmt.Printf("%s é composto (testemunha %s)\n", format.Int(n, 10), format.Int(res.Witness, 10))
+	} else {
+		fmt.Printf("%s é composto\n", format.Int(n, 10))
+	}
+	if *assert {
+		return ErrComposite
+	}
+	return nil
+}
+
+// parseNumber le um numero em decimal, hexadecimal com prefixo 0x ou como
+// expressao (2^127-1, 10^100+267; veja numutil.ParseExpr)
+func parseNumber(text string) (*big.Int, error) {
+	text = strings.TrimSpace(text)
+	n, err := numutil.ParseExpr(text)
+	if err != nil {
+		return nil, Usagef("numero invalido %q: %v", text, err)
+	}
+	return n, nil
+}
+
+// runTest aplica o teste a n, desistindo apos timeout (se positivo). O
+// teste nao pode ser interrompido, entao continua em segundo plano ate o
+// fim do programa; release, se nao for nil, eh chamada quando ele termina
+// de fato, mesmo que seja depois do prazo.
+func runTest(test pta.PrimalityTest, n *big.Int, cfg pta.Config, timeout time.Duration, release func()) (pta.Result, error) {
+	done := make(chan pta.Result, 1)
+	go func() {
+		if release != nil {
+			defer release()
+		}
+		done <- test.IsPrime(n, cfg)
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := clock.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case res := <-done:
+		return res, res.Err
+	case <-expired:
+		return pta.Result{}, fmt.Errorf("%w: o teste passou de %s", ErrTimeout, timeout)
+	}
+}
+
+// checkLine eh o veredito de uma linha de check -f, em JSON
+type checkLine struct {
+	Line   int         `json:"line"`
+	Input  string      `json:"input"`
+	Result *pta.Result `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	err    error
+}
+
+// checkOne testa o numero text, da linha line de check -f, e chama
+// release quando nao houver mais um teste em andamento para ele
+func checkOne(line int, text string, test pta.PrimalityTest, cfg pta.Config, timeout time.Duration, release func()) checkLine {
+	v := checkLine{Line: line, Input: text}
+	n, err := parseNumber(text)
+	if err != nil {
+		release()
+	} else {
+		var res pta.Result
+		if res, err = runTest(test, n, cfg, timeout, release); err == nil {
+			v.Result = &res
+		}
+	}
+	if err != nil {
+		v.Error, v.err = err.Error(), err
+	}
+	return v
+}
+
+// batchSummary conta os vereditos de check -f
+type batchSummary struct {
+	lines, composites, invalid, timeouts, failed int
+}
+
+// checkBatch testa cada linha de r, com workers testes ao mesmo tempo, e
+// escreve em w um veredito JSON por linha, na ordem das linhas, assim que
+// ele fica pronto. Linhas vazias e comecadas por # sao ignoradas. Como em
+// runTest, um teste que passa do prazo continua em segundo plano, e ocupa
+// a vaga do seu worker ate terminar: nunca ha mais que workers testes
+// rodando, mesmo com varios prazos vencidos.
+func checkBatch(r io.Reader, w io.Writer, test pta.PrimalityTest, cfg pta.Config, timeout time.Duration, workers int) (batchSummary, error) {
+	// Cada linha recebe um canal com o seu veredito; a fila dos canais
+	// mantem a ordem e limita quantas linhas ficam em memoria
+	queue := make(chan chan checkLine, 4*workers)
+	sem := make(chan struct{}, workers)
+	var readErr error
+	go func() {
+		defer close(queue)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			done := make(chan checkLine, 1)
+			queue <- done
+			sem <- struct{}{}
+			go func() {
+				done <- checkOne(line, text, test, cfg, timeout, func() { <-sem })
+			}()
+		}
+		readErr = scanner.Err()
+	}()
+
+	var sum batchSummary
+	enc := json.NewEncoder(w)
+	var writeErr error
+	for done := range queue {
+		v := <-done
+		sum.lines++
+		switch {
+		case v.Result != nil && !v.Result.Prime:
+			sum.composites++
+		case errors.Is(v.err, ErrTimeout):
+			sum.timeouts++
+		case v.err != nil && ExitCode(v.err) == ExitUsage:
+			sum.invalid++
+		case v.err != nil:
+			sum.failed++
+		}
+		if writeErr == nil {
+			writeErr = enc.Encode(v)
+		}
+	}
+	return sum, errors.Join(readErr, writeErr)
+}
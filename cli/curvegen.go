@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"PrimeNumGenerator/curvegen"
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/sink"
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+)
+
+// Curvegen implementa o subcomando curvegen, que sorteia curvas elipticas
+// sobre um primo gerado (ou informado) e exibe os criterios de seguranca
+func Curvegen(args []string) error {
+	fs := flag.NewFlagSet("curvegen", flag.ExitOnError)
+	bits := fs.Int("bits", 32, "tamanho em bits do primo p gerado")
+	prime := fs.String("p", "", "usa o primo p informado em vez de gerar um")
+	curves := fs.Int("curves", 3, "numero de curvas sorteadas")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var p *big.Int
+	if *prime != "" {
+		if p, err = parseNumber(*prime); err != nil || !p.ProbablyPrime(20) {
+			return Usagef("primo invalido %q", *prime)
+		}
+	} else {
+		candidate, err := e.Bits(*bits)
+		if err != nil {
+			return err
+		}
+		res, err := pta.MillerRabin(candidate, *bits, TestConfig(e))
+		if err != nil {
+			return err
+		}
+		p = res.Number
+		// Apenas o primo gerado eh publicado; o informado com -p ja eh
+		// conhecido de quem o passou
+		r := sink.NewRecord("curvegen", p)
+		r.Test = "miller-rabin"
+		r.Attempts, r.DurationNS = res.Attempts, int64(res.Duration)
+		if err := out.Write(context.Background(), r); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Primo p = %s (%d bits)\n", p, p.BitLen())
+	if p.BitLen() > curvegen.MaxCountBits {
+		fmt.Printf("AVISO: p tem mais de %d bits, os pontos não serão contados\n", curvegen.MaxCountBits)
+	}
+
+	for i := 0; i < *curves; i++ {
+		c, err := curvegen.RandomCurve(p, e)
+		if err != nil {
+			return err
+		}
+		r, err := c.Check(e)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\nCurva %d: y^2 = x^3 + %s*x + %s\n", i+1, c.A, c.B)
+		if r.Order == nil {
+			fmt.Println("- Número de pontos: não calculado")
+		} else {
+			fmt.Printf("- Número de pontos: %s\n", r.Order)
+			fmt.Printf("- Maior subgrupo primo: %s (cofator %s)\n", r.Subgroup, r.Cofactor)
+		}
+		if r.Anomalous {
+			fmt.Println("- Curva anômala (#E = p)")
+		}
+		if r.EmbeddingDegree > 0 {
+			fmt.Printf("- Grau de mergulho baixo: %d\n", r.EmbeddingDegree)
+		}
+		if r.SpecialJ {
+			fmt.Println("- j-invariante especial (a = 0 ou b = 0)")
+		}
+		fmt.Printf("- Adequada: %t\n", r.Safe())
+	}
+	return nil
+}
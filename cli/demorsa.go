@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/rsa"
+	"PrimeNumGenerator/sink"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// DemoRSA implementa o subcomando demo-rsa, que percorre todo o caminho do
+// projeto, do gerador pseudoaleatorio a um criptossistema funcionando: gera
+// os primos p e q com o gerador e o teste escolhidos, monta o par de chaves
+// RSA, cifra e decifra uma mensagem e mostra cada passo com o seu tempo
+func DemoRSA(args []string) error {
+	fs := flag.NewFlagSet("demo-rsa", flag.ExitOnError)
+	bits := fs.Int("bits", 2048, "tamanho em bits do modulo n")
+	generator := fs.String("generator", "bbs", "gerador dos candidatos: lfg, bbs, hybrid ou hybrid-add")
+	testName := fs.String("test", "miller-rabin", "teste de primalidade: "+strings.Join(pta.Names(), ", "))
+	exponent := fs.Int64("e", rsa.DefaultE, "expoente publico")
+	message := fs.String("message", "Olá, RSA! Primos gerados pelo PrimeNumGenerator.", "mensagem cifrada e decifrada")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 64 || *bits%2 != 0 {
+		return Usagef("-bits deve ser par e pelo menos 64")
+	}
+	if *exponent < 3 || *exponent%2 == 0 {
+		return Usagef("-e deve ser impar e maior que 1")
+	}
+	e := big.NewInt(*exponent)
+	// n tem exatamente -bits bits, entao qualquer mensagem com ate
+	// (bits-1)/8 bytes eh menor que ele
+	if maxLen := (*bits - 1) / 8; len(*message) > maxLen {
+		return Usagef("a mensagem tem %d bytes; com -bits %d, cabem no maximo %d", len(*message), *bits, maxLen)
+	}
+	test, err := pta.Get(*testName)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+	entropy, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	half := *bits / 2
+	total := clock.Now()
+	fmt.Printf("RSA de %d bits: gerador %s, teste %s, e = %s\n", *bits, *generator, test.Name(), e)
+
+	// Passo 1: o gerador pseudoaleatorio
+	inicio := clock.Now()
+	g, err := newGenerator(*generator, half, entropy)
+	if err != nil {
+		return err
+	}
+	step(1, "Gerador %s de %d bits inicializado", inicio, *generator, half)
+
+	// Passos 2 e 3: os primos p e q, de metade do tamanho cada. O segundo bit
+	// mais alto ligado garante que n = p*q tenha exatamente -bits bits.
+	primes := make([]*big.Int, 0, 2)
+	for len(primes) < 2 {
+		inicio = clock.Now()
+		candidate := prng.ExactBits(g, half)
+		candidate.SetBit(candidate, half-2, 1)
+		res, err := pta.Generate(half, candidate, test, TestConfig(entropy))
+		if err != nil {
+			return err
+		}
+		p := res.Number
+		if !rsa.Compatible(p, e) || (len(primes) == 1 && p.Cmp(primes[0]) == 0) {
+			fmt.Printf("    primo descartado: mdc(e, p - 1) != 1 ou repetido\n")
+			continue
+		}
+		name := []string{"p", "q"}[len(primes)]
+		step(2+len(primes), "Primo %s com %d bits, após %d candidatos", inicio, name, p.BitLen(), res.Attempts)
+		fmt.Printf("    %s = %s\n", name, p)
+		r := sink.NewRecord("demo-rsa", p)
+		r.Generator, r.Test = *generator, test.Name()
+		r.Attempts, r.DurationNS = res.Attempts, int64(res.Duration)
+		if err := out.Write(context.Background(), r); err != nil {
+			return err
+		}
+		primes = append(primes, p)
+	}
+
+	// Passo 4: as chaves
+	inicio = clock.Now()
+	k, err := rsa.NewKey(primes[0], primes[1], e)
+	if err != nil {
+		return err
+	}
+	step(4, "Par de chaves: pública (n, e) e privada (d, dp, dq, qinv)", inicio)
+	fmt.Printf("    n = p·q (%d bits) = %s\n", k.N.BitLen(), k.N)
+	fmt.Printf("    λ(n) = mmc(p - 1, q - 1) = %s\n", k.Lambda)
+	fmt.Printf("    d = e⁻¹ mod λ(n) = %s\n", k.D)
+	fmt.Printf("    dp = d mod (p - 1) = %s\n", k.Dp)
+	fmt.Printf("    dq = d mod (q - 1) = %s\n", k.Dq)
+	fmt.Printf("    qinv = q⁻¹ mod p = %s\n", k.Qinv)
+
+	// Passo 5: a mensagem como inteiro
+	inicio = clock.Now()
+	m, err := rsa.EncodeMessage([]byte(*message), k.N)
+	if err != nil {
+		return err
+	}
+	step(5, "Mensagem %q convertida no inteiro m < n", inicio, *message)
+	fmt.Printf("    m = %s\n", m)
+
+	// Passo 6: cifragem
+	inicio = clock.Now()
+	c, err := k.Encrypt(m)
+	if err != nil {
+		return err
+	}
+	step(6, "Cifragem: c = m^e mod n", inicio)
+	fmt.Printf("    c = %s\n", c)
+
+	// Passo 7: decifragem direta e pelo CRT
+	inicio = clock.Now()
+	plain := k.Decrypt(c)
+	step(7, "Decifragem: m = c^d mod n", inicio)
+	inicio = clock.Now()
+	crt := k.DecryptCRT(c)
+	step(8, "Decifragem pelo CRT: m1 = c^dp mod p, m2 = c^dq mod q, m = m2 + q·(qinv·(m1 - m2) mod p)", inicio)
+
+	if plain.Cmp(m) != 0 || crt.Cmp(m) != 0 || !bytes.Equal(rsa.DecodeMessage(crt), []byte(*message)) {
+		return fmt.Errorf("demo-rsa: decifragem divergente: c^d = %s, CRT = %s, esperado %s", plain, crt, m)
+	}
+	fmt.Printf("\nMensagem recuperada: %q\n", rsa.DecodeMessage(crt))
+	fmt.Printf("Tempo total: %s\n", clock.Since(total).Round(time.Microsecond))
+	fmt.Println("\nEste é o RSA de livro-texto, sem preenchimento (OAEP): serve para estudo,")
+	fmt.Println("não para proteger dados. Para isso, use crypto/rsa.")
+	return nil
+}
+
+// step mostra o passo n da demonstracao, com o tempo desde inicio
+func step(n int, format string, inicio time.Time, args ...any) {
+	elapsed := clock.Since(inicio)
+	fmt.Printf("\n%d. %s [%s]\n", n, fmt.Sprintf(format, args...), elapsed.Round(time.Microsecond))
+}
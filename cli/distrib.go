@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/distrib"
+	"PrimeNumGenerator/sink"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Worker implementa o subcomando worker, que atende as janelas de
+// candidatos enviadas por um coordenador
+func Worker(args []string) error {
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	addr := fs.String("addr", ":9090", "endereco em que o worker escuta")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	memoryFlags := AddMemoryFlags(fs)
+	fs.Parse(args)
+
+	if err := memoryFlags.Apply(); err != nil {
+		return err
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	srv := &http.Server{Addr: *addr, Handler: distrib.Handler(distrib.Local{Config: TestConfig(e)})}
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe() }()
+	fmt.Printf("Worker escutando em %s\n", *addr)
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// Coordinator implementa o subcomando coordinator, que busca um primo
+// dividindo os candidatos entre os workers
+func Coordinator(args []string) error {
+	fs := flag.NewFlagSet("coordinator", flag.ExitOnError)
+	workers := fs.String("workers", "", "enderecos dos workers separados por virgula (ex.: http://10.0.0.2:9090)")
+	bits := fs.Int("bits", 2048, "tamanho em bits do primo buscado")
+	window := fs.Int("window", 256, "candidatos impares por janela enviada a um worker")
+	test := fs.String("test", "miller-rabin", "teste de primalidade usado pelos workers")
+	timeout := fs.Duration("timeout", 0, "prazo da busca (0 = sem prazo)")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	resumeFlags := AddResumeFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	if *workers == "" {
+		return Usagef("informe os workers com -workers")
+	}
+	if *bits < 2 {
+		return Usagef("-bits deve ser pelo menos 2")
+	}
+
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	c := distrib.Coordinator{Window: *window, Test: *test, Config: TestConfig(e)}
+	for _, url := range strings.Split(*workers, ",") {
+		c.Workers = append(c.Workers, distrib.Remote{URL: strings.TrimSuffix(strings.TrimSpace(url), "/")})
+	}
+
+	p, err := resumeFlags.Load(&c, *bits)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		// Sorteamos o inicio com o bit mais alto ligado, como em pta.Generate
+		start, err := e.Bits(*bits)
+		if err != nil {
+			return err
+		}
+		start.SetBit(start, *bits-1, 1)
+		p = c.NewProgress(start)
+		p.Bits = *bits
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	inicio := clock.Now()
+	s, err := resumeFlags.Search(ctx, &c, p)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Primo encontrado (%d bits): %s\n", s.Prime.BitLen(), s.Prime)
+	elapsed := clock.Since(inicio)
+	fmt.Printf("Janelas concluídas: %d, candidatos testados: %d, tempo: %s\n", s.Windows, s.Tested, elapsed)
+	return out.Write(ctx, searchRecord("coordinator", c, s, elapsed))
+}
+
+// searchRecord monta o registro publicado em -out do primo encontrado pela
+// busca s do coordenador c
+func searchRecord(command string, c distrib.Coordinator, s distrib.Summary, elapsed time.Duration) sink.Record {
+	r := sink.NewRecord(command, s.Prime)
+	r.Test, r.Attempts, r.DurationNS = c.Test, s.Tested, elapsed.Nanoseconds()
+	return r
+}
+
+// ResumeFlags guarda as opcoes -state e -resume, que gravam o progresso de
+// uma busca interrompida e a retomam de onde parou
+type ResumeFlags struct {
+	state  *string
+	resume *bool
+}
+
+// AddResumeFlags registra as opcoes -state e -resume em fs
+func AddResumeFlags(fs *flag.FlagSet) *ResumeFlags {
+	return &ResumeFlags{
+		state:  fs.String("state", "", "arquivo em que o progresso eh gravado se a busca for interrompida"),
+		resume: fs.Bool("resume", false, "retoma a busca gravada em -state, em vez de sortear um novo inicio"),
+	}
+}
+
+// Load retorna o progresso gravado em -state quando -resume for informado,
+// ajustando a janela e o teste de c aos da busca gravada, ou nil para uma
+// busca nova. bits eh o tamanho pedido, que deve ser o da busca gravada.
+func (f *ResumeFlags) Load(c *distrib.Coordinator, bits int) (*distrib.Progress, error) {
+	if !*f.resume {
+		return nil, nil
+	}
+	if *f.state == "" {
+		return nil, Usagef("-resume exige o arquivo de progresso em -state")
+	}
+	p, err := distrib.LoadProgress(*f.state)
+	if err != nil {
+		return nil, err
+	}
+	if p.Bits != bits {
+		return nil, Usagef("o progresso em %s eh de uma busca de %d bits, e nao de %d", *f.state, p.Bits, bits)
+	}
+	c.Window, c.Test = p.Window, p.Test
+	fmt.Printf("Retomando a busca de %s: %d janelas concluídas, %d candidatos testados\n", *f.state, p.Next+len(p.Done), p.Tested)
+	return p, nil
+}
+
+// Search executa a busca de p com c. Se ela for interrompida por um sinal
+// ou pelo prazo, o progresso eh gravado em -state; se terminar, o arquivo
+// eh removido. O candidato inicial determina toda a busca, entao o estado
+// do gerador que o sorteou nao precisa ser gravado.
+func (f *ResumeFlags) Search(ctx context.Context, c *distrib.Coordinator, p *distrib.Progress) (distrib.Summary, error) {
+	s, err := c.Resume(ctx, p)
+	if *f.state == "" {
+		return s, err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		if saveErr := p.Save(*f.state); saveErr != nil {
+			return s, errors.Join(err, saveErr)
+		}
+		fmt.Printf("Busca interrompida; progresso gravado em %s (continue com -resume)\n", *f.state)
+		return s, err
+	}
+	if err == nil {
+		if err := os.Remove(*f.state); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return s, err
+		}
+	}
+	return s, err
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/dlog"
+	"PrimeNumGenerator/group"
+	"PrimeNumGenerator/prng"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// dlogSolvers sao os algoritmos do subcomando dlog, na ordem da saida
+var dlogSolvers = []struct {
+	name, title string
+	solve       func(g, h, p, q *big.Int, e prng.Entropy) (dlog.Result, error)
+}{
+	{"bsgs", "Passo de bebê/passo de gigante", func(g, h, p, q *big.Int, _ prng.Entropy) (dlog.Result, error) {
+		return dlog.BabyStepGiantStep(g, h, p, q)
+	}},
+	{"rho", "Rho de Pollard", dlog.PollardRho},
+}
+
+// DLog implementa o subcomando dlog, que gera um grupo pequeno de primo
+// seguro, sorteia um expoente secreto x e o recupera de g^x pelos
+// algoritmos do pacote dlog, extrapolando o tempo medido para os tamanhos
+// usados na pratica. Com -sweep, repete a medicao para tamanhos crescentes.
+func DLog(args []string) error {
+	fs := flag.NewFlagSet("dlog", flag.ExitOnError)
+	bits := fs.Int("bits", 32, "tamanho em bits do primo seguro p (16 a 64)")
+	method := fs.String("method", "all", "algoritmo: bsgs, rho ou all")
+	sweep := fs.Bool("sweep", false, "mede todos os tamanhos de 16 ate -bits, de 4 em 4 bits")
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 16 || *bits > 64 {
+		return Usagef("-bits deve estar entre 16 e 64")
+	}
+	var solvers []int
+	for i, s := range dlogSolvers {
+		if *method == "all" || *method == s.name {
+			solvers = append(solvers, i)
+		}
+	}
+	if len(solvers) == 0 {
+		return Usagef("metodo desconhecido %q (use bsgs, rho ou all)", *method)
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+
+	sizes := []int{*bits}
+	if *sweep {
+		sizes = nil
+		for b := 16; b <= *bits; b += 4 {
+			sizes = append(sizes, b)
+		}
+	}
+	// perOp guarda o tempo medio de uma operacao na ultima medicao
+	var perOp time.Duration
+	for _, size := range sizes {
+		p, q, err := group.GenerateSafePrime(size, e)
+		if err != nil {
+			return err
+		}
+		_, g, err := group.FindGenerator(p, e)
+		if err != nil {
+			return err
+		}
+		x, err := e.Int(q)
+		if err != nil {
+			return err
+		}
+		h := new(big.Int).Exp(g, x, p)
+		fmt.Printf("\nGrupo de %d bits: p = %s, q = (p - 1)/2, g = %s\n", size, p, g)
+		fmt.Printf("Segredo x = %s, h = g^x mod p = %s\n", x, h)
+
+		for _, i := range solvers {
+			s := dlogSolvers[i]
+			inicio := clock.Now()
+			res, err := s.solve(g, h, p, q, e)
+			elapsed := clock.Since(inicio)
+			if err != nil {
+				fmt.Printf("- %s: %v\n", s.title, err)
+				continue
+			}
+			if res.X.Cmp(x) != 0 {
+				return fmt.Errorf("dlog: %s encontrou x = %s, esperado %s", s.name, res.X, x)
+			}
+			fmt.Printf("- %s: x encontrado com %d operações em %s\n", s.title, res.Operations, elapsed.Round(time.Microsecond))
+			if res.Operations > 0 {
+				perOp = elapsed / time.Duration(res.Operations)
+			}
+		}
+	}
+	if perOp == 0 {
+		return nil
+	}
+
+	// Os algoritmos genericos precisam de cerca de sqrt(q) operacoes, e
+	// q tem um bit a menos que p
+	fmt.Printf("\nExtrapolação (cerca de sqrt(q) operações de %s, sem contar o custo maior\n", perOp)
+	fmt.Println("das multiplicações com números maiores):")
+	for _, size := range []int{64, 128, 256, 1024, 2048, 3072} {
+		ops := float64(size-1) / 2
+		fmt.Printf("- p de %d bits: 2^%.1f operações, %s\n", size, ops, extrapolate(ops, perOp))
+	}
+	fmt.Println("\nEm Z_p*, o cálculo de índice (crivo de corpos numéricos) é bem mais rápido que")
+	fmt.Println("esses algoritmos genéricos: por isso os grupos de Diffie-Hellman têm 2048 bits ou mais.")
+	return nil
+}
+
+// extrapolate descreve o tempo de 2^log2Ops operacoes de perOp cada,
+// calculado em logaritmos para nao estourar o float64
+func extrapolate(log2Ops float64, perOp time.Duration) string {
+	const year = 365.25 * 24 * 3600
+	log10Seconds := log2Ops*math.Log10(2) + math.Log10(perOp.Seconds())
+	switch log10Years := log10Seconds - math.Log10(year); {
+	case log10Seconds < 6:
+		return time.Duration(math.Pow(10, log10Seconds) * float64(time.Second)).Round(time.Second).String()
+	case log10Years < 6:
+		return fmt.Sprintf("%.0f anos", math.Pow(10, log10Years))
+	default:
+		return fmt.Sprintf("10^%.0f anos", log10Years)
+	}
+}
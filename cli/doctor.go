@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"PrimeNumGenerator/tune"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Doctor implementa o subcomando doctor, que diagnostica a maquina (fontes
+// de entropia, geradores de hardware, CPUs e custo das operacoes com
+// inteiros grandes) e recomenda as configuracoes adequadas a ela
+func Doctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	bitList := fs.String("bits", "1024,2048,4096", "tamanhos em bits medidos, separados por virgulas")
+	errorBits := fs.Int("error", 80, "probabilidade de erro aceita nas recomendacoes, 2^-N")
+	fs.Parse(args)
+
+	var sizes []int
+	for _, s := range splitList(*bitList) {
+		bits, err := strconv.Atoi(s)
+		if err != nil || bits < 2 {
+			return Usagef("tamanho em bits invalido %q", s)
+		}
+		sizes = append(sizes, bits)
+	}
+	if *errorBits < 1 {
+		return Usagef("-error deve ser pelo menos 1")
+	}
+
+	fmt.Println("Diagnosticando a máquina...")
+	d, err := tune.Diagnose(sizes)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nSistema: %s/%s, %s, %d CPUs\n", d.OS, d.Arch, d.GoVersion, d.CPUs)
+
+	fmt.Println("\nFontes de entropia:")
+	for _, p := range d.Entropy {
+		if p.Available {
+			fmt.Printf("- %-7s %14s  %s\n", p.Source, tune.FormatRate(p.Rate), p.Note)
+		} else {
+			fmt.Printf("- %-7s %14s  %s\n", p.Source, "indisponível", p.Note)
+		}
+	}
+	if d.HWRNG != "" {
+		fmt.Printf("- gerador de hardware do kernel em %s, misturado ao crypto/rand\n", d.HWRNG)
+	}
+
+	fmt.Println("\nOperações com inteiros grandes:")
+	fmt.Printf("%6s %16s %16s %16s\n", "Bits", "Exp. modular", "Quadrado", "Pré-filtro 256")
+	for _, m := range d.Machines {
+		fmt.Printf("%6d %16s %16s %16s\n", m.Bits, roundDuration(m.ModExp), roundDuration(m.Square), roundDuration(m.Prescreen[256]))
+	}
+
+	fmt.Printf("\nRecomendações (erro de no máximo 2^-%d):\n", *errorBits)
+	for _, rec := range d.Recommendations(*errorBits) {
+		fmt.Printf("- %s\n", rec)
+	}
+	return nil
+}
+
+// roundDuration arredonda d para exibicao, com tres algarismos
+// significativos
+func roundDuration(d time.Duration) string {
+	for unit := time.Duration(1); unit < time.Second; unit *= 10 {
+		if d < 1000*unit {
+			return d.Round(unit).String()
+		}
+	}
+	return d.Round(time.Millisecond).String()
+}
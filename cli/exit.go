@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"PrimeNumGenerator/certificate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Codigos de saida do programa, para scripts e orquestradores de containers
+const (
+	ExitOK        = 0
+	ExitFailure   = 1 // erro durante a execucao
+	ExitUsage     = 2 // opcoes ou argumentos invalidos (o mesmo codigo do pacote flag)
+	ExitProblems  = 3 // execucao concluida, mas com problemas encontrados (ex.: audit, stats)
+	ExitComposite = 4 // check -assert: o numero eh composto
+	ExitTimeout   = 5 // prazo esgotado (ex.: check -timeout)
+)
+
+// LogFormatEnv eh a variavel de ambiente que escolhe o formato das
+// mensagens de erro: "json" produz uma linha JSON por erro na saida de erro
+const LogFormatEnv = "PRIMEGEN_LOG_FORMAT"
+
+// UsageError indica opcoes ou argumentos invalidos
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Usagef cria um UsageError com a mensagem formatada
+func Usagef(format string, args ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode retorna o codigo de saida correspondente a err
+func ExitCode(err error) int {
+	var usage *UsageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.Is(err, ErrAuditFailed), errors.Is(err, ErrStatsFailed), errors.Is(err, ErrBenchRegression),
+		errors.Is(err, ErrVerifyFailed), errors.Is(err, ErrHealthFailed), errors.Is(err, ErrInteropMismatch),
+		errors.Is(err, ErrNoSuitableGenerator), errors.Is(err, certificate.ErrInvalid):
+		return ExitProblems
+	case errors.Is(err, ErrComposite):
+		return ExitComposite
+	case errors.Is(err, ErrTimeout):
+		return ExitTimeout
+	default:
+		return ExitFailure
+	}
+}
+
+// Exit encerra o programa com o codigo de ExitCode(err), exibindo err na
+// saida de erro. A saida padrao fica reservada para os resultados.
+func Exit(err error) {
+	code := ExitCode(err)
+	if err != nil {
+		if os.Getenv(LogFormatEnv) == "json" {
+			json.NewEncoder(os.Stderr).Encode(map[string]any{"level": "error", "code": code, "error": err.Error()})
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"PrimeNumGenerator/explore"
+	"flag"
+	"fmt"
+	"math/big"
+)
+
+// Explore implementa o subcomando explore, com as acoes goldbach (particoes
+// de Goldbach dos pares de um intervalo) e twins (densidade dos primos
+// gemeos em um intervalo, comparada com a estimativa de Hardy-Littlewood)
+func Explore(args []string) error {
+	if len(args) == 0 {
+		return Usagef("use: explore goldbach -from N -to M | explore twins -from N -to M [-buckets K]")
+	}
+	switch args[0] {
+	case "goldbach":
+		fs := flag.NewFlagSet("explore goldbach", flag.ExitOnError)
+		from := fs.Uint64("from", 4, "primeiro numero par")
+		to := fs.Uint64("to", 100, "ultimo numero par")
+		all := fs.Bool("all", false, "lista todas as somas de cada numero (por padrao, apenas a de menor parcela)")
+		memoryFlags := AddMemoryFlags(fs)
+		fs.Parse(args[1:])
+
+		if err := memoryFlags.Apply(); err != nil {
+			return err
+		}
+		parts, err := explore.Goldbach(*from, *to)
+		if err != nil {
+			return Usagef("%v", err)
+		}
+		least, most := parts[0], parts[0]
+		for _, p := range parts {
+			fmt.Printf("%d: %d partição(ões), %d = %d + %d\n", p.N, p.Count, p.N, p.Least, p.N-p.Least)
+			if *all {
+				for q := p.Least + 1; q <= p.N/2; q++ {
+					// ProbablyPrime(0) eh exato abaixo de 2^64
+					if new(big.Int).SetUint64(q).ProbablyPrime(0) && new(big.Int).SetUint64(p.N-q).ProbablyPrime(0) {
+						fmt.Printf("  %d = %d + %d\n", p.N, q, p.N-q)
+					}
+				}
+			}
+			if p.Count < least.Count {
+				least = p
+			}
+			if p.Count > most.Count {
+				most = p
+			}
+		}
+		fmt.Printf("\n%d números pares verificados; nenhum contraexemplo\n", len(parts))
+		fmt.Printf("- Menos partições: %d (%d)\n", least.N, least.Count)
+		fmt.Printf("- Mais partições: %d (%d)\n", most.N, most.Count)
+		return nil
+	case "twins":
+		fs := flag.NewFlagSet("explore twins", flag.ExitOnError)
+		from := fs.Uint64("from", 2, "inicio do intervalo")
+		to := fs.Uint64("to", 1000000, "fim do intervalo")
+		buckets := fs.Int("buckets", 10, "numero de subintervalos de mesmo tamanho")
+		memoryFlags := AddMemoryFlags(fs)
+		fs.Parse(args[1:])
+
+		if err := memoryFlags.Apply(); err != nil {
+			return err
+		}
+		stats, err := explore.Twins(*from, *to, *buckets)
+		if err != nil {
+			return Usagef("%v", err)
+		}
+		var primes, twins int
+		var expected float64
+		for _, s := range stats {
+			size := float64(s.Hi - s.Lo + 1)
+			fmt.Printf("[%d, %d]: %d primos, %d pares de gêmeos (densidade %.6f, estimativa %.1f, razão %.3f)\n",
+				s.Lo, s.Hi, s.Primes, s.Twins, float64(s.Twins)/size, s.Expected, float64(s.Twins)/s.Expected)
+			primes += s.Primes
+			twins += s.Twins
+			expected += s.Expected
+		}
+		fmt.Printf("\nTotal: %d primos, %d pares de gêmeos (estimativa de Hardy-Littlewood: %.1f)\n", primes, twins, expected)
+		return nil
+	default:
+		return Usagef("acao desconhecida %q: use goldbach ou twins", args[0])
+	}
+}
@@ -0,0 +1,220 @@
+// O pacote cli implementa os subcomandos da linha de comando.
+package cli
+
+import (
+	"PrimeNumGenerator/dedupe"
+	"PrimeNumGenerator/memlimit"
+	"PrimeNumGenerator/numfmt"
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/sink"
+	"errors"
+	"flag"
+	"strings"
+)
+
+// EntropyFlags guarda as opcoes -security e -entropy de um subcomando
+type EntropyFlags struct {
+	security *string
+	source   *string
+}
+
+// AddEntropyFlags registra as opcoes -security e -entropy em fs
+func AddEntropyFlags(fs *flag.FlagSet) *EntropyFlags {
+	return &EntropyFlags{
+		security: fs.String("security", "strict", "comportamento se a fonte de entropia falhar: strict ou permissive"),
+		source:   fs.String("entropy", "crypto", "fonte de entropia: crypto, jitter, rdrand ou rdseed"),
+	}
+}
+
+// Entropy retorna a entropia descrita pelas opcoes
+func (f *EntropyFlags) Entropy() (prng.Entropy, error) {
+	level, err := prng.ParseSecurityLevel(*f.security)
+	if err != nil {
+		return prng.Entropy{}, &UsageError{Err: err}
+	}
+	src, err := prng.ParseEntropySource(*f.source)
+	if errors.Is(err, prng.ErrNoHardwareRNG) {
+		// A fonte existe, mas nao neste processador
+		return prng.Entropy{}, err
+	}
+	if err != nil {
+		return prng.Entropy{}, &UsageError{Err: err}
+	}
+	return prng.Entropy{Source: src, Level: level}, nil
+}
+
+// Names retorna os nomes da fonte de entropia e do nivel de seguranca
+// escolhidos, como foram informados nas opcoes
+func (f *EntropyFlags) Names() (source, security string) {
+	return *f.source, *f.security
+}
+
+// TestConfig retorna a configuracao dos testes de primalidade que usa a
+// entropia e
+func TestConfig(e prng.Entropy) pta.Config {
+	return pta.Config{Security: e.Level, Source: e.Source}
+}
+
+// GenerationFlags guarda as opcoes que ajustam a geracao de primos de um
+// subcomando: -smoothness-bound, -dedupe-db, -filter, -construction,
+// -prescreen e -tables-cache
+type GenerationFlags struct {
+	smoothnessBound *int
+	dedupeDB        *string
+	prescreen       *int
+	tablesCache     *string
+	filters         []pta.CandidateFilter
+	construction    pta.Construction
+}
+
+// AddGenerationFlags registra as opcoes de geracao em fs
+func AddGenerationFlags(fs *flag.FlagSet) *GenerationFlags {
+	f := &GenerationFlags{
+		smoothnessBound: fs.Int("smoothness-bound", 0, "rejeita primos com p-1 ou p+1 suave em relacao a esse limite (0 desativa)"),
+		dedupeDB:        fs.String("dedupe-db", "", "arquivo com os primos ja emitidos, que nao serao repetidos"),
+		prescreen:       fs.Int("prescreen", 0, "numero de primos pequenos do pre-filtro por mdc (0 = 256)"),
+		tablesCache:     fs.String("tables-cache", "", "diretorio em que as tabelas do pre-filtro sao guardadas entre execucoes (vazio desativa)"),
+	}
+	fs.Func("filter", "restringe a forma dos primos: last-digit=D, mod=R/M ou not-smooth=B (pode ser repetida)", func(spec string) error {
+		filter, err := pta.ParseFilter(spec)
+		if err != nil {
+			return err
+		}
+		f.filters = append(f.filters, filter)
+		return nil
+	})
+	fs.Func("construction", "construcao dos candidatos: increment (padrao; testa os impares a partir do candidato) ou crt (apenas os sem fatores pequenos)", func(name string) error {
+		c, err := pta.ParseConstruction(name)
+		f.construction = c
+		return err
+	})
+	return f
+}
+
+// Apply ajusta cfg de acordo com as opcoes e prepara as tabelas do
+// pre-filtro, lidas de -tables-cache quando houver. A funcao retornada fecha os recursos
+// abertos, como o registro de -dedupe-db.
+func (f *GenerationFlags) Apply(cfg *pta.Config) (func() error, error) {
+	cfg.SmoothnessBound = *f.smoothnessBound
+	cfg.Filters = f.filters
+	cfg.Construction = f.construction
+	if *f.prescreen < 0 || *f.prescreen > numutil.MaxTablePrimes {
+		return nil, Usagef("-prescreen deve estar entre 0 e %d", numutil.MaxTablePrimes)
+	}
+	if *f.prescreen > 0 {
+		cfg.Prescreen = *f.prescreen
+	}
+	// O primorial de um pre-filtro escolhido vem de uma arvore de produtos,
+	// e nao dos produtos sucessivos do cache de numutil.Primorial
+	switch k := cfg.PrescreenPrimes(); {
+	case *f.tablesCache != "":
+		if _, err := numutil.LoadTables(*f.tablesCache, k); err != nil {
+			return nil, err
+		}
+	case *f.prescreen > 0:
+		t, err := numutil.BuildTables(k)
+		if err != nil {
+			return nil, err
+		}
+		numutil.UseTables(t)
+	}
+	if *f.dedupeDB == "" {
+		return func() error { return nil }, nil
+	}
+	db, err := dedupe.Open(*f.dedupeDB)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Unique = db
+	return db.Close, nil
+}
+
+// FormatFlags guarda as opcoes de exibicao dos numeros grandes: -group,
+// -group-separator, -wrap e -truncate
+type FormatFlags struct {
+	group     *int
+	separator *string
+	width     *int
+	maxDigits *int
+}
+
+// AddFormatFlags registra as opcoes de exibicao em fs. Por padrao, os
+// numeros sao exibidos sem alteracoes.
+func AddFormatFlags(fs *flag.FlagSet) *FormatFlags {
+	return &FormatFlags{
+		group:     fs.Int("group", 0, "agrupa os digitos dos numeros de N em N (0 desativa)"),
+		separator: fs.String("group-separator", numfmt.DefaultSeparator, "separador dos grupos de -group"),
+		width:     fs.Int("wrap", 0, "quebra os numeros em linhas de ate N caracteres (0 desativa)"),
+		maxDigits: fs.Int("truncate", 0, "mostra apenas o inicio e o fim dos numeros com mais de N digitos (0 desativa)"),
+	}
+}
+
+// Format retorna a formatacao descrita pelas opcoes
+func (f *FormatFlags) Format() (numfmt.Format, error) {
+	format := numfmt.Format{Group: *f.group, Separator: *f.separator, Width: *f.width, MaxDigits: *f.maxDigits}
+	if err := format.Validate(); err != nil {
+		return numfmt.Format{}, &UsageError{Err: err}
+	}
+	return format, nil
+}
+
+// MemoryFlags guarda a opcao -max-memory
+type MemoryFlags struct {
+	budget *string
+}
+
+// AddMemoryFlags registra a opcao -max-memory em fs
+func AddMemoryFlags(fs *flag.FlagSet) *MemoryFlags {
+	return &MemoryFlags{
+		budget: fs.String("max-memory", "", "orcamento de memoria, como 256MiB ou 1G, repartido entre o crivo, os caches e o registro de -dedupe-db (vazio = sem limite)"),
+	}
+}
+
+// Apply aplica o orcamento de -max-memory (veja memlimit.Apply). Deve ser
+// chamado antes de GenerationFlags.Apply, que abre o registro de duplicatas.
+func (f *MemoryFlags) Apply() error {
+	if *f.budget == "" {
+		return nil
+	}
+	total, err := memlimit.Parse(*f.budget)
+	if err != nil {
+		return &UsageError{Err: err}
+	}
+	if _, err := memlimit.Apply(total); err != nil {
+		return &UsageError{Err: err}
+	}
+	return nil
+}
+
+// OutputFlags guarda a opcao -out, que pode ser repetida para publicar os
+// primos gerados em varios destinos (veja o pacote sink)
+type OutputFlags struct {
+	specs []string
+}
+
+// AddOutputFlags registra a opcao -out em fs
+func AddOutputFlags(fs *flag.FlagSet) *OutputFlags {
+	f := &OutputFlags{}
+	fs.Func("out", "publica os primos gerados no destino TIPO:ARGUMENTO ("+strings.Join(sink.Names(), ", ")+"); pode ser repetida", func(spec string) error {
+		f.specs = append(f.specs, spec)
+		return nil
+	})
+	return f
+}
+
+// Open abre os destinos de -out, reunidos em um sink.Tee, que fica vazio
+// se a opcao nao for usada
+func (f *OutputFlags) Open() (sink.Tee, error) {
+	var tee sink.Tee
+	for _, spec := range f.specs {
+		s, err := sink.Open(spec)
+		if err != nil {
+			tee.Close()
+			return nil, &UsageError{Err: err}
+		}
+		tee = append(tee, s)
+	}
+	return tee, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"PrimeNumGenerator/grade"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSuitableGenerator indica que nenhum dos geradores avaliados eh
+// adequado ao proposito informado
+var ErrNoSuitableGenerator = errors.New("nenhum gerador adequado ao proposito")
+
+// Grade implementa o subcomando grade, que avalia os geradores com os
+// testes estatisticos, mede a sua velocidade, consulta as fraquezas
+// conhecidas e recomenda um deles para o proposito de -purpose (veja
+// grade.Rank)
+func Grade(args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ExitOnError)
+	purposeName := fs.String("purpose", "", "uso dos numeros: simulation ou crypto (obrigatorio)")
+	generators := fs.String("generators", "lfg,bbs,hybrid,hybrid-add", "geradores avaliados, separados por virgulas")
+	bits := fs.Int("bits", 512, "tamanho em bits de cada saida")
+	samples := fs.Int("samples", 200, "numero de saidas avaliadas de cada gerador")
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	if *purposeName == "" {
+		return Usagef("informe -purpose simulation ou -purpose crypto")
+	}
+	purpose, err := grade.ParsePurpose(*purposeName)
+	if err != nil {
+		return &UsageError{Err: err}
+	}
+	if *bits < 2 || *samples < 1 {
+		return Usagef("-bits deve ser pelo menos 2 e -samples positivo")
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+
+	var ms []grade.Measurement
+	for _, name := range strings.Split(*generators, ",") {
+		name = strings.TrimSpace(name)
+		g, err := newGenerator(name, *bits, e)
+		if err != nil {
+			return err
+		}
+		m, err := grade.Measure(name, g, *samples)
+		if err != nil {
+			return err
+		}
+		ms = append(ms, m)
+	}
+
+	fmt.Printf("Propósito: %s; saídas de %d bits, %d por gerador\n\n", purpose, *bits, *samples)
+	fmt.Printf("%-12s %6s %10s %10s %14s  %s\n", "Gerador", "Nota", "Frequência", "Corridas", "Bits/s", "Adequado")
+	grades := grade.Rank(purpose, ms)
+	for _, g := range grades {
+		suitable := "não"
+		if g.Suitable {
+			suitable = "sim"
+		}
+		fmt.Printf("%-12s %6.1f %10.4f %10.4f %14.0f  %s\n", g.Generator, g.Score, g.Stats.Monobit, g.Stats.Runs, g.Rate, suitable)
+	}
+	for _, g := range grades {
+		if len(g.Notes) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", g.Generator)
+		for _, note := range g.Notes {
+			fmt.Printf("- %s\n", note)
+		}
+	}
+
+	if !grades[0].Suitable {
+		return ErrNoSuitableGenerator
+	}
+	fmt.Printf("\nRecomendação: %s\n", grades[0].Generator)
+	return nil
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Healthcheck implementa o modo --healthcheck, que consulta o /healthz de
+// um servidor em execucao e sai com codigo 0 se ele responder. Serve de
+// HEALTHCHECK em imagens sem curl ou wget.
+func Healthcheck(args []string) error {
+	fs := flag.NewFlagSet("healthcheck", flag.ExitOnError)
+	url := fs.String("url", "http://127.0.0.1:8080/healthz", "endereco de saude do servidor")
+	timeout := fs.Duration("timeout", 3*time.Second, "prazo da consulta")
+	insecure := fs.Bool("insecure", false, "nao verifica o certificado TLS do servidor (ex.: autoassinado)")
+	fs.Parse(args)
+
+	client := http.Client{Timeout: *timeout}
+	if *insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Get(*url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("healthcheck: %s respondeu %s", *url, resp.Status)
+	}
+	return nil
+}
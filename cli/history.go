@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/history"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// History implementa o subcomando history, que consulta o historico
+// gravado com a opcao -history
+func History(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	db := fs.String("db", "", "arquivo do historico")
+	generator := fs.String("generator", "", "apenas candidatos desse gerador (lfg, bbs ou server)")
+	test := fs.String("test", "", "apenas geracoes com esse teste de primalidade")
+	bits := fs.Int("bits", 0, "apenas geracoes com esse tamanho em bits")
+	since := fs.Duration("since", 0, "apenas geracoes dos ultimos instantes informados (ex.: 24h)")
+	limit := fs.Int("limit", 0, "apenas os registros mais recentes (0 para todos)")
+	asJSON := fs.Bool("json", false, "exibe os registros completos em JSON, um por linha")
+	fs.Parse(args)
+
+	if *db == "" {
+		return Usagef("informe o historico com -db")
+	}
+	filter := history.Filter{Generator: *generator, Test: *test, Bits: *bits, Limit: *limit}
+	if *since > 0 {
+		filter.Since = clock.Now().Add(-*since)
+	}
+	records, err := history.Query(*db, filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, r := range records {
+		fmt.Printf("%s  %-6s %-12s %5d bits  %4d tentativa(s)  %-14s %s\n",
+			r.Time.Local().Format(time.DateTime), r.Generator, r.Test, r.Bits, r.Attempts, r.Duration(), abbreviate(r.Number, 40))
+	}
+	fmt.Printf("%d registro(s)\n", len(records))
+	return nil
+}
+
+// abbreviate encurta numeros longos para a listagem, mantendo o inicio e o fim
+func abbreviate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	half := (max - 3) / 2
+	return s[:half] + "..." + s[len(s)-half:]
+}
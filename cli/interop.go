@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrInteropMismatch indica que o OpenSSL e os testes do pacote pta
+// discordaram sobre algum numero
+var ErrInteropMismatch = errors.New("vereditos divergentes entre o OpenSSL e o pta")
+
+// opensslTimeout eh o prazo de cada chamada ao openssl
+const opensslTimeout = time.Minute
+
+// Interop implementa o subcomando interop, que usa o OpenSSL como oraculo
+// externo: os primos gerados pelo projeto (e produtos de dois deles, que
+// devem ser compostos) sao conferidos com openssl prime -checks 64, e os
+// primos gerados pelo openssl prime -generate passam por todos os testes do
+// pacote pta. Sem o openssl no PATH, a verificacao eh ignorada, a menos que
+// -require seja usado.
+func Interop(args []string) error {
+	fs := flag.NewFlagSet("interop", flag.ExitOnError)
+	bits := fs.Int("bits", 512, "tamanho em bits dos primos")
+	count := fs.Int("count", 10, "primos gerados por cada lado")
+	opensslPath := fs.String("openssl", "openssl", "executavel do OpenSSL")
+	require := fs.Bool("require", false, "falha se o OpenSSL nao estiver disponivel")
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 8 || *count < 1 {
+		return Usagef("-bits deve ser pelo menos 8 e -count positivo")
+	}
+	openssl, err := exec.LookPath(*opensslPath)
+	if err != nil {
+		if *require {
+			return fmt.Errorf("openssl indisponivel: %w", err)
+		}
+		fmt.Printf("OpenSSL não encontrado (%s); verificação ignorada\n", *opensslPath)
+		return nil
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	cfg := TestConfig(e)
+	mr, err := pta.Get("miller-rabin")
+	if err != nil {
+		return err
+	}
+
+	// Primos do projeto conferidos pelo OpenSSL
+	fmt.Printf("Conferindo %d primo(s) de %d bits do projeto com %s prime -checks 64\n", *count, *bits, openssl)
+	mismatches := 0
+	var previous *big.Int
+	for i := 0; i < *count; i++ {
+		candidate, err := e.Bits(*bits)
+		if err != nil {
+			return err
+		}
+		res, err := pta.Generate(*bits, candidate, mr, cfg)
+		if err != nil {
+			return err
+		}
+		numbers := []*big.Int{res.Number}
+		if previous != nil {
+			numbers = append(numbers, new(big.Int).Mul(previous, res.Number))
+		}
+		previous = res.Number
+		for j, n := range numbers {
+			prime, err := opensslIsPrime(openssl, n)
+			if err != nil {
+				return err
+			}
+			if want := j == 0; prime != want {
+				mismatches++
+				fmt.Printf("- DIVERGÊNCIA: %s é %s para o projeto e %s para o OpenSSL\n", n, verdict(want), verdict(prime))
+			}
+		}
+	}
+
+	// Primos do OpenSSL conferidos pelos testes do pacote pta
+	fmt.Printf("Conferindo %d primo(s) de %d bits do OpenSSL com os testes %s\n", *count, *bits, strings.Join(pta.Names(), ", "))
+	for i := 0; i < *count; i++ {
+		p, err := opensslGenerate(openssl, *bits)
+		if err != nil {
+			return err
+		}
+		for _, name := range pta.Names() {
+			test, err := pta.Get(name)
+			if err != nil {
+				return err
+			}
+			res := test.IsPrime(p, cfg)
+			if res.Err != nil {
+				return res.Err
+			}
+			if !res.Prime {
+				mismatches++
+				fmt.Printf("- DIVERGÊNCIA: %s é primo para o OpenSSL e composto para %s (testemunha %s)\n", p, name, res.Witness)
+			}
+		}
+	}
+
+	checked := 2**count - 1 + *count*len(pta.Names())
+	if mismatches > 0 {
+		return fmt.Errorf("%w: %d de %d verificação(ões)", ErrInteropMismatch, mismatches, checked)
+	}
+	fmt.Printf("\nOs %d vereditos concordam\n", checked)
+	return nil
+}
+
+// verdict descreve um veredito de primalidade
+func verdict(prime bool) string {
+	if prime {
+		return "primo"
+	}
+	return "composto"
+}
+
+// opensslIsPrime testa n com openssl prime -checks 64
+func opensslIsPrime(openssl string, n *big.Int) (bool, error) {
+	out, err := runOpenSSL(openssl, "prime", "-checks", "64", n.String())
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case strings.HasSuffix(out, " is not prime"):
+		return false, nil
+	case strings.HasSuffix(out, " is prime"):
+		return true, nil
+	default:
+		return false, fmt.Errorf("resposta inesperada do openssl prime: %q", out)
+	}
+}
+
+// opensslGenerate gera um primo de bits bits com openssl prime -generate
+func opensslGenerate(openssl string, bits int) (*big.Int, error) {
+	out, err := runOpenSSL(openssl, "prime", "-generate", "-bits", fmt.Sprint(bits))
+	if err != nil {
+		return nil, err
+	}
+	p, ok := new(big.Int).SetString(out, 10)
+	if !ok {
+		return nil, fmt.Errorf("resposta inesperada do openssl prime -generate: %q", out)
+	}
+	return p, nil
+}
+
+// runOpenSSL executa o openssl com args e retorna a saida sem os espacos
+// das pontas
+func runOpenSSL(openssl string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opensslTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, openssl, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("openssl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"PrimeNumGenerator/jsonrpc"
+	"flag"
+	"os"
+)
+
+// JSONRPC implementa o modo --jsonrpc, que atende requisicoes JSON-RPC 2.0
+// lidas da entrada padrao, uma por linha, respondendo na saida padrao
+func JSONRPC(args []string) error {
+	fs := flag.NewFlagSet("jsonrpc", flag.ExitOnError)
+	generationFlags := AddGenerationFlags(fs)
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	cfg := TestConfig(e)
+	closeGeneration, err := generationFlags.Apply(&cfg)
+	if err != nil {
+		return err
+	}
+	defer closeGeneration()
+	return jsonrpc.Serve(os.Stdin, os.Stdout, cfg)
+}
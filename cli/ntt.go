@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/sink"
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+)
+
+// NTT implementa o subcomando ntt, que gera primos da forma k*2^n + 1 e
+// uma raiz primitiva 2^n-esima da unidade para cada um, como usados em
+// transformadas teoricas dos numeros e bibliotecas de FHE
+func NTT(args []string) error {
+	fs := flag.NewFlagSet("ntt", flag.ExitOnError)
+	bits := fs.Int("bits", 64, "tamanho em bits dos primos")
+	twoAdicity := fs.Int("two-adicity", 32, "expoente n: 2^n divide p - 1, permitindo NTTs de tamanho ate 2^n")
+	count := fs.Int("count", 1, "numero de primos gerados")
+	generationFlags := AddGenerationFlags(fs)
+	entropyFlags := AddEntropyFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 2 || *twoAdicity < 1 || *twoAdicity >= *bits {
+		return Usagef("-two-adicity deve estar entre 1 e -bits - 1")
+	}
+	if *count < 1 {
+		return Usagef("-count deve ser positivo")
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	cfg := TestConfig(e)
+	closeGeneration, err := generationFlags.Apply(&cfg)
+	if err != nil {
+		return err
+	}
+	defer closeGeneration()
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	for i := 0; i < *count; i++ {
+		p, root, err := pta.GenerateNTT(*bits, *twoAdicity, cfg)
+		if err != nil {
+			return err
+		}
+		pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+		zeros := pMinus1.TrailingZeroBits()
+		k := pMinus1.Rsh(pMinus1, zeros)
+		fmt.Printf("\nPrimo %d: %s (%d bits)\n", i+1, p, p.BitLen())
+		fmt.Printf("- Forma: %s * 2^%d + 1\n", k, zeros)
+		fmt.Printf("- Raiz primitiva 2^%d-ésima da unidade: %s\n", *twoAdicity, root)
+		r := sink.NewRecord("ntt", p)
+		r.Test = "miller-rabin"
+		if err := out.Write(context.Background(), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"PrimeNumGenerator/plot"
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Plot implementa o subcomando plot, com as acoes ulam (espiral de Ulam) e
+// gaps (histograma dos intervalos entre primos consecutivos). A figura eh
+// gravada em PNG ou SVG, conforme a extensao do arquivo de -out.
+func Plot(args []string) error {
+	if len(args) == 0 {
+		return Usagef("use: plot ulam [-start N] [-size L] -out arquivo.png|.svg | plot gaps -from N -to M -out arquivo.png|.svg")
+	}
+	switch args[0] {
+	case "ulam":
+		fs := flag.NewFlagSet("plot ulam", flag.ExitOnError)
+		start := fs.Uint64("start", 1, "numero no centro da espiral")
+		size := fs.Int("size", 201, "lado da espiral, em numeros")
+		scale := fs.Int("scale", 2, "pixels (ou unidades do SVG) por numero")
+		out := fs.String("out", "ulam.png", "arquivo de saida, .png ou .svg")
+		fs.Parse(args[1:])
+
+		s, err := plot.UlamSpiral(*start, *size)
+		if err != nil {
+			return Usagef("%v", err)
+		}
+		return writeFigure(*out, func() image.Image { return s.Image(*scale) }, func(w io.Writer) error { return s.WriteSVG(w, *scale) })
+	case "gaps":
+		fs := flag.NewFlagSet("plot gaps", flag.ExitOnError)
+		from := fs.Uint64("from", 2, "inicio do intervalo")
+		to := fs.Uint64("to", 1000000, "fim do intervalo")
+		out := fs.String("out", "gaps.png", "arquivo de saida, .png ou .svg")
+		memoryFlags := AddMemoryFlags(fs)
+		fs.Parse(args[1:])
+
+		if err := memoryFlags.Apply(); err != nil {
+			return err
+		}
+		h, err := plot.GapHistogram(*from, *to)
+		if err != nil {
+			return Usagef("%v", err)
+		}
+		return writeFigure(*out, h.Image, h.WriteSVG)
+	default:
+		return Usagef("acao desconhecida %q: use ulam ou gaps", args[0])
+	}
+}
+
+// writeFigure grava a figura em path, em PNG ou SVG conforme a extensao
+func writeFigure(path string, img func() image.Image, svg func(io.Writer) error) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".png" && ext != ".svg" {
+		return Usagef("extensao de -out desconhecida %q: use .png ou .svg", ext)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if ext == ".svg" {
+		err = svg(f)
+	} else {
+		err = plot.WritePNG(f, img())
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Figura gravada em %s\n", path)
+	return nil
+}
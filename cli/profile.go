@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// ProfileFlags guarda as opcoes -cpuprofile, -memprofile e -trace dos
+// subcomandos que fazem muitas contas
+type ProfileFlags struct {
+	cpu   *string
+	mem   *string
+	trace *string
+}
+
+// AddProfileFlags registra as opcoes de perfil em fs
+func AddProfileFlags(fs *flag.FlagSet) *ProfileFlags {
+	return &ProfileFlags{
+		cpu:   fs.String("cpuprofile", "", "grava o perfil de CPU nesse arquivo (veja go tool pprof)"),
+		mem:   fs.String("memprofile", "", "grava o perfil de memoria nesse arquivo ao final da execucao"),
+		trace: fs.String("trace", "", "grava o rastro de execucao nesse arquivo (veja go tool trace)"),
+	}
+}
+
+// Start inicia os perfis de CPU e o rastro pedidos. A funcao retornada os
+// encerra e grava o perfil de memoria; como costuma ser chamada com defer,
+// ela informa suas falhas na saida de erro em vez de retorna-las.
+func (f *ProfileFlags) Start() (func(), error) {
+	var stops []func() error
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil {
+				fmt.Fprintf(os.Stderr, "perfil: %v\n", err)
+			}
+		}
+	}
+
+	if *f.cpu != "" {
+		out, err := os.Create(*f.cpu)
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(out); err != nil {
+			out.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return out.Close()
+		})
+	}
+	if *f.trace != "" {
+		out, err := os.Create(*f.trace)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		if err := trace.Start(out); err != nil {
+			out.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return out.Close()
+		})
+	}
+	if *f.mem != "" {
+		path := *f.mem
+		// O perfil de memoria eh gravado por ultimo, com o estado final
+		stops = append([]func() error{func() error {
+			out, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			runtime.GC() // atualiza as estatisticas de alocacao
+			if err := pprof.WriteHeapProfile(out); err != nil {
+				out.Close()
+				return err
+			}
+			return out.Close()
+		}}, stops...)
+	}
+	return stop, nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Pseudoprime implementa o subcomando pseudoprime, que constroi um numero
+// composto aprovado pelo Miller-Rabin com as bases fixas de -bases e mostra
+// que bases sorteadas e o Baillie-PSW o rejeitam
+func Pseudoprime(args []string) error {
+	fs := flag.NewFlagSet("pseudoprime", flag.ExitOnError)
+	baseList := fs.String("bases", "2,3,5,7,11,13,17,19,23,29,31,37", "bases primas, separadas por virgula, que o numero deve enganar")
+	bits := fs.Int("bits", 128, "tamanho em bits do menor fator (o numero tem cerca do triplo)")
+	rounds := fs.Int("rounds", 20, "iteracoes do Miller-Rabin com bases sorteadas na demonstracao")
+	entropyFlags := AddEntropyFlags(fs)
+	fs.Parse(args)
+
+	var bases []int64
+	for _, s := range splitList(*baseList) {
+		a, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Usagef("base invalida %q", s)
+		}
+		bases = append(bases, a)
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+
+	n, factors, err := pta.StrongPseudoprime(bases, *bits, e)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	fmt.Printf("n = %s (%d bits)\n", n, n.BitLen())
+	fmt.Printf("n = %s * %s * %s\n", factors[0], factors[1], factors[2])
+
+	fmt.Println("\nMiller-Rabin com as bases fixas:")
+	for _, a := range bases {
+		verdict := "composto"
+		if pta.StrongProbablePrime(n, big.NewInt(a)) {
+			verdict = "provavelmente primo"
+		}
+		fmt.Printf("- base %d: %s\n", a, verdict)
+	}
+
+	test, err := pta.Get("miller-rabin")
+	if err != nil {
+		return err
+	}
+	cfg := TestConfig(e)
+	cfg.Rounds = *rounds
+	r := test.IsPrime(n, cfg)
+	if r.Err != nil {
+		return r.Err
+	}
+	fmt.Println("\nOutros testes:")
+	if r.Prime {
+		fmt.Printf("- Miller-Rabin com %d bases sorteadas: provavelmente primo\n", *rounds)
+	} else {
+		fmt.Printf("- Miller-Rabin com bases sorteadas: composto (testemunha %s, após %d iteração(ões))\n", r.Witness, r.Rounds)
+	}
+	verdict := "composto"
+	if n.ProbablyPrime(0) {
+		verdict = "provavelmente primo"
+	}
+	fmt.Printf("- Baillie-PSW ((*big.Int).ProbablyPrime(0)): %s\n", verdict)
+	return nil
+}
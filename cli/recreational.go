@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/recreational"
+	"PrimeNumGenerator/sink"
+	"context"
+	"flag"
+	"fmt"
+	"iter"
+	"math/big"
+	"strings"
+)
+
+// Palindromes implementa o subcomando palindromes, que lista os primos
+// palindromos em ordem crescente
+func Palindromes(args []string) error {
+	return recreationalSearch("palindromes", digitsLimit(7), args, func(maxDigits int) iter.Seq[*big.Int] {
+		return func(yield func(*big.Int) bool) {
+			for digits := 1; digits <= maxDigits; digits++ {
+				for n := range recreational.Palindromes(digits) {
+					if !yield(n) {
+						return
+					}
+				}
+			}
+		}
+	}, func(p *big.Int) string {
+		return p.String()
+	})
+}
+
+// Repunits implementa o subcomando repunits, que lista os repunits primos
+// (numeros formados apenas pelo digito 1) em ordem crescente
+func Repunits(args []string) error {
+	return recreationalSearch("repunits", digitsLimit(400), args, recreational.Repunits, func(p *big.Int) string {
+		// Os repunits crescem rapido, entao mostramos so o numero de digitos
+		return fmt.Sprintf("R%d", len(p.String()))
+	})
+}
+
+// Fibprime implementa o subcomando fibprime, que lista os primos de
+// Fibonacci em ordem crescente
+func Fibprime(args []string) error {
+	// O enumerador e o teste andam juntos, entao o primo encontrado eh
+	// sempre o ultimo candidato enumerado
+	last := 0
+	limit := searchLimit{flag: "max-index", usage: "maior indice k dos numeros de Fibonacci F_k", summary: "com índice até %d", value: 1000}
+	return recreationalSearch("fibprime", limit, args, func(maxIndex int) iter.Seq[*big.Int] {
+		return func(yield func(*big.Int) bool) {
+			for k, f := range recreational.Fibonacci(maxIndex) {
+				last = k
+				if !yield(f) {
+					return
+				}
+			}
+		}
+	}, func(p *big.Int) string {
+		s := p.String()
+		if len(s) <= 40 {
+			return fmt.Sprintf("F%d = %s", last, s)
+		}
+		return fmt.Sprintf("F%d (%d dígitos)", last, len(s))
+	})
+}
+
+// searchLimit descreve a opcao que limita os candidatos de uma busca do
+// pacote recreational
+type searchLimit struct {
+	flag, usage string
+	summary     string // complemento da mensagem final, com o limite
+	value       int    // valor padrao
+}
+
+// digitsLimit limita os candidatos pelo numero de digitos
+func digitsLimit(value int) searchLimit {
+	return searchLimit{flag: "max-digits", usage: "maior numero de digitos dos candidatos", summary: "com até %d dígitos", value: value}
+}
+
+// recreationalSearch executa a busca de um subcomando do pacote
+// recreational: enumerate enumera os candidatos ate o valor da opcao de
+// limit e format formata cada primo encontrado
+func recreationalSearch(name string, limit searchLimit, args []string, enumerate func(limit int) iter.Seq[*big.Int], format func(*big.Int) string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	maxValue := fs.Int(limit.flag, limit.value, limit.usage)
+	count := fs.Int("count", 0, "para apos encontrar esse numero de primos (0 = todos)")
+	testName := fs.String("test", "miller-rabin", "teste de primalidade: "+strings.Join(pta.Names(), ", "))
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	if *maxValue < 1 {
+		return Usagef("-%s deve ser positivo", limit.flag)
+	}
+	test, err := pta.Get(*testName)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	found := 0
+	for p, err := range recreational.Primes(enumerate(*maxValue), test, TestConfig(e)) {
+		if err != nil {
+			return err
+		}
+		found++
+		fmt.Println(format(p))
+		r := sink.NewRecord(name, p)
+		r.Test = test.Name()
+		if err := out.Write(context.Background(), r); err != nil {
+			return err
+		}
+		if found == *count {
+			break
+		}
+	}
+	fmt.Printf("\n%d primo(s) encontrado(s) "+limit.summary+"\n", found, *maxValue)
+	return nil
+}
+
+// Perfect implementa o subcomando perfect, que lista os numeros perfeitos
+// pares 2^(p-1) * (2^p - 1), confirmando com o teste de Lucas-Lehmer que
+// 2^p - 1 eh um primo de Mersenne
+func Perfect(args []string) error {
+	fs := flag.NewFlagSet("perfect", flag.ExitOnError)
+	maxExponent := fs.Uint("max-exponent", 1300, "maior expoente p considerado")
+	count := fs.Int("count", 0, "para apos encontrar esse numero de numeros perfeitos (0 = todos)")
+	fs.Parse(args)
+
+	found := 0
+	for p, n := range recreational.PerfectNumbers(*maxExponent) {
+		found++
+		if s := n.String(); len(s) <= 40 {
+			fmt.Printf("p = %d: %s\n", p, s)
+		} else {
+			// Os numeros perfeitos crescem rapido, entao mostramos so os digitos
+			fmt.Printf("p = %d: 2^%d * (2^%d - 1), %d dígitos\n", p, p-1, p, len(s))
+		}
+		if found == *count {
+			break
+		}
+	}
+	fmt.Printf("\n%d número(s) perfeito(s) com p até %d\n", found, *maxExponent)
+	return nil
+}
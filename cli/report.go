@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/report"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Report implementa o subcomando report, que executa a matriz de
+// experimentos (geradores x tamanhos x testes) e grava o relatorio em
+// Markdown
+func Report(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	generators := fs.String("generators", "lfg,bbs", "geradores separados por virgula: lfg, bbs, hybrid ou hybrid-add")
+	bitList := fs.String("bits", "64,128,256,512,1024", "tamanhos em bits separados por virgula")
+	tests := fs.String("tests", "miller-rabin,fermat", "testes de primalidade separados por virgula")
+	runs := fs.Int("runs", 3, "candidatos por combinacao de gerador e tamanho")
+	samples := fs.Int("samples", 100, "saidas de cada gerador avaliadas pelos testes estatisticos (0 desativa)")
+	out := fs.String("out", "", "arquivo do relatorio (saida padrao se vazio)")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	m := report.Matrix{
+		Generators: splitList(*generators),
+		Tests:      splitList(*tests),
+		Runs:       *runs,
+		Samples:    *samples,
+		Config:     TestConfig(e),
+		NewGenerator: func(name string, bits int) (prng.Generator, error) {
+			return newGenerator(name, bits, e)
+		},
+	}
+	for _, s := range splitList(*bitList) {
+		bits, err := strconv.Atoi(s)
+		if err != nil || bits < 2 {
+			return Usagef("tamanho em bits invalido %q", s)
+		}
+		m.Bits = append(m.Bits, bits)
+	}
+	for _, name := range m.Tests {
+		if _, err := pta.Get(name); err != nil {
+			return Usagef("%v", err)
+		}
+	}
+	if *runs < 1 || *samples < 0 {
+		return Usagef("-runs deve ser positivo e -samples nao pode ser negativo")
+	}
+
+	r, err := report.Run(m)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		return r.WriteMarkdown(os.Stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	err = r.WriteMarkdown(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Relatório gravado em %s\n", *out)
+	return nil
+}
+
+// splitList separa uma lista de valores separados por virgula, ignorando
+// os vazios
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
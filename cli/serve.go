@@ -0,0 +1,327 @@
+package cli
+
+import (
+	"PrimeNumGenerator/auditlog"
+	"PrimeNumGenerator/beacon"
+	"PrimeNumGenerator/history"
+	"PrimeNumGenerator/keywrap"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/server"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout eh o tempo dado as requisicoes em andamento ao encerrar
+const shutdownTimeout = 10 * time.Second
+
+// Serve implementa o subcomando serve, que inicia o servidor HTTP. O
+// servidor encerra de forma limpa ao receber SIGTERM ou SIGINT.
+func Serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "endereco em que o servidor escuta")
+	maxBits := fs.Int("max-bits", 4096, "maior tamanho, em bits, aceito por /generate e /check")
+	deadline := fs.Duration("deadline", 0, "prazo de cada /generate (0 = sem prazo); o parametro deadline pode encurta-lo")
+	withBeacon := fs.Bool("beacon", false, "expoe o farol de aleatoriedade verificavel em /beacon")
+	beaconBits := fs.Int("beacon-bits", 2048, "tamanho em bits do modulo n do farol")
+	beaconOutput := fs.Int("beacon-output-bits", 256, "tamanho em bits de cada saida do farol")
+	beaconPeriod := fs.Int("beacon-period", 16, "saidas por segmento do farol antes de revelar a semente")
+	checkpointPath := fs.String("checkpoint", "", "restaura o estado do farol do arquivo ao iniciar e o grava ao encerrar")
+	passphraseFile := fs.String("passphrase-file", "", "cifra o checkpoint com AES-GCM sob uma chave derivada da senha desse arquivo")
+	historyPath := fs.String("history", "", "grava cada primo emitido por /generate no historico em arquivo")
+	auditLogPath := fs.String("audit-log", "", "registra cada primo emitido em um log de auditoria encadeado por hashes")
+	auditKey := fs.String("audit-key", "", "assina as entradas do log de auditoria com a chave Ed25519 do arquivo (veja auditlog keygen)")
+	tlsCert := fs.String("tls-cert", "", "certificado TLS (PEM); com -tls-key, o servidor atende por HTTPS")
+	tlsKey := fs.String("tls-key", "", "chave privada do certificado TLS (PEM)")
+	clientCA := fs.String("client-ca", "", "autoridades (PEM) aceitas para certificados de clientes (TLS mutuo)")
+	authPath := fs.String("auth", "", "exige autenticacao com as chaves e clientes do arquivo JSON (veja o README)")
+	rate := fs.Float64("rate", 0, "requisicoes por segundo permitidas a cada cliente (0 = sem limite)")
+	burst := fs.Int("burst", 10, "requisicoes seguidas permitidas a cada cliente antes de aplicar -rate")
+	maxConcurrent := fs.Int("max-concurrent", 0, "requisicoes atendidas ao mesmo tempo (0 = sem limite)")
+	maxQueue := fs.Int("max-queue", 64, "requisicoes aguardando vaga antes de responder 429")
+	candidates := fs.String("prng", "", "gerador dos candidatos de /generate: lfg, bbs, hybrid ou hybrid-add (vazio = fonte de entropia)")
+	reseedBytes := fs.Int64("reseed-bytes", 1<<20, "troca a semente do gerador de -prng apos esse numero de bytes (0 = nunca)")
+	reseedInterval := fs.Duration("reseed-interval", time.Hour, "troca a semente do gerador de -prng apos esse tempo (0 = nunca); SIGHUP tambem troca")
+	keyLimits := map[string]server.Limit{}
+	fs.Func("key-limit", "limite `chave=taxa[:rajada]` para a chave de API do cabecalho X-API-Key (pode ser repetido)", func(v string) error {
+		key, limit, err := parseKeyLimit(v)
+		keyLimits[key] = limit
+		return err
+	})
+	generationFlags := AddGenerationFlags(fs)
+	entropyFlags := AddEntropyFlags(fs)
+	memoryFlags := AddMemoryFlags(fs)
+	fs.Parse(args)
+
+	if err := memoryFlags.Apply(); err != nil {
+		return err
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	if *checkpointPath != "" && !*withBeacon {
+		return Usagef("-checkpoint exige -beacon")
+	}
+	if *passphraseFile != "" && *checkpointPath == "" {
+		return Usagef("-passphrase-file exige -checkpoint")
+	}
+	passphrase, err := ReadPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	if (*tlsCert == "") != (*tlsKey == "") {
+		return Usagef("informe -tls-cert e -tls-key juntos")
+	}
+	if *clientCA != "" && *tlsCert == "" {
+		return Usagef("-client-ca exige -tls-cert e -tls-key")
+	}
+
+	cfg := server.Config{Tests: TestConfig(e), MaxBits: *maxBits, Deadline: *deadline}
+	cfg.Limits = server.Limits{
+		Default:       server.Limit{Rate: *rate, Burst: *burst},
+		Keys:          keyLimits,
+		MaxConcurrent: *maxConcurrent,
+		MaxQueue:      *maxQueue,
+	}
+	closeGeneration, err := generationFlags.Apply(&cfg.Tests)
+	if err != nil {
+		return err
+	}
+	defer closeGeneration()
+	if *historyPath != "" {
+		if cfg.History, err = history.Open(*historyPath); err != nil {
+			return err
+		}
+		defer cfg.History.Close()
+	}
+	if *auditLogPath != "" {
+		var key ed25519.PrivateKey
+		if *auditKey != "" {
+			if key, err = auditlog.LoadKey(*auditKey); err != nil {
+				return err
+			}
+		}
+		if cfg.AuditLog, err = auditlog.Open(*auditLogPath, key); err != nil {
+			return err
+		}
+		defer cfg.AuditLog.Close()
+	}
+	if *authPath != "" {
+		if cfg.Auth, err = server.LoadAuth(*authPath); err != nil {
+			return err
+		}
+	}
+	if *candidates != "" {
+		policy := prng.ReseedPolicy{MaxBytes: *reseedBytes, MaxAge: *reseedInterval}
+		g, err := prng.NewReseeding(func() (prng.Generator, error) {
+			return newGenerator(*candidates, serveGeneratorBits, e)
+		}, policy)
+		if err != nil {
+			return err
+		}
+		cfg.Candidates = g
+		stopReseed := reseedOnSignal(g)
+		defer stopReseed()
+	}
+	if *withBeacon {
+		if cfg.Beacon, err = openBeacon(*checkpointPath, passphrase, *beaconBits, *beaconOutput, *beaconPeriod, e); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	srv := &http.Server{Addr: *addr, Handler: server.New(cfg)}
+	if *clientCA != "" {
+		if srv.TLSConfig, err = clientTLSConfig(*clientCA); err != nil {
+			return err
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		if *tlsCert != "" {
+			done <- srv.ListenAndServeTLS(*tlsCert, *tlsKey)
+		} else {
+			done <- srv.ListenAndServe()
+		}
+	}()
+	fmt.Printf("Servidor escutando em %s\n", *addr)
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	fmt.Fprintln(os.Stderr, "Sinal recebido, encerrando o servidor...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	if *checkpointPath != "" {
+		// O estado eh gravado mesmo se o prazo estourar, ja que o farol
+		// serializa o acesso ao seu estado
+		err = errors.Join(err, saveBeacon(cfg.Beacon, *checkpointPath, passphrase, e))
+	}
+	return err
+}
+
+// serveGeneratorBits eh o tamanho das saidas do gerador de serve -prng,
+// concatenadas para formar candidatos maiores
+const serveGeneratorBits = 256
+
+// reseedOnSignal troca a semente de g a cada SIGHUP recebido, ate que a
+// funcao retornada seja chamada
+func reseedOnSignal(g *prng.Reseeding) (stop func()) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hup:
+				if err := g.Reseed(); err != nil {
+					fmt.Fprintf(os.Stderr, "Falha ao trocar a semente: %v\n", err)
+				} else {
+					fmt.Fprintln(os.Stderr, "Semente do gerador trocada")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
+
+// parseKeyLimit interpreta o valor de -key-limit
+func parseKeyLimit(v string) (string, server.Limit, error) {
+	key, spec, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return "", server.Limit{}, errors.New("use chave=taxa[:rajada]")
+	}
+	rateText, burstText, hasBurst := strings.Cut(spec, ":")
+	limit := server.Limit{Burst: 10}
+	var err error
+	if limit.Rate, err = strconv.ParseFloat(rateText, 64); err != nil || limit.Rate < 0 {
+		return "", server.Limit{}, fmt.Errorf("taxa invalida %q", rateText)
+	}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burstText); err != nil || limit.Burst < 1 {
+			return "", server.Limit{}, fmt.Errorf("rajada invalida %q", burstText)
+		}
+	}
+	return key, limit, nil
+}
+
+// clientTLSConfig aceita certificados de clientes emitidos pelas
+// autoridades do arquivo PEM caPath. O certificado eh opcional no TLS, para
+// que clientes com chave de API continuem aceitos; quem decide se o
+// cliente foi autenticado eh a configuracao de -auth.
+func clientTLSConfig(caPath string) (*tls.Config, error) {
+	data, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("nenhum certificado em %s", caPath)
+	}
+	return &tls.Config{ClientCAs: pool, ClientAuth: tls.VerifyClientCertIfGiven}, nil
+}
+
+// ReadPassphrase le a senha do arquivo path, sem a quebra de linha final,
+// ou retorna nil se path for vazio
+func ReadPassphrase(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	passphrase := bytes.TrimRight(data, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, Usagef("o arquivo de senha %s esta vazio", path)
+	}
+	return passphrase, nil
+}
+
+// openBeacon restaura o farol do checkpoint em path, se ele existir, ou
+// cria um novo farol com os parametros dados. Um checkpoint cifrado eh
+// decifrado com passphrase; um checkpoint em claro eh aceito mesmo com
+// senha, e sera cifrado ao ser gravado.
+func openBeacon(path string, passphrase []byte, modulusBits, outputBits, period int, e prng.Entropy) (*beacon.Beacon, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if keywrap.IsSealed(data) {
+				if passphrase == nil {
+					return nil, Usagef("o checkpoint %s esta cifrado: informe -passphrase-file", path)
+				}
+				if data, err = keywrap.Open(data, passphrase); err != nil {
+					return nil, err
+				}
+			}
+			fmt.Printf("Farol restaurado de %s\n", path)
+			return beacon.Restore(data, e)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	fmt.Printf("Gerando o módulo do farol (%d bits)...\n", modulusBits)
+	return beacon.New(modulusBits, outputBits, period, e)
+}
+
+// saveBeacon grava o checkpoint do farol em path. O arquivo eh escrito ao
+// lado do destino e renomeado, para que uma interrupcao no meio da escrita
+// nao corrompa o checkpoint anterior. Com passphrase, o checkpoint eh
+// cifrado (veja keywrap.Seal).
+func saveBeacon(b *beacon.Beacon, path string, passphrase []byte, e prng.Entropy) error {
+	data, err := b.Checkpoint()
+	if err != nil {
+		return err
+	}
+	if passphrase != nil {
+		if data, err = keywrap.Seal(data, passphrase, e); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
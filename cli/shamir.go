@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/shamir"
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// Split implementa o subcomando split, que divide um primo gerado, um
+// numero informado ou um arquivo (por exemplo, uma chave privada RSA) em
+// partes de Shamir, impressas uma por linha
+func Split(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	threshold := fs.Int("t", 2, "numero minimo de partes para reconstruir o segredo")
+	parts := fs.Int("n", 3, "numero de partes geradas")
+	bits := fs.Int("bits", 0, "gera um primo com esse numero de bits e o divide")
+	secretFlag := fs.String("secret", "", "numero a dividir, em decimal ou hexadecimal com prefixo 0x")
+	in := fs.String("in", "", "arquivo a dividir, como uma chave privada RSA em PEM")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+
+	var shares []shamir.Share
+	switch {
+	case *in != "":
+		data, err := os.ReadFile(*in)
+		if err != nil {
+			return err
+		}
+		if shares, err = shamir.SplitBytes(data, *threshold, *parts, e); err != nil {
+			return err
+		}
+	case *secretFlag != "" || *bits > 0:
+		secret, ok := new(big.Int).SetString(*secretFlag, 0)
+		if *secretFlag == "" {
+			candidate, err := e.Bits(*bits)
+			if err != nil {
+				return err
+			}
+			res, err := pta.MillerRabin(candidate, *bits, TestConfig(e))
+			if err != nil {
+				return err
+			}
+			secret, ok = res.Number, true
+			fmt.Fprintf(os.Stderr, "Primo gerado: %s\n", secret)
+		}
+		if !ok || secret.Sign() < 0 {
+			return Usagef("segredo invalido %q", *secretFlag)
+		}
+		p, err := shamir.FieldFor(secret, e)
+		if err != nil {
+			return err
+		}
+		if shares, err = shamir.Split(secret, *threshold, *parts, p, e); err != nil {
+			return err
+		}
+	default:
+		return Usagef("informe -bits, -secret ou -in")
+	}
+
+	for _, s := range shares {
+		fmt.Println(s)
+	}
+	return nil
+}
+
+// Combine implementa o subcomando combine, que reconstroi o segredo a partir
+// das partes lidas dos arquivos informados (ou da entrada padrao)
+func Combine(args []string) error {
+	fs := flag.NewFlagSet("combine", flag.ExitOnError)
+	// Nao eh -out: nos subcomandos que geram primos, -out publica cada primo
+	// em um destino do pacote sink, e um segredo nao deve ser confundido com
+	// um deles. Os subcomandos que gravam um unico arquivo proprio (plot,
+	// report, certify e auditlog keygen) mantem -out como caminho.
+	out := fs.String("secret-out", "", "grava o segredo binario (dividido com -in) nesse arquivo")
+	fs.Parse(args)
+
+	var shares []shamir.Share
+	read := func(r io.Reader) error {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			s, err := shamir.ParseShare(line)
+			if err != nil {
+				return err
+			}
+			shares = append(shares, s)
+		}
+		return scanner.Err()
+	}
+
+	if fs.NArg() == 0 {
+		if err := read(os.Stdin); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = read(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if *out != "" {
+		data, err := shamir.CombineBytes(shares)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*out, data, 0o600)
+	}
+	secret, err := shamir.Combine(shares)
+	if err != nil {
+		return err
+	}
+	fmt.Println(secret)
+	return nil
+}
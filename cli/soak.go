@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/stats"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ErrHealthFailed indica que o gerador falhou em um teste de saude do
+// subcomando soak
+var ErrHealthFailed = errors.New("gerador reprovado nos testes de saude")
+
+// Soak implementa o subcomando soak, que gera saidas continuamente por
+// -hours horas, aplicando a cada byte os testes de saude do SP 800-90B
+// (veja stats.Health) e a cada bloco de -block saidas os testes de
+// frequencia e de corridas. Uma falha nos testes de saude interrompe a
+// execucao com ErrHealthFailed, a menos que -keep-going seja usado; as
+// falhas dos blocos, esperadas por acaso em cerca de 2% deles, sao apenas
+// alertadas. Um sinal de interrupcao encerra a execucao com o resumo.
+func Soak(args []string) error {
+	fs := flag.NewFlagSet("soak", flag.ExitOnError)
+	generator := fs.String("prng", "bbs", "gerador avaliado: lfg, bbs, hybrid (LFG xor BBS) ou hybrid-add (LFG + BBS)")
+	bits := fs.Int("bits", 256, "tamanho em bits de cada saida")
+	hours := fs.Float64("hours", 1, "duracao da execucao, em horas")
+	minEntropy := fs.Float64("min-entropy", 6, "entropia minima suposta por byte, que define os limites dos testes de saude")
+	block := fs.Int("block", 1000, "saidas de cada bloco dos testes de frequencia e de corridas")
+	interval := fs.Duration("interval", time.Minute, "intervalo entre as linhas de acompanhamento")
+	keepGoing := fs.Bool("keep-going", false, "apenas alerta as falhas dos testes de saude, sem interromper")
+	whiten := fs.String("whiten", "none", "pos-processamento da saida do gerador: none, vonneumann ou sha256")
+	entropyFlags := AddEntropyFlags(fs)
+	memoryFlags := AddMemoryFlags(fs)
+	fs.Parse(args)
+
+	if *bits < 2 || *hours <= 0 || *block < 1 || *interval <= 0 {
+		return Usagef("-bits, -hours, -block e -interval devem ser positivos")
+	}
+	if err := memoryFlags.Apply(); err != nil {
+		return err
+	}
+	health, err := stats.NewHealth(*minEntropy)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	whitening, err := prng.ParseWhitening(*whiten)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	g, err := newGenerator(*generator, *bits, e)
+	if err != nil {
+		return err
+	}
+	g = prng.Whiten(g, whitening)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	ctx, cancel := clock.WithTimeout(ctx, time.Duration(*hours*float64(time.Hour)))
+	defer cancel()
+
+	rct, apt := health.Cutoffs()
+	fmt.Printf("Gerador: %s (pós-processamento %s), %d bits por saída, por até %g hora(s)\n", *generator, whitening, *bits, *hours)
+	fmt.Printf("Limites dos testes de saúde (H = %g): repetições %d, proporção adaptativa %d em %d\n", *minEntropy, rct, apt, stats.HealthWindow)
+
+	inicio := clock.Now()
+	ticker := clock.NewTicker(*interval)
+	defer ticker.Stop()
+	var outputs, blocks, blockFailures, healthFailures int
+	var window []uint8
+	for ctx.Err() == nil {
+		out := stats.Bits(g, 1)
+		outputs++
+		if err := health.AddBits(out); err != nil {
+			healthFailures++
+			fmt.Fprintf(os.Stderr, "ALERTA: %v\n", err)
+			if !*keepGoing {
+				return fmt.Errorf("%w: %v", ErrHealthFailed, err)
+			}
+		}
+		window = append(window, out...)
+		if outputs%*block == 0 {
+			blocks++
+			if mono, runs := stats.Monobit(window), stats.Runs(window); mono < stats.Alpha || runs < stats.Alpha {
+				blockFailures++
+				fmt.Fprintf(os.Stderr, "ALERTA: bloco %d reprovado (frequência p = %.4f, corridas p = %.4f)\n", blocks, mono, runs)
+			}
+			window = window[:0]
+		}
+
+		select {
+		case <-ticker.C:
+			fmt.Printf("%s: %d saídas, %d bytes testados, %d falha(s) de saúde, %d de %d bloco(s) reprovado(s)\n",
+				clock.Since(inicio).Round(time.Second), outputs, health.Samples(), healthFailures, blockFailures, blocks)
+		default:
+		}
+	}
+
+	fmt.Printf("\nFim após %s: %d saídas, %d bytes testados, %d falha(s) de saúde, %d de %d bloco(s) reprovado(s)\n",
+		clock.Since(inicio).Round(time.Second), outputs, health.Samples(), healthFailures, blockFailures, blocks)
+	if healthFailures > 0 {
+		return fmt.Errorf("%w: %d falha(s)", ErrHealthFailed, healthFailures)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/stats"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// ErrStatsFailed indica que a sequencia avaliada falhou em algum teste
+var ErrStatsFailed = errors.New("sequencia reprovada nos testes estatisticos")
+
+// Stats implementa o subcomando stats, que avalia a sequencia de um
+// gerador com os testes de frequencia e de corridas
+func Stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	generator := fs.String("generator", "hybrid", "gerador avaliado: lfg, bbs, hybrid (LFG xor BBS) ou hybrid-add (LFG + BBS)")
+	bits := fs.Int("bits", 256, "tamanho em bits de cada saida")
+	samples := fs.Int("samples", 1000, "numero de saidas avaliadas")
+	whiten := fs.String("whiten", "none", "pos-processamento da saida do gerador: none, vonneumann ou sha256")
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	fs.Parse(args)
+
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	if *bits < 2 {
+		return Usagef("-bits deve ser pelo menos 2")
+	}
+	whitening, err := prng.ParseWhitening(*whiten)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	g, err := newGenerator(*generator, *bits, e)
+	if err != nil {
+		return err
+	}
+	g = prng.Whiten(g, whitening)
+
+	r, err := stats.Evaluate(g, *samples)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	fmt.Printf("Gerador: %s (pós-processamento %s), %d bits avaliados, %d uns (%.4f)\n", *generator, whitening, r.Bits, r.Ones, float64(r.Ones)/float64(r.Bits))
+	fmt.Printf("- Frequência (monobit): p = %.4f\n", r.Monobit)
+	fmt.Printf("- Corridas (runs): p = %.4f\n", r.Runs)
+	if !r.Pass() {
+		return ErrStatsFailed
+	}
+	fmt.Printf("Aprovado (alfa = %.2f)\n", stats.Alpha)
+	return nil
+}
+
+// newGenerator cria o gerador de nome name, com saidas de bits bits. O LFG
+// usa os parametros e o aquecimento da demonstracao.
+func newGenerator(name string, bits int, e prng.Entropy) (prng.Generator, error) {
+	newLFG := func() (prng.Generator, error) {
+		lfg, err := prng.NewLFGWithEntropy(10, 7, 10, bits, e)
+		if err != nil {
+			return nil, err
+		}
+		lfg.Discard(prng.DefaultWarmup(10))
+		return lfg, nil
+	}
+	switch name {
+	case "lfg":
+		return newLFG()
+	case "bbs":
+		return prng.NewBBSWithEntropy(bits, e)
+	case "hybrid", "hybrid-add":
+		lfg, err := newLFG()
+		if err != nil {
+			return nil, err
+		}
+		bbs, err := prng.NewBBSWithEntropy(bits, e)
+		if err != nil {
+			return nil, err
+		}
+		if name == "hybrid-add" {
+			return prng.NewHybridAdd(lfg, bbs)
+		}
+		return prng.NewHybrid(lfg, bbs)
+	default:
+		return nil, Usagef("gerador desconhecido %q: use lfg, bbs, hybrid ou hybrid-add", name)
+	}
+}
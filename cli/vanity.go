@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/recreational"
+	"PrimeNumGenerator/sink"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Vanity implementa o subcomando vanity, que busca primos cuja
+// representacao decimal ou hexadecimal termina com ou contem um padrao
+func Vanity(args []string) error {
+	fs := flag.NewFlagSet("vanity", flag.ExitOnError)
+	suffix := fs.String("suffix", "", "padrao com que os primos terminam")
+	contains := fs.String("contains", "", "padrao que os primos contem")
+	hex := fs.Bool("hex", false, "procura o padrao na representacao hexadecimal")
+	bits := fs.Int("bits", 128, "tamanho em bits dos primos")
+	count := fs.Int("count", 1, "numero de primos gerados")
+	testName := fs.String("test", "miller-rabin", "teste de primalidade: "+strings.Join(pta.Names(), ", "))
+	entropyFlags := AddEntropyFlags(fs)
+	profileFlags := AddProfileFlags(fs)
+	outputFlags := AddOutputFlags(fs)
+	fs.Parse(args)
+
+	pattern, mode := *suffix, recreational.Suffix
+	switch {
+	case (*suffix == "") == (*contains == ""):
+		return Usagef("informe exatamente uma das opcoes -suffix e -contains")
+	case *contains != "":
+		pattern, mode = *contains, recreational.Contains
+	}
+	if *count < 1 {
+		return Usagef("-count deve ser positivo")
+	}
+	base := 10
+	if *hex {
+		base = 16
+	}
+	test, err := pta.Get(*testName)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	for i := 0; i < *count; i++ {
+		p, err := recreational.Vanity(*bits, pattern, base, mode, test, TestConfig(e))
+		if errors.Is(err, recreational.ErrVanityPattern) {
+			return &UsageError{Err: err}
+		}
+		if err != nil {
+			return err
+		}
+		text := p.Text(base)
+		// Destaca a ultima ocorrencia do padrao, que eh a construida
+		at := strings.LastIndex(text, strings.ToLower(pattern))
+		fmt.Printf("%s[%s]%s\n", text[:at], text[at:at+len(pattern)], text[at+len(pattern):])
+		r := sink.NewRecord("vanity", p)
+		r.Test = test.Name()
+		if err := out.Write(context.Background(), r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
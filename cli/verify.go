@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"PrimeNumGenerator/manifest"
+	"PrimeNumGenerator/pta"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrVerifyFailed indica que a reexecucao nao reproduziu o manifesto
+var ErrVerifyFailed = errors.New("a execucao nao reproduziu o manifesto")
+
+// Verify implementa o subcomando verify, que reexecuta com run a
+// demonstracao descrita por um manifesto (veja a opcao -manifest das
+// demonstracoes) e confere se ela reproduz os mesmos candidatos e primos.
+// run recebe o nome da demonstracao e suas opcoes.
+func Verify(args []string, run func(name string, args []string) error) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	seedHex := fs.String("seed", "", "semente hexadecimal, se o manifesto guardar apenas seu hash")
+	verbose := fs.Bool("v", false, "exibe a saida da reexecucao")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return Usagef("use: verify [-seed HEX] [-v] manifesto.json")
+	}
+	want, err := manifest.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	demoArgs, err := replayArgs(want, *seedHex)
+	if err != nil {
+		return err
+	}
+	if want.Version != manifest.Version() {
+		fmt.Printf("Aviso: manifesto gerado pela versão %s; esta é a versão %s\n", want.Version, manifest.Version())
+	}
+
+	dir, err := os.MkdirTemp("", "primegen-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "manifest.json")
+	demoArgs = append(demoArgs, "-manifest", path)
+
+	fmt.Printf("Reexecutando %s %v...\n", want.Command, demoArgs[:len(demoArgs)-2])
+	if err := runQuiet(!*verbose, func() error { return run(want.Command, demoArgs) }); err != nil {
+		return err
+	}
+	got, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	diffs := manifest.Compare(want, got)
+	for _, d := range diffs {
+		fmt.Printf("- %s\n", d)
+	}
+	if len(diffs) > 0 {
+		return ErrVerifyFailed
+	}
+	fmt.Printf("Execução reproduzida: %d tamanhos, candidatos e primos idênticos\n", len(want.Outputs))
+	return nil
+}
+
+// replayArgs monta as opcoes da demonstracao que reproduzem o manifesto m
+func replayArgs(m *manifest.Manifest, seedHex string) ([]string, error) {
+	if m.Command != "fibonacci" && m.Command != "bbs" {
+		return nil, fmt.Errorf("manifesto de uma demonstracao desconhecida: %q", m.Command)
+	}
+	if m.Entropy.Source != "seed" {
+		return nil, errors.New("a execucao nao usou -seed e nao pode ser reproduzida")
+	}
+	if m.Tests.Rounds != 0 {
+		return nil, errors.New("o manifesto usa iteracoes que a demonstracao nao permite escolher")
+	}
+
+	// A semente do manifesto tem precedencia; a informada deve bater com o hash
+	if m.Entropy.Seed != "" {
+		seedHex = m.Entropy.Seed
+	}
+	if seedHex == "" {
+		return nil, Usagef("o manifesto guarda apenas o hash da semente: informe-a com -seed")
+	}
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return nil, Usagef("-seed deve ser um valor hexadecimal")
+	}
+	if manifest.HashBytes(seed) != m.Entropy.SeedSHA256 {
+		return nil, errors.New("a semente nao corresponde ao hash registrado no manifesto")
+	}
+
+	args := []string{"-seed", seedHex, "-security", m.Entropy.Security}
+	if w, ok := m.Generator.Params["whitening"].(string); ok {
+		args = append(args, "-whiten", w)
+	}
+	if warmup, ok := m.Generator.Params["warmup"].(float64); ok {
+		if warmup == 0 {
+			warmup = -1 // no manifesto, 0 eh a ausencia de aquecimento
+		}
+		args = append(args, "-warmup", strconv.FormatInt(int64(warmup), 10))
+	}
+	if m.Tests.ConstantTime {
+		args = append(args, "-constant-time")
+	}
+	if m.Tests.SmoothnessBound > 0 {
+		args = append(args, "-smoothness-bound", strconv.Itoa(m.Tests.SmoothnessBound))
+	}
+	if m.Tests.Construction != "" {
+		args = append(args, "-construction", m.Tests.Construction)
+	}
+	if m.Tests.Prescreen > 0 && m.Tests.Prescreen != pta.DefaultPrescreen {
+		args = append(args, "-prescreen", strconv.Itoa(m.Tests.Prescreen))
+	}
+	return args, nil
+}
+
+// runQuiet executa f descartando o que ela escreve na saida padrao, se quiet
+func runQuiet(quiet bool, f func() error) error {
+	if !quiet {
+		return f()
+	}
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return err
+	}
+	defer devNull.Close()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+	return f()
+}
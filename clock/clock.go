@@ -0,0 +1,138 @@
+// O pacote clock abstrai a leitura do relogio. Os horarios gravados nos
+// manifestos, historicos e relatorios, os prazos das buscas e os tempos
+// exibidos passam por ele, de forma que os testes e as reexecucoes possam
+// usar um relogio simulado, deterministico e que nao depende do horario da
+// maquina. Os prazos e intervalos (NewTimer, NewTicker e WithTimeout)
+// tambem seguem o relogio do processo: com o relogio simulado, eles vencem
+// quando o horario simulado passa do prazo.
+//
+// As medicoes de desempenho do hardware (a fonte jitter, as sondagens do
+// subcomando auto e o grade) continuam no relogio real: com um relogio
+// simulado, elas perderiam o sentido, e a fonte jitter, a entropia. Pelo
+// mesmo motivo, os prazos que protegem processos e conexoes externos (o
+// OpenSSL do interop e o encerramento dos servidores HTTP) usam o pacote
+// time diretamente.
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock eh uma fonte de horarios
+type Clock interface {
+	Now() time.Time
+}
+
+// System eh o relogio real da maquina
+type System struct{}
+
+// Now implementa Clock
+func (System) Now() time.Time { return time.Now() }
+
+// Simulated eh um relogio simulado: comeca em um horario fixo e so avanca
+// step a cada leitura, ou quando Advance e Set sao chamados. Com step zero,
+// todos os tempos medidos sao nulos e os temporizadores so disparam com
+// Advance e Set. Pode ser usado por varias goroutines.
+type Simulated struct {
+	mu      sync.Mutex
+	now     time.Time
+	step    time.Duration
+	waiters []*waiter // temporizadores pendentes
+}
+
+// NewSimulated cria um relogio simulado que comeca em start e avanca step
+// a cada leitura
+func NewSimulated(start time.Time, step time.Duration) *Simulated {
+	return &Simulated{now: start, step: step}
+}
+
+// Now implementa Clock
+func (s *Simulated) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.now
+	s.now = s.now.Add(s.step)
+	s.fire()
+	return t
+}
+
+// Advance avanca o relogio em d
+func (s *Simulated) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+	s.fire()
+}
+
+// Set leva o relogio ao horario t
+func (s *Simulated) Set(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = t
+	s.fire()
+}
+
+// holder permite guardar qualquer Clock no atomic.Value, que exige sempre
+// o mesmo tipo concreto
+type holder struct{ c Clock }
+
+var current atomic.Value
+
+func init() {
+	current.Store(holder{System{}})
+}
+
+// Set troca o relogio do processo por c e retorna o anterior, para que
+// quem o trocou possa restaura-lo. c nil volta ao relogio real.
+func Set(c Clock) Clock {
+	if c == nil {
+		c = System{}
+	}
+	return current.Swap(holder{c}).(holder).c
+}
+
+// Get retorna o relogio do processo
+func Get() Clock {
+	return current.Load().(holder).c
+}
+
+// Now retorna o horario do relogio do processo
+func Now() time.Time {
+	return Get().Now()
+}
+
+// Since retorna o tempo decorrido desde t no relogio do processo
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Env eh a variavel de ambiente que troca o relogio real por um simulado
+// (veja Parse)
+const Env = "PRIMEGEN_CLOCK"
+
+// Parse interpreta a descricao de um relogio: "system" (ou vazio) eh o
+// relogio real e "HORARIO[,PASSO]", como "2024-01-01T00:00:00Z,1ms", eh um
+// relogio simulado que comeca no horario, em RFC 3339, e avanca o passo a
+// cada leitura
+func Parse(s string) (Clock, error) {
+	if s == "" || s == "system" {
+		return System{}, nil
+	}
+	start, stepText, _ := strings.Cut(s, ",")
+	t, err := time.Parse(time.RFC3339Nano, start)
+	if err != nil {
+		return nil, fmt.Errorf("clock: horario invalido %q: use RFC 3339, como 2024-01-01T00:00:00Z", start)
+	}
+	var step time.Duration
+	if stepText != "" {
+		if step, err = time.ParseDuration(stepText); err != nil || step < 0 {
+			return nil, errors.New("clock: passo invalido " + stepText)
+		}
+	}
+	return NewSimulated(t, step), nil
+}
@@ -0,0 +1,109 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSimulated(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSimulated(start, time.Millisecond)
+	previous := Set(s)
+	defer Set(previous)
+
+	if got := Now(); !got.Equal(start) {
+		t.Fatalf("Now = %v; esperado %v", got, start)
+	}
+	if got := Since(start); got != time.Millisecond {
+		t.Fatalf("Since = %v; esperado 1ms", got)
+	}
+	s.Advance(time.Hour)
+	if got := Now(); !got.Equal(start.Add(time.Hour + 2*time.Millisecond)) {
+		t.Fatalf("Now apos Advance = %v", got)
+	}
+	s.Set(start)
+	if got := Now(); !got.Equal(start) {
+		t.Fatalf("Now apos Set = %v", got)
+	}
+
+	Set(nil)
+	if _, ok := Get().(System); !ok {
+		t.Fatal("Set(nil) nao voltou ao relogio real")
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse("2024-01-01T00:00:00Z,1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := c.Now(), c.Now()
+	if first.Year() != 2024 || second.Sub(first) != time.Second {
+		t.Fatalf("relogio = %v, %v", first, second)
+	}
+	for _, s := range []string{"", "system"} {
+		if c, err := Parse(s); err != nil || c != (System{}) {
+			t.Errorf("Parse(%q) = %v, %v; esperado o relogio real", s, c, err)
+		}
+	}
+	for _, s := range []string{"ontem", "2024-01-01T00:00:00Z,-1s", "2024-01-01T00:00:00Z,x"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) aceito", s)
+		}
+	}
+}
+
+func TestSimulatedTimers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSimulated(start, 0)
+	previous := Set(s)
+	defer Set(previous)
+
+	timer := NewTimer(time.Minute)
+	ticker := NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	ctx, cancel := WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	s.Advance(30 * time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("temporizador disparou antes do prazo")
+	case got := <-ticker.C:
+		if !got.Equal(start.Add(30 * time.Second)) {
+			t.Errorf("ticker disparou em %v", got)
+		}
+	}
+	s.Advance(30 * time.Second)
+	if got := <-timer.C; !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("temporizador disparou em %v", got)
+	}
+	if timer.Stop() {
+		t.Error("Stop informou um temporizador pendente depois do disparo")
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("contexto cancelado antes do prazo: %v", ctx.Err())
+	}
+
+	s.Advance(time.Hour)
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err = %v; esperado context.DeadlineExceeded", ctx.Err())
+	}
+	if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(start.Add(time.Hour)) {
+		t.Errorf("Deadline = %v, %v", deadline, ok)
+	}
+
+	stopped := NewTimer(time.Second)
+	if !stopped.Stop() {
+		t.Error("Stop nao encontrou o temporizador pendente")
+	}
+	s.Advance(time.Hour)
+	select {
+	case <-stopped.C:
+		t.Error("temporizador parado disparou")
+	default:
+	}
+}
@@ -0,0 +1,182 @@
+// Esse arquivo traz os temporizadores e os prazos de contexto que seguem o
+//  relogio do processo.
+
+package clock
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Timer envia o horario em C uma unica vez, quando o prazo vence, como o
+// time.Timer
+type Timer struct {
+	C    <-chan time.Time
+	stop func() bool
+}
+
+// Stop cancela o temporizador e informa se ele ainda estava pendente
+func (t *Timer) Stop() bool { return t.stop() }
+
+// Ticker envia o horario em C a cada periodo, como o time.Ticker. Os
+// disparos que o leitor nao consumir a tempo sao descartados.
+type Ticker struct {
+	C    <-chan time.Time
+	stop func() bool
+}
+
+// Stop encerra os disparos do ticker
+func (t *Ticker) Stop() { t.stop() }
+
+// Timers eh implementado pelos relogios com temporizadores proprios. Com os
+// relogios que nao o implementam, NewTimer e NewTicker usam os do pacote
+// time.
+type Timers interface {
+	NewTimer(d time.Duration) *Timer
+	NewTicker(d time.Duration) *Ticker
+}
+
+// NewTimer implementa Timers com o time.NewTimer
+func (System) NewTimer(d time.Duration) *Timer {
+	t := time.NewTimer(d)
+	return &Timer{C: t.C, stop: t.Stop}
+}
+
+// NewTicker implementa Timers com o time.NewTicker
+func (System) NewTicker(d time.Duration) *Ticker {
+	t := time.NewTicker(d)
+	return &Ticker{C: t.C, stop: func() bool { t.Stop(); return true }}
+}
+
+// waiter eh um temporizador pendente do relogio simulado
+type waiter struct {
+	when   time.Time
+	period time.Duration // positivo nos tickers
+	c      chan time.Time
+}
+
+// NewTimer implementa Timers: o temporizador dispara quando o horario
+// simulado chega a d a partir de agora
+func (s *Simulated) NewTimer(d time.Duration) *Timer {
+	w := s.add(d, 0)
+	return &Timer{C: w.c, stop: func() bool { return s.remove(w) }}
+}
+
+// NewTicker implementa Timers. Entra em panico se d nao for positivo, como
+// o time.NewTicker.
+func (s *Simulated) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("clock: periodo nao positivo em NewTicker")
+	}
+	w := s.add(d, d)
+	return &Ticker{C: w.c, stop: func() bool { return s.remove(w) }}
+}
+
+func (s *Simulated) add(d, period time.Duration) *waiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := &waiter{when: s.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	s.waiters = append(s.waiters, w)
+	s.fire()
+	return w
+}
+
+func (s *Simulated) remove(w *waiter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, x := range s.waiters {
+		if x == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fire dispara os temporizadores vencidos; deve ser chamada com s.mu
+// travado, depois de cada mudanca do horario
+func (s *Simulated) fire() {
+	kept := s.waiters[:0]
+	for _, w := range s.waiters {
+		if w.when.After(s.now) {
+			kept = append(kept, w)
+			continue
+		}
+		select {
+		case w.c <- s.now:
+		default:
+		}
+		if w.period > 0 {
+			// Um salto grande do relogio vale um disparo so, como no
+			// time.Ticker quando o leitor se atrasa
+			w.when = w.when.Add((s.now.Sub(w.when)/w.period + 1) * w.period)
+			kept = append(kept, w)
+		}
+	}
+	clear(s.waiters[len(kept):])
+	s.waiters = kept
+}
+
+// NewTimer cria um temporizador de d no relogio do processo
+func NewTimer(d time.Duration) *Timer {
+	if t, ok := Get().(Timers); ok {
+		return t.NewTimer(d)
+	}
+	return System{}.NewTimer(d)
+}
+
+// NewTicker cria um ticker de periodo d no relogio do processo
+func NewTicker(d time.Duration) *Ticker {
+	if t, ok := Get().(Timers); ok {
+		return t.NewTicker(d)
+	}
+	return System{}.NewTicker(d)
+}
+
+// WithTimeout eh o context.WithTimeout no relogio do processo: o contexto
+// retornado eh cancelado, com context.DeadlineExceeded, quando d se passar
+// nesse relogio
+func WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, Now().Add(d))
+}
+
+// WithDeadline eh o context.WithDeadline no relogio do processo
+func WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if _, ok := Get().(System); ok {
+		return context.WithDeadline(parent, deadline)
+	}
+	inner, cancel := context.WithCancel(parent)
+	ctx := &deadlineCtx{Context: inner, deadline: deadline}
+	timer := NewTimer(deadline.Sub(Now()))
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			if inner.Err() == nil {
+				ctx.expired.Store(true)
+			}
+			cancel()
+		case <-inner.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// deadlineCtx eh o contexto de WithDeadline com um relogio que nao eh o
+// real: o cancelamento vem de um Timer desse relogio, e Err informa
+// context.DeadlineExceeded como no pacote context
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	expired  atomic.Bool
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) { return c.deadline, true }
+
+func (c *deadlineCtx) Err() error {
+	if c.expired.Load() {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
@@ -0,0 +1,120 @@
+package curvegen
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+)
+
+const (
+	// maxEmbeddingDegree eh o maior grau de mergulho verificado pelo
+	// criterio MOV
+	maxEmbeddingDegree = 20
+	// trialBound limita a divisao por tentativa usada para fatorar #E
+	trialBound = 1 << 16
+)
+
+// Report resume os criterios de seguranca verificados para uma curva
+type Report struct {
+	Curve *Curve
+	// Order eh #E; nil se p for grande demais para contar os pontos
+	Order *big.Int
+	// Subgroup eh o maior fator primo de #E e Cofactor = #E/Subgroup
+	Subgroup, Cofactor *big.Int
+	// Anomalous indica #E = p (vulneravel ao ataque de Smart)
+	Anomalous bool
+	// EmbeddingDegree eh o menor k <= 20 com p^k = 1 mod Subgroup (vulneravel
+	// aos ataques MOV/Frey-Ruck); 0 se nao houver
+	EmbeddingDegree int
+	// SpecialJ indica a = 0 ou b = 0 (j-invariante 0 ou 1728), curvas com
+	// endomorfismos extras que merecem cuidado
+	SpecialJ bool
+}
+
+// Safe informa se a curva passou em todos os criterios: ordem conhecida,
+// cofator pequeno, nao anomala e sem grau de mergulho baixo
+func (r Report) Safe() bool {
+	return r.Order != nil && r.Cofactor.Cmp(big.NewInt(8)) <= 0 &&
+		!r.Anomalous && r.EmbeddingDegree == 0
+}
+
+// Check conta os pontos da curva (quando p permite) e verifica os
+// criterios usuais: curvas anomalas, grau de mergulho baixo e cofator.
+// Para p acima de MaxCountBits apenas os criterios que nao dependem de #E
+// sao verificados.
+func (c *Curve) Check(e prng.Entropy) (Report, error) {
+	r := Report{Curve: c, SpecialJ: c.A.Sign() == 0 || c.B.Sign() == 0}
+
+	order, err := c.CountPoints(e)
+	if err == ErrTooLarge {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	r.Order = order
+	r.Anomalous = order.Cmp(c.P) == 0
+
+	// Fatoramos #E por tentativa e o que sobrar com o rho de Pollard
+	rest := new(big.Int).Set(order)
+	largest := big.NewInt(1)
+	for d := int64(2); d < trialBound && rest.Cmp(big.NewInt(1)) > 0; d++ {
+		div := big.NewInt(d)
+		for new(big.Int).Mod(rest, div).Sign() == 0 {
+			rest.Div(rest, div)
+			largest = div
+		}
+	}
+	r.Subgroup = largest
+	if rest.Cmp(big.NewInt(1)) > 0 {
+		r.Subgroup = largestFactor(rest)
+	}
+	r.Cofactor = new(big.Int).Div(order, r.Subgroup)
+
+	// Criterio MOV: o menor k com p^k = 1 mod n
+	if r.Subgroup.Cmp(c.P) != 0 {
+		pk := big.NewInt(1)
+		for k := 1; k <= maxEmbeddingDegree; k++ {
+			pk.Mul(pk, c.P).Mod(pk, r.Subgroup)
+			if pk.Cmp(big.NewInt(1)) == 0 {
+				r.EmbeddingDegree = k
+				break
+			}
+		}
+	}
+	return r, nil
+}
+
+// largestFactor retorna o maior fator primo de n, que nao tem fatores
+// menores que trialBound, usando o rho de Pollard
+func largestFactor(n *big.Int) *big.Int {
+	if n.ProbablyPrime(20) {
+		return n
+	}
+	d := pollardRho(n)
+	a, b := largestFactor(d), largestFactor(new(big.Int).Div(n, d))
+	if a.Cmp(b) > 0 {
+		return a
+	}
+	return b
+}
+
+// pollardRho encontra um fator nao trivial do composto impar n
+func pollardRho(n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	for c := int64(1); ; c++ {
+		// f(x) = x^2 + c, com a deteccao de ciclos de Floyd
+		f := func(x *big.Int) *big.Int {
+			x.Mul(x, x).Add(x, big.NewInt(c))
+			return x.Mod(x, n)
+		}
+		x, y, d := big.NewInt(2), big.NewInt(2), big.NewInt(1)
+		for d.Cmp(one) == 0 {
+			f(x)
+			f(f(y))
+			d.GCD(nil, nil, new(big.Int).Abs(new(big.Int).Sub(x, y)), n)
+		}
+		if d.Cmp(n) != 0 {
+			return d
+		}
+	}
+}
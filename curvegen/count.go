@@ -0,0 +1,120 @@
+package curvegen
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+)
+
+const (
+	// NaiveCountBits eh o maior tamanho de p contado ponto a ponto
+	NaiveCountBits = 16
+	// MaxCountBits eh o maior tamanho de p aceito por CountPoints; acima
+	// dele o passo de bebe/passo de gigante fica lento demais
+	MaxCountBits = 64
+	// maxPoints limita quantos pontos aleatorios CountPoints examina
+	maxPoints = 32
+)
+
+// ErrTooLarge indica que p eh grande demais para contar os pontos
+var ErrTooLarge = errors.New("curvegen: primo grande demais para contar os pontos")
+
+// CountPointsNaive conta os pontos da curva (incluindo o ponto no infinito)
+// somando os simbolos de Legendre: #E = p + 1 + sum (x^3 + ax + b | p)
+func (c *Curve) CountPointsNaive() *big.Int {
+	count := new(big.Int).Add(c.P, big.NewInt(1))
+	for x := new(big.Int); x.Cmp(c.P) < 0; x.Add(x, big.NewInt(1)) {
+		count.Add(count, big.NewInt(int64(numutil.Legendre(c.rhs(x), c.P))))
+	}
+	return count
+}
+
+// hasse retorna o intervalo [p+1-2sqrt(p), p+1+2sqrt(p)] que contem #E
+func (c *Curve) hasse() (lo, hi *big.Int) {
+	// 2sqrt(p) <= isqrt(4p) + 1
+	width := new(big.Int).Lsh(c.P, 2)
+	width.Sqrt(width).Add(width, big.NewInt(1))
+	center := new(big.Int).Add(c.P, big.NewInt(1))
+	return new(big.Int).Sub(center, width), new(big.Int).Add(center, width)
+}
+
+// CountPoints retorna #E. Para p de ate NaiveCountBits bits os pontos sao
+// contados um a um; ate MaxCountBits bits, a ordem de pontos aleatorios eh
+// encontrada com o passo de bebe/passo de gigante dentro do intervalo de
+// Hasse, ate que reste um unico multiplo do mmc das ordens no intervalo.
+func (c *Curve) CountPoints(e prng.Entropy) (*big.Int, error) {
+	if c.P.BitLen() <= NaiveCountBits {
+		return c.CountPointsNaive(), nil
+	}
+	if c.P.BitLen() > MaxCountBits {
+		return nil, ErrTooLarge
+	}
+
+	lo, hi := c.hasse()
+	lcm := big.NewInt(1)
+	for i := 0; i < maxPoints; i++ {
+		pt, err := c.RandomPoint(e)
+		if err != nil {
+			return nil, err
+		}
+		order := c.pointOrder(pt, lo, hi)
+		lcm.Div(new(big.Int).Mul(lcm, order), new(big.Int).GCD(nil, nil, lcm, order))
+
+		// Multiplos de lcm dentro de [lo, hi]
+		first := new(big.Int).Add(lo, new(big.Int).Sub(lcm, big.NewInt(1)))
+		first.Div(first, lcm).Mul(first, lcm)
+		if new(big.Int).Add(first, lcm).Cmp(hi) > 0 {
+			return first, nil
+		}
+	}
+	return nil, errors.New("curvegen: nao foi possivel determinar #E")
+}
+
+// pointOrder retorna a ordem de pt, que divide algum numero em [lo, hi]
+func (c *Curve) pointOrder(pt Point, lo, hi *big.Int) *big.Int {
+	// m ~ sqrt(hi - lo): passos de bebe j*pt para 0 <= j < m
+	m := new(big.Int).Sub(hi, lo)
+	m.Sqrt(m).Add(m, big.NewInt(1))
+	steps := int(m.Int64())
+
+	baby := make(map[string]int, steps)
+	q := Infinity()
+	for j := 0; j < steps; j++ {
+		if j > 0 && q.IsInfinity() {
+			// A ordem eh menor que m
+			return big.NewInt(int64(j))
+		}
+		baby[pointKey(q)] = j
+		q = c.Add(q, pt)
+	}
+
+	// Passos de gigante: procuramos k = lo + i*m + j com k*pt = O, ou seja,
+	// j*pt = -(lo + i*m)*pt. Os k encontrados sao multiplos consecutivos da
+	// ordem de pt.
+	giant := c.Neg(c.ScalarMult(m, pt))
+	r := c.Neg(c.ScalarMult(lo, pt))
+	var matches []*big.Int
+	for k := new(big.Int).Set(lo); k.Cmp(hi) <= 0; k.Add(k, m) {
+		if j, ok := baby[pointKey(r)]; ok {
+			match := new(big.Int).Add(k, big.NewInt(int64(j)))
+			if match.Cmp(hi) <= 0 {
+				matches = append(matches, match)
+			}
+		}
+		r = c.Add(r, giant)
+	}
+
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return new(big.Int).Sub(matches[1], matches[0])
+}
+
+// pointKey identifica um ponto no mapa dos passos de bebe
+func pointKey(pt Point) string {
+	if pt.IsInfinity() {
+		return "O"
+	}
+	return pt.X.Text(16) + "," + pt.Y.Text(16)
+}
@@ -0,0 +1,202 @@
+// O pacote curvegen eh um experimento educacional que constroi curvas
+// elipticas de Weierstrass y^2 = x^3 + ax + b sobre um primo p gerado pelo
+// projeto, conta seus pontos e verifica os criterios usuais de seguranca.
+//
+// A aritmetica usa coordenadas afins e math/big, sem nenhuma preocupacao
+// com desempenho ou tempo constante: o objetivo eh mostrar onde os primos
+// grandes sao usados, nao oferecer criptografia de curvas elipticas.
+package curvegen
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+)
+
+// Curve eh a curva y^2 = x^3 + A*x + B sobre o corpo primo F_P
+type Curve struct {
+	P, A, B *big.Int
+}
+
+// Point eh um ponto afim da curva; X nil representa o ponto no infinito
+type Point struct {
+	X, Y *big.Int
+}
+
+// Infinity retorna o ponto no infinito, elemento neutro do grupo
+func Infinity() Point {
+	return Point{}
+}
+
+// IsInfinity informa se pt eh o ponto no infinito
+func (pt Point) IsInfinity() bool {
+	return pt.X == nil
+}
+
+// Equal informa se pt e q sao o mesmo ponto
+func (pt Point) Equal(q Point) bool {
+	if pt.IsInfinity() || q.IsInfinity() {
+		return pt.IsInfinity() == q.IsInfinity()
+	}
+	return pt.X.Cmp(q.X) == 0 && pt.Y.Cmp(q.Y) == 0
+}
+
+// NewCurve cria a curva de coeficientes a e b sobre F_p, verificando que
+// p > 3 e que a curva nao eh singular (4a^3 + 27b^2 != 0 mod p)
+func NewCurve(p, a, b *big.Int) (*Curve, error) {
+	if p.Cmp(big.NewInt(3)) <= 0 || p.Bit(0) == 0 {
+		return nil, errors.New("curvegen: p deve ser um primo maior que 3")
+	}
+	c := &Curve{
+		P: new(big.Int).Set(p),
+		A: new(big.Int).Mod(a, p),
+		B: new(big.Int).Mod(b, p),
+	}
+	if c.Discriminant().Sign() == 0 {
+		return nil, errors.New("curvegen: curva singular")
+	}
+	return c, nil
+}
+
+// RandomCurve sorteia coeficientes a e b ate encontrar uma curva nao
+// singular sobre F_p
+func RandomCurve(p *big.Int, e prng.Entropy) (*Curve, error) {
+	if p.Cmp(big.NewInt(3)) <= 0 || p.Bit(0) == 0 {
+		return nil, errors.New("curvegen: p deve ser um primo maior que 3")
+	}
+	for {
+		a, err := e.Int(p)
+		if err != nil {
+			return nil, err
+		}
+		b, err := e.Int(p)
+		if err != nil {
+			return nil, err
+		}
+		if c, err := NewCurve(p, a, b); err == nil {
+			return c, nil
+		}
+	}
+}
+
+// Discriminant retorna 4a^3 + 27b^2 mod p
+func (c *Curve) Discriminant() *big.Int {
+	a3 := new(big.Int).Exp(c.A, big.NewInt(3), c.P)
+	a3.Mul(a3, big.NewInt(4))
+	b2 := new(big.Int).Mul(c.B, c.B)
+	b2.Mul(b2, big.NewInt(27))
+	d := a3.Add(a3, b2)
+	return d.Mod(d, c.P)
+}
+
+// rhs calcula x^3 + ax + b mod p
+func (c *Curve) rhs(x *big.Int) *big.Int {
+	y2 := new(big.Int).Mul(x, x)
+	y2.Add(y2, c.A)
+	y2.Mul(y2, x)
+	y2.Add(y2, c.B)
+	return y2.Mod(y2, c.P)
+}
+
+// OnCurve informa se pt pertence a curva
+func (c *Curve) OnCurve(pt Point) bool {
+	if pt.IsInfinity() {
+		return true
+	}
+	y2 := new(big.Int).Mul(pt.Y, pt.Y)
+	return y2.Mod(y2, c.P).Cmp(c.rhs(pt.X)) == 0
+}
+
+// RandomPoint sorteia um ponto da curva diferente do ponto no infinito
+func (c *Curve) RandomPoint(e prng.Entropy) (Point, error) {
+	for {
+		x, err := e.Int(c.P)
+		if err != nil {
+			return Point{}, err
+		}
+		y2 := c.rhs(x)
+		if y2.Sign() == 0 {
+			return Point{X: x, Y: y2}, nil
+		}
+		y, err := numutil.SqrtMod(y2, c.P)
+		if err != nil {
+			continue
+		}
+		// Escolhemos uma das duas raizes ao acaso
+		var sign [1]byte
+		if _, err := e.Read(sign[:]); err != nil {
+			return Point{}, err
+		}
+		if sign[0]&1 == 1 {
+			y.Sub(c.P, y)
+		}
+		return Point{X: x, Y: y}, nil
+	}
+}
+
+// Neg retorna -pt
+func (c *Curve) Neg(pt Point) Point {
+	if pt.IsInfinity() {
+		return pt
+	}
+	y := new(big.Int).Neg(pt.Y)
+	return Point{X: new(big.Int).Set(pt.X), Y: y.Mod(y, c.P)}
+}
+
+// Add retorna pt + q pela lei de grupo da curva
+func (c *Curve) Add(pt, q Point) Point {
+	if pt.IsInfinity() {
+		return q
+	}
+	if q.IsInfinity() {
+		return pt
+	}
+
+	var lambda *big.Int
+	if pt.X.Cmp(q.X) == 0 {
+		// pt = -q: a reta eh vertical
+		sum := new(big.Int).Add(pt.Y, q.Y)
+		if sum.Mod(sum, c.P).Sign() == 0 {
+			return Infinity()
+		}
+		// Duplicacao: lambda = (3x^2 + a) / 2y
+		num := new(big.Int).Mul(pt.X, pt.X)
+		num.Mul(num, big.NewInt(3))
+		num.Add(num, c.A)
+		den := new(big.Int).Lsh(pt.Y, 1)
+		lambda = num.Mul(num, den.ModInverse(den, c.P))
+	} else {
+		// Soma: lambda = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(q.Y, pt.Y)
+		den := new(big.Int).Sub(q.X, pt.X)
+		den.Mod(den, c.P)
+		lambda = num.Mul(num, den.ModInverse(den, c.P))
+	}
+	lambda.Mod(lambda, c.P)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, pt.X)
+	x.Sub(x, q.X)
+	x.Mod(x, c.P)
+	y := new(big.Int).Sub(pt.X, x)
+	y.Mul(y, lambda)
+	y.Sub(y, pt.Y)
+	y.Mod(y, c.P)
+	return Point{X: x, Y: y}
+}
+
+// ScalarMult retorna k*pt pelo metodo de duplicar e somar
+func (c *Curve) ScalarMult(k *big.Int, pt Point) Point {
+	if k.Sign() < 0 {
+		return c.ScalarMult(new(big.Int).Neg(k), c.Neg(pt))
+	}
+	result := Infinity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = c.Add(result, result)
+		if k.Bit(i) == 1 {
+			result = c.Add(result, pt)
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package curvegen
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+func TestCountPointsKnownCurve(t *testing.T) {
+	// Exemplo classico: y^2 = x^3 + x + 1 sobre F_23 tem 28 pontos
+	c, err := NewCurve(big.NewInt(23), big.NewInt(1), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.CountPointsNaive(); got.Int64() != 28 {
+		t.Fatalf("#E = %s, esperado 28", got)
+	}
+	if _, err := NewCurve(big.NewInt(23), big.NewInt(0), big.NewInt(0)); err == nil {
+		t.Error("curva singular aceita")
+	}
+}
+
+func TestCountPointsBabyStepGiantStep(t *testing.T) {
+	// Primo de 18 bits: pequeno o bastante para comparar com a contagem
+	// ponto a ponto, grande o bastante para usar o passo de bebe
+	p := big.NewInt(262139)
+	for i := 0; i < 5; i++ {
+		c, err := RandomCurve(p, prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := c.CountPointsNaive()
+		got, err := c.CountPoints(prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("a=%s b=%s: #E = %s, esperado %s", c.A, c.B, got, want)
+		}
+		pt, err := c.RandomPoint(prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.OnCurve(pt) || !c.ScalarMult(got, pt).IsInfinity() {
+			t.Fatalf("#E*P != O para P = (%s, %s)", pt.X, pt.Y)
+		}
+	}
+}
+
+func TestCheckSupersingular(t *testing.T) {
+	// y^2 = x^3 + x sobre p = 3 mod 4 eh supersingular: #E = p + 1 e o grau
+	// de mergulho eh 2
+	c, err := NewCurve(big.NewInt(262147), big.NewInt(1), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := c.Check(prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Order.Int64() != 262148 || r.EmbeddingDegree != 2 || !r.SpecialJ || r.Safe() {
+		t.Fatalf("relatorio inesperado: %+v", r)
+	}
+}
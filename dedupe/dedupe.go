@@ -0,0 +1,199 @@
+// O pacote dedupe mantem em disco o registro dos primos ja emitidos, para
+// garantir que a geracao em lote e o servidor nao repitam um numero, mesmo
+// entre reinicializacoes.
+//
+// O arquivo guarda o SHA-256 de cada numero em registros de 32 bytes, apenas
+// acrescentados ao final. Ao abrir, os registros sao carregados em memoria
+// (ou apenas o seu inicio, veja SetIndexLimit); diferente de um filtro de
+// Bloom, a consulta eh exata (a menos de colisoes do SHA-256).
+package dedupe
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+const recordSize = sha256.Size
+
+// fullEntryBytes e compactEntryBytes estimam a memoria de cada registro no
+// indice completo e no compacto, contando o custo do mapa
+const (
+	fullEntryBytes    = 64
+	compactEntryBytes = 24
+)
+
+// indexLimit eh a memoria, em bytes, acima da qual Open usa o indice
+// compacto (0 = sem limite)
+var indexLimit atomic.Int64
+
+// SetIndexLimit limita a memoria do indice dos registros abertos depois da
+// chamada. Se o indice completo, com os 32 bytes de cada registro, passar
+// do limite, Open guarda apenas os 8 primeiros bytes de cada um e confirma
+// as coincidencias lendo o arquivo: a consulta continua exata, mas um
+// numero repetido custa uma leitura do arquivo inteiro. 0 remove o limite.
+func SetIndexLimit(maxBytes int) {
+	indexLimit.Store(int64(max(maxBytes, 0)))
+}
+
+// DB eh um registro persistente de numeros ja emitidos. Pode ser usado por
+// varias goroutines ao mesmo tempo.
+type DB struct {
+	mu   sync.Mutex
+	f    *os.File
+	seen map[[recordSize]byte]struct{} // indice completo
+	// prefixes eh o indice compacto, no lugar de seen: os 8 primeiros bytes
+	// de cada registro
+	prefixes map[uint64]struct{}
+	count    int
+}
+
+// Open abre (ou cria) o registro em path. Um registro incompleto no final
+// do arquivo, deixado por uma escrita interrompida, eh descartado.
+func Open(path string) (*DB, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	db := &DB{f: f}
+	if limit := indexLimit.Load(); limit > 0 && info.Size()/recordSize*fullEntryBytes > limit {
+		db.prefixes = make(map[uint64]struct{})
+	} else {
+		db.seen = make(map[[recordSize]byte]struct{})
+	}
+
+	var record [recordSize]byte
+	var size int64
+	r := bufio.NewReader(f)
+	for {
+		_, err := io.ReadFull(r, record[:])
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		db.index(record)
+		size += recordSize
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Compact informa se o registro usa o indice compacto (veja SetIndexLimit)
+func (db *DB) Compact() bool {
+	return db.prefixes != nil
+}
+
+// key retorna a chave de n no registro
+func key(n *big.Int) [recordSize]byte {
+	return sha256.Sum256(n.Bytes())
+}
+
+// index acrescenta k ao indice
+func (db *DB) index(k [recordSize]byte) {
+	if db.prefixes != nil {
+		db.prefixes[binary.BigEndian.Uint64(k[:])] = struct{}{}
+	} else {
+		db.seen[k] = struct{}{}
+	}
+	db.count++
+}
+
+// has informa se k esta registrado. No indice compacto, uma coincidencia
+// dos 8 primeiros bytes eh confirmada no arquivo.
+func (db *DB) has(k [recordSize]byte) (bool, error) {
+	if db.prefixes == nil {
+		_, ok := db.seen[k]
+		return ok, nil
+	}
+	if _, ok := db.prefixes[binary.BigEndian.Uint64(k[:])]; !ok {
+		return false, nil
+	}
+	if db.f == nil {
+		return false, errors.New("dedupe: registro fechado")
+	}
+	r := bufio.NewReader(io.NewSectionReader(db.f, 0, int64(db.count)*recordSize))
+	var record [recordSize]byte
+	for {
+		if _, err := io.ReadFull(r, record[:]); err != nil {
+			if err == io.EOF {
+				return false, nil
+			}
+			return false, err
+		}
+		if record == k {
+			return true, nil
+		}
+	}
+}
+
+// Contains informa se n ja foi registrado. Um erro de leitura do indice
+// compacto eh tratado como registrado, para nao emitir um numero repetido.
+func (db *DB) Contains(n *big.Int) bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	ok, err := db.has(key(n))
+	return ok || err != nil
+}
+
+// Add registra n e retorna true, ou retorna false se n ja estava
+// registrado. O registro eh gravado em disco antes de Add retornar.
+func (db *DB) Add(n *big.Int) (bool, error) {
+	k := key(n)
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.f == nil {
+		return false, errors.New("dedupe: registro fechado")
+	}
+	if ok, err := db.has(k); ok || err != nil {
+		return false, err
+	}
+	if _, err := db.f.Write(k[:]); err != nil {
+		return false, err
+	}
+	if err := db.f.Sync(); err != nil {
+		return false, err
+	}
+	db.index(k)
+	return true, nil
+}
+
+// Len retorna o numero de registros
+func (db *DB) Len() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.count
+}
+
+// Close fecha o arquivo do registro
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.f == nil {
+		return nil
+	}
+	err := db.f.Close()
+	db.f = nil
+	return err
+}
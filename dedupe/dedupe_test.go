@@ -0,0 +1,81 @@
+package dedupe
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDBPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "primos.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int64{2, 3, 5, 3} {
+		db.Add(big.NewInt(n))
+	}
+	if db.Len() != 3 {
+		t.Fatalf("Len = %d, esperado 3", db.Len())
+	}
+	db.Close()
+
+	// Simulamos uma escrita interrompida no final do arquivo
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.Write([]byte{1, 2, 3})
+	f.Close()
+
+	db, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if !db.Contains(big.NewInt(5)) || db.Contains(big.NewInt(7)) {
+		t.Fatal("conteudo nao preservado apos reabrir")
+	}
+	if added, err := db.Add(big.NewInt(3)); added || err != nil {
+		t.Errorf("Add de numero repetido = %t, %v", added, err)
+	}
+	if added, err := db.Add(big.NewInt(7)); !added || err != nil {
+		t.Errorf("Add de numero novo = %t, %v", added, err)
+	}
+	if info, _ := os.Stat(path); info.Size() != 4*recordSize {
+		t.Errorf("tamanho do arquivo = %d, esperado %d", info.Size(), 4*recordSize)
+	}
+}
+
+func TestDBCompactIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "primos.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := int64(2); n < 100; n++ {
+		db.Add(big.NewInt(n))
+	}
+	db.Close()
+
+	SetIndexLimit(1)
+	defer SetIndexLimit(0)
+	db, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if !db.Compact() {
+		t.Fatal("indice completo acima do limite")
+	}
+	if db.Len() != 98 || !db.Contains(big.NewInt(50)) || db.Contains(big.NewInt(100)) {
+		t.Fatal("consulta incorreta no indice compacto")
+	}
+	if added, err := db.Add(big.NewInt(99)); added || err != nil {
+		t.Errorf("Add de numero repetido = %t, %v", added, err)
+	}
+	if added, err := db.Add(big.NewInt(100)); !added || err != nil {
+		t.Errorf("Add de numero novo = %t, %v", added, err)
+	}
+	if !db.Contains(big.NewInt(100)) || db.Len() != 99 {
+		t.Error("numero novo nao registrado no indice compacto")
+	}
+}
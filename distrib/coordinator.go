@@ -0,0 +1,179 @@
+package distrib
+
+import (
+	"PrimeNumGenerator/pta"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// ErrNotFound indica que nenhuma janela continha um primo
+var ErrNotFound = errors.New("distrib: nenhum primo encontrado no intervalo")
+
+// Coordinator distribui as janelas de uma busca entre os Workers
+type Coordinator struct {
+	Workers []Worker
+	Window  int    // candidatos impares por janela
+	Test    string // nome do teste no registro do pta
+	// MaxWindows limita o numero de janelas da busca; 0 busca ate encontrar
+	MaxWindows int
+	// Config eh a configuracao com que o coordenador confere, com o teste
+	// Test, cada primo informado por um worker
+	Config pta.Config
+}
+
+// Summary resume uma busca
+type Summary struct {
+	Prime   *big.Int
+	Tested  int // candidatos testados por todos os workers
+	Windows int // janelas concluidas
+}
+
+// Search busca um primo a partir de start. Cada worker recebe a proxima
+// janela livre assim que termina a anterior; o primeiro primo encontrado
+// encerra a busca e cancela as janelas em andamento. Como as janelas
+// terminam fora de ordem, o primo nao eh necessariamente o menor >= start.
+// Um worker que falha eh retirado da busca e sua janela volta para a fila;
+// Search so falha se todos falharem. Um primo que nao passa no teste do
+// coordenador conta como falha do worker que o informou.
+func (c *Coordinator) Search(parent context.Context, start *big.Int) (Summary, error) {
+	return c.Resume(parent, c.NewProgress(start))
+}
+
+// Resume continua, como Search, a busca descrita por p, pulando as janelas
+// ja concluidas, e atualiza p ao terminar: se a busca for interrompida pelo
+// contexto, p pode ser gravado com Save e retomado depois. O progresso deve
+// ter a mesma janela e o mesmo teste de c. Tested e Windows do resumo
+// contam apenas a sessao atual.
+func (c *Coordinator) Resume(parent context.Context, p *Progress) (Summary, error) {
+	if len(c.Workers) == 0 || c.Window < 1 {
+		return Summary{}, errors.New("distrib: coordenador sem workers ou com janela vazia")
+	}
+	first, err := p.validate(c)
+	if err != nil {
+		return Summary{}, err
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	step := big.NewInt(2 * int64(c.Window))
+	type window struct {
+		index   int
+		scanned int // candidatos da janela examinados em sessoes anteriores
+		task    Task
+	}
+	var (
+		mu      sync.Mutex
+		next    = p.Next
+		issued  int
+		retry   []window // janelas de workers que falharam
+		summary Summary
+		errs    []error
+		wg      sync.WaitGroup
+	)
+	// task reserva a proxima janela; retorna false se a busca acabou
+	task := func() (window, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if summary.Prime != nil {
+			return window{}, false
+		}
+		if len(retry) > 0 {
+			w := retry[len(retry)-1]
+			retry = retry[:len(retry)-1]
+			return w, true
+		}
+		if c.MaxWindows > 0 && issued >= c.MaxWindows {
+			return window{}, false
+		}
+		for p.skip(next) {
+			next++
+		}
+		scanned := p.Partial[next]
+		start := new(big.Int).Mul(step, big.NewInt(int64(next)))
+		start.Add(start, first)
+		start.Add(start, big.NewInt(2*int64(scanned)))
+		w := window{index: next, scanned: scanned, task: Task{Start: start.Text(16), Count: c.Window - scanned, Test: c.Test}}
+		next++
+		issued++
+		return w, true
+	}
+
+	for _, w := range c.Workers {
+		wg.Add(1)
+		go func(w Worker) {
+			defer wg.Done()
+			for {
+				t, ok := task()
+				if !ok {
+					return
+				}
+				found, err := w.Search(ctx, t.task)
+				var prime *big.Int
+				if err == nil && found.Prime != "" {
+					prime, err = c.verify(found.Prime)
+				}
+				mu.Lock()
+				summary.Tested += found.Tested
+				p.Tested += found.Tested
+				switch {
+				case ctx.Err() != nil:
+					// Busca encerrada por outro worker ou pelo chamador
+					p.interrupt(t.index, t.scanned+found.Scanned)
+				case err != nil:
+					errs = append(errs, err)
+					retry = append(retry, t)
+				default:
+					summary.Windows++
+					if prime == nil {
+						p.complete(t.index)
+					}
+					if prime != nil && summary.Prime == nil {
+						summary.Prime = prime
+						cancel()
+					}
+				}
+				mu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if summary.Prime != nil {
+		return summary, nil
+	}
+	if err := parent.Err(); err != nil {
+		return summary, err
+	}
+	if len(errs) == len(c.Workers) {
+		return summary, errors.Join(errs...)
+	}
+	return summary, ErrNotFound
+}
+
+// verify confere o primo, em hexadecimal, que um worker diz ter encontrado.
+// Os workers remotos nao sao confiaveis: um defeituoso ou malicioso poderia
+// devolver um composto, que terminaria a busca como se fosse primo.
+func (c *Coordinator) verify(text string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(text, 16)
+	if !ok || n.Sign() <= 0 {
+		return nil, fmt.Errorf("distrib: primo invalido %q informado pelo worker", text)
+	}
+	test, err := pta.Get(c.Test)
+	if err != nil {
+		return nil, err
+	}
+	res := test.IsPrime(n, c.Config)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	if !res.Prime {
+		return nil, fmt.Errorf("distrib: o worker informou %s, que nao passou no teste %s", n, test.Name())
+	}
+	return n, nil
+}
@@ -0,0 +1,89 @@
+// O pacote distrib distribui a busca por primos grandes entre varias
+// maquinas. Um coordenador divide o intervalo de candidatos em janelas de
+// numeros impares consecutivos e as entrega aos workers, que as varrem com
+// um teste de primalidade. Quando um worker encontra um primo, o
+// coordenador cancela o trabalho em andamento nos demais.
+//
+// O protocolo eh JSON sobre HTTP: o coordenador envia um Task em
+// POST /search e o worker responde com um Found. Cancelar a requisicao
+// interrompe a varredura no worker.
+package distrib
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/pta"
+	"context"
+	"errors"
+	"math/big"
+)
+
+// Task eh uma janela de candidatos: os Count impares a partir de Start
+type Task struct {
+	Start string `json:"start"` // primeiro candidato, em hexadecimal
+	Count int    `json:"count"`
+	Test  string `json:"test"` // nome do teste no registro do pta
+}
+
+// Found eh o resultado da varredura de uma janela
+type Found struct {
+	Prime  string `json:"prime,omitempty"` // primo encontrado, em hexadecimal; vazio se nao houver
+	Tested int    `json:"tested"`          // candidatos testados
+
+	// Scanned eh o numero de candidatos da janela ja examinados quando a
+	// varredura foi cancelada, para que ela seja retomada do seguinte
+	Scanned int `json:"scanned,omitempty"`
+}
+
+// Worker varre janelas de candidatos
+type Worker interface {
+	Search(ctx context.Context, t Task) (Found, error)
+}
+
+// Local eh um Worker que varre as janelas no proprio processo com a
+// configuracao Config. Os filtros de pta.Generate (suavidade, padroes
+// fracos, Unique) nao sao aplicados; os primos encontrados devem passar
+// por eles no coordenador, se necessario.
+type Local struct {
+	Config pta.Config
+}
+
+// Search implementa Worker
+func (l Local) Search(ctx context.Context, t Task) (Found, error) {
+	n, ok := new(big.Int).SetString(t.Start, 16)
+	if !ok || n.Sign() <= 0 {
+		return Found{}, errors.New("distrib: inicio da janela invalido")
+	}
+	if t.Count < 1 {
+		return Found{}, errors.New("distrib: janela vazia")
+	}
+	test, err := pta.Get(t.Test)
+	if err != nil {
+		return Found{}, err
+	}
+	if n.Bit(0) == 0 {
+		n.Add(n, big.NewInt(1))
+	}
+
+	var found Found
+	two := big.NewInt(2)
+	for i := 0; i < t.Count; i++ {
+		if err := ctx.Err(); err != nil {
+			found.Scanned = i
+			return found, err
+		}
+		// Pre-filtro por mdc, como em pta.Generate
+		if !numutil.HasSmallFactor(n, l.Config.PrescreenPrimes()) {
+			found.Tested++
+			res := test.IsPrime(n, l.Config)
+			if res.Err != nil {
+				return found, res.Err
+			}
+			if res.Prime {
+				found.Prime = n.Text(16)
+				return found, nil
+			}
+		}
+		n.Add(n, two)
+	}
+	return found, nil
+}
@@ -0,0 +1,212 @@
+package distrib
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// blocking eh um worker que so retorna quando a busca eh cancelada
+type blocking struct{ started, canceled chan struct{} }
+
+func (b blocking) Search(ctx context.Context, t Task) (Found, error) {
+	close(b.started)
+	<-ctx.Done()
+	close(b.canceled)
+	return Found{}, ctx.Err()
+}
+
+// finder eh um worker que encontra um primo assim que o bloqueado comeca
+type finder struct{ started chan struct{} }
+
+func (f finder) Search(ctx context.Context, t Task) (Found, error) {
+	<-f.started
+	return Found{Prime: "f4243", Tested: 1}, nil // 1000003
+}
+
+// failing eh um worker que sempre falha
+type failing struct{}
+
+func (failing) Search(ctx context.Context, t Task) (Found, error) {
+	return Found{}, errors.New("worker indisponivel")
+}
+
+// lying eh um worker que informa um composto como primo
+type lying struct{}
+
+func (lying) Search(ctx context.Context, t Task) (Found, error) {
+	return Found{Prime: "f4241", Tested: 1}, nil // 1000001 = 101 * 9901
+}
+
+// empty eh um worker que nunca encontra primos
+type empty struct{}
+
+func (empty) Search(ctx context.Context, t Task) (Found, error) {
+	return Found{Tested: t.Count}, nil
+}
+
+func TestCoordinatorRemote(t *testing.T) {
+	var workers []Worker
+	for i := 0; i < 3; i++ {
+		srv := httptest.NewServer(Handler(Local{}))
+		defer srv.Close()
+		workers = append(workers, Remote{URL: srv.URL})
+	}
+	workers = append(workers, failing{})
+
+	c := Coordinator{Workers: workers, Window: 64, Test: "miller-rabin"}
+	start := new(big.Int).Lsh(big.NewInt(1), 255)
+	s, err := c.Search(context.Background(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Prime.ProbablyPrime(32) || s.Prime.Cmp(start) < 0 || s.Tested == 0 {
+		t.Fatalf("resultado invalido: %+v", s)
+	}
+}
+
+func TestCoordinatorCancelsOutstanding(t *testing.T) {
+	b := blocking{started: make(chan struct{}), canceled: make(chan struct{})}
+	c := Coordinator{Workers: []Worker{b, finder{started: b.started}}, Window: 64, Test: "miller-rabin"}
+	s, err := c.Search(context.Background(), big.NewInt(1_000_000))
+	if err != nil || s.Prime.Int64() != 1_000_003 {
+		t.Fatalf("Search = %+v, %v", s, err)
+	}
+	select {
+	case <-b.canceled:
+	default:
+		t.Fatal("janela em andamento nao foi cancelada")
+	}
+}
+
+func TestCoordinatorErrors(t *testing.T) {
+	c := Coordinator{Workers: []Worker{empty{}}, Window: 8, Test: "miller-rabin", MaxWindows: 4}
+	s, err := c.Search(context.Background(), big.NewInt(1_000_000))
+	if !errors.Is(err, ErrNotFound) || s.Windows != 4 || s.Tested != 32 {
+		t.Fatalf("Search = %+v, %v; esperado ErrNotFound apos 4 janelas", s, err)
+	}
+
+	c = Coordinator{Workers: []Worker{failing{}, failing{}}, Window: 8, Test: "miller-rabin"}
+	if _, err := c.Search(context.Background(), big.NewInt(1_000_000)); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("todos os workers falharam, mas Search retornou %v", err)
+	}
+
+	// O composto do worker mentiroso eh recusado e a janela dele volta
+	// para a fila, onde o worker honesto a varre
+	c = Coordinator{Workers: []Worker{lying{}}, Window: 8, Test: "miller-rabin"}
+	if s, err := c.Search(context.Background(), big.NewInt(1_000_000)); err == nil {
+		t.Fatalf("composto do worker aceito: %+v", s)
+	}
+	c = Coordinator{Workers: []Worker{lying{}, Local{}}, Window: 64, Test: "miller-rabin"}
+	s, err = c.Search(context.Background(), big.NewInt(1_000_000))
+	if err != nil || !s.Prime.ProbablyPrime(32) {
+		t.Fatalf("Search = %+v, %v", s, err)
+	}
+}
+
+func TestRemoteRejectsInvalidTask(t *testing.T) {
+	srv := httptest.NewServer(Handler(Local{}))
+	defer srv.Close()
+	if _, err := (Remote{URL: srv.URL}).Search(context.Background(), Task{Start: "zz", Count: 1, Test: "miller-rabin"}); err == nil {
+		t.Error("janela invalida aceita")
+	}
+}
+
+func TestCoordinatorResume(t *testing.T) {
+	// Primeira sessao: quatro janelas sem primo, como se a busca tivesse
+	// sido interrompida depois delas
+	c := Coordinator{Workers: []Worker{empty{}}, Window: 8, Test: "miller-rabin", MaxWindows: 4}
+	p := c.NewProgress(big.NewInt(1_000_000))
+	if _, err := c.Resume(context.Background(), p); !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+	if p.Next != 4 || p.Done != nil || p.Tested != 32 {
+		t.Fatalf("progresso = %+v; esperadas 4 janelas concluidas", p)
+	}
+	path := filepath.Join(t.TempDir(), "progress.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = Coordinator{Workers: []Worker{Local{}}, Window: 8, Test: "miller-rabin"}
+	s, err := c.Resume(context.Background(), loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A busca continua do candidato 1000001 + 2*8*4
+	want := big.NewInt(1_000_065)
+	for !want.ProbablyPrime(32) {
+		want.Add(want, big.NewInt(2))
+	}
+	if s.Prime.Cmp(want) != 0 || loaded.Tested != 32+s.Tested {
+		t.Fatalf("Resume = %+v, progresso %+v; esperado o primo %s", s, loaded, want)
+	}
+
+	c.Window = 16
+	if _, err := c.Resume(context.Background(), loaded); err == nil {
+		t.Error("progresso com outra janela aceito")
+	}
+}
+
+// interrupted eh um worker que examina scanned candidatos e interrompe a
+// busca, como um SIGINT
+type interrupted struct {
+	scanned int
+	cancel  context.CancelFunc
+}
+
+func (w interrupted) Search(ctx context.Context, t Task) (Found, error) {
+	w.cancel()
+	return Found{Scanned: w.scanned}, ctx.Err()
+}
+
+// recorder eh um worker que registra a primeira janela recebida e para
+type recorder struct{ tasks chan Task }
+
+func (r recorder) Search(ctx context.Context, t Task) (Found, error) {
+	r.tasks <- t
+	return Found{Prime: "f4243", Tested: 1}, nil
+}
+
+func TestCoordinatorResumePartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := Coordinator{Workers: []Worker{interrupted{scanned: 3, cancel: cancel}}, Window: 8, Test: "miller-rabin"}
+	p := c.NewProgress(big.NewInt(1_000_000))
+	if _, err := c.Resume(ctx, p); !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if p.Next != 0 || p.Partial[0] != 3 {
+		t.Fatalf("progresso = %+v; esperados 3 candidatos examinados na janela 0", p)
+	}
+
+	r := recorder{tasks: make(chan Task, 1)}
+	c.Workers = []Worker{r}
+	if _, err := c.Resume(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	if task := <-r.tasks; task.Start != big.NewInt(1_000_007).Text(16) || task.Count != 5 {
+		t.Fatalf("janela retomada = %+v; esperado o candidato 1000007", task)
+	}
+}
+
+func TestProgressComplete(t *testing.T) {
+	p := &Progress{}
+	for _, i := range []int{2, 0, 3, 5} {
+		p.complete(i)
+	}
+	if p.Next != 1 || !slices.Equal(p.Done, []int{2, 3, 5}) || !p.skip(3) || p.skip(4) {
+		t.Fatalf("progresso = %+v", p)
+	}
+	p.complete(1)
+	if p.Next != 4 || !slices.Equal(p.Done, []int{5}) {
+		t.Fatalf("progresso = %+v", p)
+	}
+}
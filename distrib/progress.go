@@ -0,0 +1,142 @@
+package distrib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ProgressVersion eh a versao do formato gravado por Progress.Save
+const ProgressVersion = 1
+
+// Progress eh o progresso de uma busca, que permite retoma-la exatamente de
+// onde parou, por exemplo depois de um SIGINT: as janelas sao numeradas a
+// partir de Start, a janela i comeca em Start + 2*Window*i, as concluidas
+// sem primo sao puladas ao retomar e as interrompidas continuam do
+// candidato seguinte ao ultimo examinado. Os workers remotos nao informam
+// ate onde chegaram, entao suas janelas interrompidas sao varridas de novo.
+type Progress struct {
+	Version int    `json:"version"`
+	Start   string `json:"start"` // primeiro candidato, impar, em hexadecimal
+	Window  int    `json:"window"`
+	Test    string `json:"test"`
+	Bits    int    `json:"bits,omitempty"` // tamanho buscado, para conferencia de quem retoma
+
+	Next    int         `json:"next"`              // as janelas 0 a Next-1 foram concluidas
+	Done    []int       `json:"done,omitempty"`    // janelas depois de Next ja concluidas, em ordem
+	Partial map[int]int `json:"partial,omitempty"` // candidatos ja examinados das janelas interrompidas
+	Tested  int         `json:"tested"`            // candidatos testados em todas as sessoes
+}
+
+// NewProgress cria o progresso de uma busca nova a partir de start, com a
+// janela e o teste de c
+func (c *Coordinator) NewProgress(start *big.Int) *Progress {
+	first := new(big.Int).Set(start)
+	if first.Bit(0) == 0 {
+		first.Add(first, big.NewInt(1))
+	}
+	return &Progress{Version: ProgressVersion, Start: first.Text(16), Window: c.Window, Test: c.Test}
+}
+
+// validate confere se o progresso pode ser retomado pelo coordenador c
+func (p *Progress) validate(c *Coordinator) (*big.Int, error) {
+	if p.Version != ProgressVersion {
+		return nil, fmt.Errorf("distrib: versao do progresso %d, esperada %d", p.Version, ProgressVersion)
+	}
+	first, ok := new(big.Int).SetString(p.Start, 16)
+	if !ok || first.Sign() <= 0 || first.Bit(0) == 0 {
+		return nil, errors.New("distrib: inicio do progresso invalido")
+	}
+	if p.Window != c.Window || p.Test != c.Test {
+		return nil, fmt.Errorf("distrib: progresso de uma busca com janela %d e teste %q", p.Window, p.Test)
+	}
+	if p.Next < 0 || !slices.IsSorted(p.Done) || len(p.Done) > 0 && p.Done[0] <= p.Next {
+		return nil, errors.New("distrib: janelas concluidas do progresso invalidas")
+	}
+	for i, scanned := range p.Partial {
+		if i < 0 || scanned < 0 || scanned >= p.Window {
+			return nil, errors.New("distrib: janelas interrompidas do progresso invalidas")
+		}
+	}
+	return first, nil
+}
+
+// LoadProgress le o progresso gravado por Save
+func LoadProgress(path string) (*Progress, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Progress)
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("distrib: progresso invalido em %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save grava o progresso em path, por um arquivo temporario renomeado, para
+// que uma interrupcao durante a gravacao nao corrompa o progresso anterior
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// complete marca a janela i como concluida, avancando Next sobre as janelas
+// concluidas em sequencia
+func (p *Progress) complete(i int) {
+	delete(p.Partial, i)
+	if i < p.Next {
+		return
+	}
+	if pos, found := slices.BinarySearch(p.Done, i); !found {
+		p.Done = slices.Insert(p.Done, pos, i)
+	}
+	for len(p.Done) > 0 && p.Done[0] == p.Next {
+		p.Done = p.Done[1:]
+		p.Next++
+	}
+	if len(p.Done) == 0 {
+		p.Done = nil
+	}
+}
+
+// interrupt registra que os primeiros scanned candidatos da janela i foram
+// examinados
+func (p *Progress) interrupt(i, scanned int) {
+	if scanned == 0 || p.skip(i) {
+		return
+	}
+	if p.Partial == nil {
+		p.Partial = make(map[int]int)
+	}
+	p.Partial[i] = scanned
+}
+
+// skip informa se a janela i ja foi concluida
+func (p *Progress) skip(i int) bool {
+	_, found := slices.BinarySearch(p.Done, i)
+	return i < p.Next || found
+}
@@ -0,0 +1,86 @@
+package distrib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// maxTaskCount limita o tamanho das janelas aceitas pelo servidor do worker
+const maxTaskCount = 1 << 20
+
+// Handler expoe o worker w em POST /search
+func Handler(w Worker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /search", func(rw http.ResponseWriter, r *http.Request) {
+		var t Task
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, 1<<20)).Decode(&t); err != nil {
+			writeJSON(rw, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if t.Count > maxTaskCount {
+			writeJSON(rw, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("janela acima de %d candidatos", maxTaskCount)})
+			return
+		}
+		// O contexto da requisicao eh cancelado quando o coordenador desiste
+		found, err := w.Search(r.Context(), t)
+		if err != nil {
+			writeJSON(rw, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(rw, http.StatusOK, found)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Remote eh um Worker acessado pela rede, no endereco base URL
+// (ex.: "http://10.0.0.2:9090")
+type Remote struct {
+	URL    string
+	Client *http.Client // http.DefaultClient se nil
+}
+
+// Search implementa Worker
+func (rw Remote) Search(ctx context.Context, t Task) (Found, error) {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return Found{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rw.URL+"/search", bytes.NewReader(body))
+	if err != nil {
+		return Found{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := rw.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Found{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct{ Error string }
+		json.NewDecoder(resp.Body).Decode(&e)
+		if e.Error == "" {
+			e.Error = resp.Status
+		}
+		return Found{}, fmt.Errorf("distrib: worker %s: %s", rw.URL, e.Error)
+	}
+	var found Found
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return Found{}, errors.Join(fmt.Errorf("distrib: resposta invalida do worker %s", rw.URL), err)
+	}
+	return found, nil
+}
@@ -0,0 +1,179 @@
+// O pacote dlog resolve logaritmos discretos em grupos pequenos, para
+// mostrar em aula por que o tamanho dos parametros importa: dado h = g^x
+// mod p, os algoritmos genericos encontram x com cerca de sqrt(q) operacoes,
+// onde q eh a ordem de g. Nos grupos de primos seguros do pacote group,
+// q = (p - 1)/2 tem quase o tamanho de p, e cada bit a mais em p multiplica
+// o trabalho por sqrt(2).
+//
+// Dois algoritmos sao oferecidos: o passo de bebe/passo de gigante de
+// Shanks (BabyStepGiantStep), deterministico, mas com memoria proporcional
+// a sqrt(q), e o rho de Pollard (PollardRho), probabilistico e com memoria
+// constante. Nenhum dos dois serve para os grupos de verdade: o objetivo
+// eh medir e extrapolar, nao atacar.
+package dlog
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// MaxBabyStepBits eh o maior tamanho, em bits, da ordem aceita por
+// BabyStepGiantStep: a tabela dos passos de bebe tem sqrt(q) entradas, ou
+// cerca de um milhao em 40 bits
+const MaxBabyStepBits = 40
+
+var (
+	// ErrNoLog indica que h nao eh uma potencia de g
+	ErrNoLog = errors.New("dlog: h nao eh uma potencia de g")
+	// ErrTooLarge indica que a ordem passa do limite do algoritmo
+	ErrTooLarge = errors.New("dlog: ordem grande demais")
+)
+
+// Result eh a solucao de um logaritmo discreto
+type Result struct {
+	X          *big.Int // g^X = h (mod p), com 0 <= X < q
+	Operations int      // multiplicacoes modulares realizadas
+}
+
+// checkOrder confere que g e h estao no subgrupo de ordem q de Z_p*
+func checkOrder(g, h, p, q *big.Int) error {
+	one := big.NewInt(1)
+	if q.Sign() <= 0 || new(big.Int).Exp(g, q, p).Cmp(one) != 0 {
+		return fmt.Errorf("dlog: g nao tem ordem dividindo %s", q)
+	}
+	if new(big.Int).Exp(h, q, p).Cmp(one) != 0 {
+		return ErrNoLog
+	}
+	return nil
+}
+
+// BabyStepGiantStep encontra x com g^x = h (mod p), sabendo que a ordem de
+// g divide q, pelo passo de bebe/passo de gigante: com m = teto(sqrt(q)),
+// guarda g^j para 0 <= j < m (passos de bebe) e procura h*g^(-im) na
+// tabela para i = 0, 1, ... (passos de gigante); x = im + j. Faz no maximo
+// 2m multiplicacoes e guarda m numeros.
+func BabyStepGiantStep(g, h, p, q *big.Int) (Result, error) {
+	if q.BitLen() > MaxBabyStepBits {
+		return Result{}, fmt.Errorf("%w: %d bits, o limite eh %d", ErrTooLarge, q.BitLen(), MaxBabyStepBits)
+	}
+	if err := checkOrder(g, h, p, q); err != nil {
+		return Result{}, err
+	}
+	m := new(big.Int).Sqrt(q)
+	if new(big.Int).Mul(m, m).Cmp(q) < 0 {
+		m.Add(m, big.NewInt(1))
+	}
+	steps := m.Int64()
+
+	// A chave da tabela sao os bytes do valor
+	table := make(map[string]int64, steps)
+	ops := 0
+	x := big.NewInt(1)
+	for j := int64(0); j < steps; j++ {
+		if _, ok := table[string(x.Bytes())]; !ok {
+			table[string(x.Bytes())] = j
+		}
+		x.Mul(x, g).Mod(x, p)
+		ops++
+	}
+
+	// factor = g^(-m) = g^(q - m mod q)
+	e := new(big.Int).Sub(q, new(big.Int).Mod(m, q))
+	factor := new(big.Int).Exp(g, e, p)
+	gamma := new(big.Int).Mod(h, p)
+	for i := int64(0); i <= steps; i++ {
+		if j, ok := table[string(gamma.Bytes())]; ok {
+			x := big.NewInt(i)
+			x.Mul(x, m).Add(x, big.NewInt(j)).Mod(x, q)
+			return Result{X: x, Operations: ops}, nil
+		}
+		gamma.Mul(gamma, factor).Mod(gamma, p)
+		ops++
+	}
+	return Result{}, ErrNoLog
+}
+
+// maxRestarts limita os recomecos do rho de Pollard
+const maxRestarts = 32
+
+// PollardRho encontra x com g^x = h (mod p), sabendo que g tem ordem prima
+// q, pelo rho de Pollard: o passeio y -> y*h, y^2 ou y*g, escolhido por
+// y mod 3, mantem y = g^a h^b; quando o passeio entra em ciclo (detectado
+// pelo algoritmo de Floyd, com uma tartaruga e uma lebre duas vezes mais
+// rapida), a + bx = a' + b'x (mod q) da x. Espera-se cerca de sqrt(q)
+// passos, com memoria constante; o inicio do passeio eh sorteado com e, e
+// a busca recomeca se b = b'.
+func PollardRho(g, h, p, q *big.Int, e prng.Entropy) (Result, error) {
+	if !q.ProbablyPrime(20) {
+		return Result{}, errors.New("dlog: o rho de Pollard exige ordem prima")
+	}
+	if err := checkOrder(g, h, p, q); err != nil {
+		return Result{}, err
+	}
+	if new(big.Int).Mod(h, p).Cmp(big.NewInt(1)) == 0 {
+		return Result{X: new(big.Int)}, nil
+	}
+
+	ops := 0
+	three := big.NewInt(3)
+	r := new(big.Int)
+	// step avanca um passo do passeio
+	step := func(w *walk) {
+		switch r.Mod(w.y, three).Int64() {
+		case 0:
+			w.y.Mul(w.y, h).Mod(w.y, p)
+			w.b.Add(w.b, big.NewInt(1)).Mod(w.b, q)
+		case 1:
+			w.y.Mul(w.y, w.y).Mod(w.y, p)
+			w.a.Lsh(w.a, 1).Mod(w.a, q)
+			w.b.Lsh(w.b, 1).Mod(w.b, q)
+		default:
+			w.y.Mul(w.y, g).Mod(w.y, p)
+			w.a.Add(w.a, big.NewInt(1)).Mod(w.a, q)
+		}
+		ops++
+	}
+
+	for range maxRestarts {
+		a, err := e.Int(q)
+		if err != nil {
+			return Result{}, err
+		}
+		b, err := e.Int(q)
+		if err != nil {
+			return Result{}, err
+		}
+		y := new(big.Int).Exp(g, a, p)
+		y.Mul(y, new(big.Int).Exp(h, b, p)).Mod(y, p)
+		tortoise := walk{y, a, b}
+		hare := walk{new(big.Int).Set(y), new(big.Int).Set(a), new(big.Int).Set(b)}
+		for {
+			step(&tortoise)
+			step(&hare)
+			step(&hare)
+			if tortoise.y.Cmp(hare.y) == 0 {
+				break
+			}
+		}
+
+		// x (b - b') = a' - a (mod q)
+		db := new(big.Int).Sub(tortoise.b, hare.b)
+		db.Mod(db, q)
+		if db.Sign() == 0 {
+			continue
+		}
+		x := new(big.Int).Sub(hare.a, tortoise.a)
+		x.Mul(x, db.ModInverse(db, q)).Mod(x, q)
+		if new(big.Int).Exp(g, x, p).Cmp(new(big.Int).Mod(h, p)) == 0 {
+			return Result{X: x, Operations: ops}, nil
+		}
+	}
+	return Result{}, fmt.Errorf("dlog: o rho de Pollard nao convergiu em %d tentativas", maxRestarts)
+}
+
+// walk eh um ponto do passeio do rho de Pollard: y = g^a h^b
+type walk struct {
+	y, a, b *big.Int
+}
@@ -0,0 +1,62 @@
+package dlog
+
+import (
+	"PrimeNumGenerator/group"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSolvers(t *testing.T) {
+	for _, bits := range []int{12, 24, 36} {
+		p, q, err := group.GenerateSafePrime(bits, prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, g, err := group.FindGenerator(p, prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, err := prng.Entropy{}.Int(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := new(big.Int).Exp(g, x, p)
+
+		bsgs, err := BabyStepGiantStep(g, h, p, q)
+		if err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		rho, err := PollardRho(g, h, p, q, prng.Entropy{})
+		if err != nil {
+			t.Fatalf("%d bits: %v", bits, err)
+		}
+		for name, res := range map[string]Result{"bsgs": bsgs, "rho": rho} {
+			if res.X.Cmp(x) != 0 {
+				t.Errorf("%d bits, %s: x = %s, esperado %s", bits, name, res.X, x)
+			}
+			// Ambos fazem da ordem de sqrt(q) operacoes
+			if limit := 16 * (1 << (q.BitLen()/2 + 1)); res.Operations > limit {
+				t.Errorf("%d bits, %s: %d operacoes", bits, name, res.Operations)
+			}
+		}
+
+		// Um elemento fora do subgrupo (um nao residuo) nao tem logaritmo
+		nonResidue := big.NewInt(2)
+		for group.InSubgroup(p, nonResidue) {
+			nonResidue.Add(nonResidue, big.NewInt(1))
+		}
+		if _, err := BabyStepGiantStep(g, nonResidue, p, q); !errors.Is(err, ErrNoLog) {
+			t.Errorf("%d bits: erro %v para h fora do subgrupo", bits, err)
+		}
+		if _, err := PollardRho(g, nonResidue, p, q, prng.Entropy{}); !errors.Is(err, ErrNoLog) {
+			t.Errorf("%d bits: erro %v para h fora do subgrupo", bits, err)
+		}
+	}
+
+	big41 := new(big.Int).Lsh(big.NewInt(1), MaxBabyStepBits)
+	if _, err := BabyStepGiantStep(big.NewInt(2), big.NewInt(4), big41, big41); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ordem de %d bits: erro %v", big41.BitLen(), err)
+	}
+}
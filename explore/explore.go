@@ -0,0 +1,157 @@
+// O pacote explore verifica numericamente duas conjecturas classicas sobre
+// os primos, com fins educacionais: a de Goldbach (todo par maior que 2 eh
+// soma de dois primos) e a dos primos gemeos (ha infinitos pares p, p + 2
+// de primos), comparando a contagem de gemeos com a estimativa de
+// Hardy-Littlewood. Os primos vem do crivo segmentado de numutil.
+package explore
+
+import (
+	"PrimeNumGenerator/numutil"
+	"fmt"
+	"math"
+)
+
+// MaxGoldbach eh o maior numero aceito por Goldbach, que guarda em memoria
+// um bit para cada impar ate o limite (64 MiB no maximo)
+const MaxGoldbach = 1 << 30
+
+// MaxBuckets eh o maior numero de intervalos aceito por Twins
+const MaxBuckets = 10000
+
+// TwinConstant eh a constante dos primos gemeos C2, o produto de
+// p(p - 2)/(p - 1)^2 sobre os primos impares
+const TwinConstant = 0.6601618158468696
+
+// Partition descreve as particoes de Goldbach de um numero par N
+type Partition struct {
+	N     uint64
+	Count int    // numero de somas N = p + q com p <= q primos
+	Least uint64 // menor p das somas (0 se nao houver nenhuma)
+}
+
+// Goldbach calcula as particoes de Goldbach dos pares em [from, to] (a
+// partir de 4). Um Count zero seria um contraexemplo da conjectura.
+func Goldbach(from, to uint64) ([]Partition, error) {
+	from = max(from, 4)
+	from += from % 2
+	if to > MaxGoldbach {
+		return nil, fmt.Errorf("explore: limite maximo de %d", uint64(MaxGoldbach))
+	}
+	if from > to {
+		return nil, fmt.Errorf("explore: intervalo sem pares maiores que 2: [%d, %d]", from, to)
+	}
+
+	// odd[i] guarda se 2i + 1 eh primo
+	odd := make([]uint64, to/128+1)
+	for p := range numutil.SegmentedSieve(3, to) {
+		i := p / 2
+		odd[i/64] |= 1 << (i % 64)
+	}
+	isOddPrime := func(n uint64) bool {
+		i := n / 2
+		return odd[i/64]&(1<<(i%64)) != 0
+	}
+
+	partitions := make([]Partition, 0, (to-from)/2+1)
+	for n := from; n <= to; n += 2 {
+		part := Partition{N: n}
+		if n == 4 {
+			part.Count, part.Least = 1, 2 // 2 + 2, a unica soma com o primo par
+		}
+		for p := uint64(3); p <= n/2; p += 2 {
+			if isOddPrime(p) && isOddPrime(n-p) {
+				if part.Count == 0 {
+					part.Least = p
+				}
+				part.Count++
+			}
+		}
+		partitions = append(partitions, part)
+	}
+	return partitions, nil
+}
+
+// TwinStats resume os primos gemeos de um intervalo
+type TwinStats struct {
+	Lo, Hi uint64 // intervalo [Lo, Hi]
+	Primes int    // primos no intervalo
+	Twins  int    // pares de gemeos (p, p + 2) com p no intervalo
+	// Expected eh a estimativa de Hardy-Littlewood para Twins, a integral
+	// de 2*C2/ln(x)^2 sobre o intervalo
+	Expected float64
+}
+
+// Twins conta os primos e os primos gemeos em [from, to], dividido em
+// buckets intervalos de mesmo tamanho, para acompanhar como a densidade
+// dos gemeos cai com o tamanho dos numeros.
+func Twins(from, to uint64, buckets int) ([]TwinStats, error) {
+	from = max(from, 2)
+	if to+2 > numutil.MaxSieve {
+		return nil, fmt.Errorf("explore: limite maximo de %d", uint64(numutil.MaxSieve-2))
+	}
+	if from > to {
+		return nil, fmt.Errorf("explore: intervalo vazio: [%d, %d]", from, to)
+	}
+	if buckets < 1 || buckets > MaxBuckets {
+		return nil, fmt.Errorf("explore: numero de intervalos invalido: %d", buckets)
+	}
+	size := to - from + 1
+	buckets = int(min(uint64(buckets), size))
+
+	stats := make([]TwinStats, buckets)
+	for i := range stats {
+		stats[i].Lo = from + uint64(i)*size/uint64(buckets)
+		stats[i].Hi = from + uint64(i+1)*size/uint64(buckets) - 1
+		stats[i].Expected = 2 * TwinConstant * integral(stats[i].Lo, stats[i].Hi+1)
+	}
+
+	// Vamos ate to + 2 para ver o par dos gemeos que comecam em to - 1 e to.
+	// Como os primos chegam em ordem, o indice do intervalo so cresce.
+	bucket := 0
+	var prev uint64
+	for p := range numutil.SegmentedSieve(from, to+2) {
+		if prev != 0 && prev <= to && p-prev == 2 {
+			stats[bucketOf(stats, &bucket, prev)].Twins++
+		}
+		if p <= to {
+			stats[bucketOf(stats, &bucket, p)].Primes++
+		}
+		prev = p
+	}
+	return stats, nil
+}
+
+// bucketOf retorna o indice do intervalo de n, procurando a partir de *from
+func bucketOf(stats []TwinStats, from *int, n uint64) int {
+	for stats[*from].Hi < n {
+		*from++
+	}
+	return *from
+}
+
+// integral aproxima a integral de 1/ln(x)^2 em [a, b] pela regra de
+// Simpson, em trechos que dobram de tamanho: dentro de cada um a funcao
+// varia pouco, mesmo perto de 2.
+func integral(a, b uint64) float64 {
+	const steps = 64 // par
+	f := func(x float64) float64 {
+		l := math.Log(x)
+		return 1 / (l * l)
+	}
+	total := 0.0
+	for x0, end := float64(a), float64(b); x0 < end; {
+		x1 := min(2*x0, end)
+		h := (x1 - x0) / steps
+		sum := f(x0) + f(x1)
+		for i := 1; i < steps; i++ {
+			w := 2.0
+			if i%2 == 1 {
+				w = 4
+			}
+			sum += w * f(x0+float64(i)*h)
+		}
+		total += sum * h / 3
+		x0 = x1
+	}
+	return total
+}
@@ -0,0 +1,96 @@
+package explore
+
+import (
+	"PrimeNumGenerator/numutil"
+	"math"
+	"testing"
+)
+
+func TestGoldbach(t *testing.T) {
+	parts, err := Goldbach(3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 49 || parts[0].N != 4 || parts[48].N != 100 {
+		t.Fatalf("%d particoes, de %d a %d", len(parts), parts[0].N, parts[len(parts)-1].N)
+	}
+	// Valores conhecidos (OEIS A045917)
+	want := map[uint64]int{4: 1, 6: 1, 8: 1, 10: 2, 12: 1, 14: 2, 16: 2, 18: 2, 20: 2, 22: 3, 100: 6}
+	for _, p := range parts {
+		if c, ok := want[p.N]; ok && p.Count != c {
+			t.Errorf("%d: %d particoes, esperadas %d", p.N, p.Count, c)
+		}
+		if p.Count == 0 {
+			t.Errorf("contraexemplo de Goldbach: %d", p.N)
+		}
+	}
+	if parts[48].Least != 3 { // 100 = 3 + 97
+		t.Errorf("menor parcela de 100 = %d", parts[48].Least)
+	}
+
+	if _, err := Goldbach(10, 8); err == nil {
+		t.Error("intervalo vazio aceito")
+	}
+	if _, err := Goldbach(4, MaxGoldbach+2); err == nil {
+		t.Error("limite acima de MaxGoldbach aceito")
+	}
+}
+
+func TestTwins(t *testing.T) {
+	stats, err := Twins(1, 1000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primes, twins := 0, 0
+	for _, s := range stats {
+		primes += s.Primes
+		twins += s.Twins
+	}
+	// 168 primos e 35 pares de gemeos abaixo de 1000
+	if primes != 168 || twins != 35 {
+		t.Errorf("%d primos e %d gemeos ate 1000, esperados 168 e 35", primes, twins)
+	}
+	if stats[0].Lo != 2 || stats[3].Hi != 1000 {
+		t.Errorf("intervalos de %d a %d", stats[0].Lo, stats[3].Hi)
+	}
+
+	// Os gemeos que cruzam a fronteira dos intervalos: (881, 883) com
+	// p = 881 no intervalo [2, 881]
+	edge, err := Twins(2, 881, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge[0].Twins != 35 {
+		t.Errorf("%d gemeos com p <= 881, esperados 35", edge[0].Twins)
+	}
+
+	// 8169 pares abaixo de 10^6; a estimativa vale desde o inicio
+	small, err := Twins(2, 1000000, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small[0].Twins != 8169 || math.Abs(small[0].Expected/8169-1) > 0.05 {
+		t.Errorf("%d gemeos ate 10^6 (estimativa %.1f), esperados 8169", small[0].Twins, small[0].Expected)
+	}
+
+	// Longe do inicio, a contagem tambem confere com a do crivo
+	lo := uint64(1) << 32
+	far, err := Twins(lo, lo+1<<20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	var prev uint64
+	for p := range numutil.SegmentedSieve(lo, lo+1<<20+2) {
+		if p-prev == 2 && prev <= lo+1<<20 {
+			count++
+		}
+		prev = p
+	}
+	if far[0].Twins != count {
+		t.Errorf("%d gemeos, esperados %d", far[0].Twins, count)
+	}
+	if r := float64(count) / far[0].Expected; math.Abs(r-1) > 0.1 {
+		t.Errorf("razao entre gemeos e estimativa: %.3f", r)
+	}
+}
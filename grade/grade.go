@@ -0,0 +1,202 @@
+// O pacote grade da notas aos geradores do projeto e recomenda um deles
+// para o proposito do usuario: simulacao, em que importam a qualidade
+// estatistica e a velocidade, ou criptografia, em que um gerador previsivel
+// eh inaceitavel por melhor que seja nos testes. A nota combina os testes
+// do pacote stats, a velocidade medida e uma base de fraquezas conhecidas
+// (Weaknesses), que os testes estatisticos simples nao detectam. Measure
+// faz as medicoes e Rank, que nao mede nada, da as notas.
+package grade
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/stats"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Purpose eh o uso pretendido dos numeros gerados
+type Purpose int
+
+const (
+	Simulation Purpose = iota // simulacoes, testes, jogos
+	Crypto                    // chaves, segredos, nonces
+)
+
+func (p Purpose) String() string {
+	switch p {
+	case Simulation:
+		return "simulation"
+	case Crypto:
+		return "crypto"
+	}
+	return fmt.Sprintf("Purpose(%d)", int(p))
+}
+
+// ParsePurpose converte "simulation" ou "crypto" no proposito
+// correspondente
+func ParsePurpose(s string) (Purpose, error) {
+	switch s {
+	case "simulation":
+		return Simulation, nil
+	case "crypto":
+		return Crypto, nil
+	}
+	return 0, fmt.Errorf("grade: proposito desconhecido %q: use simulation ou crypto", s)
+}
+
+// Weakness eh uma fraqueza conhecida de um gerador
+type Weakness struct {
+	Generator string
+	Summary   string
+	// Applies informa se a fraqueza vale para saidas de bits bits; nil
+	// vale sempre
+	Applies func(bits int) bool
+	// Crypto indica que a fraqueza torna o gerador inadequado para
+	// criptografia; Penalty eh a perda, em pontos, na nota para simulacao
+	Crypto  bool
+	Penalty float64
+}
+
+func smallBBS(bits int) bool { return bits < prng.MinSecureBBSBits }
+
+// Weaknesses eh a base de fraquezas conhecidas dos geradores do projeto,
+// com os parametros da demonstracao (LFG com atrasos 7 e 10)
+var Weaknesses = []Weakness{
+	{
+		Generator: "lfg",
+		Summary:   "linear: X_n = X_(n-7) + X_(n-10) mod 2^m, então 10 saídas consecutivas preveem todas as seguintes",
+		Crypto:    true,
+	},
+	{
+		Generator: "lfg",
+		Summary:   "atrasos curtos (7, 10) correlacionam as saídas: cada trio (X_(n-10), X_(n-7), X_n) satisfaz uma relação linear",
+		Penalty:   10,
+	},
+	{
+		Generator: "lfg",
+		Summary:   "o bit menos significativo é um LFSR de período no máximo 2^10 - 1 = 1023",
+		Crypto:    true,
+		Penalty:   10,
+	},
+	{
+		Generator: "bbs",
+		Summary:   fmt.Sprintf("módulo de menos de %d bits: pode ser fatorado, o que revela a sequência", prng.MinSecureBBSBits),
+		Applies:   smallBBS,
+		Crypto:    true,
+	},
+	{
+		Generator: "hybrid",
+		Summary:   fmt.Sprintf("o BBS interno tem módulo de menos de %d bits, e o LFG não acrescenta segurança", prng.MinSecureBBSBits),
+		Applies:   smallBBS,
+		Crypto:    true,
+	},
+	{
+		Generator: "hybrid-add",
+		Summary:   fmt.Sprintf("o BBS interno tem módulo de menos de %d bits, e o LFG não acrescenta segurança", prng.MinSecureBBSBits),
+		Applies:   smallBBS,
+		Crypto:    true,
+	},
+}
+
+// Measurement guarda as medicoes de Measure para um gerador
+type Measurement struct {
+	Generator string
+	Bits      int // tamanho de cada saida
+	Stats     stats.Report
+	Rate      float64 // bits gerados por segundo
+}
+
+// Measure avalia samples saidas de g, de nome name, com stats.Evaluate e
+// mede a velocidade da geracao
+func Measure(name string, g prng.Generator, samples int) (Measurement, error) {
+	start := time.Now()
+	r, err := stats.Evaluate(g, samples)
+	if err != nil {
+		return Measurement{}, err
+	}
+	elapsed := max(time.Since(start), time.Nanosecond)
+	return Measurement{Generator: name, Bits: g.Bits(), Stats: r, Rate: float64(r.Bits) / elapsed.Seconds()}, nil
+}
+
+// Pesos de cada parte da nota, de 0 a 100, para cada proposito: na
+// criptografia, a ausencia de fraquezas conta mais que a velocidade
+var weights = map[Purpose]struct{ stats, speed, clean float64 }{
+	Simulation: {stats: 40, speed: 40, clean: 20},
+	Crypto:     {stats: 40, speed: 10, clean: 50},
+}
+
+// speedDecades eh quantas ordens de grandeza abaixo do gerador mais rapido
+// a parte da velocidade chega a zero
+const speedDecades = 3
+
+// Grade eh a nota de um gerador
+type Grade struct {
+	Measurement
+	Score    float64  // de 0 a 100
+	Suitable bool     // adequado ao proposito
+	Notes    []string // fraquezas e justificativas da nota
+}
+
+// Rank da as notas dos geradores medidos para o proposito, do mais
+// recomendado ao menos: os adequados primeiro e, entre eles, os de maior
+// nota. O primeiro eh a recomendacao, se for adequado.
+//
+// Um gerador reprovado nos testes estatisticos nao eh adequado a nenhum
+// proposito, e um gerador com fraqueza criptografica nao eh adequado a
+// criptografia. A velocidade eh relativa ao gerador mais rapido da lista.
+func Rank(purpose Purpose, ms []Measurement) []Grade {
+	w := weights[purpose]
+	fastest := 0.0
+	for _, m := range ms {
+		fastest = max(fastest, m.Rate)
+	}
+	grades := make([]Grade, 0, len(ms))
+	for _, m := range ms {
+		g := Grade{Measurement: m, Suitable: true}
+		if m.Stats.Pass() {
+			g.Score += w.stats
+		} else {
+			g.Suitable = false
+			g.Notes = append(g.Notes, fmt.Sprintf("reprovado nos testes estatísticos (frequência p = %.4f, corridas p = %.4f)", m.Stats.Monobit, m.Stats.Runs))
+		}
+
+		if m.Rate > 0 {
+			slower := math.Log10(fastest / m.Rate)
+			g.Score += w.speed * min(max(1-slower/speedDecades, 0), 1)
+			if slower >= 1 {
+				g.Notes = append(g.Notes, fmt.Sprintf("%.0f vezes mais lento que o mais rápido", fastest/m.Rate))
+			}
+		}
+
+		clean := w.clean
+		for _, weak := range Weaknesses {
+			if weak.Generator != m.Generator || weak.Applies != nil && !weak.Applies(m.Bits) {
+				continue
+			}
+			switch {
+			case purpose == Crypto && weak.Crypto:
+				g.Suitable, clean = false, 0
+			case purpose == Simulation && weak.Penalty > 0:
+				clean -= weak.Penalty
+			default:
+				continue // a fraqueza nao afeta esse proposito
+			}
+			g.Notes = append(g.Notes, weak.Summary)
+		}
+		g.Score += max(clean, 0)
+		grades = append(grades, g)
+	}
+	slices.SortStableFunc(grades, func(a, b Grade) int {
+		if a.Suitable != b.Suitable {
+			if a.Suitable {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return grades
+}
@@ -0,0 +1,47 @@
+package grade
+
+import (
+	"PrimeNumGenerator/stats"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	pass := stats.Report{Monobit: 0.5, Runs: 0.5}
+	ms := []Measurement{
+		{Generator: "lfg", Bits: 256, Stats: pass, Rate: 1e8},
+		{Generator: "bbs", Bits: 256, Stats: pass, Rate: 1e5},
+		{Generator: "bbs", Bits: 1024, Stats: pass, Rate: 1e4},
+		{Generator: "hybrid", Bits: 1024, Stats: stats.Report{Monobit: 0.001, Runs: 0.5}, Rate: 1e4},
+	}
+
+	// Para simulacao, o LFG rapido vence mesmo com as penalidades
+	sim := Rank(Simulation, ms)
+	if sim[0].Generator != "lfg" || !sim[0].Suitable || sim[0].Score != 80 {
+		t.Errorf("simulacao: recomendado %s (%.1f, %t), esperado lfg (80)", sim[0].Generator, sim[0].Score, sim[0].Suitable)
+	}
+	if last := sim[len(sim)-1]; last.Generator != "hybrid" || last.Suitable {
+		t.Errorf("simulacao: gerador reprovado nos testes em %s, adequado = %t", last.Generator, last.Suitable)
+	}
+
+	// Para criptografia, apenas o BBS de 1024 bits eh adequado
+	crypto := Rank(Crypto, ms)
+	if crypto[0].Generator != "bbs" || crypto[0].Bits != 1024 || !crypto[0].Suitable {
+		t.Errorf("criptografia: recomendado %s de %d bits, esperado bbs de 1024", crypto[0].Generator, crypto[0].Bits)
+	}
+	for _, g := range crypto[1:] {
+		if g.Suitable || len(g.Notes) == 0 {
+			t.Errorf("criptografia: %s de %d bits adequado = %t, notas %q", g.Generator, g.Bits, g.Suitable, g.Notes)
+		}
+	}
+}
+
+func TestParsePurpose(t *testing.T) {
+	for _, p := range []Purpose{Simulation, Crypto} {
+		if got, err := ParsePurpose(p.String()); err != nil || got != p {
+			t.Errorf("ParsePurpose(%q) = %v, %v", p, got, err)
+		}
+	}
+	if _, err := ParsePurpose("jogos"); err == nil {
+		t.Error("ParsePurpose aceitou um proposito desconhecido")
+	}
+}
@@ -0,0 +1,102 @@
+// O pacote group trabalha com o grupo multiplicativo Z_p* de um primo
+// seguro p = 2q + 1, com q primo, base dos parametros de Diffie-Hellman e
+// DSA. A ordem de Z_p* eh p - 1 = 2q, de modo que seus unicos subgrupos tem
+// ordem 1, 2, q e 2q.
+package group
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"errors"
+	"math/big"
+)
+
+// ErrNotSafePrime indica que p nao eh um primo seguro
+var ErrNotSafePrime = errors.New("group: p nao eh um primo seguro")
+
+// IsSafePrime informa se p e (p-1)/2 sao provavelmente primos
+func IsSafePrime(p *big.Int) bool {
+	if p.Cmp(big.NewInt(5)) < 0 || !p.ProbablyPrime(20) {
+		return false
+	}
+	return subgroupOrder(p).ProbablyPrime(20)
+}
+
+// subgroupOrder retorna q = (p-1)/2
+func subgroupOrder(p *big.Int) *big.Int {
+	return new(big.Int).Rsh(p, 1)
+}
+
+// GenerateSafePrime gera um primo seguro p = 2q + 1 de bits bits, buscando
+// primos q de bits-1 bits com o Miller-Rabin ate que 2q + 1 tambem seja primo
+func GenerateSafePrime(bits int, e prng.Entropy) (p, q *big.Int, err error) {
+	if bits < 3 {
+		return nil, nil, errors.New("group: primo seguro deve ter ao menos 3 bits")
+	}
+	cfg := pta.Config{Security: e.Level, Source: e.Source}
+	for {
+		candidate, err := e.Bits(bits - 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		res, err := pta.MillerRabin(candidate, bits-1, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		q = res.Number
+		p = new(big.Int).Lsh(q, 1)
+		p.Add(p, big.NewInt(1))
+		if p.BitLen() == bits && p.ProbablyPrime(20) {
+			return p, q, nil
+		}
+	}
+}
+
+// FindGenerator sorteia um gerador g de Z_p* e retorna tambem gq = g^2,
+// gerador do subgrupo de ordem q. Como p - 1 = 2q, g gera Z_p* se, e
+// somente se, g^2 != 1 e g^q != 1 (mod p).
+func FindGenerator(p *big.Int, e prng.Entropy) (g, gq *big.Int, err error) {
+	if !IsSafePrime(p) {
+		return nil, nil, ErrNotSafePrime
+	}
+	q := subgroupOrder(p)
+	one := big.NewInt(1)
+	// Sorteamos g em [2, p-2]
+	max := new(big.Int).Sub(p, big.NewInt(3))
+	for {
+		g, err = e.Int(max)
+		if err != nil {
+			return nil, nil, err
+		}
+		g.Add(g, big.NewInt(2))
+
+		gq = new(big.Int).Exp(g, big.NewInt(2), p)
+		if gq.Cmp(one) != 0 && new(big.Int).Exp(g, q, p).Cmp(one) != 0 {
+			return g, gq, nil
+		}
+	}
+}
+
+// RandomElement sorteia um elemento do subgrupo de ordem q diferente de 1,
+// que eh portanto tambem um gerador desse subgrupo
+func RandomElement(p *big.Int, e prng.Entropy) (*big.Int, error) {
+	if !IsSafePrime(p) {
+		return nil, ErrNotSafePrime
+	}
+	x, err := e.Int(new(big.Int).Sub(p, big.NewInt(3)))
+	if err != nil {
+		return nil, err
+	}
+	x.Add(x, big.NewInt(2))
+	// Os quadrados formam o subgrupo de ordem q; x em [2, p-2] garante
+	// x^2 != 1
+	return x.Exp(x, big.NewInt(2), p), nil
+}
+
+// InSubgroup informa se 1 < y < p e y pertence ao subgrupo de ordem q
+func InSubgroup(p, y *big.Int) bool {
+	if y.Cmp(big.NewInt(1)) <= 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	return new(big.Int).Exp(y, subgroupOrder(p), p).Cmp(big.NewInt(1)) == 0
+}
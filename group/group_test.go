@@ -0,0 +1,61 @@
+package group
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+// modp1536 eh o primo seguro do grupo 5 da RFC 3526
+const modp1536 = "FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1" +
+	"29024E088A67CC74020BBEA63B139B22514A08798E3404DD" +
+	"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245" +
+	"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED" +
+	"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3D" +
+	"C2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F" +
+	"83655D23DCA3AD961C62F356208552BB9ED529077096966D" +
+	"670C354E4ABC9804F1746C08CA237327FFFFFFFFFFFFFFFF"
+
+func TestFindGenerator(t *testing.T) {
+	p, _ := new(big.Int).SetString(modp1536, 16)
+	small, _, err := GenerateSafePrime(64, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []*big.Int{big.NewInt(23), small, p} {
+		g, gq, err := FindGenerator(p, prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := subgroupOrder(p)
+		if new(big.Int).Exp(g, q, p).Cmp(big.NewInt(1)) == 0 {
+			t.Errorf("p=%s: g=%s tem ordem q", p, g)
+		}
+		if !InSubgroup(p, gq) || InSubgroup(p, g) {
+			t.Errorf("p=%s: subgrupo de ordem q incorreto", p)
+		}
+		y, err := RandomElement(p, prng.Entropy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !InSubgroup(p, y) {
+			t.Errorf("p=%s: elemento %s fora do subgrupo", p, y)
+		}
+	}
+
+	// Em Z_23*, o gerador deve produzir os 22 elementos
+	g, _, _ := FindGenerator(big.NewInt(23), prng.Entropy{})
+	seen := make(map[int64]bool)
+	for x, i := big.NewInt(1), 0; i < 22; i++ {
+		seen[x.Int64()] = true
+		x.Mul(x, g).Mod(x, big.NewInt(23))
+	}
+	if len(seen) != 22 {
+		t.Errorf("g=%s gera apenas %d elementos", g, len(seen))
+	}
+
+	if _, _, err := FindGenerator(big.NewInt(29), prng.Entropy{}); err != ErrNotSafePrime {
+		t.Errorf("29 aceito como primo seguro: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// O pacote history registra o historico das geracoes de primos: cada
+// candidato, o veredito dos testes, os parametros, o tempo gasto e o estado
+// do gerador que produziu o candidato, para auditoria posterior.
+//
+// O projeto usa apenas a biblioteca padrao, por isso o historico eh um
+// arquivo JSON Lines (um registro JSON por linha, apenas acrescentado ao
+// final) em vez de um banco embutido como SQLite ou BoltDB. As consultas
+// percorrem o arquivo inteiro, o que eh suficiente para o volume de uma
+// execucao de demonstracao ou de um servidor pequeno.
+package history
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/pta"
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record eh uma geracao de primo registrada no historico
+type Record struct {
+	Time      time.Time `json:"time"`
+	Generator string    `json:"generator"` // origem do candidato: lfg, bbs, server...
+	// State eh o estado do gerador antes do candidato, cifrado com
+	// keywrap.Seal, em hexadecimal; StateSHA256 eh o hash do estado em claro
+	State       string `json:"state,omitempty"`
+	StateSHA256 string `json:"state_sha256,omitempty"`
+	Candidate   string `json:"candidate,omitempty"` // candidato original, em decimal
+	Test        string `json:"test"`
+	Bits        int    `json:"bits"`
+	Security    string `json:"security"`
+
+	Prime      bool    `json:"prime"`
+	Number     string  `json:"number,omitempty"` // primo gerado, em decimal
+	Rounds     int     `json:"rounds"`
+	Confidence float64 `json:"confidence"`
+	Attempts   int     `json:"attempts"`
+	DurationNS int64   `json:"duration_ns"`
+}
+
+// NewRecord cria o registro do resultado res da geracao de um primo de
+// bits bits com o teste test e a configuracao cfg
+func NewRecord(generator, test string, bits int, cfg pta.Config, res pta.Result) Record {
+	r := Record{
+		Time:       clock.Now().UTC(),
+		Generator:  generator,
+		Test:       test,
+		Bits:       bits,
+		Security:   cfg.Security.String(),
+		Prime:      res.Prime,
+		Rounds:     res.Rounds,
+		Confidence: res.Confidence,
+		Attempts:   res.Attempts,
+		DurationNS: res.Duration.Nanoseconds(),
+	}
+	if res.Number != nil {
+		r.Number = res.Number.String()
+	}
+	return r
+}
+
+// WithCandidate acrescenta ao registro o candidato original e o SHA-256 do
+// estado serializado do gerador (veja prng.MarshalBinary); ambos podem ser
+// nil. O estado em si nao eh gravado, pois o do BBS inclui os fatores p e
+// q do modulo: para guarda-lo, cifre-o e use WithSealedState.
+func (r Record) WithCandidate(candidate *big.Int, state []byte) Record {
+	if candidate != nil {
+		r.Candidate = candidate.String()
+	}
+	if state != nil {
+		r.StateSHA256 = fmt.Sprintf("%x", sha256.Sum256(state))
+	}
+	return r
+}
+
+// WithSealedState acrescenta ao registro o estado do gerador cifrado por
+// keywrap.Seal, que pode ser decifrado com keywrap.Open e a mesma senha
+func (r Record) WithSealedState(sealed []byte) Record {
+	r.State = fmt.Sprintf("%x", sealed)
+	return r
+}
+
+// Duration retorna o tempo gasto na geracao
+func (r Record) Duration() time.Duration {
+	return time.Duration(r.DurationNS)
+}
+
+// Store eh um historico em disco. Pode ser usado por varias goroutines ao
+// mesmo tempo.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// Open abre (ou cria) o historico em path para acrescentar registros
+func Open(path string) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: path, f: f}, nil
+}
+
+// Append grava r no final do historico
+func (s *Store) Append(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return errors.New("history: historico fechado")
+	}
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+// Close fecha o historico
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// Filter seleciona registros em Query; campos vazios nao restringem
+type Filter struct {
+	Generator string
+	Test      string
+	Bits      int
+	Since     time.Time
+	Limit     int // apenas os Limit registros mais recentes; 0 para todos
+}
+
+func (f Filter) match(r Record) bool {
+	return (f.Generator == "" || r.Generator == f.Generator) &&
+		(f.Test == "" || r.Test == f.Test) &&
+		(f.Bits == 0 || r.Bits == f.Bits) &&
+		(f.Since.IsZero() || !r.Time.Before(f.Since))
+}
+
+// Query le o historico em path e retorna, em ordem de gravacao, os
+// registros que satisfazem f
+func Query(path string, f Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return query(file, f)
+}
+
+func query(r io.Reader, f Filter) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("history: linha %d: %w", line, err)
+		}
+		if f.match(rec) {
+			records = append(records, rec)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if f.Limit > 0 && len(records) > f.Limit {
+		records = records[len(records)-f.Limit:]
+	}
+	return records, nil
+}
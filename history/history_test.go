@@ -0,0 +1,58 @@
+package history
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/pta"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "historico.jsonl")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := pta.Result{Prime: true, Number: big.NewInt(527281), Rounds: 20, Attempts: 3, Duration: time.Millisecond}
+	for i, test := range []string{"miller-rabin", "fermat", "miller-rabin"} {
+		rec := NewRecord("lfg", test, 20+i, pta.Config{}, res).WithCandidate(big.NewInt(527279), []byte{'L', 1})
+		if i == 0 {
+			rec = rec.WithSealedState([]byte("PGWRAP"))
+		}
+		if err := s.Append(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	all, err := Query(path, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].Number != "527281" || all[0].State != "504757524150" || all[0].Duration() != time.Millisecond {
+		t.Fatalf("Query = %+v", all)
+	}
+	// Sem WithSealedState, apenas o hash do estado vai para o disco
+	if stateHash := fmt.Sprintf("%x", sha256.Sum256([]byte{'L', 1})); all[1].State != "" || all[1].StateSHA256 != stateHash {
+		t.Fatalf("estado gravado em claro: %+v", all[1])
+	}
+	mr, _ := Query(path, Filter{Test: "miller-rabin", Limit: 1})
+	if len(mr) != 1 || mr[0].Bits != 22 {
+		t.Fatalf("Query com filtro = %+v", mr)
+	}
+}
+
+func TestNewRecordClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	previous := clock.Set(clock.NewSimulated(start, 0))
+	defer clock.Set(previous)
+
+	rec := NewRecord("bbs", "fermat", 32, pta.Config{}, pta.Result{Prime: true})
+	if !rec.Time.Equal(start) {
+		t.Fatalf("Time = %v; esperado o horario do relogio simulado %v", rec.Time, start)
+	}
+}
@@ -0,0 +1,158 @@
+// O pacote apicheck lista a API publica dos pacotes estaveis (prng, pta e
+// numutil), no formato do api/go1.txt do Go: uma linha por funcao, metodo,
+// tipo, campo, constante ou variavel exportados. O teste do pacote compara
+// a lista com a base gravada em api/, para que nenhuma versao menor remova
+// ou altere um simbolo ja publicado.
+package apicheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Stable sao os pacotes com garantia de compatibilidade, relativos a raiz
+// do modulo. O projeto nao tem um pacote drbg separado: os geradores
+// deterministicos (LFG, BBS e os hibridos) ficam em prng e sao verificados
+// com ele.
+var Stable = []string{"numutil", "prng", "pta"}
+
+// Features retorna as linhas da API publica do pacote no diretorio dir,
+// ordenadas e sem repeticoes. Os arquivos de todas as plataformas e tags
+// sao considerados, exceto os de teste.
+func Features(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name == "main" {
+			continue
+		}
+		w := walker{fset: fset, pkg: file.Name.Name, seen: seen}
+		for _, decl := range file.Decls {
+			w.decl(decl)
+		}
+	}
+	features := make([]string, 0, len(seen))
+	for f := range seen {
+		features = append(features, f)
+	}
+	slices.Sort(features)
+	return features, nil
+}
+
+// walker acumula as linhas da API de um arquivo em seen
+type walker struct {
+	fset *token.FileSet
+	pkg  string
+	seen map[string]bool
+}
+
+func (w walker) emit(format string, args ...any) {
+	w.seen[fmt.Sprintf("pkg %s, ", w.pkg)+fmt.Sprintf(format, args...)] = true
+}
+
+// expr formata uma expressao de tipo em uma unica linha
+func (w walker) expr(x ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, w.fset, x)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+func (w walker) decl(decl ast.Decl) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return
+		}
+		sig := strings.TrimPrefix(w.expr(d.Type), "func")
+		if d.Recv == nil {
+			w.emit("func %s%s", d.Name.Name, sig)
+			return
+		}
+		recv := w.expr(d.Recv.List[0].Type)
+		if base := strings.TrimPrefix(recv, "*"); !ast.IsExported(strings.SplitN(base, "[", 2)[0]) {
+			return
+		}
+		w.emit("method (%s) %s%s", recv, d.Name.Name, sig)
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				w.typeSpec(s)
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					if !name.IsExported() {
+						continue
+					}
+					if s.Type != nil {
+						w.emit("%s %s %s", d.Tok, name.Name, w.expr(s.Type))
+					} else {
+						w.emit("%s %s", d.Tok, name.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (w walker) typeSpec(s *ast.TypeSpec) {
+	if !s.Name.IsExported() {
+		return
+	}
+	name := s.Name.Name
+	if s.Assign.IsValid() {
+		w.emit("type %s = %s", name, w.expr(s.Type))
+		return
+	}
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		w.emit("type %s struct", name)
+		for _, field := range t.Fields.List {
+			if len(field.Names) == 0 {
+				// Campo embutido
+				if typ := strings.TrimPrefix(w.expr(field.Type), "*"); ast.IsExported(typ[strings.LastIndex(typ, ".")+1:]) {
+					w.emit("type %s struct, embedded %s", name, w.expr(field.Type))
+				}
+				continue
+			}
+			for _, f := range field.Names {
+				if f.IsExported() {
+					w.emit("type %s struct, %s %s", name, f.Name, w.expr(field.Type))
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		w.emit("type %s interface", name)
+		for _, m := range t.Methods.List {
+			for _, f := range m.Names {
+				if f.IsExported() {
+					w.emit("type %s interface, %s%s", name, f.Name, strings.TrimPrefix(w.expr(m.Type), "func"))
+				}
+			}
+			if len(m.Names) == 0 {
+				w.emit("type %s interface, embedded %s", name, w.expr(m.Type))
+			}
+		}
+	default:
+		w.emit("type %s %s", name, w.expr(s.Type))
+	}
+}
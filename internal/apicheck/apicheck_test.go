@@ -0,0 +1,92 @@
+package apicheck
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "acrescenta a api/next.txt os simbolos novos dos pacotes estaveis")
+
+// root eh a raiz do modulo, relativa ao diretorio do teste
+const root = "../.."
+
+// baseline le as linhas dos arquivos de api/: a API da versao 1 e os
+// acrescimos posteriores
+func baseline(t *testing.T) []string {
+	var lines []string
+	for _, name := range []string{"v1.txt", "next.txt"} {
+		data, err := os.ReadFile(filepath.Join(root, "api", name))
+		if os.IsNotExist(err) && name == "next.txt" {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+func TestStableAPI(t *testing.T) {
+	var current []string
+	for _, pkg := range Stable {
+		features, err := Features(filepath.Join(root, pkg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		current = append(current, features...)
+	}
+	published := baseline(t)
+
+	for _, line := range published {
+		if !slices.Contains(current, line) {
+			t.Errorf("simbolo publicado removido ou alterado: %s", line)
+		}
+	}
+	var added []string
+	for _, line := range current {
+		if !slices.Contains(published, line) {
+			added = append(added, line)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	if !*update {
+		t.Errorf("simbolos novos fora de api/next.txt (rode go test ./internal/apicheck -update):\n%s", strings.Join(added, "\n"))
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(root, "api", "next.txt"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.Join(added, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	features, err := Features(filepath.Join(root, "numutil"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"pkg numutil, func Jacobi(a, n *big.Int) int",
+		"pkg numutil, type Tables struct, Primes []int",
+		"pkg numutil, const TablesVersion",
+		"pkg numutil, var ErrNotResidue",
+	} {
+		if !slices.Contains(features, want) {
+			t.Errorf("Features(numutil) sem %q", want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Esse arquivo traz a aritmetica de Montgomery de largura fixa, para os
+//  modulos pequenos em que o custo fixo do math/big domina.
+
+package modexp
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// MaxFixedBits eh o maior modulo, em bits, atendido pela aritmetica de
+// largura fixa
+const MaxFixedBits = 256
+
+const fixedLimbs = MaxFixedBits / 64
+
+// fixedInt eh um inteiro de ate MaxFixedBits bits em palavras de 64 bits,
+// da menos para a mais significativa
+type fixedInt [fixedLimbs]uint64
+
+// montgomery guarda as constantes da aritmetica de Montgomery modulo m,
+// com R = 2^(64k)
+type montgomery struct {
+	mod *big.Int
+	m   fixedInt
+	k   int      // palavras usadas por m
+	inv uint64   // -m^-1 mod 2^64
+	r2  fixedInt // R^2 mod m, que leva um valor para a forma de Montgomery
+}
+
+// newMontgomery prepara o modulo mod, impar, maior que 1 e de ate
+// MaxFixedBits bits
+func newMontgomery(mod *big.Int) *montgomery {
+	m := &montgomery{mod: mod, k: (mod.BitLen() + 63) / 64}
+	setFixed(&m.m, mod)
+	// Todo impar eh o proprio inverso modulo 8; cada passo de Newton dobra
+	// os bits corretos da inversa: 3, 6, 12, 24, 48 e 96
+	inv := m.m[0]
+	for range 5 {
+		inv *= 2 - m.m[0]*inv
+	}
+	m.inv = -inv
+	r2 := new(big.Int).Lsh(big.NewInt(1), uint(128*m.k))
+	setFixed(&m.r2, r2.Mod(r2, mod))
+	return m
+}
+
+// mul calcula z = x*y/R mod m, o produto de Montgomery, pelo metodo CIOS
+// (multiplicacao e reducao intercaladas palavra a palavra), com x, y < m.
+// z pode ser x ou y.
+func (m *montgomery) mul(z, x, y *fixedInt) {
+	switch m.k {
+	case 1:
+		z[0] = m.mul1(x[0], y[0])
+		return
+	case 2:
+		z[0], z[1] = m.mul2(x[0], x[1], y[0], y[1])
+		return
+	}
+	k := m.k
+	xs, ms := x[:k], m.m[:k]
+	var buf [fixedLimbs + 2]uint64
+	t := buf[:k+2]
+	for _, yi := range y[:k] {
+		var c, carry uint64
+		for j, xj := range xs {
+			c, t[j] = madd(xj, yi, t[j], c)
+		}
+		t[k], carry = bits.Add64(t[k], c, 0)
+		t[k+1] = carry
+
+		// Soma u*m, com u escolhido para zerar a palavra menos
+		// significativa, e desloca t uma palavra para a direita
+		u := t[0] * m.inv
+		c, _ = madd(u, ms[0], t[0], 0)
+		for j := 1; j < k; j++ {
+			c, t[j-1] = madd(u, ms[j], t[j], c)
+		}
+		t[k-1], carry = bits.Add64(t[k], c, 0)
+		t[k] = t[k+1] + carry
+	}
+
+	// t < 2m: subtrai m se t >= m, escolhendo o resultado por mascara, sem
+	// desvio que dependa dos valores
+	var d fixedInt
+	var borrow uint64
+	for j, mj := range ms {
+		d[j], borrow = bits.Sub64(t[j], mj, borrow)
+	}
+	mask := -(t[k] | (borrow ^ 1))
+	for j := range ms {
+		z[j] = d[j]&mask | t[j]&^mask
+	}
+}
+
+// mul1 eh o produto de Montgomery com modulo de uma palavra
+func (m *montgomery) mul1(x, y uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	u := lo * m.inv
+	uHi, uLo := bits.Mul64(u, m.m[0])
+	_, carry := bits.Add64(lo, uLo, 0)
+	t, carry := bits.Add64(hi, uHi, carry)
+	d, borrow := bits.Sub64(t, m.m[0], 0)
+	// t + carry*2^64 < 2m: subtrai m se t >= m
+	mask := -(carry | (borrow ^ 1))
+	return d&mask | t&^mask
+}
+
+// mul2 eh o produto de Montgomery com modulo de duas palavras, o CIOS de
+// mul desenrolado
+func (m *montgomery) mul2(x0, x1, y0, y1 uint64) (uint64, uint64) {
+	m0, m1 := m.m[0], m.m[1]
+	var t0, t1, t2, t3, c, carry uint64
+
+	// Primeira palavra de y
+	c, t0 = madd(x0, y0, 0, 0)
+	c, t1 = madd(x1, y0, 0, c)
+	t2 = c
+	u := t0 * m.inv
+	c, _ = madd(u, m0, t0, 0)
+	c, t0 = madd(u, m1, t1, c)
+	t1, carry = bits.Add64(t2, c, 0)
+	t2 = carry
+
+	// Segunda palavra de y
+	c, t0 = madd(x0, y1, t0, 0)
+	c, t1 = madd(x1, y1, t1, c)
+	t2, t3 = bits.Add64(t2, c, 0)
+	u = t0 * m.inv
+	c, _ = madd(u, m0, t0, 0)
+	c, t0 = madd(u, m1, t1, c)
+	t1, carry = bits.Add64(t2, c, 0)
+	t2 = t3 + carry
+
+	d0, borrow := bits.Sub64(t0, m0, 0)
+	d1, borrow := bits.Sub64(t1, m1, borrow)
+	mask := -(t2 | (borrow ^ 1))
+	return d0&mask | t0&^mask, d1&mask | t1&^mask
+}
+
+// madd retorna a*b + c + d, que cabe em 128 bits, como (hi, lo)
+func madd(a, b, c, d uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// exp calcula base^e mod m, com e >= 0 decomposto em janelas de 4 bits por
+// windows: cada janela custa quatro quadrados e uma multiplicacao, mesmo
+// quando eh nula
+func (m *montgomery) exp(base *big.Int, digits []uint8) *big.Int {
+	if base.Sign() < 0 || base.Cmp(m.mod) >= 0 {
+		base = new(big.Int).Mod(base, m.mod)
+	}
+	var one, x fixedInt
+	one[0] = 1
+	setFixed(&x, base)
+	var table [16]fixedInt // table[i] = base^i, na forma de Montgomery
+	m.mul(&table[0], &one, &m.r2)
+	m.mul(&table[1], &x, &m.r2)
+	for i := 2; i < len(table); i++ {
+		m.mul(&table[i], &table[i-1], &table[1])
+	}
+
+	acc := table[0]
+	for _, w := range digits {
+		for range 4 {
+			m.mul(&acc, &acc, &acc)
+		}
+		m.mul(&acc, &acc, &table[w])
+	}
+	m.mul(&acc, &acc, &one) // sai da forma de Montgomery
+	return fixedToBig(new(big.Int), &acc, m.k)
+}
+
+// square calcula z = x^2 mod m, com 0 <= x < m: o produto de Montgomery de
+// x*R por x eh x^2, sem precisar sair da forma de Montgomery
+func (m *montgomery) square(z, x *big.Int) *big.Int {
+	var a, b fixedInt
+	setFixed(&a, x)
+	m.mul(&b, &a, &m.r2)
+	m.mul(&b, &b, &a)
+	return fixedToBig(z, &b, m.k)
+}
+
+// setFixed copia x, com 0 <= x < 2^MaxFixedBits, para z
+func setFixed(z *fixedInt, x *big.Int) {
+	*z = fixedInt{}
+	for i, w := range x.Bits() {
+		if bits.UintSize == 64 {
+			z[i] = uint64(w)
+		} else {
+			z[i/2] |= uint64(w) << (32 * (i % 2))
+		}
+	}
+}
+
+// fixedToBig guarda as k primeiras palavras de x em z, reaproveitando a
+// memoria de z, e retorna z
+func fixedToBig(z *big.Int, x *fixedInt, k int) *big.Int {
+	words := z.Bits()[:0]
+	for _, w := range x[:k] {
+		if bits.UintSize == 64 {
+			words = append(words, big.Word(w))
+		} else {
+			words = append(words, big.Word(w&0xffffffff), big.Word(w>>32))
+		}
+	}
+	return z.SetBits(words)
+}
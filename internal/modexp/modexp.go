@@ -0,0 +1,113 @@
+// O pacote modexp calcula exponenciacoes e quadrados modulares reutilizando
+// o trabalho que depende apenas do modulo. Um Modulus eh preparado uma vez
+// por modulo, por exemplo por candidato testado, e atende todas as
+// iteracoes do Miller-Rabin ou todos os quadrados do Blum Blum Shub: as
+// constantes de Montgomery sao calculadas uma so vez e, em ExpBatch, a
+// decomposicao do expoente em janelas eh compartilhada pelas bases.
+//
+// Com modulos impares de ate MaxFixedBits bits, as contas sao feitas em
+// aritmetica de Montgomery sobre palavras de 64 bits de largura fixa, sem
+// alocar valores intermediarios; nos modulos maiores, em que o custo fixo
+// do math/big eh desprezivel, elas ficam com o (*big.Int).Exp.
+//
+// O pacote eh interno: fora do modulo, ele eh usado por prng.FixedBackend
+// e prng.Prepare, e pode mudar a qualquer versao.
+package modexp
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// ErrModulus indica um modulo par ou menor que 3, que nao admite a
+// aritmetica de Montgomery
+var ErrModulus = errors.New("modexp: o modulo deve ser impar e maior que 1")
+
+// Modulus eh um modulo impar preparado para exponenciacoes e quadrados. Os
+// metodos podem ser chamados por varias goroutines ao mesmo tempo.
+type Modulus struct {
+	m     *big.Int
+	fixed *montgomery // nil acima de MaxFixedBits bits
+}
+
+// New prepara o modulo m, que deve ser impar e maior que 1. m eh copiado.
+func New(m *big.Int) (*Modulus, error) {
+	if m.Sign() <= 0 || m.Bit(0) == 0 || m.BitLen() < 2 {
+		return nil, ErrModulus
+	}
+	mod := &Modulus{m: new(big.Int).Set(m)}
+	if m.BitLen() <= MaxFixedBits {
+		mod.fixed = newMontgomery(mod.m)
+	}
+	return mod, nil
+}
+
+// Int retorna uma copia do modulo
+func (mod *Modulus) Int() *big.Int {
+	return new(big.Int).Set(mod.m)
+}
+
+// Cmp compara o modulo com x, como (*big.Int).Cmp, sem copia-lo
+func (mod *Modulus) Cmp(x *big.Int) int {
+	return mod.m.Cmp(x)
+}
+
+// Fixed informa se o modulo usa a aritmetica de largura fixa
+func (mod *Modulus) Fixed() bool {
+	return mod.fixed != nil
+}
+
+// Exp retorna base^exp mod m. Expoentes negativos, que exigem o inverso de
+// base, ficam com o (*big.Int).Exp.
+func (mod *Modulus) Exp(base, exp *big.Int) *big.Int {
+	if mod.fixed == nil || exp.Sign() < 0 {
+		return new(big.Int).Exp(base, exp, mod.m)
+	}
+	return mod.fixed.exp(base, windows(exp))
+}
+
+// ExpBatch retorna bases[i]^exp mod m para cada i, o formato das iteracoes
+// de um teste de primalidade com o mesmo candidato e bases diferentes: a
+// decomposicao de exp em janelas eh feita uma unica vez
+func (mod *Modulus) ExpBatch(bases []*big.Int, exp *big.Int) []*big.Int {
+	out := make([]*big.Int, len(bases))
+	if mod.fixed == nil || exp.Sign() < 0 {
+		for i, base := range bases {
+			out[i] = new(big.Int).Exp(base, exp, mod.m)
+		}
+		return out
+	}
+	digits := windows(exp)
+	for i, base := range bases {
+		out[i] = mod.fixed.exp(base, digits)
+	}
+	return out
+}
+
+// Square calcula z = x^2 mod m, com 0 <= x < m, e retorna z. z pode ser x:
+// com largura fixa, o quadrado no lugar nao aloca memoria.
+func (mod *Modulus) Square(z, x *big.Int) *big.Int {
+	if mod.fixed != nil {
+		return mod.fixed.square(z, x)
+	}
+	square, quo := scratch.Get().(*big.Int), scratch.Get().(*big.Int)
+	square.Mul(x, x)
+	quo.QuoRem(square, mod.m, z)
+	scratch.Put(square)
+	scratch.Put(quo)
+	return z
+}
+
+// scratch guarda os valores temporarios dos quadrados com o math/big
+var scratch = sync.Pool{New: func() any { return new(big.Int) }}
+
+// windows decompoe e >= 0 em janelas de 4 bits, da mais significativa para
+// a menos significativa
+func windows(e *big.Int) []uint8 {
+	digits := make([]uint8, 0, (e.BitLen()+3)/4)
+	for pos := (e.BitLen()+3)/4*4 - 4; pos >= 0; pos -= 4 {
+		digits = append(digits, uint8(e.Bit(pos+3)<<3|e.Bit(pos+2)<<2|e.Bit(pos+1)<<1|e.Bit(pos)))
+	}
+	return digits
+}
@@ -0,0 +1,130 @@
+package modexp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// randomOdd sorteia um modulo impar de exatamente bits bits
+func randomOdd(t testing.TB, bits int) *big.Int {
+	m, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.SetBit(m.SetBit(m, bits-1, 1), 0, 1)
+}
+
+func TestModulus(t *testing.T) {
+	for _, bits := range []int{2, 3, 40, 63, 64, 65, 127, 128, 129, 192, 255, 256, 257, 512} {
+		for range 20 {
+			m := randomOdd(t, bits)
+			mod, err := New(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mod.Fixed() != (bits <= MaxFixedBits) {
+				t.Fatalf("%d bits: Fixed() = %v", bits, mod.Fixed())
+			}
+			exp, _ := rand.Int(rand.Reader, m)
+			bases := make([]*big.Int, 4)
+			for i := range bases {
+				bases[i], _ = rand.Int(rand.Reader, new(big.Int).Lsh(m, 8))
+			}
+			batch := mod.ExpBatch(bases, exp)
+			for i, base := range bases {
+				want := new(big.Int).Exp(base, exp, m)
+				if got := mod.Exp(base, exp); got.Cmp(want) != 0 {
+					t.Fatalf("%d bits: %s^%s mod %s = %s, esperado %s", bits, base, exp, m, got, want)
+				}
+				if batch[i].Cmp(want) != 0 {
+					t.Fatalf("%d bits: ExpBatch difere de Exp", bits)
+				}
+			}
+
+			x := new(big.Int).Mod(bases[0], m)
+			want := new(big.Int).Mul(x, x)
+			want.Mod(want, m)
+			if mod.Square(x, x); x.Cmp(want) != 0 {
+				t.Fatalf("%d bits: quadrado %s, esperado %s", bits, x, want)
+			}
+		}
+	}
+
+	// Casos de borda
+	mod, _ := New(big.NewInt(1000003))
+	cases := [][2]int64{{5, 0}, {0, 7}, {-5, 3}, {3, -1}, {1000003, 2}}
+	for _, c := range cases {
+		base, exp := big.NewInt(c[0]), big.NewInt(c[1])
+		if want, got := new(big.Int).Exp(base, exp, big.NewInt(1000003)), mod.Exp(base, exp); got.Cmp(want) != 0 {
+			t.Errorf("%v: %s, esperado %s", c, got, want)
+		}
+	}
+	for _, m := range []int64{0, 1, 2, 1000, -7} {
+		if _, err := New(big.NewInt(m)); err != ErrModulus {
+			t.Errorf("modulo %d aceito", m)
+		}
+	}
+}
+
+// Compara, nas iteracoes de um Miller-Rabin com o mesmo candidato, o
+// math/big com o modulo preparado uma vez, base a base ou em lote
+func BenchmarkRounds(b *testing.B) {
+	const rounds = 20
+	for _, bits := range []int{64, 128, 256, 512} {
+		m := randomOdd(b, bits)
+		exp := new(big.Int).Rsh(m, 1)
+		bases := make([]*big.Int, rounds)
+		for i := range bases {
+			bases[i], _ = rand.Int(rand.Reader, m)
+		}
+		b.Run(fmt.Sprintf("big-%d", bits), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, base := range bases {
+					new(big.Int).Exp(base, exp, m)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("prepared-%d", bits), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mod, _ := New(m)
+				for _, base := range bases {
+					mod.Exp(base, exp)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("batch-%d", bits), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mod, _ := New(m)
+				mod.ExpBatch(bases, exp)
+			}
+		})
+	}
+}
+
+// Quadrados sucessivos no lugar, como no Blum Blum Shub
+func BenchmarkSquare(b *testing.B) {
+	for _, bits := range []int{128, 256, 512} {
+		m := randomOdd(b, bits)
+		x, _ := rand.Int(rand.Reader, m)
+		b.Run(fmt.Sprintf("big-%d", bits), func(b *testing.B) {
+			b.ReportAllocs()
+			square, quo := new(big.Int), new(big.Int)
+			for i := 0; i < b.N; i++ {
+				square.Mul(x, x)
+				quo.QuoRem(square, m, x)
+			}
+		})
+		b.Run(fmt.Sprintf("modexp-%d", bits), func(b *testing.B) {
+			b.ReportAllocs()
+			mod, _ := New(m)
+			for i := 0; i < b.N; i++ {
+				mod.Square(x, x)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// O pacote sieve traz os lacos internos do crivo de numutil.SegmentedSieve:
+// riscar os multiplos de um primo e procurar o proximo numero nao riscado.
+// Em amd64 e arm64 eles tem versoes em assembly (kernels_amd64.s e
+// kernels_arm64.s), sem a verificacao de limites a cada acesso; a tag
+// purego forca as versoes em Go, que ficam sempre compiladas para os testes
+// e benchmarks. Por ser interno, o pacote pode mudar a qualquer versao.
+package sieve
+
+// MarkMultiplesGeneric marca composite[first], composite[first+step], ...
+// ate o fim de composite. step deve ser positivo.
+func MarkMultiplesGeneric(composite []bool, first, step uint64) {
+	for m := first; m < uint64(len(composite)); m += step {
+		composite[m] = true
+	}
+}
+
+// FirstUnmarkedGeneric retorna o indice do primeiro valor falso de
+// composite a partir de from (0 <= from <= len(composite)), ou
+// len(composite) se nao houver nenhum
+func FirstUnmarkedGeneric(composite []bool, from int) int {
+	for i := from; i < len(composite); i++ {
+		if !composite[i] {
+			return i
+		}
+	}
+	return len(composite)
+}
@@ -0,0 +1,68 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func MarkMultiples(composite []bool, first, step uint64)
+TEXT ·MarkMultiples(SB), NOSPLIT, $0-40
+	MOVQ composite_base+0(FP), DI
+	MOVQ composite_len+8(FP), CX
+	MOVQ first+24(FP), AX
+	MOVQ step+32(FP), DX
+	// Desenrolado em quatro enquanto couberem quatro multiplos
+	LEAQ (DX)(DX*2), R8
+loop4:
+	LEAQ (AX)(R8*1), R9
+	CMPQ R9, CX
+	JAE tail
+	MOVB $1, (DI)(AX*1)
+	ADDQ DX, AX
+	MOVB $1, (DI)(AX*1)
+	ADDQ DX, AX
+	MOVB $1, (DI)(AX*1)
+	ADDQ DX, AX
+	MOVB $1, (DI)(AX*1)
+	ADDQ DX, AX
+	JMP loop4
+tail:
+	CMPQ AX, CX
+	JAE done
+	MOVB $1, (DI)(AX*1)
+	ADDQ DX, AX
+	JMP tail
+done:
+	RET
+
+// func FirstUnmarked(composite []bool, from int) int
+TEXT ·FirstUnmarked(SB), NOSPLIT, $0-40
+	MOVQ composite_base+0(FP), SI
+	MOVQ composite_len+8(FP), CX
+	MOVQ from+24(FP), AX
+	PXOR X0, X0
+	// Compara 16 bytes por vez com zero (SSE2); a mascara dos bytes iguais
+	// a zero da a posicao do primeiro nao riscado
+vec:
+	LEAQ 16(AX), DX
+	CMPQ DX, CX
+	JA scalar
+	MOVOU (SI)(AX*1), X1
+	PCMPEQB X0, X1
+	PMOVMSKB X1, DX
+	TESTL DX, DX
+	JNZ found
+	ADDQ $16, AX
+	JMP vec
+found:
+	BSFL DX, DX
+	ADDQ DX, AX
+	MOVQ AX, ret+32(FP)
+	RET
+scalar:
+	CMPQ AX, CX
+	JAE out
+	CMPB (SI)(AX*1), $0
+	JEQ out
+	INCQ AX
+	JMP scalar
+out:
+	MOVQ AX, ret+32(FP)
+	RET
@@ -0,0 +1,64 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func MarkMultiples(composite []bool, first, step uint64)
+TEXT ·MarkMultiples(SB), NOSPLIT, $0-40
+	MOVD composite_base+0(FP), R0
+	MOVD composite_len+8(FP), R1
+	MOVD first+24(FP), R2
+	MOVD step+32(FP), R3
+	MOVD $1, R4
+	// Desenrolado em quatro enquanto couberem quatro multiplos
+	ADD R3<<1, R3, R5
+loop4:
+	ADD R5, R2, R6
+	CMP R1, R6
+	BHS tail
+	MOVB R4, (R0)(R2)
+	ADD R3, R2, R2
+	MOVB R4, (R0)(R2)
+	ADD R3, R2, R2
+	MOVB R4, (R0)(R2)
+	ADD R3, R2, R2
+	MOVB R4, (R0)(R2)
+	ADD R3, R2, R2
+	B loop4
+tail:
+	CMP R1, R2
+	BHS done
+	MOVB R4, (R0)(R2)
+	ADD R3, R2, R2
+	B tail
+done:
+	RET
+
+// func FirstUnmarked(composite []bool, from int) int
+TEXT ·FirstUnmarked(SB), NOSPLIT, $0-40
+	MOVD composite_base+0(FP), R0
+	MOVD composite_len+8(FP), R1
+	MOVD from+24(FP), R2
+	// Carrega 16 bytes por vez (NEON); como os valores sao 0 ou 1, o minimo
+	// dos bytes eh zero se algum deles nao foi riscado, e a busca termina
+	// byte a byte
+vec:
+	ADD $16, R2, R3
+	CMP R1, R3
+	BHI scalar
+	ADD R0, R2, R4
+	VLD1 (R4), [V0.B16]
+	VUMINV V0.B16, V1
+	VMOV V1.B[0], R5
+	CBZ R5, scalar
+	MOVD R3, R2
+	B vec
+scalar:
+	CMP R1, R2
+	BHS out
+	MOVBU (R0)(R2), R5
+	CBZ R5, out
+	ADD $1, R2, R2
+	B scalar
+out:
+	MOVD R2, ret+32(FP)
+	RET
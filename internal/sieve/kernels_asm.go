@@ -0,0 +1,12 @@
+//go:build (amd64 || arm64) && !purego
+
+package sieve
+
+// Implementadas em kernels_amd64.s e kernels_arm64.s, com os mesmos
+// contratos de MarkMultiplesGeneric e FirstUnmarkedGeneric
+
+//go:noescape
+func MarkMultiples(composite []bool, first, step uint64)
+
+//go:noescape
+func FirstUnmarked(composite []bool, from int) int
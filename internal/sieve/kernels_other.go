@@ -0,0 +1,11 @@
+//go:build !(amd64 || arm64) || purego
+
+package sieve
+
+func MarkMultiples(composite []bool, first, step uint64) {
+	MarkMultiplesGeneric(composite, first, step)
+}
+
+func FirstUnmarked(composite []bool, from int) int {
+	return FirstUnmarkedGeneric(composite, from)
+}
@@ -0,0 +1,56 @@
+package sieve
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSieveKernels(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		size := rng.IntN(300)
+		first, step := uint64(rng.IntN(size+20)), uint64(1+rng.IntN(40))
+		got, want := make([]bool, size), make([]bool, size)
+		MarkMultiples(got, first, step)
+		MarkMultiplesGeneric(want, first, step)
+		if !slices.Equal(got, want) {
+			t.Fatalf("MarkMultiples(%d, %d, %d) difere da versao em Go", size, first, step)
+		}
+		for from := 0; from <= size; from++ {
+			if i, j := FirstUnmarked(got, from), FirstUnmarkedGeneric(got, from); i != j {
+				t.Fatalf("FirstUnmarked a partir de %d = %d, esperado %d", from, i, j)
+			}
+		}
+	}
+}
+
+func BenchmarkSieveKernels(b *testing.B) {
+	composite := make([]bool, 1<<16)
+	b.Run("mark", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MarkMultiples(composite, 0, 3)
+		}
+	})
+	b.Run("mark-generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MarkMultiplesGeneric(composite, 0, 3)
+		}
+	})
+	clear(composite)
+	for i := range composite {
+		composite[i] = i%1000 != 999
+	}
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := FirstUnmarked(composite, 0); j < len(composite); j = FirstUnmarked(composite, j+1) {
+			}
+		}
+	})
+	b.Run("scan-generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := FirstUnmarkedGeneric(composite, 0); j < len(composite); j = FirstUnmarkedGeneric(composite, j+1) {
+			}
+		}
+	})
+}
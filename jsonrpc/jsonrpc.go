@@ -0,0 +1,268 @@
+// O pacote jsonrpc atende requisicoes JSON-RPC 2.0 lidas de um io.Reader,
+// uma por linha, e escreve as respostas em um io.Writer, tambem uma por
+// linha. Com a entrada e a saida padrao, permite controlar o gerador a
+// partir de linguagens de script sem cgo nem rede.
+//
+// Metodos:
+//
+//	generate {"bits": 512, "test": "miller-rabin"}        gera um primo
+//	check    {"n": "0x...", "test": "fermat"}             testa a primalidade de n
+//	stream   {"generator": "bbs", "bits": 256, "count": 10}
+//
+// O metodo stream envia cada numero gerado como uma notificacao
+// "stream.item" ({"id": <id da requisicao>, "index": i, "value": "hex"})
+// e termina com a resposta {"count": n}.
+package jsonrpc
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Codigos de erro definidos pela especificacao JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	// CodeServerError indica uma falha ao atender a requisicao, como a
+	// falta de entropia no nivel Strict
+	CodeServerError = -32000
+)
+
+// Limites das requisicoes
+const (
+	maxBits  = 1 << 14
+	maxCount = 1 << 16
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// Error eh o objeto de erro do JSON-RPC
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorf(code int, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// testResult eh o resultado de generate e check
+type testResult struct {
+	Test   string     `json:"test"`
+	Result pta.Result `json:"result"`
+}
+
+// streamItem eh o parametro das notificacoes de stream
+type streamItem struct {
+	ID    json.RawMessage `json:"id"`
+	Index int             `json:"index"`
+	Value string          `json:"value"`
+}
+
+// Serve atende as requisicoes lidas de r ate o fim da entrada, usando cfg
+// nos testes de primalidade. Retorna apenas erros de leitura ou escrita.
+func Serve(r io.Reader, w io.Writer, cfg pta.Config) error {
+	s := &session{cfg: cfg, enc: json.NewEncoder(w)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := s.handle(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// session guarda o estado de uma conexao
+type session struct {
+	cfg pta.Config
+	enc *json.Encoder
+}
+
+func (s *session) handle(line []byte) error {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return s.enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: errorf(CodeParseError, "json invalido: %v", err)})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return s.reply(req, nil, errorf(CodeInvalidRequest, "requisicao invalida"))
+	}
+
+	var result any
+	var rpcErr *Error
+	switch req.Method {
+	case "generate":
+		result, rpcErr = s.generate(req.Params)
+	case "check":
+		result, rpcErr = s.check(req.Params)
+	case "stream":
+		result, rpcErr = s.stream(req)
+	default:
+		rpcErr = errorf(CodeMethodNotFound, "metodo desconhecido %q", req.Method)
+	}
+	return s.reply(req, result, rpcErr)
+}
+
+// reply envia a resposta, exceto para notificacoes (requisicoes sem id)
+func (s *session) reply(req request, result any, rpcErr *Error) error {
+	if req.ID == nil {
+		return nil
+	}
+	return s.enc.Encode(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+}
+
+// decode le os parametros da requisicao em v
+func decode(params json.RawMessage, v any) *Error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return errorf(CodeInvalidParams, "parametros invalidos: %v", err)
+	}
+	return nil
+}
+
+// test retorna o teste de nome name (miller-rabin se vazio)
+func test(name string) (pta.PrimalityTest, *Error) {
+	if name == "" {
+		name = "miller-rabin"
+	}
+	t, err := pta.Get(name)
+	if err != nil {
+		return nil, errorf(CodeInvalidParams, "%v", err)
+	}
+	return t, nil
+}
+
+func (s *session) generate(params json.RawMessage) (any, *Error) {
+	var p struct {
+		Bits int    `json:"bits"`
+		Test string `json:"test"`
+	}
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Bits < 2 || p.Bits > maxBits {
+		return nil, errorf(CodeInvalidParams, "bits deve estar entre 2 e %d", maxBits)
+	}
+	t, rpcErr := test(p.Test)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	candidate, err := s.cfg.Entropy().Bits(p.Bits)
+	if err != nil {
+		return nil, errorf(CodeServerError, "%v", err)
+	}
+	res, err := pta.Generate(p.Bits, candidate, t, s.cfg)
+	if err != nil {
+		return nil, errorf(CodeServerError, "%v", err)
+	}
+	return testResult{Test: t.Name(), Result: res}, nil
+}
+
+func (s *session) check(params json.RawMessage) (any, *Error) {
+	var p struct {
+		N    string `json:"n"`
+		Test string `json:"test"`
+	}
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+	// A base 0 aceita decimal e hexadecimal com prefixo 0x
+	n, ok := new(big.Int).SetString(p.N, 0)
+	if !ok {
+		return nil, errorf(CodeInvalidParams, "n deve ser um inteiro decimal ou hexadecimal (0x...)")
+	}
+	t, rpcErr := test(p.Test)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	res := t.IsPrime(n, s.cfg)
+	if res.Err != nil {
+		return nil, errorf(CodeServerError, "%v", res.Err)
+	}
+	return testResult{Test: t.Name(), Result: res}, nil
+}
+
+func (s *session) stream(req request) (any, *Error) {
+	p := struct {
+		Generator string `json:"generator"`
+		Bits      int    `json:"bits"`
+		Count     int    `json:"count"`
+	}{Generator: "bbs", Bits: 256, Count: 1}
+	if err := decode(req.Params, &p); err != nil {
+		return nil, err
+	}
+	if p.Bits < 2 || p.Bits > maxBits || p.Count < 1 || p.Count > maxCount {
+		return nil, errorf(CodeInvalidParams, "bits deve estar entre 2 e %d e count entre 1 e %d", maxBits, maxCount)
+	}
+	if p.Generator == "bbs" && p.Bits < prng.MinBBSBits {
+		return nil, errorf(CodeInvalidParams, "o gerador bbs exige bits >= %d", prng.MinBBSBits)
+	}
+
+	var g prng.Generator
+	var err error
+	switch p.Generator {
+	case "bbs":
+		g, err = prng.NewBBSWithEntropy(p.Bits, s.cfg.Entropy())
+	case "lfg":
+		// Mesmos parametros e aquecimento da demonstracao prng.Lfg
+		var lfg *prng.LaggedFibonacciGenerator
+		if lfg, err = prng.NewLFGWithEntropy(10, 7, 10, p.Bits, s.cfg.Entropy()); err == nil {
+			lfg.Discard(prng.DefaultWarmup(10))
+			g = lfg
+		}
+	default:
+		return nil, errorf(CodeInvalidParams, "gerador desconhecido %q: use bbs ou lfg", p.Generator)
+	}
+	if err != nil {
+		return nil, errorf(CodeServerError, "%v", err)
+	}
+
+	for i := 0; i < p.Count; i++ {
+		item := notification{
+			JSONRPC: "2.0",
+			Method:  "stream.item",
+			Params:  streamItem{ID: req.ID, Index: i, Value: g.Next().Text(16)},
+		}
+		if err := s.enc.Encode(item); err != nil {
+			return nil, errorf(CodeServerError, "%v", err)
+		}
+	}
+	return map[string]int{"count": p.Count}, nil
+}
@@ -0,0 +1,72 @@
+package jsonrpc
+
+import (
+	"PrimeNumGenerator/pta"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"generate","params":{"bits":64}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"check","params":{"n":"0x5b","test":"fermat"}}`,
+		`{"jsonrpc":"2.0","id":"s","method":"stream","params":{"generator":"lfg","bits":32,"count":3}}`,
+		`{"jsonrpc":"2.0","method":"check","params":{"n":"7"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"inexistente"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"generate","params":{"bits":1}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"stream","params":{"generator":"bbs","bits":4}}`,
+		`nao eh json`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := Serve(strings.NewReader(in), &out, pta.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	type message struct {
+		ID     json.RawMessage
+		Method string
+		Result struct {
+			Test   string
+			Count  int
+			Result struct{ Prime bool }
+		}
+		Error *Error
+	}
+	var msgs []message
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("linha invalida %q: %v", line, err)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	// generate, check, 3 notificacoes + stream, metodo inexistente, bits
+	// invalidos (dois) e json invalido; a notificacao check nao tem resposta
+	if len(msgs) != 10 {
+		t.Fatalf("%d mensagens:\n%s", len(msgs), out.String())
+	}
+	if !msgs[0].Result.Result.Prime || msgs[0].Result.Test != "miller-rabin" {
+		t.Errorf("generate: %+v", msgs[0])
+	}
+	// 0x5b = 91 = 7 * 13
+	if msgs[1].Result.Test != "fermat" || msgs[1].Result.Result.Prime || msgs[1].Error != nil {
+		t.Errorf("check: %+v", msgs[1])
+	}
+	for i := 2; i < 5; i++ {
+		if msgs[i].Method != "stream.item" {
+			t.Errorf("mensagem %d nao eh item do stream: %+v", i, msgs[i])
+		}
+	}
+	if msgs[5].Result.Count != 3 || string(msgs[5].ID) != `"s"` {
+		t.Errorf("stream: %+v", msgs[5])
+	}
+	codes := []int{CodeMethodNotFound, CodeInvalidParams, CodeInvalidParams, CodeParseError}
+	for i, code := range codes {
+		if e := msgs[6+i].Error; e == nil || e.Code != code {
+			t.Errorf("mensagem %d: erro %+v, esperado codigo %d", 6+i, e, code)
+		}
+	}
+}
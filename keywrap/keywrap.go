@@ -0,0 +1,111 @@
+// O pacote keywrap cifra os estados dos geradores gravados em disco (como
+// o checkpoint do farol) com AES-256-GCM, sob uma chave derivada de uma
+// senha pelo scrypt, para que os arquivos nao revelem sementes nem os
+// fatores do BBS.
+//
+// O formato eh o cabecalho "PGWRAP", versao, log2 de N, r e p do scrypt,
+// sal (16 bytes) e nonce (12 bytes), seguido do texto cifrado com a tag do
+// GCM. O cabecalho inteiro eh autenticado como dado adicional.
+package keywrap
+
+import (
+	"PrimeNumGenerator/prng"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+)
+
+// Parametros do scrypt usados por Seal: N = 2^15 e r = 8 custam 32 MiB e
+// cerca de 100 ms por derivacao
+const (
+	logN = 15
+	r    = 8
+	p    = 1
+
+	// Limites aceitos por Open, para que um arquivo adulterado nao exija
+	// memoria ou tempo absurdos antes de a tag ser conferida
+	maxLogN = 20
+	maxR    = 32
+	maxP    = 16
+)
+
+const (
+	magic     = "PGWRAP"
+	version   = 1
+	saltSize  = 16
+	nonceSize = 12
+	keySize   = 32
+	headerLen = len(magic) + 4 + saltSize + nonceSize
+)
+
+// ErrWrongPassphrase indica que a senha esta errada ou que os dados foram
+// alterados: o GCM nao distingue os dois casos
+var ErrWrongPassphrase = errors.New("keywrap: senha incorreta ou dados corrompidos")
+
+// IsSealed informa se data foi produzido por Seal
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// Seal cifra plaintext com uma chave derivada de passphrase, com sal e
+// nonce sorteados com a entropia e
+func Seal(plaintext, passphrase []byte, e prng.Entropy) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("keywrap: senha vazia")
+	}
+	header := make([]byte, headerLen)
+	copy(header, magic)
+	header[len(magic)], header[len(magic)+1], header[len(magic)+2], header[len(magic)+3] = version, logN, r, p
+	if _, err := e.Read(header[len(magic)+4:]); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[headerLen-nonceSize:]
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// Open decifra os dados produzidos por Seal com a mesma senha
+func Open(sealed, passphrase []byte) ([]byte, error) {
+	if !IsSealed(sealed) || len(sealed) < headerLen {
+		return nil, errors.New("keywrap: os dados nao estao cifrados")
+	}
+	if v := sealed[len(magic)]; v != version {
+		return nil, fmt.Errorf("keywrap: versao %d nao suportada", v)
+	}
+	header := sealed[:headerLen]
+	aead, err := newAEAD(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, header[headerLen-nonceSize:], sealed[headerLen:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// newAEAD cria o AES-256-GCM com a chave derivada da senha e dos
+// parametros e do sal do cabecalho
+func newAEAD(passphrase, header []byte) (cipher.AEAD, error) {
+	params := header[len(magic)+1 : len(magic)+4]
+	cost, blockSize, parallel := int(params[0]), int(params[1]), int(params[2])
+	if cost < 1 || cost > maxLogN || blockSize < 1 || blockSize > maxR || parallel < 1 || parallel > maxP {
+		return nil, fmt.Errorf("keywrap: parametros do scrypt fora dos limites (N = 2^%d, r = %d, p = %d)", cost, blockSize, parallel)
+	}
+	salt := header[len(magic)+4 : len(magic)+4+saltSize]
+	key, err := scrypt(passphrase, salt, 1<<cost, blockSize, parallel, keySize)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,70 @@
+package keywrap
+
+import (
+	"PrimeNumGenerator/prng"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestScryptVectors(t *testing.T) {
+	// Vetores da RFC 7914
+	cases := []struct {
+		password, salt string
+		n, r, p        int
+		want           string
+	}{
+		{"", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+	}
+	for _, c := range cases {
+		key, err := scrypt([]byte(c.password), []byte(c.salt), c.n, c.r, c.p, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key); got != c.want {
+			t.Errorf("scrypt(%q, %q) = %s, esperado %s", c.password, c.salt, got, c.want)
+		}
+	}
+	if got := hex.EncodeToString(pbkdf2([]byte("passwd"), []byte("salt"), 1, 64)); got != "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783" {
+		t.Errorf("PBKDF2 = %s", got)
+	}
+	if _, err := scrypt(nil, nil, 1000, 1, 1, 32); err == nil {
+		t.Error("N que nao eh potencia de 2 aceito")
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	state := []byte("estado do BBS com os fatores p e q")
+	sealed, err := Seal(state, []byte("senha"), prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, state) || IsSealed(state) {
+		t.Fatal("estado gravado sem cifrar")
+	}
+	got, err := Open(sealed, []byte("senha"))
+	if err != nil || !bytes.Equal(got, state) {
+		t.Fatalf("Open = %q, %v", got, err)
+	}
+
+	if _, err := Open(sealed, []byte("errada")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("senha errada: %v", err)
+	}
+	// Alterar o sal (ou qualquer byte do cabecalho) invalida a tag
+	tampered := bytes.Clone(sealed)
+	tampered[headerLen-nonceSize-1] ^= 1
+	if _, err := Open(tampered, []byte("senha")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("sal alterado: %v", err)
+	}
+	// Parametros absurdos sao recusados antes da derivacao
+	tampered = bytes.Clone(sealed)
+	tampered[len(magic)+1] = 40
+	if _, err := Open(tampered, []byte("senha")); err == nil || errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("N = 2^40: %v", err)
+	}
+	if _, err := Seal(state, nil, prng.Entropy{}); err == nil {
+		t.Error("senha vazia aceita")
+	}
+}
@@ -0,0 +1,126 @@
+package keywrap
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// pbkdf2 deriva keyLen bytes da senha com o PBKDF2-HMAC-SHA256 (RFC 8018)
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen+sha256.Size)
+	var counter [4]byte
+	u := make([]byte, sha256.Size)
+	for block := uint32(1); len(key) < keyLen; block++ {
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// scrypt deriva keyLen bytes da senha com a funcao scrypt (RFC 7914), de
+// custo n (uma potencia de 2), tamanho de bloco r e paralelismo p. A
+// memoria usada eh de cerca de 128*n*r bytes.
+func scrypt(password, salt []byte, n, r, p, keyLen int) ([]byte, error) {
+	if n < 2 || n&(n-1) != 0 || r < 1 || p < 1 || uint64(r)*uint64(p) >= 1<<30 {
+		return nil, errors.New("keywrap: parametros do scrypt invalidos")
+	}
+	b := pbkdf2(password, salt, 1, p*128*r)
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*n*r)
+	for i := 0; i < p; i++ {
+		smix(b[i*128*r:], r, n, v, xy)
+	}
+	return pbkdf2(password, b, 1, keyLen), nil
+}
+
+// smix aplica o ROMix do scrypt ao bloco b de 128*r bytes, usando v e xy
+// como memoria de trabalho
+func smix(b []byte, r, n int, v, xy []uint32) {
+	var tmp [16]uint32
+	words := 32 * r
+	x, y := xy[:words], xy[words:]
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	for i := 0; i < n; i += 2 {
+		copy(v[i*words:], x)
+		blockMix(&tmp, x, y, r)
+		copy(v[(i+1)*words:], y)
+		blockMix(&tmp, y, x, r)
+	}
+	for i := 0; i < n; i += 2 {
+		// Integerify: a primeira palavra do ultimo bloco de 64 bytes
+		j := int(x[(2*r-1)*16] & uint32(n-1))
+		blockXOR(x, v[j*words:(j+1)*words])
+		blockMix(&tmp, x, y, r)
+		j = int(y[(2*r-1)*16] & uint32(n-1))
+		blockXOR(y, v[j*words:(j+1)*words])
+		blockMix(&tmp, y, x, r)
+	}
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[4*i:], w)
+	}
+}
+
+// blockMix aplica o BlockMix do scrypt a in, de 2*r blocos de 16 palavras,
+// escrevendo em out os blocos pares seguidos dos impares
+func blockMix(tmp *[16]uint32, in, out []uint32, r int) {
+	copy(tmp[:], in[(2*r-1)*16:])
+	for i := 0; i < 2*r; i += 2 {
+		salsaXOR(tmp, in[i*16:], out[i*8:])
+		salsaXOR(tmp, in[i*16+16:], out[i*8+r*16:])
+	}
+}
+
+func blockXOR(dst, src []uint32) {
+	for i, w := range src {
+		dst[i] ^= w
+	}
+}
+
+// salsaXOR faz tmp ^= in, aplica o Salsa20/8 a tmp e copia o resultado
+// para out
+func salsaXOR(tmp *[16]uint32, in, out []uint32) {
+	for i := range tmp {
+		tmp[i] ^= in[i]
+	}
+	x := *tmp
+	quarter := func(a, b, c, d int) {
+		x[b] ^= bits.RotateLeft32(x[a]+x[d], 7)
+		x[c] ^= bits.RotateLeft32(x[b]+x[a], 9)
+		x[d] ^= bits.RotateLeft32(x[c]+x[b], 13)
+		x[a] ^= bits.RotateLeft32(x[d]+x[c], 18)
+	}
+	for round := 0; round < 8; round += 2 {
+		// Colunas e depois linhas
+		quarter(0, 4, 8, 12)
+		quarter(5, 9, 13, 1)
+		quarter(10, 14, 2, 6)
+		quarter(15, 3, 7, 11)
+		quarter(0, 1, 2, 3)
+		quarter(5, 6, 7, 4)
+		quarter(10, 11, 8, 9)
+		quarter(15, 12, 13, 14)
+	}
+	for i := range tmp {
+		tmp[i] += x[i]
+	}
+	copy(out[:16], tmp[:])
+}
@@ -1,41 +1,405 @@
 package main
 
 import (
+	"PrimeNumGenerator/cli"
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/history"
+	"PrimeNumGenerator/keywrap"
+	"PrimeNumGenerator/manifest"
+	"PrimeNumGenerator/numfmt"
 	"PrimeNumGenerator/prng"
 	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/sink"
+	"context"
+	"encoding/hex"
+	"flag"
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-func LaggedFibonacci() {
-	bitSizes, generatedNumbers := prng.Lfg()
-	for i, size := range bitSizes {
-		pta.MillerRabin(generatedNumbers[i], size)
-		pta.Fermat(generatedNumbers[i], size)
+func LaggedFibonacci(cfg prng.DemoConfig, testCfg pta.Config, rec *recorder) error {
+	rec.watch("lfg", &cfg)
+	bitSizes, generatedNumbers, err := prng.Lfg(cfg)
+	if err != nil {
+		return err
+	}
+	return testCandidates(bitSizes, generatedNumbers, testCfg, rec, cfg.Format)
+}
+
+func Bbs(cfg prng.DemoConfig, testCfg pta.Config, rec *recorder) error {
+	rec.watch("bbs", &cfg)
+	bitSizes, generatedNumbers, err := prng.Bbs(cfg)
+	if err != nil {
+		return err
+	}
+	return testCandidates(bitSizes, generatedNumbers, testCfg, rec, cfg.Format)
+}
+
+// demoTests sao os testes aplicados a cada candidato das demonstracoes e
+// seus titulos na saida
+var demoTests = []struct {
+	test  pta.PrimalityTest
+	title string
+}{
+	{mustTest("miller-rabin"), "Miller-Rabin"},
+	{mustTest("fermat"), "Fermat"},
+}
+
+func mustTest(name string) pta.PrimalityTest {
+	test, err := pta.Get(name)
+	if err != nil {
+		panic(err)
 	}
+	return test
 }
 
-func Bbs() {
-	bitSizes, generatedNumbers := prng.Bbs()
+// testCandidates gera um primo a partir de cada candidato usando os dois
+// testes de primalidade, exibe os resultados com a formatacao format e, ao
+// final, a tabela que compara os testes em cada candidato
+func testCandidates(bitSizes []int, candidates []*big.Int, cfg pta.Config, rec *recorder, format numfmt.Format) error {
+	tests := make([]pta.PrimalityTest, len(demoTests))
+	for i, t := range demoTests {
+		tests[i] = t.test
+	}
+	var comparisons []pta.Comparison
 	for i, size := range bitSizes {
-		pta.MillerRabin(generatedNumbers[i], size)
-		pta.Fermat(generatedNumbers[i], size)
+		c, err := pta.Compare(size, candidates[i], tests, cfg)
+		if err != nil {
+			return err
+		}
+		for j, res := range c.Results {
+			printResult(demoTests[j].title, size, res, format)
+			if err := rec.record(c.Tests[j], size, cfg, res); err != nil {
+				return err
+			}
+		}
+		comparisons = append(comparisons, c)
 	}
+	printComparisons(comparisons)
+	return nil
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Use: go run main.go [fibonacci|bbs]")
+// printComparisons exibe, em uma tabela, as tentativas e o tempo de cada
+// teste em cada candidato, a diferenca de tempo para o primeiro teste e se
+// os testes concordaram
+func printComparisons(comparisons []pta.Comparison) {
+	if len(comparisons) == 0 {
 		return
 	}
+	title := "Comparação dos testes"
+	fmt.Println("\n" + title)
+	fmt.Println(strings.Repeat("=", utf8.RuneCountInString(title)))
+	fmt.Printf("%5s  %-12s  %10s  %12s  %12s  %s\n", "bits", "teste", "tentativas", "tempo", "diferença", "vereditos")
+	yesNo := map[bool]string{true: "sim", false: "não"}
+	for _, c := range comparisons {
+		for i, res := range c.Results {
+			bits, delta, agreement := "", "-", ""
+			if i == 0 {
+				bits = fmt.Sprint(c.Bits)
+				agreement = fmt.Sprintf("%d iguais, %d divergentes, mesmo primo: %s", c.Agreed, len(c.Disagreements), yesNo[c.SamePrime()])
+			} else {
+				if delta = c.Delta(i).Round(time.Microsecond).String(); c.Delta(i) >= 0 {
+					delta = "+" + delta
+				}
+			}
+			line := fmt.Sprintf("%5s  %-12s  %10d  %12v  %12s  %s", bits, c.Tests[i], res.Attempts, res.Duration.Round(time.Microsecond), delta, agreement)
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+	}
+}
+
+// recorder registra os candidatos de uma execucao de demonstracao e os
+// primos gerados a partir deles no historico, no manifesto da execucao e
+// nos destinos de -out, se houver. Um recorder nil nao registra nada.
+type recorder struct {
+	store     *history.Store     // nil se nao houver historico
+	manifest  *manifest.Manifest // nil se nao houver manifesto
+	out       sink.Tee           // vazio se nao houver destinos
+	command   string
+	generator string
+	// candidatos originais e estados dos geradores, por tamanho em bits
+	candidates map[int]*big.Int
+	states     map[int][]byte
+	// com passphrase, os estados vao cifrados para o historico; sealed
+	// guarda a versao cifrada de cada um, para cifra-lo uma vez so
+	passphrase []byte
+	sealed     map[int][]byte
+}
 
-	switch os.Args[1] {
-	case "fibonacci":
-		LaggedFibonacci()
-	case "bbs":
-		Bbs()
-	default:
-		fmt.Println("Invalid option. Use: fibonacci, bbs")
+// watch passa a acompanhar os candidatos produzidos com cfg
+func (h *recorder) watch(generator string, cfg *prng.DemoConfig) {
+	if h == nil {
 		return
 	}
+	h.generator = generator
+	h.candidates = make(map[int]*big.Int)
+	h.states = make(map[int][]byte)
+	h.sealed = make(map[int][]byte)
+	if h.manifest != nil {
+		h.manifest.Generator = manifest.Generator{Name: generator, Params: cfg.Params(generator)}
+	}
+	cfg.OnCandidate = func(bits int, candidate *big.Int, state []byte) {
+		h.candidates[bits] = candidate
+		h.states[bits] = state
+		delete(h.sealed, bits)
+		if h.manifest != nil {
+			out := h.manifest.Output(bits)
+			out.CandidateSHA256 = manifest.HashInt(candidate)
+			if state != nil {
+				out.StateSHA256 = manifest.HashBytes(state)
+			}
+		}
+	}
+}
+
+// record registra o resultado res da geracao de um primo de bits bits
+func (h *recorder) record(test string, bits int, cfg pta.Config, res pta.Result) error {
+	if h == nil {
+		return nil
+	}
+	if h.manifest != nil {
+		out := h.manifest.Output(bits)
+		out.Results = append(out.Results, manifest.Result{
+			Test:        test,
+			Rounds:      res.Rounds,
+			Attempts:    res.Attempts,
+			PrimeSHA256: manifest.HashInt(res.Number),
+		})
+	}
+	if res.Number != nil && len(h.out) > 0 {
+		r := sink.NewRecord(h.command, res.Number)
+		r.Generator, r.Test, r.Attempts, r.DurationNS = h.generator, test, res.Attempts, res.Duration.Nanoseconds()
+		if err := h.out.Write(context.Background(), r); err != nil {
+			return err
+		}
+	}
+	if h.store == nil {
+		return nil
+	}
+	r := history.NewRecord(h.generator, test, bits, cfg, res).WithCandidate(h.candidates[bits], h.states[bits])
+	if state := h.states[bits]; state != nil && h.passphrase != nil {
+		if h.sealed[bits] == nil {
+			// O sal e o nonce vem do crypto/rand, e nao da entropia da
+			// execucao: com -seed, consumi-la mudaria os candidatos seguintes
+			sealed, err := keywrap.Seal(state, h.passphrase, prng.Entropy{})
+			if err != nil {
+				return err
+			}
+			h.sealed[bits] = sealed
+		}
+		r = r.WithSealedState(h.sealed[bits])
+	}
+	return h.store.Append(r)
+}
+
+// printResult exibe o resultado da geracao de um primo de bits bits
+// usando o teste de nome testName, com os valores formatados por format
+func printResult(testName string, bits int, res pta.Result, format numfmt.Format) {
+	title := "Gerando número primo usando " + testName
+	fmt.Println("\n" + title)
+	fmt.Println(strings.Repeat("=", utf8.RuneCountInString(title)-1))
+
+	fmt.Printf("- Número gerado: %d bits\n", bits)
+	fmt.Printf("- Tempo de execução: %s\n", res.Duration)
+	fmt.Printf("- Tentativas: %d\n", res.Attempts)
+	fmt.Printf("- Tamanho do número gerado: %d dígitos\n", len(res.Number.String()))
+	fmt.Printf("- Tamanho real: %d bits\n", res.Number.BitLen())
+	fmt.Printf("- Valor decimal: %s\n", format.Int(res.Number, 10))
+	fmt.Printf("- Binário: %s\n", format.Int(res.Number, 2))
+}
+
+// commands sao os subcomandos implementados no pacote cli
+var commands = map[string]func(args []string) error{
+	"serve":         cli.Serve,
+	"split":         cli.Split,
+	"combine":       cli.Combine,
+	"stats":         cli.Stats,
+	"grade":         cli.Grade,
+	"soak":          cli.Soak,
+	"curvegen":      cli.Curvegen,
+	"audit":         cli.Audit,
+	"audit-file":    cli.AuditFile,
+	"history":       cli.History,
+	"auditlog":      cli.AuditLog,
+	"jsonrpc":       cli.JSONRPC,
+	"--jsonrpc":     cli.JSONRPC,
+	"worker":        cli.Worker,
+	"coordinator":   cli.Coordinator,
+	"auto":          cli.Auto,
+	"bench":         cli.Bench,
+	"pseudoprime":   cli.Pseudoprime,
+	"check":         cli.Check,
+	"certify":       cli.Certify,
+	"interop":       cli.Interop,
+	"report":        cli.Report,
+	"plot":          cli.Plot,
+	"explore":       cli.Explore,
+	"palindromes":   cli.Palindromes,
+	"repunits":      cli.Repunits,
+	"perfect":       cli.Perfect,
+	"fibprime":      cli.Fibprime,
+	"ntt":           cli.NTT,
+	"dlog":          cli.DLog,
+	"demo-rsa":      cli.DemoRSA,
+	"doctor":        cli.Doctor,
+	"bbs-recover":   cli.BBSRecover,
+	"vanity":        cli.Vanity,
+	"healthcheck":   cli.Healthcheck,
+	"--healthcheck": cli.Healthcheck,
+}
+
+const usage = "Use: go run main.go [fibonacci|bbs|serve|split|combine|curvegen|audit|audit-file|history|auditlog|stats|grade|soak|auto|doctor|bench|verify|ntt|dlog|demo-rsa|bbs-recover|vanity|palindromes|repunits|perfect|fibprime|explore|plot|report|check|certify|interop|pseudoprime|worker|coordinator|--jsonrpc|--healthcheck]"
+
+func main() {
+	if len(os.Args) < 2 {
+		cli.Exit(cli.Usagef("%s", usage))
+	}
+	// PRIMEGEN_CLOCK troca o relogio real por um simulado, para execucoes
+	// e reexecucoes deterministicas
+	c, err := clock.Parse(os.Getenv(clock.Env))
+	if err != nil {
+		cli.Exit(&cli.UsageError{Err: err})
+	}
+	clock.Set(c)
+
+	if os.Args[1] == "verify" {
+		// verify reexecuta as demonstracoes, que ficam neste pacote
+		cli.Exit(cli.Verify(os.Args[2:], demo))
+	}
+	if command, ok := commands[os.Args[1]]; ok {
+		cli.Exit(command(os.Args[2:]))
+	}
+	if os.Args[1] != "fibonacci" && os.Args[1] != "bbs" {
+		cli.Exit(cli.Usagef("Invalid option. %s", usage))
+	}
+	cli.Exit(demo(os.Args[1], os.Args[2:]))
+}
+
+// demo executa as demonstracoes fibonacci e bbs: gera um candidato de cada
+// tamanho com o gerador e busca um primo a partir dele com cada teste
+func demo(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	validate := fs.Bool("validate", false, "compara cada veredito com (*big.Int).ProbablyPrime(64)")
+	constantTime := fs.Bool("constant-time", false, "testa sem saidas antecipadas, omite os candidatos e apaga o estado dos geradores")
+	warmup := fs.Int("warmup", 0, "valores descartados de cada LFG antes do candidato (0 = 10*k, negativo = nenhum)")
+	whiten := fs.String("whiten", "none", "pos-processamento da saida dos geradores: none, vonneumann ou sha256")
+	historyPath := fs.String("history", "", "grava cada geracao no historico em arquivo (veja o subcomando history)")
+	passphraseFile := fs.String("passphrase-file", "", "grava no historico o estado dos geradores, cifrado com uma chave derivada da senha desse arquivo (sem ela, apenas o SHA-256 do estado)")
+	manifestPath := fs.String("manifest", "", "grava o manifesto da execucao (configuracao, semente e hashes dos resultados) nesse arquivo JSON")
+	seedHex := fs.String("seed", "", "semente hexadecimal que torna a execucao deterministica (substitui -entropy; nao use os primos como chaves)")
+	revealSeed := fs.Bool("manifest-seed", false, "inclui a semente em claro no manifesto (por padrao, apenas seu SHA-256)")
+	generationFlags := cli.AddGenerationFlags(fs)
+	entropyFlags := cli.AddEntropyFlags(fs)
+	profileFlags := cli.AddProfileFlags(fs)
+	formatFlags := cli.AddFormatFlags(fs)
+	memoryFlags := cli.AddMemoryFlags(fs)
+	outputFlags := cli.AddOutputFlags(fs)
+	fs.Parse(args)
+
+	if *passphraseFile != "" && *historyPath == "" {
+		return cli.Usagef("-passphrase-file exige -history")
+	}
+	passphrase, err := cli.ReadPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	if err := memoryFlags.Apply(); err != nil {
+		return err
+	}
+	stopProfiles, err := profileFlags.Start()
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	e, err := entropyFlags.Entropy()
+	if err != nil {
+		return err
+	}
+	var seed []byte
+	if *seedHex != "" {
+		if seed, err = hex.DecodeString(*seedHex); err != nil || len(seed) == 0 {
+			return cli.Usagef("-seed deve ser um valor hexadecimal nao vazio")
+		}
+		e.Source = prng.NewSeededSource(seed)
+	}
+
+	whitening, err := prng.ParseWhitening(*whiten)
+	if err != nil {
+		return cli.Usagef("%v", err)
+	}
+	format, err := formatFlags.Format()
+	if err != nil {
+		return err
+	}
+
+	cfg := prng.DemoConfig{Entropy: e, Sensitive: *constantTime, Warmup: *warmup, Whitening: whitening, Format: format}
+	testCfg := cli.TestConfig(e)
+	testCfg.ConstantTime = *constantTime
+	closeGeneration, err := generationFlags.Apply(&testCfg)
+	if err != nil {
+		return err
+	}
+	defer closeGeneration()
+
+	out, err := outputFlags.Open()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var rec *recorder
+	if *historyPath != "" || *manifestPath != "" || len(out) > 0 {
+		rec = &recorder{command: name, out: out}
+	}
+	if *historyPath != "" {
+		store, err := history.Open(*historyPath)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		rec.store, rec.passphrase = store, passphrase
+	}
+	if *manifestPath != "" {
+		source, security := entropyFlags.Names()
+		rec.manifest = manifest.New(name)
+		rec.manifest.Entropy = manifest.Entropy{Source: source, Security: security}
+		if seed != nil {
+			rec.manifest.SetSeed(seed, *revealSeed)
+		}
+		rec.manifest.Tests = manifest.Tests{
+			Names:           []string{"miller-rabin", "fermat"},
+			Rounds:          testCfg.Rounds,
+			ConstantTime:    testCfg.ConstantTime,
+			SmoothnessBound: testCfg.SmoothnessBound,
+			Prescreen:       testCfg.PrescreenPrimes(),
+		}
+		if testCfg.Construction != pta.Increment {
+			rec.manifest.Tests.Construction = testCfg.Construction.String()
+		}
+	}
+
+	pta.SetValidation(*validate)
+	if name == "fibonacci" {
+		err = LaggedFibonacci(cfg, testCfg, rec)
+	} else {
+		err = Bbs(cfg, testCfg, rec)
+	}
+	if err != nil {
+		return err
+	}
+	if *validate {
+		fmt.Printf("\nValidação cruzada: %d divergência(s) encontrada(s)\n", pta.Discrepancies())
+	}
+	if *manifestPath != "" {
+		if err := rec.manifest.Save(*manifestPath); err != nil {
+			return err
+		}
+		fmt.Printf("\nManifesto gravado em %s\n", *manifestPath)
+	}
+	return nil
 }
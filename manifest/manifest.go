@@ -0,0 +1,210 @@
+// O pacote manifest descreve uma execucao de demonstracao em um arquivo
+// JSON: o gerador e seus parametros, a fonte de entropia e a semente (ou
+// apenas seu hash), os testes usados, a versao do programa e o hash de cada
+// candidato e primo produzido. Com a semente, o subcomando verify reexecuta
+// a demonstracao e confere se ela reproduz os mesmos primos.
+package manifest
+
+import (
+	"PrimeNumGenerator/clock"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Manifest eh o manifesto de uma execucao
+type Manifest struct {
+	Version   string    `json:"version"` // versao do programa, veja Version
+	GoVersion string    `json:"go_version"`
+	Time      time.Time `json:"time"`
+	Command   string    `json:"command"` // demonstracao executada: fibonacci ou bbs
+	Generator Generator `json:"generator"`
+	Entropy   Entropy   `json:"entropy"`
+	Tests     Tests     `json:"tests"`
+	Outputs   []Output  `json:"outputs"`
+}
+
+// Generator descreve o gerador dos candidatos
+type Generator struct {
+	Name   string         `json:"name"` // lfg ou bbs
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// Entropy descreve a fonte de entropia da execucao. Seed so eh preenchida
+// a pedido; SeedSHA256 compromete a execucao com a semente sem revela-la.
+type Entropy struct {
+	Source     string `json:"source"` // crypto, jitter, rdrand, rdseed ou seed
+	Security   string `json:"security"`
+	Seed       string `json:"seed,omitempty"` // em hexadecimal
+	SeedSHA256 string `json:"seed_sha256,omitempty"`
+}
+
+// Tests descreve a configuracao dos testes de primalidade
+type Tests struct {
+	Names           []string `json:"names"`
+	Rounds          int      `json:"rounds"` // 0: escolhidas pelo tamanho em bits
+	ConstantTime    bool     `json:"constant_time,omitempty"`
+	SmoothnessBound int      `json:"smoothness_bound,omitempty"`
+	Prescreen       int      `json:"prescreen"`
+	Construction    string   `json:"construction,omitempty"` // vazio: increment
+}
+
+// Output eh o que a execucao produziu para um tamanho em bits
+type Output struct {
+	Bits int `json:"bits"`
+	// StateSHA256 eh o hash do estado do gerador antes do candidato, um
+	// compromisso com a semente mesmo quando ela nao eh registrada
+	StateSHA256     string   `json:"state_sha256,omitempty"`
+	CandidateSHA256 string   `json:"candidate_sha256"`
+	Results         []Result `json:"results"`
+}
+
+// Result eh o primo gerado a partir do candidato por um teste
+type Result struct {
+	Test        string `json:"test"`
+	Rounds      int    `json:"rounds"`
+	Attempts    int    `json:"attempts"`
+	PrimeSHA256 string `json:"prime_sha256"`
+}
+
+// New cria o manifesto da demonstracao command, ainda sem saidas
+func New(command string) *Manifest {
+	return &Manifest{
+		Version:   Version(),
+		GoVersion: runtime.Version(),
+		Time:      clock.Now().UTC(),
+		Command:   command,
+	}
+}
+
+// SetSeed registra o hash da semente e, se reveal, a propria semente
+func (m *Manifest) SetSeed(seed []byte, reveal bool) {
+	m.Entropy.Source = "seed"
+	m.Entropy.SeedSHA256 = HashBytes(seed)
+	if reveal {
+		m.Entropy.Seed = hex.EncodeToString(seed)
+	}
+}
+
+// Output retorna a saida de bits bits, criando-a se necessario
+func (m *Manifest) Output(bits int) *Output {
+	if out := findOutput(m, bits); out != nil {
+		return out
+	}
+	m.Outputs = append(m.Outputs, Output{Bits: bits})
+	return &m.Outputs[len(m.Outputs)-1]
+}
+
+// Save grava o manifesto em path
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Load le o manifesto gravado em path
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := new(Manifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("manifest: %s invalido: %w", path, err)
+	}
+	return m, nil
+}
+
+// HashInt retorna o SHA-256 da representacao big-endian de n, em
+// hexadecimal
+func HashInt(n *big.Int) string {
+	return HashBytes(n.Bytes())
+}
+
+// HashBytes retorna o SHA-256 de data, em hexadecimal
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Version retorna a versao do programa: a do modulo principal, que ja
+// inclui a revisao do git quando o binario eh compilado de um repositorio,
+// ou "(devel)" seguida da revisao, se houver (com "+dirty" se havia
+// alteracoes nao registradas)
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "desconhecida"
+	}
+	version := info.Main.Version
+	if version != "" && version != "(devel)" {
+		return version
+	}
+	var revision string
+	var modified bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision != "" {
+		version += " " + revision
+		if modified {
+			version += "+dirty"
+		}
+	}
+	return version
+}
+
+// Compare compara as saidas de got com as de want, a execucao original, e
+// retorna a descricao de cada divergencia. Campos ausentes em want (como o
+// estado, omitido no modo sensivel) nao sao comparados.
+func Compare(want, got *Manifest) []string {
+	var diffs []string
+	for _, w := range want.Outputs {
+		g := findOutput(got, w.Bits)
+		if g == nil {
+			diffs = append(diffs, fmt.Sprintf("%d bits: saida ausente", w.Bits))
+			continue
+		}
+		if w.StateSHA256 != "" && w.StateSHA256 != g.StateSHA256 {
+			diffs = append(diffs, fmt.Sprintf("%d bits: estado do gerador diferente", w.Bits))
+		}
+		if w.CandidateSHA256 != g.CandidateSHA256 {
+			diffs = append(diffs, fmt.Sprintf("%d bits: candidato diferente", w.Bits))
+		}
+		if len(w.Results) != len(g.Results) {
+			diffs = append(diffs, fmt.Sprintf("%d bits: %d resultados, esperados %d", w.Bits, len(g.Results), len(w.Results)))
+			continue
+		}
+		for i, r := range w.Results {
+			if r != g.Results[i] {
+				diffs = append(diffs, fmt.Sprintf("%d bits: resultado de %s diferente", w.Bits, r.Test))
+			}
+		}
+	}
+	if len(got.Outputs) > len(want.Outputs) {
+		diffs = append(diffs, fmt.Sprintf("%d saidas a mais", len(got.Outputs)-len(want.Outputs)))
+	}
+	return diffs
+}
+
+func findOutput(m *Manifest, bits int) *Output {
+	for i := range m.Outputs {
+		if m.Outputs[i].Bits == bits {
+			return &m.Outputs[i]
+		}
+	}
+	return nil
+}
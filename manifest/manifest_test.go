@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	m := New("fibonacci")
+	m.Generator = Generator{Name: "lfg", Params: map[string]any{"j": 7, "k": 10}}
+	m.SetSeed([]byte{0x01, 0xab}, false)
+	out := m.Output(40)
+	out.CandidateSHA256 = HashInt(big.NewInt(12345))
+	out.Results = append(out.Results, Result{Test: "fermat", Rounds: 20, Attempts: 3, PrimeSHA256: HashInt(big.NewInt(12347))})
+	if m.Output(40) != &m.Outputs[0] || len(m.Outputs) != 1 {
+		t.Fatal("Output criou uma segunda saida para o mesmo tamanho")
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Command != "fibonacci" || got.Generator.Params["k"] != 10.0 || got.Outputs[0].Results[0] != out.Results[0] {
+		t.Errorf("manifesto lido difere do gravado: %+v", got)
+	}
+	// Sem pedir, a semente nao eh revelada, apenas seu hash
+	if got.Entropy.Seed != "" || got.Entropy.SeedSHA256 != HashBytes([]byte{0x01, 0xab}) {
+		t.Errorf("semente registrada incorretamente: %+v", got.Entropy)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	run := func(prime int64) *Manifest {
+		m := New("bbs")
+		out := m.Output(56)
+		out.CandidateSHA256 = HashInt(big.NewInt(100))
+		out.Results = []Result{{Test: "miller-rabin", Rounds: 20, Attempts: 2, PrimeSHA256: HashInt(big.NewInt(prime))}}
+		return m
+	}
+	if diffs := Compare(run(101), run(101)); len(diffs) != 0 {
+		t.Errorf("execucoes iguais com divergencias: %v", diffs)
+	}
+	if diffs := Compare(run(101), run(103)); len(diffs) != 1 {
+		t.Errorf("divergencias %v, esperada uma no primo", diffs)
+	}
+	if diffs := Compare(run(101), New("bbs")); len(diffs) != 1 {
+		t.Errorf("divergencias %v, esperada a saida ausente", diffs)
+	}
+}
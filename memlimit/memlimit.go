@@ -0,0 +1,98 @@
+// O pacote memlimit aplica um orcamento de memoria ao programa inteiro, para
+// rodar em dispositivos com pouca memoria (como um Raspberry Pi) sem ser
+// encerrado pelo sistema. O orcamento vira o limite flexivel do coletor de
+// lixo (debug.SetMemoryLimit) e eh repartido entre os subsistemas que
+// guardam dados em memoria: os segmentos do crivo, o cache de primoriais, o
+// pool de temporarios e o indice do registro de duplicatas. Cada um deles
+// degrada com o limite (mais calculo ou mais leituras do disco) em vez de
+// falhar.
+package memlimit
+
+import (
+	"PrimeNumGenerator/dedupe"
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Min eh o menor orcamento aceito por Apply
+const Min = 16 << 20
+
+// Limits eh a reparticao de um orcamento entre os subsistemas
+type Limits struct {
+	Total          int64 // orcamento inteiro, usado como limite do coletor
+	SieveSegment   int   // tamanho dos segmentos do crivo
+	PrimorialCache int   // memoria do cache de primoriais
+	Pool           int   // capacidade maxima dos valores do pool
+	DedupeIndex    int   // memoria do indice do registro de duplicatas
+}
+
+// Split reparte total entre os subsistemas. As fracoes deixam a maior parte
+// do orcamento para o trabalho em si (numeros, buffers de E/S, servidor).
+func Split(total int64) Limits {
+	share := func(div int64) int {
+		return int(min(total/div, math.MaxInt32))
+	}
+	return Limits{
+		Total:          total,
+		SieveSegment:   min(max(share(4096), numutil.MinSieveSegment), numutil.DefaultSieveSegment),
+		PrimorialCache: share(16),
+		Pool:           share(4096),
+		DedupeIndex:    share(8),
+	}
+}
+
+// Apply aplica o orcamento total, em bytes, ao programa e retorna a sua
+// reparticao. Deve ser chamado antes de abrir os registros de duplicatas,
+// que escolhem o indice ao abrir.
+func Apply(total int64) (Limits, error) {
+	if total < Min {
+		return Limits{}, fmt.Errorf("memlimit: orcamento de %d bytes abaixo do minimo de %d MiB", total, Min>>20)
+	}
+	l := Split(total)
+	debug.SetMemoryLimit(l.Total)
+	numutil.SetSieveSegment(l.SieveSegment)
+	numutil.SetPrimorialCache(l.PrimorialCache)
+	prng.SetPoolLimit(l.Pool)
+	dedupe.SetIndexLimit(l.DedupeIndex)
+	return l, nil
+}
+
+// units sao os sufixos aceitos por Parse, do mais longo ao mais curto
+var units = []struct {
+	suffix string
+	size   int64
+}{
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1e3}, {"mb", 1e6}, {"gb", 1e9}, {"tb", 1e12},
+	{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30}, {"t", 1 << 40},
+	{"b", 1},
+}
+
+// Parse le um tamanho como 512MiB, 256M, 1.5G ou 1000000 (bytes). Os
+// sufixos sem "i" e sem "B" (K, M, G, T) sao binarios, como no GOMEMLIMIT;
+// KB, MB, GB e TB sao decimais. Maiusculas e minusculas sao equivalentes.
+func Parse(s string) (int64, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	unit := int64(1)
+	for _, u := range units {
+		if number, ok := strings.CutSuffix(text, u.suffix); ok {
+			text, unit = strings.TrimSpace(number), u.size
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("memlimit: tamanho invalido %q", s)
+	}
+	size := v * float64(unit)
+	if size >= math.MaxInt64 {
+		return 0, errors.New("memlimit: tamanho grande demais")
+	}
+	return int64(size), nil
+}
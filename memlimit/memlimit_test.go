@@ -0,0 +1,48 @@
+package memlimit
+
+import (
+	"PrimeNumGenerator/numutil"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]int64{
+		"1000":    1000,
+		"512MiB":  512 << 20,
+		"256M":    256 << 20,
+		"1.5g":    3 << 29,
+		"2 GB":    2e9,
+		"64kib":   64 << 10,
+		"100b":    100,
+		" 8MiB  ": 8 << 20,
+	}
+	for s, want := range cases {
+		if got, err := Parse(s); err != nil || got != want {
+			t.Errorf("Parse(%q) = %d, %v; esperado %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "MiB", "-1M", "1X", "abc", "1e30G"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) sem erro", s)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	small := Split(Min)
+	if small.SieveSegment != numutil.MinSieveSegment {
+		t.Errorf("segmento do crivo = %d", small.SieveSegment)
+	}
+	large := Split(64 << 30)
+	if large.SieveSegment != numutil.DefaultSieveSegment {
+		t.Errorf("segmento do crivo = %d, esperado o padrao", large.SieveSegment)
+	}
+	for _, l := range []Limits{small, large} {
+		if sum := int64(l.SieveSegment + l.PrimorialCache + l.Pool + l.DedupeIndex); sum >= l.Total/2 {
+			t.Errorf("subsistemas usam %d de %d bytes", sum, l.Total)
+		}
+	}
+	if _, err := Apply(Min - 1); err == nil {
+		t.Error("Apply aceitou orcamento abaixo do minimo")
+	}
+}
@@ -0,0 +1,125 @@
+// O pacote numfmt formata numeros grandes para exibicao no terminal:
+// agrupamento de digitos, quebra de linha em uma largura fixa e truncamento
+// dos valores com milhares de digitos, mostrando apenas o inicio e o fim.
+//
+// A formatacao nao depende da localidade do sistema: o separador eh sempre
+// o escolhido explicitamente (por padrao "_", que a linguagem Go e o
+// subcomando check aceitam de volta como entrada).
+package numfmt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultSeparator eh o separador usado quando Format.Separator eh vazio
+const DefaultSeparator = "_"
+
+// Format descreve a formatacao de um numero. O valor zero nao altera nada:
+// cada campo zero desativa o recurso correspondente.
+type Format struct {
+	Group     int    // digitos por grupo, contados a partir da direita
+	Separator string // separador dos grupos (DefaultSeparator se vazio)
+	Width     int    // largura maxima de cada linha, em caracteres
+	MaxDigits int    // acima desse numero de digitos, mostra apenas o inicio e o fim
+}
+
+// Validate informa se os campos de f sao coerentes
+func (f Format) Validate() error {
+	switch {
+	case f.Group < 0 || f.Width < 0 || f.MaxDigits < 0:
+		return errors.New("numfmt: valores negativos")
+	case f.MaxDigits > 0 && f.MaxDigits < 2:
+		return errors.New("numfmt: o truncamento exige ao menos 2 digitos")
+	case strings.ContainsAny(f.Separator, "0123456789abcdefABCDEF\n"):
+		return fmt.Errorf("numfmt: separador %q se confunde com os digitos", f.Separator)
+	}
+	return nil
+}
+
+// Int formata n na base informada (2 a 62, como em big.Int.Text)
+func (f Format) Int(n *big.Int, base int) string {
+	return f.Digits(n.Text(base))
+}
+
+// Digits formata um numero ja convertido em digitos, com sinal opcional
+func (f Format) Digits(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	var parts []string
+	if f.MaxDigits > 0 && len(s) > f.MaxDigits {
+		head, tail := s[:(f.MaxDigits+1)/2], s[len(s)-f.MaxDigits/2:]
+		parts = []string{sign + f.group(head, true), "...", f.group(tail, false), fmt.Sprintf("(%d dígitos)", len(s))}
+	} else {
+		parts = []string{sign + f.group(s, false)}
+	}
+	return f.wrap(strings.Join(parts, " "))
+}
+
+// group separa os digitos em grupos contados a partir da direita ou, com
+// fromLeft, a partir da esquerda, como no inicio de um numero truncado, em
+// que o alinhamento pela direita dependeria dos digitos omitidos
+func (f Format) group(s string, fromLeft bool) string {
+	if f.Group == 0 || len(s) <= f.Group {
+		return s
+	}
+	first := len(s) % f.Group
+	if fromLeft || first == 0 {
+		first = f.Group
+	}
+	var b strings.Builder
+	b.WriteString(s[:first])
+	for i := first; i < len(s); i += f.Group {
+		b.WriteString(f.separator())
+		b.WriteString(s[i:min(i+f.Group, len(s))])
+	}
+	return b.String()
+}
+
+func (f Format) separator() string {
+	if f.Separator == "" {
+		return DefaultSeparator
+	}
+	return f.Separator
+}
+
+// wrap quebra s em linhas de no maximo f.Width caracteres, de preferencia
+// nos espacos e separadores, para nao partir os grupos
+func (f Format) wrap(s string) string {
+	text := []rune(s)
+	if f.Width == 0 || len(text) <= f.Width {
+		return s
+	}
+	var lines []string
+	for len(text) > f.Width {
+		cut := f.breakPoint(text)
+		if cut == 0 {
+			cut = f.Width
+		}
+		lines = append(lines, strings.TrimRight(string(text[:cut]), " "))
+		for text = text[cut:]; len(text) > 0 && text[0] == ' '; {
+			text = text[1:]
+		}
+	}
+	return strings.Join(append(lines, string(text)), "\n")
+}
+
+// breakPoint retorna a ultima posicao ate f.Width logo apos um espaco ou um
+// separador de text, ou 0 se nao houver nenhuma. Um espaco na posicao
+// f.Width tambem serve, pois ele eh removido da quebra.
+func (f Format) breakPoint(text []rune) int {
+	sep := []rune(f.separator())
+	for i := f.Width; i > 0; i-- {
+		if text[i] == ' ' || text[i-1] == ' ' {
+			return i
+		}
+		if f.Group > 0 && i >= len(sep) && string(text[i-len(sep):i]) == string(sep) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package numfmt
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDigits(t *testing.T) {
+	for _, c := range []struct {
+		f    Format
+		in   string
+		want string
+	}{
+		{Format{}, "1234567", "1234567"},
+		{Format{Group: 3}, "1234567", "1_234_567"},
+		{Format{Group: 3}, "123456", "123_456"},
+		{Format{Group: 3}, "-1234", "-1_234"},
+		{Format{Group: 3, Separator: " "}, "1234567", "1 234 567"},
+		{Format{Group: 4, Separator: " "}, "1010110", "101 0110"},
+		{Format{MaxDigits: 6}, "1234567890", "123 ... 890 (10 dígitos)"},
+		{Format{MaxDigits: 7}, "1234567890", "1234 ... 890 (10 dígitos)"},
+		{Format{MaxDigits: 10}, "1234567890", "1234567890"},
+		// O inicio eh agrupado pela esquerda e o fim pela direita
+		{Format{Group: 3, MaxDigits: 10}, "12345678901234", "123_45 ... 01_234 (14 dígitos)"},
+		{Format{Width: 4}, "1234567890", "1234\n5678\n90"},
+		{Format{Group: 3, Width: 8}, "1234567890", "1_234_\n567_890"},
+		{Format{MaxDigits: 6, Width: 12}, "1234567890", "123 ... 890\n(10 dígitos)"},
+	} {
+		if got := c.f.Digits(c.in); got != c.want {
+			t.Errorf("%+v.Digits(%q) = %q, esperado %q", c.f, c.in, got, c.want)
+		}
+	}
+}
+
+func TestIntWrapAndRoundTrip(t *testing.T) {
+	n := new(big.Int).Exp(big.NewInt(3), big.NewInt(5000), nil)
+	f := Format{Group: 3, Width: 80}
+	out := f.Int(n, 10)
+	for _, line := range strings.Split(out, "\n") {
+		if utf8.RuneCountInString(line) > 80 {
+			t.Fatalf("linha com mais de 80 caracteres: %q", line)
+		}
+	}
+	// Sem as quebras de linha, o separador padrao eh aceito pelo SetString
+	back, ok := new(big.Int).SetString(strings.ReplaceAll(out, "\n", ""), 0)
+	if !ok || back.Cmp(n) != 0 {
+		t.Error("o numero formatado nao eh lido de volta")
+	}
+	if got := (Format{Group: 4}).Int(big.NewInt(255), 2); got != "1111_1111" {
+		t.Errorf("255 em binario = %q", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, f := range []Format{{Group: -1}, {Width: -1}, {MaxDigits: 1}, {Separator: "0"}, {Separator: "a"}} {
+		if f.Validate() == nil {
+			t.Errorf("%+v aceito", f)
+		}
+	}
+	if err := (Format{Group: 3, Separator: ".", Width: 80, MaxDigits: 100}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,41 @@
+package numutil
+
+import (
+	"errors"
+	"math/big"
+)
+
+// CRT combina as congruencias x = residues[i] (mod moduli[i]), com modulos
+// positivos e coprimos dois a dois, e retorna a solucao x em [0, M) junto
+// com M, o produto dos modulos
+func CRT(residues, moduli []*big.Int) (x, m *big.Int, err error) {
+	if len(residues) != len(moduli) {
+		return nil, nil, errors.New("numutil: numero de residuos e de modulos diferente")
+	}
+	x, m = new(big.Int), big.NewInt(1)
+	for i := range moduli {
+		if x, m, err = CRTPair(x, m, residues[i], moduli[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return x, m, nil
+}
+
+// CRTPair combina x = a (mod m) e x = b (mod n), com m e n coprimos,
+// retornando a solucao em [0, m*n) e m*n
+func CRTPair(a, m, b, n *big.Int) (x, mn *big.Int, err error) {
+	if m.Sign() <= 0 || n.Sign() <= 0 {
+		return nil, nil, errors.New("numutil: modulos devem ser positivos")
+	}
+	inv, err := ModInverse(m, n)
+	if err != nil {
+		return nil, nil, errors.New("numutil: modulos nao sao coprimos")
+	}
+	mn = new(big.Int).Mul(m, n)
+	// x = a + m * ((b - a) * m^-1 mod n)
+	k := new(big.Int).Sub(b, a)
+	k.Mul(k, inv).Mod(k, n)
+	x = k.Mul(k, m)
+	x.Add(x, a).Mod(x, mn)
+	return x, mn, nil
+}
@@ -0,0 +1,245 @@
+package numutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxExprBits eh o maior tamanho, em bits, de um valor intermediario de
+// ParseExpr, para que entradas como 10^10^10 falhem em vez de esgotar a
+// memoria
+const MaxExprBits = 1 << 20
+
+// ParseExpr avalia uma expressao inteira como 2^127-1, 10^100+267 ou
+// (2^89-1)*(2^107-1). Os numeros podem ser decimais, hexadecimais (0x),
+// octais (0o), binarios (0b) ou em notacao cientifica (1e100, 6.02e23,
+// desde que o valor seja inteiro), com _ separando os digitos. Os
+// operadores sao, em ordem crescente de precedencia, + e -, *, / e %, -
+// unario e ^ (associativo a direita). / e % fazem a divisao euclidiana,
+// como big.Int.Div e big.Int.Mod: o resto eh sempre nao negativo e
+// a = (a/b)*b + a%b, entao -7/2 = -4 e -7%2 = 1.
+func ParseExpr(s string) (*big.Int, error) {
+	p := exprParser{text: s}
+	p.next()
+	v, err := p.expr()
+	if err == nil && p.tok != "" {
+		err = p.errorf("%q inesperado", p.tok)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// exprParser eh um analisador descendente recursivo; tok eh o token atual
+// ("" no fim da entrada) e pos a sua posicao
+type exprParser struct {
+	text     string
+	tok      string
+	pos, end int
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("numutil: expressao invalida na posicao %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// next avanca para o proximo token: um numero ou um operador
+func (p *exprParser) next() {
+	for p.end < len(p.text) && strings.ContainsRune(" \t\r\n", rune(p.text[p.end])) {
+		p.end++
+	}
+	p.pos = p.end
+	if p.end == len(p.text) {
+		p.tok = ""
+		return
+	}
+	if isLiteralByte(p.text[p.end]) {
+		for p.end < len(p.text) && isLiteralByte(p.text[p.end]) {
+			p.end++
+		}
+	} else {
+		p.end++
+	}
+	p.tok = p.text[p.pos:p.end]
+}
+
+func isLiteralByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '.'
+}
+
+// expr := term (("+" | "-") term)*
+func (p *exprParser) expr() (*big.Int, error) {
+	v, err := p.term()
+	for err == nil && (p.tok == "+" || p.tok == "-") {
+		op := p.tok
+		p.next()
+		var w *big.Int
+		if w, err = p.term(); err != nil {
+			break
+		}
+		if op == "+" {
+			v.Add(v, w)
+		} else {
+			v.Sub(v, w)
+		}
+		err = p.checkSize(v)
+	}
+	return v, err
+}
+
+// term := unary (("*" | "/" | "%") unary)*
+func (p *exprParser) term() (*big.Int, error) {
+	v, err := p.unary()
+	for err == nil && (p.tok == "*" || p.tok == "/" || p.tok == "%") {
+		op, pos := p.tok, p.pos
+		p.next()
+		var w *big.Int
+		if w, err = p.unary(); err != nil {
+			break
+		}
+		switch {
+		case op == "*":
+			v.Mul(v, w)
+			err = p.checkSize(v)
+		case w.Sign() == 0:
+			p.pos = pos
+			return nil, p.errorf("divisao por zero")
+		case op == "/":
+			v.Div(v, w)
+		default:
+			v.Mod(v, w)
+		}
+	}
+	return v, err
+}
+
+// unary := "-" unary | "+" unary | power
+func (p *exprParser) unary() (*big.Int, error) {
+	switch p.tok {
+	case "-":
+		p.next()
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return v.Neg(v), nil
+	case "+":
+		p.next()
+		return p.unary()
+	}
+	return p.power()
+}
+
+// power := primary ("^" unary)?
+func (p *exprParser) power() (*big.Int, error) {
+	v, err := p.primary()
+	if err != nil || p.tok != "^" {
+		return v, err
+	}
+	pos := p.pos
+	p.next()
+	e, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	p.pos = pos
+	if e.Sign() < 0 {
+		return nil, p.errorf("expoente negativo")
+	}
+	// |v| <= 1 nao cresce; nos demais casos o resultado tem ao menos
+	// e*(bits(|v|)-1)+1 bits, o que permite recusar expoentes enormes
+	// antes de calcular a potencia
+	if v.CmpAbs(big.NewInt(1)) > 0 {
+		if !e.IsInt64() || e.Int64() > MaxExprBits || e.Int64()*int64(v.BitLen()-1) > MaxExprBits {
+			return nil, p.errorf("valor com mais de %d bits", MaxExprBits)
+		}
+	}
+	v.Exp(v, e, nil)
+	return v, p.checkSize(v)
+}
+
+// primary := numero | "(" expr ")"
+func (p *exprParser) primary() (*big.Int, error) {
+	switch {
+	case p.tok == "":
+		return nil, p.errorf("fim inesperado")
+	case p.tok == "(":
+		p.next()
+		v, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, p.errorf("esperado \")\"")
+		}
+		p.next()
+		return v, nil
+	case !isLiteralByte(p.tok[0]):
+		return nil, p.errorf("%q inesperado", p.tok)
+	}
+	v, err := parseLiteral(p.tok)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	p.next()
+	return v, nil
+}
+
+// parseLiteral le um numero, inclusive em notacao cientifica
+func parseLiteral(tok string) (*big.Int, error) {
+	lower := strings.ToLower(tok)
+	mantissa, exponent, scientific := strings.Cut(lower, "e")
+	if strings.HasPrefix(lower, "0x") || !scientific {
+		if v, ok := new(big.Int).SetString(tok, 0); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("numero invalido %q", tok)
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	whole, okWhole := stripSeparators(whole)
+	frac, okFrac := stripSeparators(frac)
+	digits, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok || !okWhole || !okFrac || whole == "" {
+		return nil, fmt.Errorf("numero invalido %q", tok)
+	}
+	exponent, okExp := stripSeparators(exponent)
+	exp, err := strconv.Atoi(exponent)
+	if err != nil || !okExp || strings.ContainsAny(exponent, "+-") {
+		return nil, fmt.Errorf("expoente invalido em %q", tok)
+	}
+	shift := exp - len(frac)
+	// 10^shift tem cerca de 3.33*shift bits
+	if shift > MaxExprBits*3/10 {
+		return nil, fmt.Errorf("valor com mais de %d bits", MaxExprBits)
+	}
+	ten := big.NewInt(10)
+	if shift >= 0 {
+		return digits.Mul(digits, ten.Exp(ten, big.NewInt(int64(shift)), nil)), nil
+	}
+	q, r := new(big.Int).QuoRem(digits, ten.Exp(ten, big.NewInt(int64(-shift)), nil), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, errors.New("o valor de " + tok + " nao eh inteiro")
+	}
+	return q, nil
+}
+
+// stripSeparators remove os _ de uma parte de um numero em notacao
+// cientifica, que o SetString na base 10 nao aceita. Como na base 0, o _
+// deve ficar entre dois digitos.
+func stripSeparators(s string) (string, bool) {
+	if strings.HasPrefix(s, "_") || strings.HasSuffix(s, "_") || strings.Contains(s, "__") {
+		return "", false
+	}
+	return strings.ReplaceAll(s, "_", ""), true
+}
+
+// checkSize falha se v passar de MaxExprBits
+func (p *exprParser) checkSize(v *big.Int) error {
+	if v.BitLen() > MaxExprBits {
+		return p.errorf("valor com mais de %d bits", MaxExprBits)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package numutil
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrNotInvertible indica que o valor nao tem inverso modular
+var ErrNotInvertible = errors.New("numutil: valor nao eh inversivel")
+
+// ExtGCD calcula pelo algoritmo de Euclides estendido g = mdc(a, b) e os
+// coeficientes de Bezout x e y com a*x + b*y = g
+func ExtGCD(a, b *big.Int) (g, x, y *big.Int) {
+	// Invariantes: oldR = a*oldX + b*oldY e r = a*x + b*y
+	oldR, r := new(big.Int).Set(a), new(big.Int).Set(b)
+	oldX, x := big.NewInt(1), big.NewInt(0)
+	oldY, y := big.NewInt(0), big.NewInt(1)
+	q, tmp := new(big.Int), new(big.Int)
+	for r.Sign() != 0 {
+		q.Quo(oldR, r)
+		tmp.Mul(q, r)
+		oldR, r = r, oldR.Sub(oldR, tmp)
+		tmp.Mul(q, x)
+		oldX, x = x, oldX.Sub(oldX, tmp)
+		tmp.Mul(q, y)
+		oldY, y = y, oldY.Sub(oldY, tmp)
+	}
+	// Normalizamos o mdc para ser nao negativo
+	if oldR.Sign() < 0 {
+		oldR.Neg(oldR)
+		oldX.Neg(oldX)
+		oldY.Neg(oldY)
+	}
+	return oldR, oldX, oldY
+}
+
+// ModInverse retorna a^-1 mod m em [0, m), ou ErrNotInvertible se
+// mdc(a, m) != 1
+func ModInverse(a, m *big.Int) (*big.Int, error) {
+	if m.Sign() <= 0 {
+		return nil, errors.New("numutil: modulo deve ser positivo")
+	}
+	g, x, _ := ExtGCD(new(big.Int).Mod(a, m), m)
+	if g.Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrNotInvertible
+	}
+	return x.Mod(x, m), nil
+}
+
+// BatchInverse inverte todos os valores modulo m com uma unica inversao,
+// pelo truque de Montgomery: acumulamos os produtos prefixos, invertemos o
+// produto total e desfazemos os prefixos de tras para frente. Custa uma
+// inversao e 3(n-1) multiplicacoes, em vez de n inversoes.
+func BatchInverse(values []*big.Int, m *big.Int) ([]*big.Int, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	// prefix[i] = values[0] * ... * values[i] mod m
+	prefix := make([]*big.Int, len(values))
+	acc := big.NewInt(1)
+	for i, v := range values {
+		acc = new(big.Int).Mul(acc, v)
+		prefix[i] = acc.Mod(acc, m)
+	}
+
+	inv, err := ModInverse(prefix[len(prefix)-1], m)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*big.Int, len(values))
+	for i := len(values) - 1; i > 0; i-- {
+		// values[i]^-1 = (v_0...v_i)^-1 * (v_0...v_(i-1))
+		result[i] = new(big.Int).Mul(inv, prefix[i-1])
+		result[i].Mod(result[i], m)
+		inv.Mul(inv, values[i]).Mod(inv, m)
+	}
+	result[0] = inv
+	return result, nil
+}
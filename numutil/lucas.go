@@ -0,0 +1,57 @@
+package numutil
+
+import "math/big"
+
+// LucasSequence calcula os termos U_k e V_k das sequencias de Lucas de
+// parametros p e q (U_0 = 0, U_1 = 1, V_0 = 2, V_1 = p e
+// X_(i+1) = p*X_i - q*X_(i-1)) modulo n, ou exatos se n for nil, pelo
+// metodo da duplicacao, em O(log k) passos:
+//
+//	U_2m     = U_m * (2*U_(m+1) - p*U_m)
+//	U_(2m+1) = U_(m+1)^2 - q*U_m^2
+//
+// e V_k = 2*U_(k+1) - p*U_k. As formulas nao dividem por 2, entao n pode
+// ser par. Entra em panico se k for negativo.
+func LucasSequence(k, p, q, n *big.Int) (u, v *big.Int) {
+	if k.Sign() < 0 {
+		panic("numutil: LucasSequence exige k >= 0")
+	}
+	reduce := func(x *big.Int) *big.Int {
+		if n != nil {
+			x.Mod(x, n)
+		}
+		return x
+	}
+	a, b := new(big.Int), big.NewInt(1) // U_m, U_(m+1), comecando com m = 0
+	t, s := new(big.Int), new(big.Int)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		// (U_m, U_(m+1)) -> (U_2m, U_(2m+1))
+		t.Lsh(b, 1).Sub(t, s.Mul(p, a))
+		t.Mul(t, a)
+		s.Mul(a, a).Mul(s, q)
+		b.Mul(b, b).Sub(b, s)
+		a, t = reduce(t), a
+		reduce(b)
+		if k.Bit(i) == 1 {
+			// (U_2m, U_(2m+1)) -> (U_(2m+1), U_(2m+2) = p*U_(2m+1) - q*U_2m)
+			t.Mul(p, b).Sub(t, s.Mul(q, a))
+			a, b, t = b, reduce(t), a
+		}
+	}
+	v = new(big.Int).Lsh(b, 1)
+	return a, reduce(v.Sub(v, s.Mul(p, a)))
+}
+
+// Fibonacci retorna o k-esimo numero de Fibonacci F_k = U_k(1, -1) modulo
+// n, ou exato se n for nil
+func Fibonacci(k, n *big.Int) *big.Int {
+	u, _ := LucasSequence(k, big.NewInt(1), big.NewInt(-1), n)
+	return u
+}
+
+// LucasNumber retorna o k-esimo numero de Lucas L_k = V_k(1, -1) modulo n,
+// ou exato se n for nil
+func LucasNumber(k, n *big.Int) *big.Int {
+	_, v := LucasSequence(k, big.NewInt(1), big.NewInt(-1), n)
+	return v
+}
@@ -0,0 +1,376 @@
+package numutil
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestJacobi(t *testing.T) {
+	for n := int64(1); n < 200; n += 2 {
+		for a := int64(-50); a < 250; a++ {
+			want := big.Jacobi(big.NewInt(a), big.NewInt(n))
+			if got := Jacobi(big.NewInt(a), big.NewInt(n)); got != want {
+				t.Fatalf("Jacobi(%d, %d) = %d, esperado %d", a, n, got, want)
+			}
+		}
+	}
+}
+
+func TestSqrtMod(t *testing.T) {
+	// 3 mod 4, 5 mod 8 e um primo com 2^s grande em p-1
+	primes := []int64{3, 7, 13, 17, 97, 193, 7681, 65537, 998244353}
+	for _, p := range primes {
+		bp := big.NewInt(p)
+		for a := int64(0); a < 300; a++ {
+			r, err := SqrtMod(big.NewInt(a), bp)
+			if Legendre(big.NewInt(a), bp) == -1 {
+				if err != ErrNotResidue {
+					t.Fatalf("SqrtMod(%d, %d) aceitou um nao residuo", a, p)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("SqrtMod(%d, %d): %v", a, p, err)
+			}
+			sq := new(big.Int).Mul(r, r)
+			if sq.Mod(sq, bp).Int64() != a%p {
+				t.Fatalf("SqrtMod(%d, %d) = %s", a, p, r)
+			}
+		}
+	}
+}
+
+func TestSqrtModComposite(t *testing.T) {
+	// Modulos compostos em que o Tonelli-Shanks nao converge: quadrados
+	// perfeitos, sem nao residuos de Jacobi, e produtos com p-1 par
+	for _, p := range []int64{9, 21, 25, 33, 49, 65, 105} {
+		bp := big.NewInt(p)
+		for a := int64(1); a < p; a++ {
+			r, err := SqrtMod(big.NewInt(a), bp)
+			if err != nil {
+				continue
+			}
+			// Se nao houver erro, a raiz tem de estar certa
+			if sq := new(big.Int).Mul(r, r); sq.Mod(sq, bp).Int64() != a {
+				t.Errorf("SqrtMod(%d, %d) = %s", a, p, r)
+			}
+		}
+	}
+	for _, c := range [][2]int64{{1, 9}, {4, 21}, {2, 33}, {1, 8}} {
+		if _, err := SqrtMod(big.NewInt(c[0]), big.NewInt(c[1])); err == nil {
+			t.Errorf("SqrtMod(%d, %d) aceito", c[0], c[1])
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	x, m, err := CRT(
+		[]*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(2)},
+		[]*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Int64() != 23 || m.Int64() != 105 {
+		t.Fatalf("CRT = %s mod %s, esperado 23 mod 105", x, m)
+	}
+	if _, _, err := CRTPair(big.NewInt(1), big.NewInt(4), big.NewInt(1), big.NewInt(6)); err == nil {
+		t.Error("modulos nao coprimos aceitos")
+	}
+}
+
+func TestExtGCD(t *testing.T) {
+	for a := int64(-30); a <= 30; a++ {
+		for b := int64(-30); b <= 30; b++ {
+			g, x, y := ExtGCD(big.NewInt(a), big.NewInt(b))
+			want := new(big.Int).GCD(nil, nil, new(big.Int).Abs(big.NewInt(a)), new(big.Int).Abs(big.NewInt(b)))
+			bezout := new(big.Int).Mul(big.NewInt(a), x)
+			bezout.Add(bezout, new(big.Int).Mul(big.NewInt(b), y))
+			if g.Cmp(want) != 0 || bezout.Cmp(g) != 0 {
+				t.Fatalf("ExtGCD(%d, %d) = %s, %s, %s", a, b, g, x, y)
+			}
+		}
+	}
+}
+
+func TestBatchInverse(t *testing.T) {
+	m := big.NewInt(1000003)
+	var values []*big.Int
+	for i := int64(1); i <= 50; i++ {
+		values = append(values, big.NewInt(i*i*7919-3))
+	}
+	inverses, err := BatchInverse(values, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range values {
+		want, err := ModInverse(v, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inverses[i].Cmp(want) != 0 || want.Cmp(new(big.Int).ModInverse(v, m)) != 0 {
+			t.Fatalf("inverso de %s = %s, esperado %s", v, inverses[i], want)
+		}
+	}
+
+	values = append(values, big.NewInt(2000006))
+	if _, err := BatchInverse(values, m); err != ErrNotInvertible {
+		t.Errorf("valor nao inversivel aceito: %v", err)
+	}
+}
+
+func TestPrimorial(t *testing.T) {
+	want := []int64{1, 2, 6, 30, 210, 2310, 30030, 510510}
+	// Em ordem decrescente para exercitar o cache
+	for k := len(want) - 1; k >= 0; k-- {
+		if got := Primorial(k); got.Int64() != want[k] {
+			t.Errorf("Primorial(%d) = %s, esperado %d", k, got, want[k])
+		}
+	}
+	// Com o cache limitado, os produtos que nao cabem sao recalculados
+	defer SetPrimorialCache(0)
+	SetPrimorialCache(64)
+	want1000 := big.NewInt(1)
+	for _, p := range FirstPrimes(1000) {
+		want1000.Mul(want1000, big.NewInt(int64(p)))
+	}
+	for i := 0; i < 2; i++ {
+		if Primorial(1000).Cmp(want1000) != 0 || Primorial(5).Int64() != 2310 {
+			t.Fatal("primorial errado com o cache limitado")
+		}
+	}
+	if primorials.size > 64 {
+		t.Errorf("cache com %d bytes, limite de 64", primorials.size)
+	}
+	if p := FirstPrimes(1000); p[999] != 7919 {
+		t.Errorf("milesimo primo = %d, esperado 7919", p[999])
+	}
+
+	cases := map[int64]bool{1: false, 2: false, 13: false, 6: true, 169: true, 221: true, 1009: false, 1009 * 1013: false}
+	for n, want := range cases {
+		if got := HasSmallFactor(big.NewInt(n), 6); got != want {
+			t.Errorf("HasSmallFactor(%d) = %t, esperado %t", n, got, want)
+		}
+	}
+}
+
+func TestSegmentedSieve(t *testing.T) {
+	primes := PrimesUpTo(300000)
+	ranges := [][2]uint64{{0, 300000}, {65530, 65550}, {131071, 131072}, {200000, 200100}, {10, 9}}
+	// Segmentos pequenos, como com pouca memoria, dao o mesmo resultado
+	defer SetSieveSegment(DefaultSieveSegment)
+	for i, r := range append(ranges, ranges...) {
+		if i == len(ranges) {
+			SetSieveSegment(1)
+		}
+		var want []uint64
+		for _, p := range primes {
+			if uint64(p) >= r[0] && uint64(p) <= r[1] {
+				want = append(want, uint64(p))
+			}
+		}
+		var got []uint64
+		for p := range SegmentedSieve(r[0], r[1]) {
+			got = append(got, p)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("[%d, %d]: %d primos, esperados %d", r[0], r[1], len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("[%d, %d]: primo %d = %d, esperado %d", r[0], r[1], i, got[i], want[i])
+			}
+		}
+	}
+
+	// Longe do inicio, conferimos com ProbablyPrime
+	lo := uint64(1) << 40
+	count := 0
+	for p := range SegmentedSieve(lo, lo+20000) {
+		if !new(big.Int).SetUint64(p).ProbablyPrime(20) {
+			t.Fatalf("%d nao eh primo", p)
+		}
+		count++
+	}
+	for n := lo; n <= lo+20000; n++ {
+		if new(big.Int).SetUint64(n).ProbablyPrime(20) {
+			count--
+		}
+	}
+	if count != 0 {
+		t.Errorf("contagem de primos em [2^40, 2^40 + 20000] difere em %d", count)
+	}
+}
+
+func TestLucasSequence(t *testing.T) {
+	for _, pq := range [][2]int64{{1, -1}, {3, 2}, {1, 2}, {-4, 7}} {
+		p, q := big.NewInt(pq[0]), big.NewInt(pq[1])
+		// Calculo direto pela recorrencia
+		u0, u1 := big.NewInt(0), big.NewInt(1)
+		v0, v1 := big.NewInt(2), new(big.Int).Set(p)
+		for k := int64(0); k < 80; k++ {
+			for _, n := range []*big.Int{nil, big.NewInt(1000003), big.NewInt(1 << 20)} {
+				u, v := LucasSequence(big.NewInt(k), p, q, n)
+				wantU, wantV := new(big.Int).Set(u0), new(big.Int).Set(v0)
+				if n != nil {
+					wantU.Mod(wantU, n)
+					wantV.Mod(wantV, n)
+				}
+				if u.Cmp(wantU) != 0 || v.Cmp(wantV) != 0 {
+					t.Fatalf("P=%d Q=%d k=%d n=%v: (%s, %s), esperado (%s, %s)", pq[0], pq[1], k, n, u, v, wantU, wantV)
+				}
+			}
+			next := func(x0, x1 *big.Int) (*big.Int, *big.Int) {
+				x := new(big.Int).Mul(p, x1)
+				return x1, x.Sub(x, new(big.Int).Mul(q, x0))
+			}
+			u0, u1 = next(u0, u1)
+			v0, v1 = next(v0, v1)
+		}
+	}
+
+	if f := Fibonacci(big.NewInt(100), nil); f.String() != "354224848179261915075" {
+		t.Errorf("F_100 = %s", f)
+	}
+	if l := LucasNumber(big.NewInt(10), big.NewInt(100)); l.Int64() != 23 { // L_10 = 123
+		t.Errorf("L_10 mod 100 = %s", l)
+	}
+}
+
+func TestParseExpr(t *testing.T) {
+	pow := func(b, e int64) *big.Int { return new(big.Int).Exp(big.NewInt(b), big.NewInt(e), nil) }
+	m127 := new(big.Int).Sub(pow(2, 127), big.NewInt(1))
+	for expr, want := range map[string]*big.Int{
+		"2^127-1":             m127,
+		" 2 ^ 127 - 1 ":       m127,
+		"10^100+267":          new(big.Int).Add(pow(10, 100), big.NewInt(267)),
+		"0x1fffffffffffffff":  new(big.Int).SetUint64(1<<61 - 1),
+		"0b1010 + 0o17":       big.NewInt(25),
+		"1_000_003":           big.NewInt(1000003),
+		"2^3^2":               big.NewInt(512),
+		"-2^2":                big.NewInt(-4),
+		"(2+3)*4-6/4":         big.NewInt(19),
+		"-7 % 3":              big.NewInt(2),
+		"-7 / 2":              big.NewInt(-4),
+		"-7 / 2 * 2 + -7 % 2": big.NewInt(-7),
+		"7 / -2":              big.NewInt(-3),
+		"7 % -2":              big.NewInt(1),
+		"1_0e2":               big.NewInt(1000),
+		"1_2.5_0e1_0":         new(big.Int).Mul(big.NewInt(1250), pow(10, 8)),
+		"1e100":               pow(10, 100),
+		"6.02E23":             new(big.Int).Mul(big.NewInt(602), pow(10, 21)),
+		"1.50e1":              big.NewInt(15),
+		"(2^89-1)*(2^107-1)":  new(big.Int).Mul(new(big.Int).Sub(pow(2, 89), big.NewInt(1)), new(big.Int).Sub(pow(2, 107), big.NewInt(1))),
+		"0xe":                 big.NewInt(14),
+		"1^1000000000000":     big.NewInt(1),
+	} {
+		got, err := ParseExpr(expr)
+		if err != nil || got.Cmp(want) != 0 {
+			t.Errorf("ParseExpr(%q) = %v, %v; esperado %s", expr, got, err, want)
+		}
+	}
+
+	for _, expr := range []string{
+		"", "2^", "(2+3", "2+3)", "2 3", "1/0", "5%0", "2^-1", "1.5", "1.5e0", "1e-5",
+		"1e", "abc", "2**3", "10^10^10", "2^1048577", "1e400000", "0x",
+		"1_e2", "_1e2", "1__0e2", "1._5e2", "1e_2", "1e2_",
+	} {
+		if got, err := ParseExpr(expr); err == nil {
+			t.Errorf("ParseExpr(%q) = %s, esperado erro", expr, got)
+		}
+	}
+}
+
+func BenchmarkSegmentedSieve(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for range SegmentedSieve(1<<32, 1<<32+1<<22) {
+		}
+	}
+}
+
+func TestTables(t *testing.T) {
+	defer UseTables(Tables{})
+	dir := t.TempDir()
+	built, err := LoadTables(dir, 3000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if built.Primorial.Cmp(primorialProducts(3000)) != 0 || len(built.Primes) != 3000 {
+		t.Fatal("tabelas calculadas incorretas")
+	}
+	if got := Primorial(3000); got.Cmp(built.Primorial) != 0 {
+		t.Fatal("Primorial nao usa as tabelas")
+	}
+	if got := FirstPrimes(10); got[9] != 29 {
+		t.Fatalf("FirstPrimes(10) = %v", got)
+	}
+
+	// A segunda carga le o arquivo
+	data, err := os.ReadFile(TablesPath(dir, 3000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadTables(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Primorial.Cmp(built.Primorial) != 0 || !slices.Equal(loaded.Primes, built.Primes) {
+		t.Fatal("tabelas lidas diferem das gravadas")
+	}
+
+	// Arquivos corrompidos e de outra versao sao recusados e recalculados
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 1
+	if _, err := ReadTables(bytes.NewReader(corrupt)); !errors.Is(err, ErrTablesCorrupt) {
+		t.Errorf("arquivo corrompido: erro %v", err)
+	}
+	other := bytes.Clone(data)
+	other[len(tablesMagic)] = TablesVersion + 1
+	if _, err := ReadTables(bytes.NewReader(other)); !errors.Is(err, ErrTablesVersion) {
+		t.Errorf("outra versao: erro %v", err)
+	}
+	if err := os.WriteFile(TablesPath(dir, 3000), other, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTables(dir, 3000); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(TablesPath(dir, 3000)); !bytes.Equal(data[:len(tablesMagic)+1], []byte(tablesMagic+"\x01")) {
+		t.Error("arquivo de outra versao nao foi regravado")
+	}
+}
+
+// primorialProducts calcula p_k# com os produtos sucessivos
+func primorialProducts(k int) *big.Int {
+	product := big.NewInt(1)
+	for _, p := range sieveFirstPrimes(k) {
+		product.Mul(product, big.NewInt(int64(p)))
+	}
+	return product
+}
+
+func BenchmarkTables(b *testing.B) {
+	const k = 1 << 16
+	b.Run("products", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			primorialProducts(k)
+		}
+	})
+	b.Run("tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BuildTables(k)
+		}
+	})
+	var buf bytes.Buffer
+	t, _ := BuildTables(k)
+	t.WriteTo(&buf)
+	b.Run("load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ReadTables(bytes.NewReader(buf.Bytes()))
+		}
+	})
+}
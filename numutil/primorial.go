@@ -0,0 +1,148 @@
+package numutil
+
+import (
+	"PrimeNumGenerator/internal/sieve"
+	"math"
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// PrimesUpTo retorna os primos menores ou iguais a limit pelo crivo de
+// Eratostenes
+func PrimesUpTo(limit int) []int {
+	if limit < 2 {
+		return nil
+	}
+	composite := make([]bool, limit+1)
+	var primes []int
+	for i := sieve.FirstUnmarked(composite, 2); i <= limit; i = sieve.FirstUnmarked(composite, i+1) {
+		primes = append(primes, i)
+		sieve.MarkMultiples(composite, uint64(i)*uint64(i), uint64(i))
+	}
+	return primes
+}
+
+// FirstPrimes retorna os k primeiros primos, copiados das tabelas de
+// UseTables quando elas os contem
+func FirstPrimes(k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	primorials.mu.Lock()
+	pinned := primorials.pinned.Primes
+	primorials.mu.Unlock()
+	if k <= len(pinned) {
+		return append([]int(nil), pinned[:k]...)
+	}
+	return sieveFirstPrimes(k)
+}
+
+// sieveFirstPrimes calcula os k primeiros primos, k > 0, pelo crivo
+func sieveFirstPrimes(k int) []int {
+	// O k-esimo primo eh menor que k(ln k + ln ln k) para k >= 6
+	limit := 15
+	if k >= 6 {
+		fk := float64(k)
+		limit = int(fk*(math.Log(fk)+math.Log(math.Log(fk)))) + 1
+	}
+	return PrimesUpTo(limit)[:k]
+}
+
+// primorials guarda os produtos dos primeiros primos ja calculados:
+// products[i] eh o produto dos i primeiros primos. size eh a memoria usada
+// pelos produtos e limit, se positivo, o maximo permitido. pinned sao as
+// tabelas de UseTables, fora do limite.
+var primorials struct {
+	mu          sync.Mutex
+	products    []*big.Int
+	size, limit int
+	pinned      Tables
+}
+
+// UseTables passa a usar as tabelas t, calculadas por BuildTables ou lidas
+// por ReadTables: FirstPrimes copia os primos delas e Primorial retorna o
+// primorial de todos os primos de t sem calcular os produtos
+// intermediarios, que, para um pre-filtro grande, nao caberiam no cache.
+// As tabelas nao contam no limite de SetPrimorialCache.
+func UseTables(t Tables) {
+	primorials.mu.Lock()
+	defer primorials.mu.Unlock()
+	primorials.pinned = t
+}
+
+// SetPrimorialCache limita a memoria, em bytes, do cache de Primorial. Os
+// produtos que nao cabem no limite sao recalculados a cada chamada, a
+// partir do maior produto em cache. 0 remove o limite.
+func SetPrimorialCache(maxBytes int) {
+	primorials.mu.Lock()
+	defer primorials.mu.Unlock()
+	primorials.limit = max(maxBytes, 0)
+	// Descarta os produtos que passam do novo limite
+	for primorials.limit > 0 && primorials.size > primorials.limit && len(primorials.products) > 1 {
+		last := primorials.products[len(primorials.products)-1]
+		primorials.size -= len(last.Bits()) * bits.UintSize / 8
+		primorials.products = primorials.products[:len(primorials.products)-1]
+	}
+}
+
+// Primorial retorna p_k#, o produto dos k primeiros primos. Os produtos
+// calculados ficam em cache, de modo que chamadas repetidas (como no
+// pre-filtro por mdc da geracao de primos) custam apenas uma copia.
+func Primorial(k int) *big.Int {
+	return new(big.Int).Set(primorial(k))
+}
+
+// primorial retorna o valor em cache de p_k#, que nao pode ser alterado
+func primorial(k int) *big.Int {
+	if k < 0 {
+		k = 0
+	}
+	primorials.mu.Lock()
+	defer primorials.mu.Unlock()
+
+	if pinned := primorials.pinned; k > 0 && k == len(pinned.Primes) {
+		return pinned.Primorial
+	}
+	if len(primorials.products) == 0 {
+		primorials.products = []*big.Int{big.NewInt(1)}
+	}
+	have := len(primorials.products) - 1
+	if have >= k {
+		return primorials.products[k]
+	}
+	// FirstPrimes tomaria a trava de novo
+	primes := sieveFirstPrimes(k)
+	product := primorials.products[have]
+	for i, p := range primes[have:] {
+		product = new(big.Int).Mul(product, big.NewInt(int64(p)))
+		size := len(product.Bits()) * bits.UintSize / 8
+		if primorials.limit > 0 && primorials.size+size > primorials.limit {
+			// Sem espaco no cache: o restante do produto nao eh guardado
+			for _, p := range primes[have+i+1:] {
+				product.Mul(product, big.NewInt(int64(p)))
+			}
+			return product
+		}
+		primorials.products = append(primorials.products, product)
+		primorials.size += size
+	}
+	return product
+}
+
+// HasSmallFactor informa se n eh divisivel por algum dos k primeiros primos
+// sem ser ele proprio um desses primos, com um unico mdc contra o primorial
+// p_k#
+func HasSmallFactor(n *big.Int, k int) bool {
+	abs := n
+	if n.Sign() < 0 {
+		abs = new(big.Int).Neg(n)
+	}
+	// O mdc le o primorial em cache sem copia-lo
+	g := new(big.Int).GCD(nil, nil, abs, primorial(k))
+	if g.Cmp(big.NewInt(1)) == 0 {
+		return false
+	}
+	// g = |n| apenas se n for um produto de primos pequenos distintos
+	return g.CmpAbs(n) != 0 || !n.ProbablyPrime(0)
+}
@@ -0,0 +1,84 @@
+package numutil
+
+import (
+	"PrimeNumGenerator/internal/sieve"
+	"fmt"
+	"iter"
+	"math"
+	"sync/atomic"
+)
+
+// MaxSieve eh o maior limite aceito por SegmentedSieve. Os primos base, ate
+// a raiz quadrada do limite, sao guardados em memoria.
+const MaxSieve = 1 << 50
+
+// Tamanhos dos segmentos do crivo: o padrao foi escolhido para caber no
+// cache, e o minimo mantem o custo de percorrer os primos base a cada
+// segmento razoavel
+const (
+	DefaultSieveSegment = 1 << 16
+	MinSieveSegment     = 1 << 12
+)
+
+// sieveSegment eh o tamanho atual de cada segmento do crivo
+var sieveSegment atomic.Uint64
+
+func init() {
+	sieveSegment.Store(DefaultSieveSegment)
+}
+
+// SetSieveSegment muda o tamanho, em bytes, dos segmentos de
+// SegmentedSieve, com minimo MinSieveSegment. Segmentos menores usam
+// menos memoria, mas percorrem os primos base mais vezes.
+func SetSieveSegment(size int) {
+	sieveSegment.Store(uint64(max(size, MinSieveSegment)))
+}
+
+// SegmentedSieve enumera, em ordem crescente, os primos em [lo, hi] pelo
+// crivo de Eratostenes segmentado: os primos ate sqrt(hi) riscam os
+// multiplos em um segmento de cada vez, de modo que a memoria usada eh
+// O(sqrt(hi)) em vez de O(hi). Entra em panico se hi > MaxSieve.
+func SegmentedSieve(lo, hi uint64) iter.Seq[uint64] {
+	if hi > MaxSieve {
+		panic(fmt.Sprintf("numutil: limite do crivo grande demais: %d", hi))
+	}
+	return func(yield func(uint64) bool) {
+		lo = max(lo, 2)
+		if lo > hi {
+			return
+		}
+		root := uint64(math.Sqrt(float64(hi)))
+		for root*root > hi {
+			root--
+		}
+		for (root+1)*(root+1) <= hi {
+			root++
+		}
+		base := PrimesUpTo(int(root))
+
+		segment := sieveSegment.Load()
+		composite := make([]bool, segment)
+		for start := lo; start <= hi; start += segment {
+			end := min(start+segment-1, hi) // segmento [start, end]
+			window := composite[:end-start+1]
+			clear(window)
+			for _, bp := range base {
+				p := uint64(bp)
+				if p*p > end {
+					break
+				}
+				// Primeiro multiplo de p no segmento, a partir de p^2
+				first := max(p*p, (start+p-1)/p*p)
+				sieve.MarkMultiples(window, first-start, p)
+			}
+			for i := sieve.FirstUnmarked(window, 0); i < len(window); i = sieve.FirstUnmarked(window, i+1) {
+				if !yield(start + uint64(i)) {
+					return
+				}
+			}
+			if end == hi {
+				return // evita o estouro de start + segment
+			}
+		}
+	}
+}
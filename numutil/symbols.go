@@ -0,0 +1,117 @@
+// O pacote numutil reune funcoes de teoria dos numeros sobre math/big:
+// simbolos de Jacobi e de Legendre, raizes quadradas modulares e o Teorema
+// Chines do Resto. Elas servem de base para os testes de primalidade e
+// para a analise dos geradores, e podem ser usadas diretamente.
+//
+// A API exportada segue o versionamento semantico: nenhuma versao menor
+// remove ou altera um simbolo listado em api/v1.txt. Os lacos em assembly
+// do crivo ficam em internal/sieve.
+package numutil
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrNotResidue indica que o valor nao eh residuo quadratico modulo p
+var ErrNotResidue = errors.New("numutil: valor nao eh residuo quadratico")
+
+// errComposite indica que o Tonelli-Shanks nao convergiu, o que so acontece
+// com um modulo composto
+var errComposite = errors.New("numutil: modulo composto em SqrtMod")
+
+// Jacobi calcula o simbolo de Jacobi (a/n) para n impar e positivo, pela
+// lei de reciprocidade quadratica. Entra em panico se n for par ou n <= 0.
+func Jacobi(a, n *big.Int) int {
+	if n.Sign() <= 0 || n.Bit(0) == 0 {
+		panic("numutil: Jacobi exige n impar e positivo")
+	}
+	a = new(big.Int).Mod(a, n)
+	n = new(big.Int).Set(n)
+	result := 1
+	for a.Sign() != 0 {
+		// Retiramos os fatores 2 de a: (2/n) = -1 se n = 3, 5 mod 8
+		twos := a.TrailingZeroBits()
+		a.Rsh(a, twos)
+		if r := n.Bits()[0] & 7; twos&1 == 1 && (r == 3 || r == 5) {
+			result = -result
+		}
+		// Reciprocidade: (a/n) = -(n/a) se a = n = 3 mod 4
+		if a.Bits()[0]&3 == 3 && n.Bits()[0]&3 == 3 {
+			result = -result
+		}
+		a, n = n.Mod(n, a), a
+	}
+	if n.Cmp(big.NewInt(1)) == 0 {
+		return result
+	}
+	return 0
+}
+
+// Legendre calcula o simbolo de Legendre (a/p) para o primo impar p:
+// 1 se a eh residuo quadratico, -1 se nao eh e 0 se p divide a
+func Legendre(a, p *big.Int) int {
+	return Jacobi(a, p)
+}
+
+// SqrtMod retorna uma raiz quadrada de a modulo o primo impar p pelo
+// algoritmo de Tonelli-Shanks, ou ErrNotResidue se ela nao existir. Se p
+// for composto, o algoritmo pode nao convergir: nesse caso SqrtMod retorna
+// um erro em vez de entrar em um laco sem fim.
+func SqrtMod(a, p *big.Int) (*big.Int, error) {
+	if p.Cmp(big.NewInt(3)) < 0 || p.Bit(0) == 0 {
+		return nil, errors.New("numutil: SqrtMod exige um primo impar")
+	}
+	a = new(big.Int).Mod(a, p)
+	if a.Sign() == 0 {
+		return new(big.Int), nil
+	}
+	if Legendre(a, p) != 1 {
+		return nil, ErrNotResidue
+	}
+
+	one := big.NewInt(1)
+	// p - 1 = q * 2^s com q impar
+	q := new(big.Int).Sub(p, one)
+	s := q.TrailingZeroBits()
+	q.Rsh(q, s)
+
+	// Caso p = 3 mod 4: a raiz eh a^((p+1)/4)
+	if s == 1 {
+		e := new(big.Int).Add(p, one)
+		return e.Exp(a, e.Rsh(e, 2), p), nil
+	}
+
+	// z eh um nao residuo qualquer. Com p primo, metade dos valores serve;
+	// um z com fator comum com p, ou nenhum nao residuo, revela um p composto
+	z := big.NewInt(2)
+	for j := Legendre(z, p); j != -1; j = Legendre(z, p) {
+		if j == 0 || z.Cmp(p) >= 0 {
+			return nil, errComposite
+		}
+		z.Add(z, one)
+	}
+
+	m := s
+	c := new(big.Int).Exp(z, q, p)
+	t := new(big.Int).Exp(a, q, p)
+	r := new(big.Int).Exp(a, new(big.Int).Rsh(new(big.Int).Add(q, one), 1), p)
+	for t.Cmp(one) != 0 {
+		// Menor i com t^(2^i) = 1
+		i := uint(0)
+		for t2 := new(big.Int).Set(t); t2.Cmp(one) != 0; i++ {
+			// Com p primo, a ordem de t divide 2^(m-1), entao i < m
+			if i == m {
+				return nil, errComposite
+			}
+			t2.Mul(t2, t2).Mod(t2, p)
+		}
+		// b = c^(2^(m-i-1))
+		b := new(big.Int).Exp(c, new(big.Int).Lsh(one, m-i-1), p)
+		m = i
+		c.Mul(b, b).Mod(c, p)
+		t.Mul(t, c).Mod(t, p)
+		r.Mul(r, b).Mod(r, p)
+	}
+	return r, nil
+}
@@ -0,0 +1,206 @@
+// Esse arquivo traz a persistencia em disco das tabelas do pre-filtro (os
+//  primeiros primos e o seu primorial), para que um pre-filtro grande nao
+//  seja recalculado a cada execucao.
+
+package numutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+// TablesVersion eh a versao do formato gravado por Tables.WriteTo. Arquivos
+// de outras versoes sao recusados por ReadTables e recalculados por
+// LoadTables.
+const TablesVersion = 1
+
+// MaxTablePrimes eh o maior numero de primos aceito nas tabelas
+const MaxTablePrimes = 1 << 24
+
+// tablesMagic identifica os arquivos de tabelas
+const tablesMagic = "PNGT"
+
+var (
+	// ErrTablesVersion indica um arquivo de tabelas de outra versao
+	ErrTablesVersion = errors.New("numutil: versao das tabelas incompativel")
+	// ErrTablesCorrupt indica um arquivo de tabelas truncado ou alterado
+	ErrTablesCorrupt = errors.New("numutil: arquivo de tabelas corrompido")
+)
+
+// Tables sao as tabelas do pre-filtro por mdc para os k primeiros primos:
+// os primos e o primorial p_k#, que eh a parte cara de calcular quando k eh
+// grande
+type Tables struct {
+	Primes    []int
+	Primorial *big.Int
+}
+
+// BuildTables calcula as tabelas dos k primeiros primos. O primorial eh
+// calculado por uma arvore de produtos, em vez dos k produtos sucessivos de
+// Primorial.
+func BuildTables(k int) (Tables, error) {
+	if k < 1 || k > MaxTablePrimes {
+		return Tables{}, fmt.Errorf("numutil: numero de primos das tabelas fora de 1 a %d: %d", MaxTablePrimes, k)
+	}
+	primes := FirstPrimes(k)
+	return Tables{Primes: primes, Primorial: productTree(primes)}, nil
+}
+
+// productTree retorna o produto dos primos, multiplicando metades de
+// tamanho parecido
+func productTree(primes []int) *big.Int {
+	if len(primes) <= 16 {
+		product := big.NewInt(1)
+		for _, p := range primes {
+			product.Mul(product, big.NewInt(int64(p)))
+		}
+		return product
+	}
+	half := len(primes) / 2
+	return new(big.Int).Mul(productTree(primes[:half]), productTree(primes[half:]))
+}
+
+// WriteTo grava as tabelas: o cabecalho com a versao, os primos como
+// diferencas em varint, o primorial e um SHA-256 de todo o conteudo
+func (t Tables) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.WriteString(tablesMagic)
+	buf.Write(binary.AppendUvarint(nil, TablesVersion))
+	buf.Write(binary.AppendUvarint(nil, uint64(len(t.Primes))))
+	previous := 0
+	for _, p := range t.Primes {
+		buf.Write(binary.AppendUvarint(nil, uint64(p-previous)))
+		previous = p
+	}
+	product := t.Primorial.Bytes()
+	buf.Write(binary.AppendUvarint(nil, uint64(len(product))))
+	buf.Write(product)
+	sum := sha256.Sum256(buf.Bytes())
+	buf.Write(sum[:])
+	return buf.WriteTo(w)
+}
+
+// ReadTables le as tabelas gravadas por WriteTo, conferindo a versao e o
+// SHA-256. O conteudo nao eh recalculado: um arquivo adulterado com a soma
+// corrigida pode fazer o pre-filtro descartar primos, mas nunca aprovar um
+// numero composto, que ainda passa pelo teste de primalidade.
+func ReadTables(r io.Reader) (Tables, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Tables{}, err
+	}
+	if len(data) < len(tablesMagic)+sha256.Size || string(data[:len(tablesMagic)]) != tablesMagic {
+		return Tables{}, ErrTablesCorrupt
+	}
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	rd := bufio.NewReader(bytes.NewReader(body[len(tablesMagic):]))
+	version, err := binary.ReadUvarint(rd)
+	if err != nil {
+		return Tables{}, ErrTablesCorrupt
+	}
+	if version != TablesVersion {
+		return Tables{}, fmt.Errorf("%w: %d, esperada %d", ErrTablesVersion, version, TablesVersion)
+	}
+	if want := sha256.Sum256(body); !bytes.Equal(sum, want[:]) {
+		return Tables{}, ErrTablesCorrupt
+	}
+
+	k, err := binary.ReadUvarint(rd)
+	if err != nil || k < 1 || k > MaxTablePrimes {
+		return Tables{}, ErrTablesCorrupt
+	}
+	t := Tables{Primes: make([]int, k)}
+	previous := 0
+	for i := range t.Primes {
+		gap, err := binary.ReadUvarint(rd)
+		if err != nil || gap == 0 || gap > 1<<20 {
+			return Tables{}, ErrTablesCorrupt
+		}
+		previous += int(gap)
+		t.Primes[i] = previous
+	}
+	size, err := binary.ReadUvarint(rd)
+	if err != nil || size > uint64(len(body)) {
+		return Tables{}, ErrTablesCorrupt
+	}
+	product := make([]byte, size)
+	if _, err := io.ReadFull(rd, product); err != nil {
+		return Tables{}, ErrTablesCorrupt
+	}
+	if _, err := rd.ReadByte(); err != io.EOF {
+		return Tables{}, ErrTablesCorrupt
+	}
+	t.Primorial = new(big.Int).SetBytes(product)
+	if t.Primes[0] != 2 || t.Primorial.Sign() == 0 {
+		return Tables{}, ErrTablesCorrupt
+	}
+	return t, nil
+}
+
+// TablesPath eh o arquivo das tabelas de k primos no diretorio dir. O nome
+// inclui a versao, para que versoes diferentes do programa nao disputem o
+// mesmo arquivo.
+func TablesPath(dir string, k int) string {
+	return filepath.Join(dir, fmt.Sprintf("prescreen-v%d-%d.bin", TablesVersion, k))
+}
+
+// LoadTables le as tabelas de k primos de dir e passa a usa-las (veja
+// UseTables). Se o arquivo nao existir, for de outra versao ou estiver
+// corrompido, as tabelas sao calculadas e gravadas de novo, de forma
+// atomica. O diretorio eh criado se preciso.
+func LoadTables(dir string, k int) (Tables, error) {
+	path := TablesPath(dir, k)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		t, err := ReadTables(bytes.NewReader(data))
+		if err == nil && len(t.Primes) == k {
+			UseTables(t)
+			return t, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return Tables{}, err
+	}
+
+	t, err := BuildTables(k)
+	if err != nil {
+		return Tables{}, err
+	}
+	if err := writeTables(path, t); err != nil {
+		return Tables{}, err
+	}
+	UseTables(t)
+	return t, nil
+}
+
+// writeTables grava t em path por um arquivo temporario renomeado, para que
+// uma execucao concorrente nunca leia um arquivo pela metade
+func writeTables(path string, t Tables) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := t.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
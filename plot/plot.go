@@ -0,0 +1,215 @@
+// O pacote plot desenha figuras sobre a distribuicao dos primos, para uso
+// didatico: a espiral de Ulam, em que os primos se alinham em diagonais, e
+// o histograma dos intervalos entre primos consecutivos. As figuras sao
+// exportadas em PNG ou SVG, e os primos vem do crivo segmentado de numutil.
+package plot
+
+import (
+	"PrimeNumGenerator/numutil"
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// MaxSpiral eh o maior lado aceito por UlamSpiral
+const MaxSpiral = 4096
+
+// Cores das figuras
+var (
+	background = color.Gray{Y: 0xff}
+	foreground = color.Gray{Y: 0x00}
+)
+
+// Spiral eh a espiral de Ulam de Size x Size numeros a partir de Start, no
+// centro. Prime guarda as celulas linha a linha, de cima para baixo.
+type Spiral struct {
+	Size  int
+	Start uint64
+	Prime []bool
+}
+
+// UlamSpiral monta a espiral de Ulam de lado size: start fica no centro e
+// os numeros seguintes giram em sentido anti-horario, um passo para a
+// direita, um para cima, dois para a esquerda, dois para baixo e assim por
+// diante.
+func UlamSpiral(start uint64, size int) (*Spiral, error) {
+	if size < 1 || size > MaxSpiral {
+		return nil, fmt.Errorf("plot: lado da espiral invalido: %d", size)
+	}
+	cells := uint64(size) * uint64(size)
+	if start+cells-1 > numutil.MaxSieve {
+		return nil, fmt.Errorf("plot: a espiral passa do limite do crivo")
+	}
+
+	// Caminho da espiral, relativo ao centro
+	xs, ys := make([]int, cells), make([]int, cells)
+	x, y, dx, dy := 0, 0, 1, 0
+	for i, leg := uint64(0), 1; i < cells; leg++ {
+		// Cada comprimento de trecho se repete duas vezes
+		for turn := 0; turn < 2 && i < cells; turn++ {
+			for step := 0; step < leg && i < cells; step++ {
+				xs[i], ys[i] = x, y
+				x, y = x+dx, y+dy
+				i++
+			}
+			dx, dy = dy, -dx // gira 90 graus (y cresce para baixo)
+		}
+	}
+	minX, minY := slices.Min(xs), slices.Min(ys)
+
+	s := &Spiral{Size: size, Start: start, Prime: make([]bool, cells)}
+	for p := range numutil.SegmentedSieve(start, start+cells-1) {
+		i := p - start
+		s.Prime[(ys[i]-minY)*size+xs[i]-minX] = true
+	}
+	return s, nil
+}
+
+// Image desenha a espiral com cada numero em um quadrado de scale pixels:
+// preto para os primos e branco para os demais
+func (s *Spiral) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	img := image.NewGray(image.Rect(0, 0, s.Size*scale, s.Size*scale))
+	for i, prime := range s.Prime {
+		c := background
+		if prime {
+			c = foreground
+		}
+		x0, y0 := i%s.Size*scale, i/s.Size*scale
+		for y := y0; y < y0+scale; y++ {
+			for x := x0; x < x0+scale; x++ {
+				img.SetGray(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// WriteSVG escreve a espiral em SVG, com um quadrado de scale unidades por
+// primo
+func (s *Spiral) WriteSVG(w io.Writer, scale int) error {
+	scale = max(scale, 1)
+	side := s.Size * scale
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", side, side, side, side)
+	fmt.Fprintf(bw, `<title>Espiral de Ulam de %d a %d</title>`+"\n", s.Start, s.Start+uint64(len(s.Prime))-1)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", side, side)
+	for i, prime := range s.Prime {
+		if prime {
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d"/>`+"\n", i%s.Size*scale, i/s.Size*scale, scale, scale)
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// Gaps eh o histograma dos intervalos entre primos consecutivos: Count[g]
+// eh o numero de pares de primos consecutivos p < q com q - p = g
+type Gaps struct {
+	From, To uint64
+	Count    []int
+}
+
+// GapHistogram conta os intervalos entre os primos consecutivos de
+// [from, to]
+func GapHistogram(from, to uint64) (*Gaps, error) {
+	if to > numutil.MaxSieve {
+		return nil, fmt.Errorf("plot: limite maximo de %d", uint64(numutil.MaxSieve))
+	}
+	if from > to {
+		return nil, fmt.Errorf("plot: intervalo vazio: [%d, %d]", from, to)
+	}
+	h := &Gaps{From: from, To: to}
+	var prev uint64
+	for p := range numutil.SegmentedSieve(from, to) {
+		if prev != 0 {
+			gap := int(p - prev)
+			if gap >= len(h.Count) {
+				h.Count = append(h.Count, make([]int, gap+1-len(h.Count))...)
+			}
+			h.Count[gap]++
+		}
+		prev = p
+	}
+	return h, nil
+}
+
+// Dimensoes do grafico de barras do histograma
+const (
+	barWidth    = 8
+	chartHeight = 256
+	chartMargin = 24
+)
+
+// bars retorna a altura, em pixels, da barra de cada intervalo
+func (h *Gaps) bars() []int {
+	most := 0
+	for _, c := range h.Count {
+		most = max(most, c)
+	}
+	heights := make([]int, len(h.Count))
+	for g, c := range h.Count {
+		if most > 0 {
+			heights[g] = c * chartHeight / most
+		}
+	}
+	return heights
+}
+
+// Image desenha o histograma em um grafico de barras, uma barra por
+// intervalo, da esquerda para a direita
+func (h *Gaps) Image() image.Image {
+	width := max(len(h.Count), 1)*barWidth + 2*chartMargin
+	height := chartHeight + 2*chartMargin
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = background.Y
+	}
+	for g, bar := range h.bars() {
+		x0 := chartMargin + g*barWidth
+		for y := chartMargin + chartHeight - bar; y < chartMargin+chartHeight; y++ {
+			for x := x0; x < x0+barWidth-1; x++ {
+				img.SetGray(x, y, foreground)
+			}
+		}
+	}
+	// Eixo horizontal
+	for x := chartMargin; x < width-chartMargin; x++ {
+		img.SetGray(x, chartMargin+chartHeight, foreground)
+	}
+	return img
+}
+
+// WriteSVG escreve o histograma em SVG, com o valor de cada intervalo sob
+// a sua barra e a contagem no titulo de cada barra
+func (h *Gaps) WriteSVG(w io.Writer) error {
+	width := max(len(h.Count), 1)*barWidth + 2*chartMargin
+	height := chartHeight + 2*chartMargin
+	base := chartMargin + chartHeight
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, `<title>Intervalos entre primos consecutivos de %d a %d</title>`+"\n", h.From, h.To)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	for g, bar := range h.bars() {
+		if h.Count[g] == 0 {
+			continue
+		}
+		x := chartMargin + g*barWidth
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d"><title>%d: %d</title></rect>`+"\n", x, base-bar, barWidth-1, bar, g, h.Count[g])
+		if g%10 == 0 || g <= 2 {
+			fmt.Fprintf(bw, `<text x="%d" y="%d" font-size="10">%d</text>`+"\n", x, base+12, g)
+		}
+	}
+	fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", chartMargin, base, width-chartMargin, base)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// WritePNG codifica img em PNG
+func WritePNG(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
@@ -0,0 +1,114 @@
+package plot
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestUlamSpiral(t *testing.T) {
+	// Espiral 3x3 a partir de 1:
+	//	5 4 3
+	//	6 1 2
+	//	7 8 9
+	s, err := UlamSpiral(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{
+		true, false, true,
+		false, false, true,
+		true, false, false,
+	}
+	for i := range want {
+		if s.Prime[i] != want[i] {
+			t.Fatalf("celula %d: %t, esperado %t", i, s.Prime[i], want[i])
+		}
+	}
+
+	// Lado par: os 16 primeiros numeros preenchem o quadrado 4x4
+	s, err = UlamSpiral(1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primes := 0
+	for _, p := range s.Prime {
+		if p {
+			primes++
+		}
+	}
+	if primes != 6 { // 2, 3, 5, 7, 11, 13
+		t.Errorf("%d primos na espiral 4x4, esperados 6", primes)
+	}
+
+	if _, err := UlamSpiral(1, 0); err == nil {
+		t.Error("lado 0 aceito")
+	}
+}
+
+func TestSpiralOutput(t *testing.T) {
+	s, err := UlamSpiral(41, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, s.Image(3)); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 33 || b.Dy() != 33 {
+		t.Errorf("imagem de %v, esperada 33x33", b)
+	}
+	// 41 eh primo e fica no centro
+	if r, _, _, _ := img.At(16, 16).RGBA(); r != 0 {
+		t.Error("centro da espiral nao esta marcado como primo")
+	}
+
+	buf.Reset()
+	if err := s.WriteSVG(&buf, 2); err != nil {
+		t.Fatal(err)
+	}
+	primes := 0
+	for _, p := range s.Prime {
+		if p {
+			primes++
+		}
+	}
+	// Um retangulo por primo, mais o fundo
+	if got := strings.Count(buf.String(), "<rect"); got != primes+1 {
+		t.Errorf("%d retangulos no SVG, esperados %d", got, primes+1)
+	}
+}
+
+func TestGapHistogram(t *testing.T) {
+	h, err := GapHistogram(2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Os 25 primos ate 100 tem 24 intervalos: 1 de 1, 8 de 2, 7 de 4, 7 de
+	// 6 e 1 de 8 (89, 97)
+	want := []int{0, 1, 8, 0, 7, 0, 7, 0, 1}
+	if len(h.Count) != len(want) {
+		t.Fatalf("histograma %v, esperado %v", h.Count, want)
+	}
+	for g := range want {
+		if h.Count[g] != want[g] {
+			t.Fatalf("histograma %v, esperado %v", h.Count, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := h.WriteSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<title>2: 8</title>") {
+		t.Error("barra do intervalo 2 ausente no SVG")
+	}
+	if err := WritePNG(&buf, h.Image()); err != nil {
+		t.Fatal(err)
+	}
+}
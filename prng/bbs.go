@@ -4,10 +4,10 @@
 package prng
 
 import (
-	"crypto/rand"
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/internal/modexp"
 	"fmt"
 	"math/big"
-	"time"
 )
 
 // BlumBlumShub implementa o algoritmo BBS para gerar números pseudoaleatórios
@@ -16,27 +16,74 @@ type BlumBlumShub struct {
 	n       *big.Int // n = p * q
 	state   *big.Int // Estado atual x_i
 	bitSize int      // Tamanho desejado em bits
+	backend ModExpBackend
+	mod     *modexp.Modulus // n preparado para os quadrados, sob demanda
+	tried   bool            // se o preparo de n ja foi tentado
 }
 
 // NewBBS cria um novo gerador BBS
+//
+// Os primos e a semente sao gerados no nivel Strict; NewBBS entra em panico
+// se a fonte de entropia falhar. Use NewBBSWithSecurity para tratar o erro.
 func NewBBS(bitSize int) *BlumBlumShub {
+	bbs, err := NewBBSWithSecurity(bitSize, Strict)
+	if err != nil {
+		panic(err)
+	}
+	return bbs
+}
+
+// NewBBSWithSecurity cria um novo gerador BBS como NewBBS, usando o nivel
+// de seguranca level caso a fonte de entropia falhe.
+func NewBBSWithSecurity(bitSize int, level SecurityLevel) (*BlumBlumShub, error) {
+	return NewBBSWithEntropy(bitSize, Entropy{Level: level})
+}
+
+// MinBBSBits eh o menor tamanho aceito por NewBBSWithEntropy. Os primos
+// de Entropy.Prime tem os dois bits mais altos ligados, e abaixo de 7 bits
+// ha no maximo um deles congruente a 3 mod 4 (3, 7, 31 ou 59; nenhum com 4
+// bits): a busca por p != q nunca terminaria.
+const MinBBSBits = 13
+
+// MinSecureBBSBits eh o menor modulo do BBS, em bits, considerado seguro:
+// abaixo dele, n pode ser fatorado, o que revela a sequencia
+const MinSecureBBSBits = 512
+
+// NewBBSWithEntropy cria um novo gerador BBS como NewBBS, sorteando os
+// primos e a semente a partir da entropia e. Retorna erro se bitSize for
+// menor que MinBBSBits.
+func NewBBSWithEntropy(bitSize int, e Entropy) (*BlumBlumShub, error) {
+	if bitSize < MinBBSBits {
+		return nil, fmt.Errorf("prng: o BBS exige pelo menos %d bits, pedidos %d", MinBBSBits, bitSize)
+	}
 	// Calcula quantos bits cada primo deve ter (aproximadamente metade do tamanho total)
 	primeBits := (bitSize + 1) / 2
 
 	// Gera os primos p e q, ambos congruentes a 3 mod 4
-	p := generateSafePrime(primeBits)
-	q := generateSafePrime(primeBits)
+	p, err := generateSafePrime(primeBits, e)
+	if err != nil {
+		return nil, err
+	}
+	q, err := generateSafePrime(primeBits, e)
+	if err != nil {
+		return nil, err
+	}
 
 	// Garante que p != q
 	for p.Cmp(q) == 0 {
-		q = generateSafePrime(primeBits)
+		if q, err = generateSafePrime(primeBits, e); err != nil {
+			return nil, err
+		}
 	}
 
 	// Calcula n = p * q
 	n := new(big.Int).Mul(p, q)
 
 	// Gera um valor inicial (semente) x_0 que seja coprimo com n
-	seed := generateSeed(n)
+	seed, err := generateSeed(n, e)
+	if err != nil {
+		return nil, err
+	}
 
 	bbs := &BlumBlumShub{
 		p:       p,
@@ -46,74 +93,37 @@ func NewBBS(bitSize int) *BlumBlumShub {
 		bitSize: bitSize,
 	}
 
-	return bbs
+	return bbs, nil
 }
 
 // generateSafePrime gera um numero primo p tal que p ≡ 3 (mod 4)
-func generateSafePrime(bits int) *big.Int {
+func generateSafePrime(bits int, e Entropy) (*big.Int, error) {
 	three := big.NewInt(3)
 	four := big.NewInt(4)
 
 	for {
 		// Gera um numero primo aleatorio com o tamanho especificado
-		p, err := rand.Prime(rand.Reader, bits)
+		p, err := e.Prime(bits)
 		if err != nil {
-			// Fallback se rand.Prime falhar
-			p = generateFallbackPrime(bits)
+			return nil, err
 		}
 
 		// Verifica se p ≡ 3 (mod 4)
 		if new(big.Int).Mod(p, four).Cmp(three) == 0 {
-			return p
+			return p, nil
 		}
 	}
 }
 
-// generateFallbackPrime gera um numero primo quando rand.Prime falha
-func generateFallbackPrime(bits int) *big.Int {
-	// Inicia com um numero impar aleatorio
-	candidate := big.NewInt(0)
-	max := new(big.Int).Lsh(big.NewInt(1), uint(bits))
-
-	for {
-		// Gera um numero aleatório
-		t := time.Now().UnixNano()
-		candidate.SetInt64(t)
-		candidate.Mod(candidate, max)
-
-		// Garante que eh impar
-		if candidate.Bit(0) == 0 {
-			candidate.Add(candidate, big.NewInt(1))
-		}
-
-		// Garante que eh congruente a 3 mod 4
-		if new(big.Int).Mod(candidate, big.NewInt(4)).Cmp(big.NewInt(3)) != 0 {
-			candidate.Add(candidate, big.NewInt(2))
-		}
-
-		// Verifica se eh provavelmente primo
-		if candidate.ProbablyPrime(20) {
-			return candidate
-		}
-
-		// Tenta o proximo numero congruente a 3 mod 4
-		candidate.Add(candidate, big.NewInt(4))
-		time.Sleep(time.Nanosecond) // Varia o timestamp
-	}
-}
-
 // generateSeed gera um valor inicial x_0 que seja coprimo com n
-func generateSeed(n *big.Int) *big.Int {
+func generateSeed(n *big.Int, e Entropy) (*big.Int, error) {
 	one := big.NewInt(1)
 
 	for {
 		// Gera um numero aleatorio entre 2 e n-1
-		seed, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(2)))
+		seed, err := e.Int(new(big.Int).Sub(n, big.NewInt(2)))
 		if err != nil {
-			// Fallback se rand.Int falhar
-			t := time.Now().UnixNano()
-			seed = big.NewInt(t)
-			seed.Mod(seed, n)
+			return nil, err
 		}
 
 		seed.Add(seed, big.NewInt(2)) // Agora seed estah entre 2 e n-1
@@ -124,22 +134,56 @@ func generateSeed(n *big.Int) *big.Int {
 		if gcd.Cmp(one) == 0 {
 			// Calcula x_0 = seed^2 mod n para iniciar a sequencia
 			x0 := new(big.Int).Exp(seed, big.NewInt(2), n)
-			return x0
+			return x0, nil
 		}
 	}
 }
 
 // NextState calcula o proximo estado x_(i+1) = x_i^2 mod n
 func (bbs *BlumBlumShub) NextState() *big.Int {
-	// x_(i+1) = x_i^2 mod n
-	bbs.state = new(big.Int).Exp(bbs.state, big.NewInt(2), bbs.n)
+	bbs.step()
 	return new(big.Int).Set(bbs.state)
 }
 
+// step avanca o estado no lugar, x_(i+1) = x_i^2 mod n, com o pacote
+// modexp quando nao ha um backend externo
+func (bbs *BlumBlumShub) step() {
+	if bbs.backend != nil {
+		bbs.state = bbs.backend.Exp(bbs.state, big.NewInt(2), bbs.n)
+		return
+	}
+	bbs.square(bbs.state)
+}
+
+// square calcula x = x^2 mod n no lugar. O modulo eh preparado na primeira
+// chamada; um n par, que so um estado restaurado pode ter, fica com valores
+// temporarios do pool, sem novas tentativas de preparo.
+func (bbs *BlumBlumShub) square(x *big.Int) {
+	if !bbs.tried {
+		bbs.mod, _ = modexp.New(bbs.n)
+		bbs.tried = true
+	}
+	if bbs.mod != nil {
+		bbs.mod.Square(x, x)
+		return
+	}
+	square, quo := GetInt(), GetInt()
+	square.Mul(x, x)
+	quo.QuoRem(square, bbs.n, x)
+	PutInt(square)
+	PutInt(quo)
+}
+
+// SetBackend define o backend das exponenciacoes modulares do gerador;
+// nil volta ao backend padrao
+func (bbs *BlumBlumShub) SetBackend(b ModExpBackend) {
+	bbs.backend = b
+}
+
 // NextBit gera o proximo bit (o bit de paridade do estado)
 func (bbs *BlumBlumShub) NextBit() uint {
 	// Atualizar o estado
-	bbs.NextState()
+	bbs.step()
 
 	// Retorna o bit de paridade (LSB)
 	return bbs.state.Bit(0)
@@ -147,23 +191,23 @@ func (bbs *BlumBlumShub) NextBit() uint {
 
 // Next gera um numero pseudoaleatorio com o tamanho aproximado de bitSize
 func (bbs *BlumBlumShub) Next() *big.Int {
-	result := big.NewInt(0)
-
-	// Gera bitSize bits para formar o número
-	for i := 0; i < bbs.bitSize; i++ {
-		bit := bbs.NextBit()
-
-		// Desloca o resultado e adicionar o novo bit
-		result.Lsh(result, 1)
-		if bit == 1 {
-			result.Or(result, big.NewInt(1))
+	result := new(big.Int)
+
+	// Gera bitSize bits para formar o número, do mais significativo para o
+	// menos significativo; ligar os bits no lugar evita realocar o
+	// resultado a cada deslocamento
+	for i := bbs.bitSize - 1; i >= 0; i-- {
+		if bbs.NextBit() == 1 {
+			result.SetBit(result, i, 1)
 		}
 	}
 
 	return result
 }
 
-func Bbs() ([]int, []*big.Int) {
+// Bbs gera um numero pseudoaleatorio para cada tamanho de bits do
+// enunciado, de acordo com a configuracao cfg.
+func Bbs(cfg DemoConfig) ([]int, []*big.Int, error) {
 	// Tamanhos de bits para testar
 	bitSizes := []int{40, 56, 80, 128, 168, 224, 256, 512, 1024, 2048, 4096}
 	generatedNumbers := make([]*big.Int, len(bitSizes))
@@ -176,23 +220,30 @@ func Bbs() ([]int, []*big.Int) {
 
 		// Criamos um novo gerador para cada tamanho de bits
 		fmt.Printf("- Gerando primos p e q (isso pode levar alguns instantes)...\n")
-		init_time := time.Now()
-		bbs := NewBBS(bits)
-		elapsed_time := time.Since(init_time)
+		init_time := clock.Now()
+		bbs, err := NewBBSWithEntropy(bits, cfg.Entropy)
+		if err != nil {
+			return nil, nil, err
+		}
+		elapsed_time := clock.Since(init_time)
 		fmt.Printf("- Tempo de geração: %s\n", elapsed_time)
 
 		// Mostrando info sobre o modulo n
 		fmt.Printf("- Módulo n gerado com %d bits\n", bbs.n.BitLen())
 		fmt.Printf("- Gerando bits aleatórios...\n")
-		randomNum := bbs.Next()
+		state := snapshot(bbs, cfg)
+		randomNum := Whiten(bbs, cfg.Whitening).Next()
 
 		bitLength := randomNum.BitLen()
 		fmt.Printf("- Tamanho real: %d bits\n", bitLength)
-		fmt.Printf("- Valor decimal: %s\n", randomNum.String())
-		fmt.Printf("- Representação binária: %s\n", randomNum.Text(2))
+		printCandidate(randomNum, cfg)
+		notifyCandidate(bits, randomNum, state, cfg)
+		if cfg.Sensitive {
+			bbs.Wipe()
+		}
 
 		generatedNumbers[i] = randomNum
 	}
 
-	return bitSizes, generatedNumbers
+	return bitSizes, generatedNumbers, nil
 }
@@ -0,0 +1,84 @@
+// Esse arquivo trata dos parametros privados (p e q) do Blum Blum Shub,
+//  permitindo descarta-los quando nao forem mais necessarios.
+
+package prng
+
+import (
+	"errors"
+	"math/big"
+)
+
+// DestroyPrivate apaga os fatores p e q da memoria, mantendo apenas o
+// modulo n e o estado atual. O gerador continua produzindo a mesma
+// sequencia, mas operacoes que dependem da fatoracao deixam de funcionar.
+func (bbs *BlumBlumShub) DestroyPrivate() {
+	WipeInt(bbs.p)
+	WipeInt(bbs.q)
+	bbs.p, bbs.q = nil, nil
+}
+
+// HasPrivate informa se o gerador ainda conhece os fatores p e q
+func (bbs *BlumBlumShub) HasPrivate() bool {
+	return bbs.p != nil && bbs.q != nil
+}
+
+// Modulus retorna uma copia do modulo publico n
+func (bbs *BlumBlumShub) Modulus() *big.Int {
+	return new(big.Int).Set(bbs.n)
+}
+
+// State retorna uma copia do estado atual x_i, que, junto com n, basta
+// para prever toda a saida seguinte do gerador
+func (bbs *BlumBlumShub) State() *big.Int {
+	return new(big.Int).Set(bbs.state)
+}
+
+// NewBBSPublicOnly cria um gerador como NewBBSWithEntropy e descarta
+// imediatamente os fatores p e q, de modo que nunca fiquem retidos.
+func NewBBSPublicOnly(bitSize int, e Entropy) (*BlumBlumShub, error) {
+	bbs, err := NewBBSWithEntropy(bitSize, e)
+	if err != nil {
+		return nil, err
+	}
+	bbs.DestroyPrivate()
+	return bbs, nil
+}
+
+// NewBBSFromPublic cria um gerador a partir apenas do modulo n e do estado
+// atual x, sem conhecer a fatoracao de n. Os valores sao copiados.
+func NewBBSFromPublic(n, x *big.Int, bitSize int) (*BlumBlumShub, error) {
+	if n.Cmp(big.NewInt(3)) < 0 || n.Bit(0) == 0 {
+		return nil, errors.New("prng: modulo do BBS invalido")
+	}
+	if x.Sign() <= 0 || x.Cmp(n) >= 0 {
+		return nil, errors.New("prng: estado do BBS fora do intervalo")
+	}
+	return &BlumBlumShub{
+		n:       new(big.Int).Set(n),
+		state:   new(big.Int).Set(x),
+		bitSize: bitSize,
+	}, nil
+}
+
+// Reseed sorteia uma nova semente x_0, coprima com n, a partir da entropia
+// e e reinicia o gerador nela. Nao depende dos fatores p e q. Retorna uma
+// copia de x_0.
+func (bbs *BlumBlumShub) Reseed(e Entropy) (*big.Int, error) {
+	x0, err := generateSeed(bbs.n, e)
+	if err != nil {
+		return nil, err
+	}
+	WipeInt(bbs.state)
+	bbs.state = x0
+	return new(big.Int).Set(x0), nil
+}
+
+// Advance avanca o gerador t estados, calculando x_(i+t) = x_i^(2^t) mod n
+// por t quadrados sucessivos, sem usar a fatoracao de n. Os quadrados usam
+// o backend do gerador, como NextState. Retorna uma copia do novo estado.
+func (bbs *BlumBlumShub) Advance(t uint64) *big.Int {
+	for i := uint64(0); i < t; i++ {
+		bbs.step()
+	}
+	return new(big.Int).Set(bbs.state)
+}
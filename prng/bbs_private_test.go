@@ -0,0 +1,67 @@
+package prng
+
+import "testing"
+
+func TestDestroyPrivate(t *testing.T) {
+	bbs := NewBBS(64)
+	p := bbs.p
+	clone := new(BlumBlumShub)
+	data, _ := bbs.MarshalBinary()
+	clone.UnmarshalBinary(data)
+
+	bbs.DestroyPrivate()
+	if bbs.HasPrivate() || p.Sign() != 0 {
+		t.Fatal("fatores nao foram apagados")
+	}
+	if a, b := bbs.Next(), clone.Next(); a.Cmp(b) != 0 {
+		t.Fatal("sequencia mudou apos DestroyPrivate")
+	}
+
+	// O estado sem fatores tambem deve ser serializavel
+	data, _ = bbs.MarshalBinary()
+	restored := new(BlumBlumShub)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.HasPrivate() {
+		t.Error("estado restaurado nao deveria ter fatores")
+	}
+}
+
+func TestPublicOnlyConstructors(t *testing.T) {
+	bbs, err := NewBBSPublicOnly(64, Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bbs.HasPrivate() {
+		t.Fatal("NewBBSPublicOnly manteve os fatores")
+	}
+
+	public, err := NewBBSFromPublic(bbs.Modulus(), bbs.state, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := bbs.Next(), public.Next(); a.Cmp(b) != 0 {
+		t.Fatal("NewBBSFromPublic produz sequencia diferente")
+	}
+	if _, err := NewBBSFromPublic(bbs.Modulus(), bbs.Modulus(), 64); err == nil {
+		t.Error("estado fora do intervalo aceito")
+	}
+}
+
+func TestBBSMinBits(t *testing.T) {
+	// Abaixo de MinBBSBits ha no maximo um primo de Blum do tamanho pedido, e
+	// a busca por p != q nunca terminaria
+	for bits := 1; bits < MinBBSBits; bits++ {
+		if _, err := NewBBSWithEntropy(bits, Entropy{}); err == nil {
+			t.Errorf("NewBBSWithEntropy(%d) aceito", bits)
+		}
+	}
+	bbs, err := NewBBSWithEntropy(MinBBSBits, Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bbs.p.Cmp(bbs.q) == 0 {
+		t.Errorf("p = q = %s", bbs.p)
+	}
+}
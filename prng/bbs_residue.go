@@ -0,0 +1,130 @@
+// Esse arquivo traz as ferramentas de residuosidade quadratica em torno do
+//  Blum Blum Shub: o teste de residuo modulo n, com e sem os fatores, a
+//  raiz quadrada principal e o retrocesso do gerador quando a fatoracao eh
+//  conhecida.
+
+package prng
+
+import (
+	"PrimeNumGenerator/numutil"
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrNotResidue indica que o valor nao eh residuo quadratico modulo n
+	ErrNotResidue = errors.New("prng: valor nao eh residuo quadratico modulo n")
+
+	errNotBlum = errors.New("prng: p e q precisam ser primos distintos congruentes a 3 mod 4")
+)
+
+// Residuosity classifica um valor x modulo n = p*q quanto a ser um residuo
+// quadratico, isto eh, o quadrado de algum elemento de Z_n*
+type Residuosity int
+
+const (
+	// NonResidue: x nao eh residuo (ou nao eh coprimo com n)
+	NonResidue Residuosity = iota
+	// Residue: x eh residuo, o que so pode ser afirmado com os fatores
+	Residue
+	// UnknownResiduosity: o simbolo de Jacobi (x/n) vale 1 e, sem os
+	// fatores, nao ha como distinguir um residuo de um pseudo-quadrado
+	UnknownResiduosity
+)
+
+func (r Residuosity) String() string {
+	switch r {
+	case NonResidue:
+		return "não resíduo"
+	case Residue:
+		return "resíduo"
+	}
+	return "desconhecido"
+}
+
+// QuadraticResiduosity classifica x modulo n sem conhecer a fatoracao,
+// apenas pelo simbolo de Jacobi: (x/n) = -1 garante que x nao eh residuo,
+// mas (x/n) = 1 vale tanto para os residuos quanto para os
+// pseudo-quadrados, que nao sao residuo modulo p nem modulo q. Decidir
+// entre os dois sem p e q eh o problema da residuosidade quadratica, em que
+// se apoia a seguranca do BBS. n deve ser impar e positivo.
+func QuadraticResiduosity(x, n *big.Int) Residuosity {
+	if numutil.Jacobi(x, n) != 1 {
+		return NonResidue
+	}
+	return UnknownResiduosity
+}
+
+// IsQuadraticResidue informa se x eh residuo quadratico modulo n = p*q,
+// usando os fatores: x tem de ser coprimo com n e residuo modulo p e
+// modulo q
+func IsQuadraticResidue(x, p, q *big.Int) bool {
+	return numutil.Legendre(x, p) == 1 && numutil.Legendre(x, q) == 1
+}
+
+// PrincipalSquareRoot retorna a raiz quadrada principal de x modulo
+// n = p*q, com p e q primos distintos congruentes a 3 mod 4 (um inteiro de
+// Blum, como o modulo do BBS): das quatro raizes de um residuo, ela eh a
+// unica que tambem eh residuo. Como o quadrado eh uma permutacao dos
+// residuos de um inteiro de Blum, a raiz principal de x_(i+1) eh sempre o
+// estado x_i do gerador. O erro eh ErrNotResidue se x nao for residuo.
+func PrincipalSquareRoot(x, p, q *big.Int) (*big.Int, error) {
+	return rootPower(x, p, q, 1)
+}
+
+// rootPower aplica t vezes a raiz quadrada principal a x: em Z_p*, com
+// p = 3 mod 4, a raiz principal eh x^((p+1)/4), entao t raizes equivalem a
+// x^(((p+1)/4)^t mod (p-1)), calculado de uma vez; o resultado modulo n
+// vem do Teorema Chines do Resto
+func rootPower(x, p, q *big.Int, t uint64) (*big.Int, error) {
+	three := big.NewInt(3)
+	if p.Cmp(q) == 0 || p.Cmp(three) < 0 || q.Cmp(three) < 0 || p.Bits()[0]&3 != 3 || q.Bits()[0]&3 != 3 {
+		return nil, errNotBlum
+	}
+	if !IsQuadraticResidue(x, p, q) {
+		return nil, ErrNotResidue
+	}
+	one := big.NewInt(1)
+	root := func(prime *big.Int) *big.Int {
+		order := new(big.Int).Sub(prime, one)
+		e := new(big.Int).Add(prime, one)
+		e.Rsh(e, 2)
+		e.Exp(e, new(big.Int).SetUint64(t), order)
+		return e.Exp(new(big.Int).Mod(x, prime), e, prime)
+	}
+	r, _, err := numutil.CRTPair(root(p), p, root(q), q)
+	return r, err
+}
+
+// Residuosity classifica x modulo o n do gerador: com os fatores, a
+// resposta eh exata, como em IsQuadraticResidue; sem eles, eh a de
+// QuadraticResiduosity. Os estados do gerador sao sempre residuos.
+func (bbs *BlumBlumShub) Residuosity(x *big.Int) Residuosity {
+	if !bbs.HasPrivate() {
+		return QuadraticResiduosity(x, bbs.n)
+	}
+	if IsQuadraticResidue(x, bbs.p, bbs.q) {
+		return Residue
+	}
+	return NonResidue
+}
+
+// Rewind recua o gerador t estados, o inverso de Advance: com os fatores,
+// x_(i-t) eh a t-esima raiz quadrada principal de x_i. Quem conhece p e q
+// e captura um unico estado reconstroi assim toda a saida anterior do
+// gerador, ate a semente; sem eles, recuar um estado eh tao dificil quanto
+// fatorar n. Recuar alem de x_0 leva aos residuos que o precederiam na
+// mesma orbita. Retorna uma copia do novo estado, ou ErrNoPrivate se o
+// gerador nao conhecer os fatores.
+func (bbs *BlumBlumShub) Rewind(t uint64) (*big.Int, error) {
+	if !bbs.HasPrivate() {
+		return nil, ErrNoPrivate
+	}
+	x, err := rootPower(bbs.state, bbs.p, bbs.q, t)
+	if err != nil {
+		return nil, err
+	}
+	WipeInt(bbs.state)
+	bbs.state = x
+	return new(big.Int).Set(x), nil
+}
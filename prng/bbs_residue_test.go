@@ -0,0 +1,83 @@
+package prng
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestResiduosity(t *testing.T) {
+	// n = 7 * 11: os residuos de Z_77* sao os quadrados
+	p, q, n := big.NewInt(7), big.NewInt(11), big.NewInt(77)
+	squares := make(map[int64]bool)
+	for y := int64(1); y < 77; y++ {
+		if y%7 != 0 && y%11 != 0 {
+			squares[y*y%77] = true
+		}
+	}
+	for v := int64(1); v < 77; v++ {
+		x := big.NewInt(v)
+		if got := IsQuadraticResidue(x, p, q); got != squares[v] {
+			t.Fatalf("IsQuadraticResidue(%d) = %v", v, got)
+		}
+		public := QuadraticResiduosity(x, n)
+		if squares[v] && public != UnknownResiduosity || public == Residue {
+			t.Fatalf("QuadraticResiduosity(%d) = %v", v, public)
+		}
+
+		root, err := PrincipalSquareRoot(x, p, q)
+		if !squares[v] {
+			if !errors.Is(err, ErrNotResidue) {
+				t.Fatalf("raiz de %d: erro %v", v, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !squares[root.Int64()] || root.Int64()*root.Int64()%77 != v {
+			t.Fatalf("raiz principal de %d = %d", v, root)
+		}
+	}
+	if _, err := PrincipalSquareRoot(big.NewInt(4), big.NewInt(5), q); err == nil {
+		t.Error("p = 1 mod 4 aceito")
+	}
+}
+
+func TestRewind(t *testing.T) {
+	bbs := NewBBS(128)
+	start := new(big.Int).Set(bbs.state)
+	var bits []uint
+	for range 100 {
+		bits = append(bits, bbs.NextBit())
+	}
+	if bbs.Residuosity(bbs.state) != Residue {
+		t.Fatal("estado nao eh residuo")
+	}
+
+	// Com os fatores, o estado capturado leva de volta ao inicio
+	recovered, err := bbs.Rewind(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.Cmp(start) != 0 {
+		t.Fatal("Rewind nao voltou ao estado inicial")
+	}
+	for i, want := range bits {
+		if got := bbs.NextBit(); got != want {
+			t.Fatalf("bit %d diferente apos Rewind", i)
+		}
+	}
+	x, _ := bbs.Rewind(1)
+	if y, _ := bbs.Rewind(1); new(big.Int).Exp(y, big.NewInt(2), bbs.n).Cmp(x) != 0 {
+		t.Fatal("Rewind(1) nao eh a raiz de x")
+	}
+
+	bbs.DestroyPrivate()
+	if _, err := bbs.Rewind(1); !errors.Is(err, ErrNoPrivate) {
+		t.Errorf("Rewind sem fatores: erro %v", err)
+	}
+	if got := bbs.Residuosity(bbs.state); got != UnknownResiduosity {
+		t.Errorf("residuosidade sem fatores = %v", got)
+	}
+}
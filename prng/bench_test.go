@@ -0,0 +1,57 @@
+package prng
+
+import (
+	"math/big"
+	"testing"
+)
+
+func BenchmarkBBSNext(b *testing.B) {
+	bbs := NewBBS(512)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bbs.Next()
+	}
+}
+
+func BenchmarkLFGNext(b *testing.B) {
+	lfg := NewLFG(10, 7, 10, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lfg.Next()
+	}
+}
+
+// Com o buffer circular, o custo de Next nao depende do atraso k
+func BenchmarkLFGNextLag607(b *testing.B) {
+	lfg := NewLFG(607, 273, 607, 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lfg.Next()
+	}
+}
+
+func BenchmarkExactBits(b *testing.B) {
+	lfg := NewLFG(10, 7, 10, 61)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExactBits(lfg, 4096)
+	}
+}
+
+func BenchmarkOrShifted(b *testing.B) {
+	src := make([]big.Word, 64)
+	for i := range src {
+		src[i] = big.Word(i) * 2654435761
+	}
+	dst := make([]big.Word, len(src)+1)
+	b.Run("asm", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orShifted(dst, src, 13)
+		}
+	})
+	b.Run("generic", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orShiftedGeneric(dst, src, 13)
+		}
+	})
+}
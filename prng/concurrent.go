@@ -0,0 +1,45 @@
+// Esse arquivo traz um adaptador que permite compartilhar um gerador entre
+//  varias goroutines.
+
+package prng
+
+import (
+	"math/big"
+	"sync"
+)
+
+// Concurrent retorna um gerador que repassa as chamadas a g sob um mutex,
+// de modo que pode ser usado por varias goroutines ao mesmo tempo. Nenhum
+// gerador do pacote eh seguro para uso concorrente por conta propria.
+//
+// Cada chamada disputa o mesmo mutex, o que limita o desempenho quando
+// muitas goroutines geram numeros sem parar. Nesse caso eh melhor dar a
+// cada goroutine um fluxo proprio e independente, obtido com Split.
+//
+// Apenas as chamadas individuais sao atomicas: as saidas que ExactBits ou
+// NextBelow concatenam podem se intercalar com as de outras goroutines, o
+// que nao afeta a distribuicao, mas torna a sequencia de cada goroutine
+// imprevisivel mesmo com semente conhecida.
+func Concurrent(g Generator) Generator {
+	if c, ok := g.(*concurrent); ok {
+		return c
+	}
+	return &concurrent{g: g}
+}
+
+type concurrent struct {
+	mu sync.Mutex
+	g  Generator
+}
+
+func (c *concurrent) Next() *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.g.Next()
+}
+
+func (c *concurrent) Bits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.g.Bits()
+}
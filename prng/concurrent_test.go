@@ -0,0 +1,51 @@
+package prng
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestConcurrent(t *testing.T) {
+	seed := make([]*big.Int, 10)
+	for i := range seed {
+		seed[i] = new(big.Int).SetUint64(0x9e3779b97f4a7c15 * uint64(i+1))
+	}
+	ref, _ := NewLFGFromState(7, 10, 64, seed)
+	shared, _ := NewLFGFromState(7, 10, 64, seed)
+	g := Concurrent(shared)
+	if Concurrent(g) != g {
+		t.Error("Concurrent embrulhou um gerador ja concorrente")
+	}
+
+	// As goroutines repartem a mesma sequencia: juntas, elas obtem
+	// exatamente os valores da sequencia de referencia, cada um uma vez
+	const workers, perWorker = 8, 500
+	var mu sync.Mutex
+	seen := map[string]int{}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values := make([]string, perWorker)
+			for i := range values {
+				values[i] = g.Next().Text(16)
+			}
+			mu.Lock()
+			for _, v := range values {
+				seen[v]++
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < workers*perWorker; i++ {
+		v := ref.Next().Text(16)
+		if seen[v] == 0 {
+			t.Fatalf("valor %d da sequencia (%s) nao foi gerado", i, v)
+		}
+		seen[v]--
+	}
+}
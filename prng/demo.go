@@ -0,0 +1,96 @@
+// Esse arquivo traz a configuracao compartilhada pelas execucoes de
+//  demonstracao Lfg e Bbs.
+
+package prng
+
+import (
+	"PrimeNumGenerator/numfmt"
+	"fmt"
+	"math/big"
+)
+
+// DemoConfig agrupa as opcoes das execucoes de demonstracao Lfg e Bbs.
+// Com Sensitive, os candidatos gerados nao sao exibidos e o estado de cada
+// gerador eh apagado da memoria assim que o candidato eh extraido.
+// OnCandidate, se nao for nil, recebe cada candidato junto com o estado
+// serializado do gerador antes de produzi-lo (nil no modo Sensitive), por
+// exemplo para registra-los no historico. Warmup eh o numero de valores
+// descartados de cada LFG antes de extrair o candidato: 0 usa
+// DefaultWarmup(k) e um valor negativo desliga o aquecimento. Whitening
+// eh o pos-processamento aplicado a saida dos geradores antes de montar
+// o candidato, e Format, a formatacao dos candidatos exibidos.
+type DemoConfig struct {
+	Entropy     Entropy
+	Sensitive   bool
+	OnCandidate func(bits int, candidate *big.Int, state []byte)
+	Warmup      int
+	Whitening   Whitening
+	Format      numfmt.Format
+}
+
+// Atrasos do LFG das demonstracoes, parametros comuns tirados do segundo
+// volume de The Art of Computer Programming
+const (
+	demoLagJ = 7
+	demoLagK = 10
+)
+
+// Params descreve os parametros do gerador de nome generator ("lfg" ou
+// "bbs") nas demonstracoes com a configuracao cfg, por exemplo para o
+// manifesto de uma execucao
+func (cfg DemoConfig) Params(generator string) map[string]any {
+	params := map[string]any{"whitening": cfg.Whitening.String()}
+	if generator == "lfg" {
+		params["j"] = demoLagJ
+		params["k"] = demoLagK
+		params["size"] = demoLagK
+		params["warmup"] = cfg.warmup(demoLagK)
+	}
+	return params
+}
+
+// warmup retorna o aquecimento de um LFG com atraso k
+func (cfg DemoConfig) warmup(k int) uint64 {
+	switch {
+	case cfg.Warmup < 0:
+		return 0
+	case cfg.Warmup == 0:
+		return DefaultWarmup(k)
+	default:
+		return uint64(cfg.Warmup)
+	}
+}
+
+// snapshot retorna o estado serializado de g para OnCandidate, ou nil se
+// nao houver OnCandidate ou se o estado for sensivel
+func snapshot(g interface{ MarshalBinary() ([]byte, error) }, cfg DemoConfig) []byte {
+	if cfg.OnCandidate == nil || cfg.Sensitive {
+		return nil
+	}
+	state, err := g.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return state
+}
+
+// notifyCandidate repassa o candidato para OnCandidate
+func notifyCandidate(bits int, n *big.Int, state []byte, cfg DemoConfig) {
+	if cfg.OnCandidate != nil {
+		cfg.OnCandidate(bits, new(big.Int).Set(n), state)
+	}
+}
+
+// printCandidate exibe o candidato gerado, a menos que ele seja sensivel
+func printCandidate(n *big.Int, cfg DemoConfig) {
+	if cfg.Sensitive {
+		fmt.Println("- Valor omitido (modo sensível)")
+		return
+	}
+
+	// Exibimos a representacao decimal
+	fmt.Printf("- Valor decimal: %s\n", cfg.Format.Int(n, 10))
+
+	// Exibimos a representacao binaria
+	fmt.Printf("- Representação binária: %s\n", cfg.Format.Int(n, 2))
+}
@@ -0,0 +1,10 @@
+// O pacote prng implementa os geradores de numeros pseudoaleatorios (Lagged
+// Fibonacci, Blum Blum Shub e os hibridos), as fontes de entropia que os
+// alimentam e os backends de exponenciacao modular.
+//
+// A API exportada segue o versionamento semantico: a partir da versao 1,
+// nenhuma versao menor remove ou altera um simbolo listado em api/v1.txt
+// (veja internal/apicheck). Os detalhes de implementacao, como a fonte
+// jitter e a aritmetica de Montgomery, ficam em internal/ e podem mudar a
+// qualquer versao.
+package prng
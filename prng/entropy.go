@@ -0,0 +1,122 @@
+// Esse arquivo define a abstracao das fontes de entropia usadas para semear
+//  os geradores e sortear valores, centralizando o tratamento de falhas.
+
+package prng
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// EntropySource eh uma fonte de bytes aleatorios. Read deve preencher p
+// por completo ou retornar um erro.
+type EntropySource interface {
+	Read(p []byte) (n int, err error)
+}
+
+// CryptoSource eh a fonte padrao, baseada em crypto/rand
+var CryptoSource EntropySource = rand.Reader
+
+// Entropy combina uma fonte de entropia com o nivel de seguranca que define
+// o que fazer quando ela falha. O valor zero usa CryptoSource no nivel Strict.
+type Entropy struct {
+	Source EntropySource
+	Level  SecurityLevel
+}
+
+// reader retorna a fonte a ser usada, ja com o fallback do nivel Permissive
+func (e Entropy) reader() EntropySource {
+	src := e.Source
+	if src == nil {
+		src = CryptoSource
+	}
+	if e.Level == Permissive {
+		return fallbackReader{src}
+	}
+	return src
+}
+
+// Read preenche p com bytes da fonte de entropia
+func (e Entropy) Read(p []byte) (int, error) {
+	n, err := e.reader().Read(p)
+	if err == nil && n < len(p) {
+		err = errors.New("leitura incompleta")
+	}
+	if err != nil {
+		return n, entropyError(err)
+	}
+	return n, nil
+}
+
+// Int retorna um valor uniforme em [0, max). Entra em panico se max <= 0.
+func (e Entropy) Int(max *big.Int) (*big.Int, error) {
+	v, err := rand.Int(e.reader(), max)
+	if err != nil {
+		return nil, entropyError(err)
+	}
+	return v, nil
+}
+
+// Bits retorna um numero aleatorio de ate bits bits
+func (e Entropy) Bits(bits int) (*big.Int, error) {
+	buf := make([]byte, (bits+7)/8)
+	if _, err := e.Read(buf); err != nil {
+		return nil, err
+	}
+	v := new(big.Int).SetBytes(buf)
+	// Descartamos os bits excedentes do byte mais significativo
+	return v.Rsh(v, uint(len(buf)*8-bits)), nil
+}
+
+// Prime retorna um numero provavelmente primo com exatamente bits bits.
+// Assim como crypto/rand.Prime, os dois bits mais significativos sao
+// ligados para que o produto de dois primos tenha exatamente 2*bits bits.
+// Diferente de crypto/rand.Prime, a fonte de entropia eh sempre respeitada.
+func (e Entropy) Prime(bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, errors.New("prng: primo deve ter ao menos 2 bits")
+	}
+	for {
+		p, err := e.Bits(bits)
+		if err != nil {
+			return nil, err
+		}
+		p.SetBit(p, bits-1, 1)
+		if bits > 2 {
+			p.SetBit(p, bits-2, 1)
+		}
+		p.SetBit(p, 0, 1)
+
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// fallbackReader recorre a fonte jitter quando a fonte original falha. Eh
+// usado apenas no nivel Permissive, pois a qualidade do jitter depende do
+// hardware e nao eh verificavel como a do crypto/rand.
+type fallbackReader struct {
+	src EntropySource
+}
+
+func (f fallbackReader) Read(p []byte) (int, error) {
+	n, err := f.src.Read(p)
+	if err == nil && n == len(p) {
+		return n, nil
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	j, jerr := sharedJitter()
+	if jerr != nil {
+		return n, errors.Join(err, jerr)
+	}
+	m, jerr := j.Read(p[n:])
+	if jerr != nil {
+		return n + m, errors.Join(err, jerr)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,136 @@
+package prng
+
+import (
+	"PrimeNumGenerator/internal/jitter"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// counterSource eh uma fonte deterministica baseada em SHA-256 de um contador
+type counterSource struct {
+	counter uint64
+	buf     []byte
+}
+
+func (c *counterSource) Read(p []byte) (int, error) {
+	for i := range p {
+		if len(c.buf) == 0 {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], c.counter)
+			c.counter++
+			sum := sha256.Sum256(block[:])
+			c.buf = sum[:]
+		}
+		p[i] = c.buf[0]
+		c.buf = c.buf[1:]
+	}
+	return len(p), nil
+}
+
+// failingSource sempre falha
+type failingSource struct{}
+
+func (failingSource) Read(p []byte) (int, error) {
+	return 0, errors.New("fonte indisponivel")
+}
+
+func TestDeterministicSource(t *testing.T) {
+	a, err := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: &counterSource{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: &counterSource{}})
+	for i := 0; i < 20; i++ {
+		if x, y := a.Next(), b.Next(); x.Cmp(y) != 0 {
+			t.Fatalf("LFGs com a mesma fonte divergem na saida %d", i)
+		}
+	}
+
+	c, err := NewBBSWithEntropy(64, Entropy{Source: &counterSource{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := NewBBSWithEntropy(64, Entropy{Source: &counterSource{}})
+	if c.n.Cmp(d.n) != 0 || c.Next().Cmp(d.Next()) != 0 {
+		t.Fatal("BBS com a mesma fonte divergem")
+	}
+}
+
+func TestEntropyFailure(t *testing.T) {
+	_, err := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: failingSource{}, Level: Strict})
+	if !errors.Is(err, ErrEntropy) {
+		t.Fatalf("esperado ErrEntropy no nivel Strict, obtido %v", err)
+	}
+	_, err = NewBBSWithEntropy(64, Entropy{Source: failingSource{}, Level: Strict})
+	if !errors.Is(err, ErrEntropy) {
+		t.Fatalf("esperado ErrEntropy no nivel Strict, obtido %v", err)
+	}
+
+	if _, err := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: failingSource{}, Level: Permissive}); err != nil {
+		t.Fatalf("nivel Permissive deveria recorrer ao relogio: %v", err)
+	}
+	if _, err := NewBBSWithEntropy(40, Entropy{Source: failingSource{}, Level: Permissive}); err != nil {
+		t.Fatalf("nivel Permissive deveria recorrer ao relogio: %v", err)
+	}
+}
+
+func TestEntropyPrime(t *testing.T) {
+	e := Entropy{Source: &counterSource{}}
+	for _, bits := range []int{2, 8, 40, 128} {
+		p, err := e.Prime(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.BitLen() != bits || !p.ProbablyPrime(20) {
+			t.Errorf("Prime(%d) = %s", bits, p)
+		}
+	}
+}
+
+func TestJitterSource(t *testing.T) {
+	j, err := NewJitterSource()
+	if errors.Is(err, jitter.ErrHealth) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := make([]byte, 40), make([]byte, 40)
+	j.Read(a)
+	j.Read(b)
+	if string(a) == string(b) {
+		t.Error("leituras consecutivas identicas")
+	}
+}
+
+func TestSeededSource(t *testing.T) {
+	// A mesma semente reproduz os mesmos bytes, qualquer que seja o
+	// tamanho das leituras
+	a, b := NewSeededSource([]byte("semente")), NewSeededSource([]byte("semente"))
+	whole := make([]byte, 100)
+	a.Read(whole)
+	parts := make([]byte, 0, 100)
+	for _, size := range []int{1, 31, 32, 36} {
+		chunk := make([]byte, size)
+		b.Read(chunk)
+		parts = append(parts, chunk...)
+	}
+	if !bytes.Equal(whole, parts) {
+		t.Error("leituras fracionadas diferem da leitura inteira")
+	}
+
+	other := make([]byte, 100)
+	NewSeededSource([]byte("outra")).Read(other)
+	if bytes.Equal(whole, other) {
+		t.Error("sementes diferentes produziram os mesmos bytes")
+	}
+
+	x, _ := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: NewSeededSource([]byte{1})})
+	y, _ := NewLFGWithEntropy(10, 7, 10, 64, Entropy{Source: NewSeededSource([]byte{1})})
+	if x.Next().Cmp(y.Next()) != 0 {
+		t.Error("geradores de mesma semente divergiram")
+	}
+}
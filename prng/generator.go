@@ -0,0 +1,151 @@
+// Esse arquivo define a interface comum dos geradores de numeros
+//  pseudoaleatorios.
+
+package prng
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Generator eh a interface implementada pelos geradores do pacote. Next
+// retorna o proximo numero da sequencia, de ate Bits() bits.
+//
+// A interface fica com esses dois metodos para que geradores externos
+// continuem a implementa-la: os sorteios derivados (ExactBits, NextBelow e
+// NextInRange) sao funcoes do pacote que aceitam qualquer Generator, e os
+// geradores do pacote tambem os oferecem como metodos.
+type Generator interface {
+	Next() *big.Int
+	Bits() int
+}
+
+// ExactBits monta um numero com exatamente bits bits a partir das saidas
+// de g: concatena quantas saidas de g.Bits() bits forem necessarias, mantem
+// os bits mais significativos e liga o bit bits-1.
+func ExactBits(g Generator, bits int) *big.Int {
+	if bits < 1 {
+		return new(big.Int)
+	}
+	result := randomBits(g, bits)
+	return result.SetBit(result, bits-1, 1)
+}
+
+// randomBits retorna os bits bits mais significativos da concatenacao das
+// proximas saidas de g, um numero entre 0 e 2^bits - 1
+//
+// As saidas sao acrescentadas a um vetor de palavras alocado uma vez, da
+// menos para a mais significativa, em vez de deslocar o resultado inteiro
+// a cada saida, o que custaria tempo quadratico no numero de saidas.
+func randomBits(g Generator, bits int) *big.Int {
+	if bits <= 0 {
+		return new(big.Int)
+	}
+	width := g.Bits()
+	count := (bits + width - 1) / width
+	have := count * width
+	outputs := make([]*big.Int, count)
+	for i := range outputs {
+		outputs[i] = g.Next()
+	}
+	// Uma saida com mais de width bits, fora do contrato de Generator, entra
+	// por inteiro no resultado, como no deslocamento seguido de Or
+	size := have
+	for _, out := range outputs {
+		size = max(size, (count-1)*width+out.BitLen())
+	}
+	words := make([]big.Word, (size+wordBits-1)/wordBits+1)
+	for i, out := range outputs {
+		offset := (count - 1 - i) * width
+		orShifted(words[offset/wordBits:], out.Bits(), uint(offset%wordBits))
+	}
+	result := new(big.Int).SetBits(words)
+	return result.Rsh(result, uint(have-bits))
+}
+
+// wordBits eh o tamanho de big.Word, em bits
+const wordBits = bits.UintSize
+
+// NextBelow retorna um numero uniforme em [0, max) a partir das saidas de
+// g. Usa amostragem por rejeicao: sorteia numeros com o tamanho em bits de
+// max - 1 e descarta os que passam de max, o que evita o vies de reduzir
+// mod max e custa, em media, menos de dois sorteios. Entra em panico se
+// max <= 0, como crypto/rand.Int.
+func NextBelow(g Generator, max *big.Int) *big.Int {
+	if max.Sign() <= 0 {
+		panic("prng: NextBelow com max <= 0")
+	}
+	bits := new(big.Int).Sub(max, big.NewInt(1)).BitLen()
+	for {
+		v := randomBits(g, bits)
+		if v.Cmp(max) < 0 {
+			return v
+		}
+	}
+}
+
+// NextInRange retorna um numero uniforme em [lo, hi) a partir das saidas
+// de g. Entra em panico se hi <= lo.
+func NextInRange(g Generator, lo, hi *big.Int) *big.Int {
+	if hi.Cmp(lo) <= 0 {
+		panic("prng: NextInRange com intervalo vazio")
+	}
+	v := NextBelow(g, new(big.Int).Sub(hi, lo))
+	return v.Add(v, lo)
+}
+
+// NextBelow retorna um numero uniforme em [0, max) (veja NextBelow)
+func (lfg *LaggedFibonacciGenerator) NextBelow(max *big.Int) *big.Int {
+	return NextBelow(lfg, max)
+}
+
+// NextInRange retorna um numero uniforme em [lo, hi) (veja NextInRange)
+func (lfg *LaggedFibonacciGenerator) NextInRange(lo, hi *big.Int) *big.Int {
+	return NextInRange(lfg, lo, hi)
+}
+
+// NextBelow retorna um numero uniforme em [0, max) (veja NextBelow)
+func (bbs *BlumBlumShub) NextBelow(max *big.Int) *big.Int {
+	return NextBelow(bbs, max)
+}
+
+// NextInRange retorna um numero uniforme em [lo, hi) (veja NextInRange)
+func (bbs *BlumBlumShub) NextInRange(lo, hi *big.Int) *big.Int {
+	return NextInRange(bbs, lo, hi)
+}
+
+// NextBelow retorna um numero uniforme em [0, max) (veja NextBelow)
+func (h *Hybrid) NextBelow(max *big.Int) *big.Int {
+	return NextBelow(h, max)
+}
+
+// NextInRange retorna um numero uniforme em [lo, hi) (veja NextInRange)
+func (h *Hybrid) NextInRange(lo, hi *big.Int) *big.Int {
+	return NextInRange(h, lo, hi)
+}
+
+// NextBelow retorna um numero uniforme em [0, max) (veja NextBelow)
+func (r *Reseeding) NextBelow(max *big.Int) *big.Int {
+	return NextBelow(r, max)
+}
+
+// NextInRange retorna um numero uniforme em [lo, hi) (veja NextInRange)
+func (r *Reseeding) NextInRange(lo, hi *big.Int) *big.Int {
+	return NextInRange(r, lo, hi)
+}
+
+// NextExactBits retorna um numero com exatamente bits bits, montado a
+// partir de uma ou mais chamadas a Next (veja ExactBits)
+func (lfg *LaggedFibonacciGenerator) NextExactBits(bits int) *big.Int {
+	return ExactBits(lfg, bits)
+}
+
+// Bits retorna o tamanho em bits dos numeros gerados
+func (lfg *LaggedFibonacciGenerator) Bits() int {
+	return lfg.bitSize
+}
+
+// Bits retorna o tamanho em bits dos numeros gerados
+func (bbs *BlumBlumShub) Bits() int {
+	return bbs.bitSize
+}
@@ -0,0 +1,133 @@
+package prng
+
+import (
+	"math/big"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestNextBelow(t *testing.T) {
+	lfg := NewLFG(10, 7, 10, 8)
+	counts := make([]int, 5)
+	for i := 0; i < 5000; i++ {
+		v := NextBelow(lfg, big.NewInt(5))
+		if v.Sign() < 0 || v.Cmp(big.NewInt(5)) >= 0 {
+			t.Fatalf("NextBelow(5) = %s", v)
+		}
+		counts[v.Int64()]++
+	}
+	// Com 5000 sorteios cada valor deve aparecer perto de 1000 vezes
+	for v, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Errorf("valor %d sorteado %d vezes em 5000", v, c)
+		}
+	}
+
+	// max = 1 so admite 0; max grande usa varias saidas do gerador
+	if v := NextBelow(lfg, big.NewInt(1)); v.Sign() != 0 {
+		t.Errorf("NextBelow(1) = %s", v)
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), 100)
+	max.Add(max, big.NewInt(1))
+	if v := NextBelow(lfg, max); v.Cmp(max) >= 0 {
+		t.Errorf("NextBelow(2^100 + 1) = %s", v)
+	}
+}
+
+func TestNextInRange(t *testing.T) {
+	bbs := NewBBS(64)
+	lo, hi := big.NewInt(1000), big.NewInt(1010)
+	seen := map[int64]bool{}
+	for i := 0; i < 500; i++ {
+		v := NextInRange(bbs, lo, hi)
+		if v.Cmp(lo) < 0 || v.Cmp(hi) >= 0 {
+			t.Fatalf("NextInRange(1000, 1010) = %s", v)
+		}
+		seen[v.Int64()] = true
+	}
+	if len(seen) != 10 {
+		t.Errorf("apenas %d dos 10 valores sorteados", len(seen))
+	}
+
+	// Os metodos dos geradores do pacote sao as mesmas funcoes
+	a := NewLFG(10, 7, 10, 32)
+	data, _ := a.MarshalBinary()
+	b := new(LaggedFibonacciGenerator)
+	if err := b.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if x, y := a.NextInRange(lo, hi), NextInRange(b, lo, hi); x.Cmp(y) != 0 {
+		t.Errorf("metodo NextInRange = %s, funcao = %s", x, y)
+	}
+	if x, y := a.NextBelow(hi), NextBelow(b, hi); x.Cmp(y) != 0 {
+		t.Errorf("metodo NextBelow = %s, funcao = %s", x, y)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("intervalo vazio aceito")
+		}
+	}()
+	NextInRange(bbs, hi, lo)
+}
+
+func TestOrShifted(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 500 {
+		src := make([]big.Word, rng.IntN(8))
+		for i := range src {
+			src[i] = big.Word(rng.Uint64())
+		}
+		s := uint(rng.IntN(bits.UintSize))
+		got := make([]big.Word, len(src)+1)
+		for i := range got {
+			got[i] = big.Word(rng.Uint64())
+		}
+		want := slices.Clone(got)
+		orShifted(got, src, s)
+		orShiftedGeneric(want, src, s)
+		if !slices.Equal(got, want) {
+			t.Fatalf("orShifted(%x, %d) = %x, esperado %x", src, s, got, want)
+		}
+	}
+}
+
+// concatGenerator devolve as saidas de outputs em sequencia
+type concatGenerator struct {
+	outputs []*big.Int
+	width   int
+}
+
+func (g *concatGenerator) Next() *big.Int {
+	out := g.outputs[0]
+	g.outputs = g.outputs[1:]
+	return out
+}
+
+func (g *concatGenerator) Bits() int { return g.width }
+
+func TestRandomBits(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	for range 200 {
+		width, size := 1+rng.IntN(150), 1+rng.IntN(600)
+		g := &concatGenerator{width: width}
+		// Resultado esperado pelo deslocamento seguido de Or
+		want := new(big.Int)
+		have := 0
+		for have < size {
+			out := new(big.Int)
+			for j := range width {
+				out.SetBit(out, j, uint(rng.IntN(2)))
+			}
+			g.outputs = append(g.outputs, out)
+			want.Lsh(want, uint(width)).Or(want, out)
+			have += width
+		}
+		want.Rsh(want, uint(have-size))
+		if got := randomBits(g, size); got.Cmp(want) != 0 {
+			t.Fatalf("randomBits com saidas de %d bits, %d bits: %x, esperado %x", width, size, got, want)
+		}
+	}
+}
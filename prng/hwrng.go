@@ -0,0 +1,130 @@
+// Esse arquivo implementa uma fonte de entropia baseada nas instrucoes
+//  RDRAND e RDSEED dos processadores x86.
+
+package prng
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoHardwareRNG indica que o processador nao oferece a instrucao pedida
+var ErrNoHardwareRNG = errors.New("prng: gerador de hardware indisponivel")
+
+// errHealthTest indica que a saida do hardware falhou nos testes de saude
+var errHealthTest = errors.New("prng: gerador de hardware falhou no teste de saude")
+
+// startupWords eh o numero de palavras avaliadas na criacao da fonte
+const startupWords = 16
+
+// HardwareSource eh uma EntropySource que le do RDRAND ou do RDSEED. Cada
+// palavra passa por um teste de repeticao e eh combinada por XOR com bytes
+// de crypto/rand, de modo que uma falha (ou backdoor) do hardware sozinha
+// nao compromete a saida. Eh segura para uso concorrente.
+type HardwareSource struct {
+	mu     sync.Mutex
+	read   func() (uint64, bool)
+	last   uint64
+	primed bool
+}
+
+// NewHardwareSource cria uma fonte que usa o RDSEED se useSeed for true, ou
+// o RDRAND caso contrario. Retorna ErrNoHardwareRNG se a instrucao nao
+// existir, ou um erro se o teste de inicializacao falhar.
+func NewHardwareSource(useSeed bool) (*HardwareSource, error) {
+	h := &HardwareSource{read: rdrand64}
+	if useSeed {
+		if !hasRDSEED {
+			return nil, fmt.Errorf("%w: RDSEED", ErrNoHardwareRNG)
+		}
+		h.read = rdseed64
+	} else if !hasRDRAND {
+		return nil, fmt.Errorf("%w: RDRAND", ErrNoHardwareRNG)
+	}
+
+	// Teste de inicializacao: as primeiras palavras nao podem ser todas
+	// iguais nem ser apenas zeros ou uns
+	words := make(map[uint64]bool, startupWords)
+	for i := 0; i < startupWords; i++ {
+		v, err := h.word()
+		if err != nil {
+			return nil, err
+		}
+		if v == 0 || v == ^uint64(0) {
+			return nil, errHealthTest
+		}
+		words[v] = true
+	}
+	if len(words) < startupWords/2 {
+		return nil, errHealthTest
+	}
+	return h, nil
+}
+
+// word le uma palavra do hardware aplicando o teste de repeticao: duas
+// palavras de 64 bits consecutivas iguais indicam uma fonte travada
+func (h *HardwareSource) word() (uint64, error) {
+	v, ok := h.read()
+	if !ok {
+		return 0, errors.New("prng: instrucao de hardware nao retornou dados")
+	}
+	if h.primed && v == h.last {
+		return 0, errHealthTest
+	}
+	h.last, h.primed = v, true
+	return v, nil
+}
+
+// Read preenche p com bytes do hardware combinados com crypto/rand
+func (h *HardwareSource) Read(p []byte) (int, error) {
+	mix := make([]byte, len(p))
+	if _, err := CryptoSource.Read(mix); err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var buf [8]byte
+	for i := 0; i < len(p); i += 8 {
+		v, err := h.word()
+		if err != nil {
+			return i, err
+		}
+		binary.LittleEndian.PutUint64(buf[:], v)
+		n := copy(p[i:], buf[:])
+		for j := 0; j < n; j++ {
+			p[i+j] ^= mix[i+j]
+		}
+	}
+	return len(p), nil
+}
+
+// ParseEntropySource retorna a fonte de entropia com o nome informado:
+// "crypto" (crypto/rand), "jitter", "rdrand" ou "rdseed"
+func ParseEntropySource(name string) (EntropySource, error) {
+	var useSeed bool
+	switch name {
+	case "crypto":
+		return CryptoSource, nil
+	case "jitter":
+		j, err := NewJitterSource()
+		if err != nil {
+			return nil, err
+		}
+		return j, nil
+	case "rdrand":
+	case "rdseed":
+		useSeed = true
+	default:
+		return nil, fmt.Errorf("prng: fonte de entropia desconhecida %q", name)
+	}
+
+	h, err := NewHardwareSource(useSeed)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,43 @@
+package prng
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHardwareSource(t *testing.T) {
+	for _, name := range []string{"rdrand", "rdseed"} {
+		t.Run(name, func(t *testing.T) {
+			src, err := ParseEntropySource(name)
+			if errors.Is(err, ErrNoHardwareRNG) {
+				t.Skip(err)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a, b := make([]byte, 37), make([]byte, 37)
+			if _, err := src.Read(a); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := src.Read(b); err != nil {
+				t.Fatal(err)
+			}
+			if string(a) == string(b) {
+				t.Error("leituras consecutivas identicas")
+			}
+
+			if _, err := NewBBSWithEntropy(64, Entropy{Source: src}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestHardwareSourceHealthTest(t *testing.T) {
+	h := &HardwareSource{read: func() (uint64, bool) { return 42, true }}
+	buf := make([]byte, 16)
+	if _, err := h.Read(buf); !errors.Is(err, errHealthTest) {
+		t.Fatalf("fonte travada deveria falhar no teste de repeticao, obtido %v", err)
+	}
+}
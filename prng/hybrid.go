@@ -0,0 +1,67 @@
+// Esse arquivo traz o gerador hibrido, que combina as saidas de varios
+//  geradores (por exemplo, o LFG rapido e o BBS seguro).
+
+package prng
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Hybrid combina as saidas de varios geradores. Com a combinacao por XOR,
+// a saida eh pelo menos tao imprevisivel quanto a do melhor gerador, desde
+// que os geradores sejam independentes; a soma mod 2^Bits() tem a mesma
+// propriedade e eh a combinacao usada em alguns geradores classicos.
+type Hybrid struct {
+	gens    []Generator
+	bits    int
+	add     bool
+	modulus *big.Int
+}
+
+// NewHybrid cria um gerador que combina as saidas de gens por XOR. O
+// tamanho das saidas eh o do maior gerador.
+func NewHybrid(gens ...Generator) (*Hybrid, error) {
+	if len(gens) == 0 {
+		return nil, errors.New("prng: gerador hibrido sem geradores")
+	}
+	h := &Hybrid{gens: gens}
+	for _, g := range gens {
+		h.bits = max(h.bits, g.Bits())
+	}
+	h.modulus = new(big.Int).Lsh(big.NewInt(1), uint(h.bits))
+	return h, nil
+}
+
+// NewHybridAdd cria um gerador como NewHybrid, mas que combina as saidas
+// somando-as mod 2^Bits()
+func NewHybridAdd(gens ...Generator) (*Hybrid, error) {
+	h, err := NewHybrid(gens...)
+	if err != nil {
+		return nil, err
+	}
+	h.add = true
+	return h, nil
+}
+
+// Next implementa Generator
+func (h *Hybrid) Next() *big.Int {
+	result := new(big.Int)
+	for _, g := range h.gens {
+		v := g.Next()
+		if h.add {
+			result.Add(result, v)
+		} else {
+			result.Xor(result, v)
+		}
+	}
+	if h.add {
+		result.Mod(result, h.modulus)
+	}
+	return result
+}
+
+// Bits implementa Generator
+func (h *Hybrid) Bits() int {
+	return h.bits
+}
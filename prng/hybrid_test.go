@@ -0,0 +1,33 @@
+package prng
+
+import (
+	"math/big"
+	"testing"
+)
+
+// constant eh um gerador que sempre retorna o mesmo valor
+type constant struct {
+	v    int64
+	bits int
+}
+
+func (c constant) Next() *big.Int { return big.NewInt(c.v) }
+func (c constant) Bits() int      { return c.bits }
+
+func TestHybrid(t *testing.T) {
+	x, _ := NewHybrid(constant{0b1100, 4}, constant{0b1010, 4}, constant{0b1, 2})
+	if v := x.Next().Int64(); v != 0b0111 || x.Bits() != 4 {
+		t.Errorf("XOR = %b (%d bits), esperado 111 (4 bits)", v, x.Bits())
+	}
+	add, _ := NewHybridAdd(constant{12, 4}, constant{10, 4})
+	if v := add.Next().Int64(); v != 6 {
+		t.Errorf("soma = %d, esperado (12 + 10) mod 16 = 6", v)
+	}
+	if _, err := NewHybrid(); err == nil {
+		t.Error("gerador hibrido vazio aceito")
+	}
+
+	// Um dos geradores pode ser o LFG e o outro o BBS
+	var _ Generator = NewLFG(10, 7, 10, 64)
+	var _ Generator = NewBBS(64)
+}
@@ -0,0 +1,35 @@
+// Esse arquivo expoe a fonte de entropia baseada na variacao (jitter) do
+//  tempo de execucao da CPU, implementada em internal/jitter.
+
+package prng
+
+import (
+	"PrimeNumGenerator/internal/jitter"
+	"sync"
+)
+
+// JitterSource eh uma EntropySource que mede o ruido no tempo de execucao
+// de um laco com acessos a memoria, no estilo do haveged/jitterentropy. As
+// diferencas de tempo medidas sao condensadas com SHA-256. Eh segura para
+// uso concorrente.
+type JitterSource = jitter.Source
+
+// NewJitterSource cria uma fonte jitter e verifica se o relogio tem
+// resolucao suficiente para ela
+func NewJitterSource() (*JitterSource, error) {
+	return jitter.New()
+}
+
+var (
+	jitterOnce sync.Once
+	jitterSrc  *JitterSource
+	jitterErr  error
+)
+
+// sharedJitter retorna a fonte jitter compartilhada, criada sob demanda
+func sharedJitter() (*JitterSource, error) {
+	jitterOnce.Do(func() {
+		jitterSrc, jitterErr = NewJitterSource()
+	})
+	return jitterSrc, jitterErr
+}
@@ -0,0 +1,41 @@
+// Esse arquivo traz o laco interno da montagem dos candidatos a partir das
+//  saidas dos geradores: acrescentar os bits de uma saida, deslocados, a um
+//  vetor de palavras. Em amd64 e arm64 ele tem versoes em assembly
+//  (kernels_amd64.s e kernels_arm64.s); a tag purego forca a versao em Go.
+
+package prng
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// orShifted faz dst |= src << s, palavra a palavra, com 0 <= s <
+// bits.UintSize. dst precisa de uma palavra alem de len(src) quando s > 0.
+// Entra em panico se dst for pequeno demais, pois a versao em assembly nao
+// verifica os limites.
+func orShifted(dst, src []big.Word, s uint) {
+	need := len(src)
+	if s > 0 {
+		need++
+	}
+	if s >= bits.UintSize || len(dst) < need {
+		panic("prng: orShifted fora dos limites")
+	}
+	if len(src) > 0 {
+		orShiftedKernel(dst, src, s)
+	}
+}
+
+func orShiftedGeneric(dst, src []big.Word, s uint) {
+	// Em Go, deslocar por bits.UintSize ou mais da zero, entao s = 0 nao
+	// precisa de caso especial
+	var prev big.Word
+	for i, w := range src {
+		dst[i] |= w<<s | prev>>(bits.UintSize-s)
+		prev = w
+	}
+	if s > 0 {
+		dst[len(src)] |= prev >> (bits.UintSize - s)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func orShiftedKernel(dst, src []big.Word, s uint)
+TEXT ·orShiftedKernel(SB), NOSPLIT, $0-56
+	MOVQ dst_base+0(FP), DI
+	MOVQ src_base+24(FP), SI
+	MOVQ src_len+32(FP), BX
+	MOVQ s+48(FP), CX
+	XORQ R8, R8 // palavra anterior de src
+	XORQ DX, DX
+	// Duas palavras por volta enquanto houver ao menos duas
+	SUBQ $2, BX
+	JL tail
+loop2:
+	MOVQ (SI)(DX*8), R9
+	MOVQ 8(SI)(DX*8), R11
+	MOVQ R9, R10
+	MOVQ R11, R12
+	// SHLD: R10 = src[i]<<s | src[i-1]>>(64-s); com s = 0, R10 = src[i]
+	SHLQ CX, R8, R10
+	SHLQ CX, R9, R12
+	ORQ (DI)(DX*8), R10
+	ORQ 8(DI)(DX*8), R12
+	MOVQ R10, (DI)(DX*8)
+	MOVQ R12, 8(DI)(DX*8)
+	MOVQ R11, R8
+	ADDQ $2, DX
+	CMPQ DX, BX
+	JLE loop2
+tail:
+	ADDQ $2, BX
+	CMPQ DX, BX
+	JAE last
+	MOVQ (SI)(DX*8), R9
+	MOVQ R9, R10
+	SHLQ CX, R8, R10
+	ORQ R10, (DI)(DX*8)
+	MOVQ R9, R8
+	INCQ DX
+last:
+	// A ultima palavra recebe os bits que transbordaram, se s > 0
+	TESTQ CX, CX
+	JZ done
+	XORQ R10, R10
+	SHLQ CX, R8, R10
+	ORQ R10, (DI)(DX*8)
+done:
+	RET
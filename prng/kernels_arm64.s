@@ -0,0 +1,45 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func orShiftedKernel(dst, src []big.Word, s uint)
+TEXT ·orShiftedKernel(SB), NOSPLIT, $0-56
+	MOVD dst_base+0(FP), R0
+	MOVD src_base+24(FP), R1
+	MOVD src_len+32(FP), R2
+	MOVD s+48(FP), R3
+	// O deslocamento por registrador usa o valor mod 64, entao 64 - s nao
+	// serve para s = 0, que fica com um laco proprio
+	CBZ R3, plain
+	MOVD $64, R4
+	SUB R3, R4, R4
+	MOVD ZR, R5 // palavra anterior de src
+loop:
+	CBZ R2, last
+	MOVD.P 8(R1), R6
+	LSL R3, R6, R7
+	LSR R4, R5, R8
+	ORR R8, R7, R7
+	MOVD (R0), R9
+	ORR R9, R7, R7
+	MOVD.P R7, 8(R0)
+	MOVD R6, R5
+	SUB $1, R2, R2
+	B loop
+last:
+	// A ultima palavra recebe os bits que transbordaram
+	LSR R4, R5, R8
+	MOVD (R0), R9
+	ORR R9, R8, R8
+	MOVD R8, (R0)
+	RET
+plain:
+	CBZ R2, done
+	MOVD.P 8(R1), R6
+	MOVD (R0), R9
+	ORR R9, R6, R6
+	MOVD.P R6, 8(R0)
+	SUB $1, R2, R2
+	B plain
+done:
+	RET
@@ -0,0 +1,11 @@
+//go:build (amd64 || arm64) && !purego
+
+package prng
+
+import "math/big"
+
+// Implementada em kernels_amd64.s e kernels_arm64.s, com o mesmo contrato
+// de orShiftedGeneric
+
+//go:noescape
+func orShiftedKernel(dst, src []big.Word, s uint)
@@ -0,0 +1,9 @@
+//go:build !(amd64 || arm64) || purego
+
+package prng
+
+import "math/big"
+
+func orShiftedKernel(dst, src []big.Word, s uint) {
+	orShiftedGeneric(dst, src, s)
+}
@@ -4,18 +4,27 @@
 package prng
 
 import (
-	"crypto/rand"
+	"PrimeNumGenerator/clock"
 	"fmt"
 	"math/big"
-	"time"
 )
 
 // LaggedFibonacciGenerator implementa o algoritmo de mesmo nome
 //
 //	para gerar os numeros pseudoaleatorios grandes.
+//
+// A sequencia segue a recorrencia aditiva classica (Knuth, TAOCP vol. 2,
+// 3.2.2): X_n = (X_(n-j) + X_(n-k)) mod 2^bitSize, com 0 < j < k. O estado
+// guarda os size >= k valores mais recentes, X_(n-size) ... X_(n-1); apenas
+// os k ultimos participam da recorrencia.
+//
+// O estado eh um buffer circular: pos eh o indice do valor mais antigo, de
+// modo que o i-esimo valor mais antigo esta em state[(pos+i)%size] e Next
+// nao precisa deslocar o buffer.
 type LaggedFibonacciGenerator struct {
 	j, k     int
 	state    []*big.Int
+	pos      int
 	size     int
 	modValue *big.Int
 	bitSize  int
@@ -23,13 +32,37 @@ type LaggedFibonacciGenerator struct {
 
 // A funcao NewLFG cria um novo gerador com os parametros especificados
 // size --> 	define o tamanho do buffer de estado
-// j, k --> 	definem os indices usados na soma
+// j, k --> 	definem os atrasos usados na soma (0 < j < k)
 // bitSize --> 	define o tamanho em bits dos numeros gerados
 // returns --> 	retorna um ponteiro para o gerador
+//
+// O estado inicial eh gerado no nivel Strict; NewLFG entra em panico se os
+// parametros forem invalidos ou se a fonte de entropia falhar. Use
+// NewLFGWithSecurity para tratar o erro.
 func NewLFG(size, j, k int, bitSize int) *LaggedFibonacciGenerator {
-	// Garantimos que j < k
-	if j >= k {
-		panic("j deve ser menor que k")
+	lfg, err := NewLFGWithSecurity(size, j, k, bitSize, Strict)
+	if err != nil {
+		panic(err)
+	}
+	return lfg
+}
+
+// NewLFGWithSecurity cria um novo gerador como NewLFG, usando o nivel de
+// seguranca level caso a fonte de entropia falhe.
+func NewLFGWithSecurity(size, j, k int, bitSize int, level SecurityLevel) (*LaggedFibonacciGenerator, error) {
+	return NewLFGWithEntropy(size, j, k, bitSize, Entropy{Level: level})
+}
+
+// NewLFGWithEntropy cria um novo gerador como NewLFG, sorteando o estado
+// inicial a partir da entropia e. Retorna erro se nao valer 0 < j < k ou
+// se bitSize nao for positivo.
+func NewLFGWithEntropy(size, j, k int, bitSize int, e Entropy) (*LaggedFibonacciGenerator, error) {
+	// Garantimos que 0 < j < k; com j = 0 o valor seria somado a si mesmo
+	if j < 1 || j >= k {
+		return nil, fmt.Errorf("prng: j e k devem satisfazer 0 < j < k (j=%d, k=%d)", j, k)
+	}
+	if bitSize < 1 {
+		return nil, fmt.Errorf("prng: tamanho em bits invalido: %d", bitSize)
 	}
 
 	if size < k {
@@ -50,11 +83,10 @@ func NewLFG(size, j, k int, bitSize int) *LaggedFibonacciGenerator {
 
 	// Inicializamos o estado com valores aleatorios verdadeiros do tamanho apropriado
 	for i := 0; i < size; i++ {
-		// Criamos um numero aleatorio criptograficamente seguro com o tamanho de bits desejado
-		randBits, err := rand.Int(rand.Reader, new(big.Int).Sub(lfg.modValue, big.NewInt(1)))
+		// Criamos um numero aleatorio com o tamanho de bits desejado
+		randBits, err := e.Int(new(big.Int).Sub(lfg.modValue, big.NewInt(1)))
 		if err != nil {
-			// Fallback para um metodo menos seguro se rand.Int falhar
-			randBits = generateFallbackRandom(bitSize)
+			return nil, err
 		}
 
 		// Aqui garantimos que o numero tem um tamanho proximo ao desejado
@@ -62,44 +94,46 @@ func NewLFG(size, j, k int, bitSize int) *LaggedFibonacciGenerator {
 		if bitSize > 1 {
 			randBits.SetBit(randBits, bitSize-1, 1)
 			// Configuramos tambem alguns bits aleatorios para garantir certa variacao
-			randBits.SetBit(randBits, bitSize/2, uint(time.Now().UnixNano()%2))
-			randBits.SetBit(randBits, bitSize/3, uint((time.Now().UnixNano()+int64(i))%2))
+			var variation [1]byte
+			if _, err := e.Read(variation[:]); err != nil {
+				return nil, err
+			}
+			randBits.SetBit(randBits, bitSize/2, uint(variation[0]&1))
+			randBits.SetBit(randBits, bitSize/3, uint(variation[0]>>1&1))
 		}
 
 		lfg.state[i] = randBits
 	}
 
-	return lfg
+	return lfg, nil
 }
 
-// A funcao generateFallbackRandom gera um numero aleatorio grande usando um
-// metodo menos seguro mas mais garantido de funcionar em todos os ambientes
-// caso o rand.Int usado em NewLFG falhe.
-func generateFallbackRandom(bitSize int) *big.Int {
-	result := new(big.Int)
-
-	// Calculamos quantos blocos de 31 bits precisamos
-	// (usamos 31 em vez de 32 para evitar problemas com sinal em int64 no Go)
-	blocks := (bitSize + 30) / 31
-
-	// Geracao de cada bloco
-	for i := 0; i < blocks; i++ {
-		// Geracao de um numero aleatorio de 31 bits usando o timestamp
-		seed := time.Now().UnixNano() + int64(i*9999)
-		blockValue := new(big.Int).Mod(big.NewInt(seed), big.NewInt(1<<31))
-
-		// Deslocamos o bloco para sua posição correta
-		if i > 0 {
-			blockValue.Lsh(blockValue, uint(i*31))
+// NewLFGFromState cria um gerador a partir de um estado conhecido, dado do
+// valor mais antigo para o mais recente: seed[i] = X_i. O primeiro valor
+// produzido por Next eh X_len(seed). Serve para reproduzir sequencias de
+// referencia; o tamanho do estado eh len(seed), que deve ser pelo menos k.
+func NewLFGFromState(j, k, bitSize int, seed []*big.Int) (*LaggedFibonacciGenerator, error) {
+	if j < 1 || j >= k || len(seed) < k {
+		return nil, fmt.Errorf("prng: parametros do LFG invalidos (j=%d, k=%d, size=%d)", j, k, len(seed))
+	}
+	if bitSize < 1 {
+		return nil, fmt.Errorf("prng: tamanho em bits invalido: %d", bitSize)
+	}
+	lfg := &LaggedFibonacciGenerator{
+		j:        j,
+		k:        k,
+		state:    make([]*big.Int, len(seed)),
+		size:     len(seed),
+		modValue: new(big.Int).Lsh(big.NewInt(1), uint(bitSize)),
+		bitSize:  bitSize,
+	}
+	for i, v := range seed {
+		if v.Sign() < 0 || v.Cmp(lfg.modValue) >= 0 {
+			return nil, fmt.Errorf("prng: valor %d do estado fora do intervalo", i)
 		}
-
-		// Adicionamos ao resultado
-		result.Or(result, blockValue)
-
-		time.Sleep(time.Nanosecond)
+		lfg.state[i] = new(big.Int).Set(v)
 	}
-
-	return result
+	return lfg, nil
 }
 
 // Next gera e retorna o proximo numero na sequencia pseudoaleatoria
@@ -107,29 +141,77 @@ func generateFallbackRandom(bitSize int) *big.Int {
 //	e atualiza o estado do gerador. O resultado eh um ponteiro
 //	para um big.Int que representa o numero gerado.
 //
-// O numero gerado eh o resultado da soma dos dois numeros anteriores
+// O numero gerado eh o resultado da soma dos numeros gerados j e k
 //
-//	na sequencia, com os indices j e k definidos no construtor.
+//	posicoes antes na sequencia, com j e k definidos no construtor.
 func (lfg *LaggedFibonacciGenerator) Next() *big.Int {
-	// Calculamos o proximo valor como state[i-j] + state[i-k] mod 2^bitSize
-	result := new(big.Int)
+	return new(big.Int).Set(lfg.step())
+}
 
-	// state[i-j] + state[i-k]
-	result.Add(lfg.state[lfg.size-lfg.j], lfg.state[lfg.size-lfg.k])
-	result.Mod(result, lfg.modValue)
+// Discard avanca o gerador n valores, sem produzi-los. Serve para aquecer o
+// gerador (veja DefaultWarmup) ou para pular parte da sequencia.
+func (lfg *LaggedFibonacciGenerator) Discard(n uint64) {
+	for ; n > 0; n-- {
+		lfg.step()
+	}
+}
 
-	// Deslocamos todos os valores no array
-	for i := 0; i < lfg.size-1; i++ {
-		lfg.state[i] = lfg.state[i+1]
+// DefaultWarmup retorna quantos valores descartar de um gerador recem-criado
+// com atraso k. A cada k valores gerados o buffer eh renovado por inteiro,
+// entao o aquecimento proporcional a k garante que cada valor inicial
+// influencie todo o estado antes do primeiro uso, qualquer que seja o atraso.
+func DefaultWarmup(k int) uint64 {
+	return warmupRounds * uint64(k)
+}
+
+// warmupRounds eh o numero de voltas completas pelo buffer de estado no
+// aquecimento padrao
+const warmupRounds = 10
+
+// step calcula o proximo valor e o retorna; o valor pertence ao estado e
+// nao pode ser alterado
+func (lfg *LaggedFibonacciGenerator) step() *big.Int {
+	// Calculamos o proximo valor como X_(n-j) + X_(n-k) mod 2^bitSize.
+	// O valor mais antigo sai do estado nesta chamada, entao reaproveitamos
+	// sua posicao e sua memoria para o resultado (a soma aceita o mesmo
+	// valor como destino e operando)
+	result := lfg.state[lfg.pos]
+
+	// X_(n-j) + X_(n-k)
+	result.Add(lfg.lag(lfg.j), lfg.lag(lfg.k))
+	// A soma de dois valores menores que 2^bitSize eh menor que
+	// 2^(bitSize+1), entao reduzir mod 2^bitSize eh so apagar o bit bitSize
+	if result.Cmp(lfg.modValue) >= 0 {
+		result.SetBit(result, lfg.bitSize, 0)
+	}
+
+	// O novo valor passa a ser o mais recente: basta avancar o inicio do
+	// buffer, sem deslocar os demais
+	lfg.pos++
+	if lfg.pos == lfg.size {
+		lfg.pos = 0
 	}
 
-	// Adicionamos o novo valor ao final
-	lfg.state[lfg.size-1] = result
+	return result
+}
+
+// lag retorna X_(n-d), o d-esimo valor mais recente do estado (1 <= d <= size)
+func (lfg *LaggedFibonacciGenerator) lag(d int) *big.Int {
+	return lfg.at(lfg.size - d)
+}
 
-	return new(big.Int).Set(result)
+// at retorna o i-esimo valor mais antigo do estado
+func (lfg *LaggedFibonacciGenerator) at(i int) *big.Int {
+	i += lfg.pos
+	if i >= lfg.size {
+		i -= lfg.size
+	}
+	return lfg.state[i]
 }
 
-func Lfg() ([]int, []*big.Int) {
+// Lfg gera um numero pseudoaleatorio para cada tamanho de bits do
+// enunciado, de acordo com a configuracao cfg.
+func Lfg(cfg DemoConfig) ([]int, []*big.Int, error) {
 	// Tamanhos de bits para testar especificados no enunciado do trabalho
 	bitSizes := []int{40, 56, 80, 128, 168, 224, 256, 512, 1024, 2048, 4096}
 	generatedNumbers := make([]*big.Int, len(bitSizes))
@@ -140,45 +222,44 @@ func Lfg() ([]int, []*big.Int) {
 	// Usamos j=7, k=10 como exemplo de parametros comuns para LFG
 	// usando como ref. o segundo volume da serie de livros
 	// The Art of Computer Programming
-	j := 7
-	k := 10
+	j := demoLagJ
+	k := demoLagK
 
 	for i, bits := range bitSizes {
 		fmt.Printf("\nGerando número de %d bits:\n", bits)
 
 		// Criamos um novo gerador para cada tamanho de bits
-		lfg := NewLFG(k, j, k, bits)
+		lfg, err := NewLFGWithEntropy(k, j, k, bits, cfg.Entropy)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		// "Aquecemos" o gerador descartando alguns valores iniciais
-		for i := 0; i < 20; i++ {
-			lfg.Next()
-		}
+		lfg.Discard(cfg.warmup(k))
 
-		startTime := time.Now()
+		state := snapshot(lfg, cfg)
+		startTime := clock.Now()
 
 		// Geramos o numero
-		randomNum := lfg.Next()
+		// O candidato tem exatamente bits bits, mesmo que a saida do
+		// gerador tenha zeros a esquerda
+		randomNum := ExactBits(Whiten(lfg, cfg.Whitening), bits)
 
-		elapsedTime := time.Since(startTime)
+		elapsedTime := clock.Since(startTime)
 		fmt.Printf("- Tempo de geração: %s\n", elapsedTime)
 
 		// Exibimos o tamanho real em bits do numero gerado
 		bitLength := randomNum.BitLen()
 		fmt.Printf("- Tamanho real: %d bits\n", bitLength)
 
-		// Exibimos a representacao decimal
-		fmt.Printf("- Valor decimal: %s\n", randomNum.String())
-
-		// Exibimos a representacao binaria
-		fmt.Printf("- Representação binária: %s\n", randomNum.Text(2))
-
-		// Por fim, verificamos se o numero tem o tamanho esperado ou proximo disso (dentro de 4 bits)
-		if bitLength < bits-4 {
-			fmt.Printf("AVISO: O número gerado tem menos bits que o solicitado (%d < %d)\n", bitLength, bits)
+		printCandidate(randomNum, cfg)
+		notifyCandidate(bits, randomNum, state, cfg)
+		if cfg.Sensitive {
+			lfg.Wipe()
 		}
 
 		generatedNumbers[i] = randomNum
 	}
 
-	return bitSizes, generatedNumbers
+	return bitSizes, generatedNumbers, nil
 }
@@ -0,0 +1,158 @@
+package prng
+
+import (
+	"math/big"
+	"testing"
+)
+
+// shiftNext eh a implementacao anterior de Next, que desloca o estado
+// inteiro a cada chamada, usada como referencia para o buffer circular
+func shiftNext(state []*big.Int, j, k int, mod *big.Int) *big.Int {
+	size := len(state)
+	result := new(big.Int).Add(state[size-j], state[size-k])
+	result.Mod(result, mod)
+	copy(state, state[1:])
+	state[size-1] = result
+	return result
+}
+
+func TestLFGRingBufferMatchesShift(t *testing.T) {
+	for _, p := range []struct{ size, j, k int }{{10, 7, 10}, {12, 3, 7}, {55, 24, 55}} {
+		lfg := NewLFG(p.size, p.j, p.k, 64)
+		reference := make([]*big.Int, lfg.size)
+		for i := range reference {
+			reference[i] = new(big.Int).Set(lfg.at(i))
+		}
+		for i := 0; i < 3*lfg.size; i++ {
+			want := shiftNext(reference, p.j, p.k, lfg.modValue)
+			if got := lfg.Next(); got.Cmp(want) != 0 {
+				t.Fatalf("size=%d j=%d k=%d, saida %d: %s, esperado %s", p.size, p.j, p.k, i, got, want)
+			}
+		}
+	}
+}
+
+func TestLFGDiscard(t *testing.T) {
+	lfg := NewLFG(10, 7, 10, 64)
+	data, _ := lfg.MarshalBinary()
+	clone := new(LaggedFibonacciGenerator)
+	clone.UnmarshalBinary(data)
+
+	lfg.Discard(25)
+	for i := 0; i < 25; i++ {
+		clone.Next()
+	}
+	if a, b := lfg.Next(), clone.Next(); a.Cmp(b) != 0 {
+		t.Fatal("Discard(25) difere de 25 chamadas a Next")
+	}
+
+	cases := []struct {
+		warmup int
+		want   uint64
+	}{{0, 100}, {-1, 0}, {7, 7}}
+	for _, c := range cases {
+		if got := (DemoConfig{Warmup: c.warmup}).warmup(10); got != c.want {
+			t.Errorf("Warmup %d: %d valores descartados, esperado %d", c.warmup, got, c.want)
+		}
+	}
+}
+
+// ints converte os valores em big.Int
+func ints(values ...int64) []*big.Int {
+	out := make([]*big.Int, len(values))
+	for i, v := range values {
+		out[i] = big.NewInt(v)
+	}
+	return out
+}
+
+func TestLFGReferenceSequences(t *testing.T) {
+	cases := []struct {
+		name       string
+		j, k, bits int
+		seed       []*big.Int
+		want       []int64
+	}{
+		// Com j = 1 e k = 2 a recorrencia eh a propria sequencia de
+		// Fibonacci: 0, 1, 1, 2, 3, 5, ... mod 2^8
+		{"fibonacci", 1, 2, 8, ints(0, 1), []int64{1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233, 121, 98, 219, 61}},
+		// Calculada a mao: X_7 = X_4 + X_0 = 5 + 1, X_8 = X_5 + X_1 = 6 + 2, ...
+		{"j=3 k=7 mod 16", 3, 7, 4, ints(1, 2, 3, 4, 5, 6, 7), []int64{6, 8, 10, 10, 13, 0, 1}},
+		// Com estado maior que k, os valores mais antigos nao participam
+		{"j=3 k=7 mod 16, size=9", 3, 7, 4, ints(15, 15, 1, 2, 3, 4, 5, 6, 7), []int64{6, 8, 10, 10, 13, 0, 1}},
+	}
+	for _, c := range cases {
+		lfg, err := NewLFGFromState(c.j, c.k, c.bits, c.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range c.want {
+			if got := lfg.Next(); got.Int64() != want {
+				t.Fatalf("%s: X_%d = %s, esperado %d", c.name, len(c.seed)+i, got, want)
+			}
+		}
+	}
+}
+
+// TestLFGMitchellMoore compara os atrasos (24, 55) de Mitchell e Moore,
+// citados no TAOCP, com a recorrencia X_n = X_(n-24) + X_(n-55) mod 2^32
+// aplicada diretamente a uma lista
+func TestLFGMitchellMoore(t *testing.T) {
+	for _, size := range []int{55, 60} {
+		x := make([]*big.Int, size)
+		for i := range x {
+			x[i] = big.NewInt(int64(i*i + 1))
+		}
+		lfg, err := NewLFGFromState(24, 55, 32, x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mod := new(big.Int).Lsh(big.NewInt(1), 32)
+		for n := size; n < size+500; n++ {
+			want := new(big.Int).Add(x[n-24], x[n-55])
+			want.Mod(want, mod)
+			x = append(x, want)
+			if got := lfg.Next(); got.Cmp(want) != 0 {
+				t.Fatalf("size=%d: X_%d = %s, esperado %s", size, n, got, want)
+			}
+		}
+	}
+}
+
+func TestLFGInvalidLags(t *testing.T) {
+	if _, err := NewLFGFromState(0, 2, 8, ints(1, 2)); err == nil {
+		t.Error("j = 0 aceito")
+	}
+	if _, err := NewLFGFromState(1, 3, 8, ints(1, 2)); err == nil {
+		t.Error("estado menor que k aceito")
+	}
+	for _, c := range [][3]int{{0, 10, 64}, {10, 10, 64}, {12, 10, 64}, {7, 10, 0}, {7, 10, -8}} {
+		if _, err := NewLFGWithEntropy(10, c[0], c[1], c[2], Entropy{}); err == nil {
+			t.Errorf("NewLFGWithEntropy(j=%d, k=%d, bits=%d) aceito", c[0], c[1], c[2])
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLFG com j = 0 nao entrou em panico")
+		}
+	}()
+	NewLFG(10, 0, 10, 64)
+}
+
+func TestLFGNextExactBits(t *testing.T) {
+	lfg := NewLFG(10, 7, 10, 64)
+	for _, bits := range []int{1, 40, 64, 65, 150} {
+		for i := 0; i < 20; i++ {
+			if got := lfg.NextExactBits(bits).BitLen(); got != bits {
+				t.Fatalf("NextExactBits(%d) com %d bits", bits, got)
+			}
+		}
+	}
+
+	// Saidas 1, 2, 3 de 8 bits: 0x010203, truncado a 20 bits e com o bit
+	// 19 ligado
+	fib, _ := NewLFGFromState(1, 2, 8, ints(0, 1))
+	if got := fib.NextExactBits(20).Int64(); got != 0x81020 {
+		t.Errorf("NextExactBits(20) = %#x, esperado 0x81020", got)
+	}
+}
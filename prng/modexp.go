@@ -0,0 +1,143 @@
+// Esse arquivo define a interface de exponenciacao modular usada pelo BBS
+//  e pelos testes de primalidade, permitindo trocar a implementacao.
+
+package prng
+
+import (
+	"PrimeNumGenerator/internal/modexp"
+	"math/big"
+)
+
+// ModExpBackend calcula exponenciacoes modulares. A implementacao padrao,
+// FixedBackend, usa palavras de largura fixa nos modulos pequenos e o
+// math/big nos demais; um backend externo (CUDA, OpenCL) pode implementar a
+// interface para assumir esse trabalho. Os resultados sao valores novos:
+// os argumentos nao sao alterados.
+type ModExpBackend interface {
+	// Exp retorna base^exp mod mod
+	Exp(base, exp, mod *big.Int) *big.Int
+	// ExpBatch retorna bases[i]^exps[i] mod mod para cada i. Todas as
+	// exponenciacoes compartilham o modulo, o formato adequado para enviar
+	// um lote inteiro a um acelerador de uma so vez.
+	ExpBatch(bases, exps []*big.Int, mod *big.Int) []*big.Int
+}
+
+// BigBackend eh o ModExpBackend baseado apenas no (*big.Int).Exp
+type BigBackend struct{}
+
+// Exp implementa ModExpBackend
+func (BigBackend) Exp(base, exp, mod *big.Int) *big.Int {
+	return new(big.Int).Exp(base, exp, mod)
+}
+
+// ExpBatch implementa ModExpBackend, calculando as exponenciacoes em ordem
+func (BigBackend) ExpBatch(bases, exps []*big.Int, mod *big.Int) []*big.Int {
+	out := make([]*big.Int, len(bases))
+	for i := range bases {
+		out[i] = new(big.Int).Exp(bases[i], exps[i], mod)
+	}
+	return out
+}
+
+// FixedBackend eh o ModExpBackend padrao (veja Backend). Com modulo impar
+// de ate modexp.MaxFixedBits bits, como nos primos de 40 a 256 bits, ele
+// calcula as exponenciacoes com a aritmetica de largura fixa do pacote
+// modexp; os demais casos (modulos maiores ou pares e expoentes negativos)
+// ficam com o math/big. Cada chamada prepara o modulo de novo: para varias
+// exponenciacoes com o mesmo modulo, use Prepare.
+type FixedBackend struct{}
+
+// Exp implementa ModExpBackend
+func (FixedBackend) Exp(base, exp, mod *big.Int) *big.Int {
+	m, err := modexp.New(mod)
+	if err != nil {
+		return BigBackend{}.Exp(base, exp, mod)
+	}
+	return m.Exp(base, exp)
+}
+
+// ExpBatch implementa ModExpBackend, preparando o modulo uma unica vez para
+// todo o lote
+func (FixedBackend) ExpBatch(bases, exps []*big.Int, mod *big.Int) []*big.Int {
+	m, err := modexp.New(mod)
+	if err != nil {
+		return BigBackend{}.ExpBatch(bases, exps, mod)
+	}
+	return PreparedBackend{m: m}.ExpBatch(bases, exps, mod)
+}
+
+// PreparedBackend eh o FixedBackend com um modulo ja preparado, criado por
+// Prepare. As exponenciacoes com outros modulos ficam com o FixedBackend.
+// O valor zero nao tem modulo preparado e nao deve ser usado.
+type PreparedBackend struct {
+	m *modexp.Modulus
+}
+
+// Prepare retorna um backend para varias exponenciacoes com o modulo mod,
+// como as iteracoes de um teste de primalidade com o mesmo candidato: com o
+// backend padrao, o modulo eh preparado uma unica vez (veja
+// PreparedBackend); os demais backends, e os modulos que o pacote modexp
+// nao atende, sao retornados sem mudanca.
+func Prepare(b ModExpBackend, mod *big.Int) ModExpBackend {
+	b = Backend(b)
+	if _, ok := b.(FixedBackend); !ok {
+		return b
+	}
+	m, err := modexp.New(mod)
+	if err != nil {
+		return b
+	}
+	return PreparedBackend{m: m}
+}
+
+// Exp implementa ModExpBackend
+func (p PreparedBackend) Exp(base, exp, mod *big.Int) *big.Int {
+	if !p.same(mod) {
+		return FixedBackend{}.Exp(base, exp, mod)
+	}
+	return p.m.Exp(base, exp)
+}
+
+// ExpBatch implementa ModExpBackend. Quando todas as bases usam o mesmo
+// expoente, como no Miller-Rabin em tempo constante, a decomposicao do
+// expoente tambem eh compartilhada (veja modexp.m.ExpBatch).
+func (p PreparedBackend) ExpBatch(bases, exps []*big.Int, mod *big.Int) []*big.Int {
+	if !p.same(mod) {
+		return FixedBackend{}.ExpBatch(bases, exps, mod)
+	}
+	shared := true
+	for _, e := range exps {
+		shared = shared && e.Cmp(exps[0]) == 0
+	}
+	if len(exps) > 0 && shared {
+		return p.m.ExpBatch(bases, exps[0])
+	}
+	out := make([]*big.Int, len(bases))
+	for i := range bases {
+		out[i] = p.m.Exp(bases[i], exps[i])
+	}
+	return out
+}
+
+// Square calcula x = x^2 mod mod no lugar, sem alocar quando mod eh o
+// modulo preparado, como nos quadrados sucessivos do Miller-Rabin
+func (p PreparedBackend) Square(x, mod *big.Int) {
+	if !p.same(mod) {
+		x.Mul(x, x).Mod(x, mod)
+		return
+	}
+	p.m.Square(x, x)
+}
+
+// same informa se mod eh o modulo preparado
+func (p PreparedBackend) same(mod *big.Int) bool {
+	return p.m.Cmp(mod) == 0
+}
+
+// Backend retorna b, ou o backend padrao, FixedBackend, se b for nil
+func Backend(b ModExpBackend) ModExpBackend {
+	if b == nil {
+		return FixedBackend{}
+	}
+	return b
+}
@@ -0,0 +1,139 @@
+package prng
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// squareBackend conta as exponenciacoes feitas pelo BBS
+type squareBackend struct {
+	BigBackend
+	calls int
+}
+
+func (b *squareBackend) Exp(base, exp, mod *big.Int) *big.Int {
+	b.calls++
+	return b.BigBackend.Exp(base, exp, mod)
+}
+
+func TestBBSBackend(t *testing.T) {
+	bbs := NewBBS(64)
+	data, _ := bbs.MarshalBinary()
+	clone := new(BlumBlumShub)
+	clone.UnmarshalBinary(data)
+
+	b := &squareBackend{}
+	bbs.SetBackend(b)
+	if a, c := bbs.Next(), clone.Next(); a.Cmp(c) != 0 {
+		t.Fatal("backend alterou a sequencia do BBS")
+	}
+	if b.calls != 64 {
+		t.Errorf("%d exponenciacoes para 64 bits", b.calls)
+	}
+	// Advance tambem passa pelo backend
+	if a, c := bbs.Advance(10), clone.Advance(10); a.Cmp(c) != 0 {
+		t.Fatal("backend alterou o estado de Advance")
+	}
+	if b.calls != 74 {
+		t.Errorf("%d exponenciacoes depois de Advance(10), esperadas 74", b.calls)
+	}
+
+	// Um n par nao eh preparado, e o preparo nao eh tentado de novo
+	even := &BlumBlumShub{n: big.NewInt(1000), state: big.NewInt(7)}
+	if got := even.Advance(2); got.Int64() != 401 || !even.tried || even.mod != nil {
+		t.Errorf("Advance(2) com n = 1000: %s, preparo tentado %t", got, even.tried)
+	}
+
+	out := BigBackend{}.ExpBatch([]*big.Int{big.NewInt(2), big.NewInt(3)}, []*big.Int{big.NewInt(10), big.NewInt(4)}, big.NewInt(1000))
+	if out[0].Int64() != 24 || out[1].Int64() != 81 {
+		t.Errorf("ExpBatch = %v", out)
+	}
+}
+
+func TestFixedBackend(t *testing.T) {
+	e := Entropy{}
+	var fixed FixedBackend
+	for _, bits := range []int{2, 3, 40, 63, 64, 65, 127, 128, 192, 255, 256, 257, 512} {
+		for range 20 {
+			mod, _ := e.Bits(bits)
+			mod.SetBit(mod, bits-1, 1)
+			if bits > 2 {
+				mod.SetBit(mod, 0, 1)
+			}
+			base, _ := e.Bits(bits + 8)
+			exp, _ := e.Bits(bits)
+			if want, got := new(big.Int).Exp(base, exp, mod), fixed.Exp(base, exp, mod); got.Cmp(want) != 0 {
+				t.Fatalf("%d bits: %s^%s mod %s = %s, esperado %s", bits, base, exp, mod, got, want)
+			}
+		}
+	}
+
+	// Casos de borda e os que ficam com o math/big
+	mod := big.NewInt(1000003)
+	cases := [][3]int64{{5, 0, 1000003}, {0, 7, 1000003}, {-5, 3, 1000003}, {2, 10, 1000}, {3, -1, 1000003}, {9, 9, 1}}
+	for _, c := range cases {
+		base, exp, mod := big.NewInt(c[0]), big.NewInt(c[1]), big.NewInt(c[2])
+		if want, got := new(big.Int).Exp(base, exp, mod), fixed.Exp(base, exp, mod); got.Cmp(want) != 0 {
+			t.Errorf("%v: %s, esperado %s", c, got, want)
+		}
+	}
+	out := fixed.ExpBatch([]*big.Int{big.NewInt(2), big.NewInt(3)}, []*big.Int{big.NewInt(20), big.NewInt(-1)}, mod)
+	check := new(big.Int).Mul(out[1], big.NewInt(3))
+	if out[0].Int64() != 1048576%1000003 || check.Mod(check, mod).Int64() != 1 {
+		t.Errorf("ExpBatch = %v", out)
+	}
+}
+
+// Compara os backends nos tamanhos de um Miller-Rabin com primos pequenos
+func BenchmarkModExp(b *testing.B) {
+	for _, bits := range []int{64, 128, 256, 512} {
+		mod, _ := Entropy{}.Prime(bits)
+		base, _ := Entropy{}.Int(mod)
+		exp := new(big.Int).Rsh(mod, 1)
+		for _, backend := range []struct {
+			name string
+			ModExpBackend
+		}{{"big", BigBackend{}}, {"fixed", FixedBackend{}}} {
+			b.Run(fmt.Sprintf("%s-%d", backend.name, bits), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					backend.Exp(base, exp, mod)
+				}
+			})
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	mod := big.NewInt(1000003)
+	b, ok := Prepare(nil, mod).(PreparedBackend)
+	if !ok {
+		t.Fatal("Prepare nao preparou o modulo com o backend padrao")
+	}
+	bases := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(5)}
+	exps := []*big.Int{big.NewInt(500001), big.NewInt(500001), big.NewInt(500001)}
+	for i, got := range b.ExpBatch(bases, exps, mod) {
+		if want := new(big.Int).Exp(bases[i], exps[i], mod); got.Cmp(want) != 0 {
+			t.Errorf("ExpBatch[%d] = %s, esperado %s", i, got, want)
+		}
+	}
+	// Outro modulo fica com o FixedBackend
+	if got := b.Exp(big.NewInt(3), big.NewInt(4), big.NewInt(10)); got.Int64() != 1 {
+		t.Errorf("3^4 mod 10 = %s", got)
+	}
+	for _, m := range []*big.Int{mod, big.NewInt(10)} {
+		x := big.NewInt(999)
+		if b.Square(x, m); x.Cmp(new(big.Int).Exp(big.NewInt(999), big.NewInt(2), m)) != 0 {
+			t.Errorf("Square(999, %s) = %s", m, x)
+		}
+	}
+
+	external := &squareBackend{}
+	if Prepare(external, mod) != ModExpBackend(external) {
+		t.Error("Prepare alterou um backend externo")
+	}
+	if _, ok := Prepare(nil, big.NewInt(1000)).(FixedBackend); !ok {
+		t.Error("modulo par preparado")
+	}
+}
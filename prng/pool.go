@@ -0,0 +1,46 @@
+// Esse arquivo traz o pool de valores temporarios usado nas operacoes
+//  aritmeticas dos geradores e dos testes de primalidade.
+
+package prng
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// intPool guarda big.Int temporarios, para que as contas internas dos
+// geradores e dos testes reaproveitem a memoria em vez de alocar a cada
+// chamada
+var intPool = sync.Pool{New: func() any { return new(big.Int) }}
+
+// poolMaxBytes eh o maior valor, em bytes de capacidade, guardado no pool
+// (0 = sem limite)
+var poolMaxBytes atomic.Int64
+
+// SetPoolLimit limita a capacidade dos valores guardados no pool a maxBytes:
+// PutInt descarta os maiores, que ficam para o coletor de lixo, em vez de
+// manter seus buffers vivos. Com pouca memoria, isso troca algumas
+// alocacoes a mais por um pool que nao retem os temporarios dos numeros
+// enormes. 0 remove o limite.
+func SetPoolLimit(maxBytes int) {
+	poolMaxBytes.Store(int64(max(maxBytes, 0)))
+}
+
+// GetInt retorna um big.Int temporario do pool, com valor indefinido. Ele
+// deve ser devolvido com PutInt quando nao for mais usado.
+func GetInt() *big.Int {
+	return intPool.Get().(*big.Int)
+}
+
+// PutInt apaga x e o devolve ao pool. x nao pode ser usado depois da
+// chamada. O valor eh apagado porque os temporarios podem conter estados
+// dos geradores ou testemunhas dos testes.
+func PutInt(x *big.Int) {
+	WipeInt(x)
+	if limit := poolMaxBytes.Load(); limit > 0 && int64(cap(x.Bits())*bits.UintSize/8) > limit {
+		return
+	}
+	intPool.Put(x)
+}
@@ -0,0 +1,23 @@
+package prng
+
+// Implementadas em rdrand_amd64.s
+func rdrand64() (v uint64, ok bool)
+func rdseed64() (v uint64, ok bool)
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+var hasRDRAND, hasRDSEED = detectHardwareRNG()
+
+// detectHardwareRNG consulta o CPUID para saber se as instrucoes RDRAND
+// (CPUID.1:ECX[30]) e RDSEED (CPUID.7.0:EBX[18]) estao disponiveis
+func detectHardwareRNG() (rdrand, rdseed bool) {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf >= 1 {
+		_, _, ecx, _ := cpuid(1, 0)
+		rdrand = ecx&(1<<30) != 0
+	}
+	if maxLeaf >= 7 {
+		_, ebx, _, _ := cpuid(7, 0)
+		rdseed = ebx&(1<<18) != 0
+	}
+	return rdrand, rdseed
+}
@@ -0,0 +1,47 @@
+#include "textflag.h"
+
+// func rdrand64() (v uint64, ok bool)
+TEXT ·rdrand64(SB), NOSPLIT, $0-9
+	// A Intel recomenda ate 10 tentativas antes de considerar o RDRAND em falha
+	MOVL $10, CX
+retry:
+	RDRANDQ AX
+	JCS ok
+	DECL CX
+	JNZ retry
+	MOVQ $0, v+0(FP)
+	MOVB $0, ok+8(FP)
+	RET
+ok:
+	MOVQ AX, v+0(FP)
+	MOVB $1, ok+8(FP)
+	RET
+
+// func rdseed64() (v uint64, ok bool)
+TEXT ·rdseed64(SB), NOSPLIT, $0-9
+	// O RDSEED esgota com mais facilidade; esperamos com PAUSE entre tentativas
+	MOVL $100, CX
+retry:
+	RDSEEDQ AX
+	JCS ok
+	PAUSE
+	DECL CX
+	JNZ retry
+	MOVQ $0, v+0(FP)
+	MOVB $0, ok+8(FP)
+	RET
+ok:
+	MOVQ AX, v+0(FP)
+	MOVB $1, ok+8(FP)
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
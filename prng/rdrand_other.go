@@ -0,0 +1,9 @@
+//go:build !amd64
+
+package prng
+
+// Fora de amd64 nao ha RDRAND nem RDSEED
+const hasRDRAND, hasRDSEED = false, false
+
+func rdrand64() (uint64, bool) { return 0, false }
+func rdseed64() (uint64, bool) { return 0, false }
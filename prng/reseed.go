@@ -0,0 +1,125 @@
+// Esse arquivo traz a troca periodica da semente de geradores de longa
+//  duracao, como os usados pelo servidor.
+
+package prng
+
+import (
+	"PrimeNumGenerator/clock"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReseedPolicy define quando um gerador Reseeding troca de semente: apos
+// MaxBytes bytes emitidos ou quando a instancia atual tiver MaxAge de
+// idade, o que vier primeiro. Um campo zero desativa o gatilho
+// correspondente; a troca tambem pode ser pedida a qualquer momento com
+// Reseeding.Reseed (por exemplo, ao receber um sinal).
+type ReseedPolicy struct {
+	MaxBytes int64
+	MaxAge   time.Duration
+}
+
+// Reseeding eh um gerador seguro para uso concorrente que substitui
+// periodicamente a instancia em uso por uma nova, criada por uma funcao
+// que a semeia com entropia nova. A nova instancia eh criada em segundo
+// plano e trocada de forma atomica: as chamadas em andamento terminam na
+// instancia antiga, e nenhuma espera pela criacao da nova (o que, no BBS,
+// exige gerar dois primos). Ate la, a instancia antiga continua em uso,
+// entao os limites da politica podem ser ultrapassados por pouco.
+type Reseeding struct {
+	create  func() (Generator, error)
+	policy  ReseedPolicy
+	current atomic.Pointer[reseedInstance]
+
+	pending atomic.Bool // troca em segundo plano em andamento
+	mu      sync.Mutex  // protege reseeds e err
+	reseeds uint64
+	err     error // ultimo erro de uma troca em segundo plano
+}
+
+// reseedInstance eh uma instancia do gerador e o que ela ja emitiu
+type reseedInstance struct {
+	mu      sync.Mutex
+	g       Generator
+	emitted int64
+	created time.Time
+}
+
+// NewReseeding cria o gerador com a primeira instancia de create
+func NewReseeding(create func() (Generator, error), policy ReseedPolicy) (*Reseeding, error) {
+	if policy.MaxBytes < 0 || policy.MaxAge < 0 {
+		return nil, errors.New("prng: limites de troca de semente negativos")
+	}
+	r := &Reseeding{create: create, policy: policy}
+	g, err := create()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(&reseedInstance{g: g, created: clock.Now()})
+	return r, nil
+}
+
+// Next retorna a proxima saida da instancia atual e dispara a troca em
+// segundo plano se a politica pedir
+func (r *Reseeding) Next() *big.Int {
+	inst := r.current.Load()
+	inst.mu.Lock()
+	v := inst.g.Next()
+	inst.emitted += int64((inst.g.Bits() + 7) / 8)
+	due := (r.policy.MaxBytes > 0 && inst.emitted >= r.policy.MaxBytes) ||
+		(r.policy.MaxAge > 0 && clock.Since(inst.created) >= r.policy.MaxAge)
+	inst.mu.Unlock()
+
+	if due && r.pending.CompareAndSwap(false, true) {
+		go func() {
+			defer r.pending.Store(false)
+			if err := r.Reseed(); err != nil {
+				r.mu.Lock()
+				r.err = err
+				r.mu.Unlock()
+			}
+		}()
+	}
+	return v
+}
+
+// Bits implementa Generator
+func (r *Reseeding) Bits() int {
+	inst := r.current.Load()
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.g.Bits()
+}
+
+// Reseed cria uma nova instancia e a coloca em uso. Se a criacao falhar, a
+// instancia atual continua em uso e o erro eh retornado.
+func (r *Reseeding) Reseed() error {
+	g, err := r.create()
+	if err != nil {
+		return err
+	}
+	r.current.Store(&reseedInstance{g: g, created: clock.Now()})
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reseeds++
+	r.err = nil
+	return nil
+}
+
+// Reseeds retorna quantas trocas de semente ja foram feitas
+func (r *Reseeding) Reseeds() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reseeds
+}
+
+// Err retorna o erro da ultima troca em segundo plano, se ela falhou e
+// nenhuma troca posterior teve sucesso
+func (r *Reseeding) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
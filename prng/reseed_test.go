@@ -0,0 +1,120 @@
+package prng
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// seededLFGs retorna uma funcao que cria LFGs de 64 bits, cada um com uma
+// semente diferente, e o numero de geradores criados
+func seededLFGs() (func() (Generator, error), *atomic.Int64) {
+	var created atomic.Int64
+	return func() (Generator, error) {
+		n := uint64(created.Add(1))
+		seed := make([]*big.Int, 10)
+		for i := range seed {
+			seed[i] = new(big.Int).SetUint64(0x9e3779b97f4a7c15*uint64(i+1) + n)
+		}
+		return NewLFGFromState(7, 10, 64, seed)
+	}, &created
+}
+
+// waitReseeds espera ate que r tenha feito ao menos n trocas
+func waitReseeds(t *testing.T, r *Reseeding, n uint64) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); r.Reseeds() < n; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d trocas de semente, esperadas %d", r.Reseeds(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReseedingBytes(t *testing.T) {
+	create, created := seededLFGs()
+	r, err := NewReseeding(create, ReseedPolicy{MaxBytes: 800})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Chamadas concorrentes nunca ficam sem saida durante as trocas
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if v := r.Next(); v == nil || v.BitLen() > 64 {
+					t.Errorf("saida invalida %v", v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	// 1600 saidas de 8 bytes, trocando a cada 100
+	waitReseeds(t, r, 1)
+	if created.Load() < 2 || r.Bits() != 64 || r.Err() != nil {
+		t.Errorf("%d geradores criados, %d bits, erro %v", created.Load(), r.Bits(), r.Err())
+	}
+}
+
+func TestReseedingAgeAndSignal(t *testing.T) {
+	create, _ := seededLFGs()
+	r, err := NewReseeding(create, ReseedPolicy{MaxAge: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Next()
+	time.Sleep(2 * time.Millisecond)
+	r.Next()
+	waitReseeds(t, r, 1)
+
+	// A troca pedida explicitamente muda a sequencia
+	before := r.Reseeds()
+	ref, _ := create()
+	if err := r.Reseed(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Reseeds() != before+1 {
+		t.Errorf("Reseeds = %d, esperado %d", r.Reseeds(), before+1)
+	}
+	if r.Next().Cmp(ref.Next()) == 0 {
+		t.Error("a nova instancia repete a sequencia de outra semente")
+	}
+}
+
+func TestReseedingFailure(t *testing.T) {
+	create, _ := seededLFGs()
+	fail := errors.New("sem entropia")
+	var broken atomic.Bool
+	r, err := NewReseeding(func() (Generator, error) {
+		if broken.Load() {
+			return nil, fail
+		}
+		return create()
+	}, ReseedPolicy{MaxBytes: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken.Store(true)
+	if err := r.Reseed(); !errors.Is(err, fail) {
+		t.Errorf("Reseed retornou %v", err)
+	}
+	// A instancia antiga continua em uso e o erro da troca em segundo
+	// plano fica registrado
+	for deadline := time.Now().Add(5 * time.Second); r.Err() == nil; {
+		if r.Next() == nil || time.Now().After(deadline) {
+			t.Fatal("erro da troca em segundo plano nao registrado")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if r.Reseeds() != 0 {
+		t.Errorf("%d trocas com a criacao falhando", r.Reseeds())
+	}
+	if _, err := NewReseeding(create, ReseedPolicy{MaxBytes: -1}); err == nil {
+		t.Error("limite negativo aceito")
+	}
+}
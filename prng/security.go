@@ -0,0 +1,50 @@
+// Esse arquivo define os niveis de seguranca que controlam o que os
+//  geradores fazem quando a fonte de entropia do sistema falha.
+
+package prng
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SecurityLevel define o comportamento em caso de falha da fonte de entropia.
+type SecurityLevel int
+
+const (
+	// Strict retorna ErrEntropy quando a fonte de entropia falha. Eh o
+	// nivel padrao e o unico adequado para uso criptografico.
+	Strict SecurityLevel = iota
+	// Permissive recorre a fonte jitter (JitterSource) quando a fonte de
+	// entropia falha. Sua qualidade nao eh garantida; use apenas em demonstracoes.
+	Permissive
+)
+
+// ErrEntropy indica que a fonte de entropia falhou no nivel Strict
+var ErrEntropy = errors.New("prng: falha na fonte de entropia")
+
+func (l SecurityLevel) String() string {
+	switch l {
+	case Strict:
+		return "strict"
+	case Permissive:
+		return "permissive"
+	}
+	return fmt.Sprintf("SecurityLevel(%d)", int(l))
+}
+
+// ParseSecurityLevel converte "strict" ou "permissive" no nivel correspondente
+func ParseSecurityLevel(s string) (SecurityLevel, error) {
+	switch s {
+	case "strict":
+		return Strict, nil
+	case "permissive":
+		return Permissive, nil
+	}
+	return 0, fmt.Errorf("prng: nivel de seguranca desconhecido %q", s)
+}
+
+// entropyError embrulha err em ErrEntropy
+func entropyError(err error) error {
+	return fmt.Errorf("%w: %v", ErrEntropy, err)
+}
@@ -0,0 +1,43 @@
+// Esse arquivo traz uma fonte de entropia deterministica, derivada de uma
+//  semente, para execucoes reproduziveis.
+
+package prng
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// SeededSource eh uma fonte deterministica: a partir da mesma semente,
+// produz sempre os mesmos bytes, os blocos SHA-256(SHA-256(semente) ||
+// contador). Serve para reproduzir uma execucao inteira; os numeros gerados sao tao secretos quanto a semente, entao ela
+// nao deve ser usada para gerar chaves.
+type SeededSource struct {
+	key     [sha256.Size]byte
+	counter uint64
+	buf     []byte // bytes do bloco atual ainda nao lidos
+}
+
+// NewSeededSource cria uma fonte deterministica a partir de seed
+func NewSeededSource(seed []byte) *SeededSource {
+	return &SeededSource{key: sha256.Sum256(seed)}
+}
+
+// Read implementa EntropySource; nunca falha
+func (s *SeededSource) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.buf) == 0 {
+			var block [sha256.Size + 8]byte
+			copy(block[:], s.key[:])
+			binary.BigEndian.PutUint64(block[sha256.Size:], s.counter)
+			s.counter++
+			sum := sha256.Sum256(block[:])
+			s.buf = sum[:]
+		}
+		c := copy(p[n:], s.buf)
+		s.buf = s.buf[c:]
+		n += c
+	}
+	return n, nil
+}
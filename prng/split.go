@@ -0,0 +1,176 @@
+// Esse arquivo permite derivar de um gerador varios fluxos independentes,
+//  um para cada goroutine de uma busca paralela.
+
+package prng
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Splitter eh implementado pelos geradores que sabem se dividir em fluxos
+// independentes. Split retorna n geradores cujas sequencias nao se
+// sobrepoem nem estao correlacionadas entre si ou com a do gerador
+// original, que continua utilizavel. Cada fluxo pode ser usado por uma
+// goroutine diferente sem trava nenhuma (compare com Concurrent).
+type Splitter interface {
+	Split(n int) ([]Generator, error)
+}
+
+// ErrNoPrivate indica uma operacao do BBS que depende dos fatores p e q de
+// um gerador que ja os descartou
+var ErrNoPrivate = errors.New("prng: operacao exige os fatores p e q do BBS")
+
+// splitStride eh o log2 da distancia, em estados, entre os fluxos do BBS
+const splitStride = 64
+
+// Split divide g em n fluxos independentes, se g implementar Splitter
+func Split(g Generator, n int) ([]Generator, error) {
+	s, ok := g.(Splitter)
+	if !ok {
+		return nil, fmt.Errorf("prng: o gerador %T nao pode ser dividido", g)
+	}
+	return s.Split(n)
+}
+
+func checkStreams(n int) error {
+	if n < 1 {
+		return fmt.Errorf("prng: numero de fluxos invalido: %d", n)
+	}
+	return nil
+}
+
+// Jump avanca o gerador t estados de uma vez. Como x_(i+t) = x_i^(2^t) mod
+// n e a ordem de x_i divide lambda(n) = mmc(p-1, q-1), basta uma
+// exponenciacao com o expoente 2^t mod lambda(n), qualquer que seja t.
+// Exige os fatores p e q; sem eles, use Advance.
+func (bbs *BlumBlumShub) Jump(t *big.Int) error {
+	if !bbs.HasPrivate() {
+		return ErrNoPrivate
+	}
+	if t.Sign() < 0 {
+		return errors.New("prng: salto negativo")
+	}
+	one := big.NewInt(1)
+	pMinus1 := new(big.Int).Sub(bbs.p, one)
+	qMinus1 := new(big.Int).Sub(bbs.q, one)
+	lambda := new(big.Int).GCD(nil, nil, pMinus1, qMinus1)
+	lambda.Div(pMinus1, lambda).Mul(lambda, qMinus1)
+
+	e := new(big.Int).Exp(big.NewInt(2), t, lambda)
+	bbs.state = Backend(bbs.backend).Exp(bbs.state, e, bbs.n)
+	return nil
+}
+
+// Split implementa Splitter. Os fluxos sao trechos disjuntos da sequencia
+// do proprio gerador, separados por 2^64 estados: o fluxo i comeca onde o
+// gerador estaria apos i saltos (veja Jump), e o gerador original avanca
+// para depois do ultimo fluxo. Exige os fatores p e q.
+func (bbs *BlumBlumShub) Split(n int) ([]Generator, error) {
+	if err := checkStreams(n); err != nil {
+		return nil, err
+	}
+	if !bbs.HasPrivate() {
+		return nil, ErrNoPrivate
+	}
+	stride := new(big.Int).Lsh(big.NewInt(1), splitStride)
+	streams := make([]Generator, n)
+	for i := range streams {
+		streams[i] = &BlumBlumShub{
+			p:       new(big.Int).Set(bbs.p),
+			q:       new(big.Int).Set(bbs.q),
+			n:       new(big.Int).Set(bbs.n),
+			state:   new(big.Int).Set(bbs.state),
+			bitSize: bbs.bitSize,
+			backend: bbs.backend,
+		}
+		if err := bbs.Jump(stride); err != nil {
+			return nil, err
+		}
+	}
+	return streams, nil
+}
+
+// Split implementa Splitter. Saltar adiante no LFG eh caro, entao cada
+// fluxo recebe um estado inicial proprio, derivado com SHA-256 do estado
+// do gerador e do indice do fluxo, e eh aquecido com DefaultWarmup. O
+// gerador original avanca size valores, de modo que uma nova chamada
+// produz outros fluxos.
+func (lfg *LaggedFibonacciGenerator) Split(n int) ([]Generator, error) {
+	if err := checkStreams(n); err != nil {
+		return nil, err
+	}
+	key, err := lfg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+
+	streams := make([]Generator, n)
+	for i := range streams {
+		seed := make([]*big.Int, lfg.size)
+		for v := range seed {
+			seed[v] = deriveInt(key, lfg.bitSize, uint64(i), uint64(v))
+		}
+		// Com todos os valores pares o periodo cai drasticamente
+		seed[0].SetBit(seed[0], 0, 1)
+		child, err := NewLFGFromState(lfg.j, lfg.k, lfg.bitSize, seed)
+		if err != nil {
+			return nil, err
+		}
+		child.Discard(DefaultWarmup(lfg.k))
+		streams[i] = child
+	}
+	lfg.Discard(uint64(lfg.size))
+	return streams, nil
+}
+
+// Split implementa Splitter, dividindo cada um dos geradores combinados.
+// Falha se algum deles nao implementar Splitter.
+func (h *Hybrid) Split(n int) ([]Generator, error) {
+	if err := checkStreams(n); err != nil {
+		return nil, err
+	}
+	parts := make([][]Generator, len(h.gens))
+	for i, g := range h.gens {
+		var err error
+		if parts[i], err = Split(g, n); err != nil {
+			return nil, err
+		}
+	}
+	streams := make([]Generator, n)
+	for i := range streams {
+		gens := make([]Generator, len(h.gens))
+		for j := range gens {
+			gens[j] = parts[j][i]
+		}
+		streams[i] = &Hybrid{gens: gens, bits: h.bits, add: h.add, modulus: h.modulus}
+	}
+	return streams, nil
+}
+
+// deriveInt deriva de key um numero de bits bits, concatenando blocos
+// SHA-256(key || labels || contador)
+func deriveInt(key []byte, bits int, labels ...uint64) *big.Int {
+	size := (bits + 7) / 8
+	out := make([]byte, 0, size+sha256.Size)
+	var word [8]byte
+	for counter := uint64(0); len(out) < size; counter++ {
+		h := sha256.New()
+		h.Write(key)
+		for _, label := range labels {
+			binary.BigEndian.PutUint64(word[:], label)
+			h.Write(word[:])
+		}
+		binary.BigEndian.PutUint64(word[:], counter)
+		h.Write(word[:])
+		out = h.Sum(out)
+	}
+	result := new(big.Int).SetBytes(out[:size])
+	clear(out)
+	// Descartamos os bits excedentes do byte mais significativo
+	return result.Rsh(result, uint(size*8-bits))
+}
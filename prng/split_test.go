@@ -0,0 +1,113 @@
+package prng
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func cloneBBS(t *testing.T, bbs *BlumBlumShub) *BlumBlumShub {
+	t.Helper()
+	data, err := bbs.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := new(BlumBlumShub)
+	if err := clone.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	return clone
+}
+
+func TestBBSJump(t *testing.T) {
+	bbs := NewBBS(64)
+	ref := cloneBBS(t, bbs)
+	want := ref.Advance(1000)
+	if err := bbs.Jump(big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if bbs.state.Cmp(want) != 0 {
+		t.Errorf("Jump(1000) = %s, Advance(1000) = %s", bbs.state, want)
+	}
+
+	bbs.DestroyPrivate()
+	if err := bbs.Jump(big.NewInt(1)); !errors.Is(err, ErrNoPrivate) {
+		t.Errorf("Jump sem os fatores: %v", err)
+	}
+	if _, err := bbs.Split(2); !errors.Is(err, ErrNoPrivate) {
+		t.Errorf("Split sem os fatores: %v", err)
+	}
+}
+
+func TestBBSSplit(t *testing.T) {
+	bbs := NewBBS(64)
+	ref := cloneBBS(t, bbs)
+	streams, err := bbs.Split(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O primeiro fluxo continua a sequencia de onde o gerador estava; os
+	// demais e o proprio gerador seguem 2^64 estados adiante cada um
+	stride := new(big.Int).Lsh(big.NewInt(1), splitStride)
+	for i, s := range streams {
+		if got, want := s.Next(), cloneBBS(t, ref).Next(); got.Cmp(want) != 0 {
+			t.Errorf("fluxo %d: %s, esperado %s", i, got, want)
+		}
+		ref.Jump(stride)
+	}
+	if bbs.state.Cmp(ref.state) != 0 {
+		t.Error("o gerador original nao avancou para depois dos fluxos")
+	}
+}
+
+func TestLFGSplit(t *testing.T) {
+	seed := make([]*big.Int, 10)
+	for i := range seed {
+		seed[i] = new(big.Int).SetUint64(0x9e3779b97f4a7c15 * uint64(i+1))
+	}
+	a, _ := NewLFGFromState(7, 10, 64, seed)
+	b, _ := NewLFGFromState(7, 10, 64, seed)
+
+	first, err := a.Split(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, _ := b.Split(4)
+	second, _ := a.Split(4)
+
+	seen := map[string]bool{}
+	for i := range first {
+		x, y, z := first[i].Next(), same[i].Next(), second[i].Next()
+		// A divisao eh deterministica, mas cada chamada produz outros fluxos
+		if x.Cmp(y) != 0 {
+			t.Errorf("fluxo %d difere entre geradores de mesmo estado", i)
+		}
+		if x.Cmp(z) == 0 {
+			t.Errorf("fluxo %d repetido em uma nova divisao", i)
+		}
+		if seen[x.Text(16)] {
+			t.Errorf("fluxo %d repete a saida de outro fluxo", i)
+		}
+		seen[x.Text(16)] = true
+	}
+
+	if _, err := a.Split(0); err == nil {
+		t.Error("Split(0) aceito")
+	}
+}
+
+func TestHybridSplit(t *testing.T) {
+	h, _ := NewHybrid(NewLFG(10, 7, 10, 64), NewBBS(64))
+	streams, err := Split(h, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if streams[0].Bits() != 64 || streams[0].Next().Cmp(streams[1].Next()) == 0 {
+		t.Error("fluxos do hibrido iguais")
+	}
+
+	if _, err := Split(Whiten(NewLFG(10, 7, 10, 64), VonNeumann), 2); err == nil {
+		t.Error("gerador sem Split dividido")
+	}
+}
@@ -0,0 +1,229 @@
+// Esse arquivo implementa a serializacao do estado dos geradores, usada
+//  para salvar e restaurar um gerador exatamente do ponto em que parou.
+
+package prng
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Cada formato comeca com um byte identificando o gerador e um byte de versao
+const (
+	stateVersion = 1
+	lfgStateTag  = 'L'
+	bbsStateTag  = 'B'
+
+	// Limites usados na decodificacao para rejeitar estados absurdos
+	maxStateBitSize = 1 << 16
+	maxStateSize    = 1 << 16
+)
+
+var errTruncatedState = errors.New("prng: estado truncado")
+
+// stateWriter acumula os campos de um estado serializado
+type stateWriter struct {
+	buf []byte
+}
+
+func newStateWriter(tag byte) *stateWriter {
+	return &stateWriter{buf: []byte{tag, stateVersion}}
+}
+
+func (w *stateWriter) uint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// bigInt escreve um inteiro nao negativo; nil eh codificado com tamanho zero
+func (w *stateWriter) bigInt(v *big.Int) {
+	if v == nil {
+		w.uint(0)
+		return
+	}
+	b := v.Bytes()
+	w.uint(uint64(len(b)) + 1)
+	w.buf = append(w.buf, b...)
+}
+
+// stateReader le os campos escritos por stateWriter
+type stateReader struct {
+	buf []byte
+}
+
+func newStateReader(data []byte, tag byte) (*stateReader, error) {
+	if len(data) < 2 {
+		return nil, errTruncatedState
+	}
+	if data[0] != tag {
+		return nil, fmt.Errorf("prng: estado de tipo %q, esperado %q", data[0], tag)
+	}
+	if data[1] != stateVersion {
+		return nil, fmt.Errorf("prng: versao de estado %d nao suportada", data[1])
+	}
+	return &stateReader{buf: data[2:]}, nil
+}
+
+func (r *stateReader) uint(max uint64) (uint64, error) {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		return 0, errTruncatedState
+	}
+	if v > max {
+		return 0, fmt.Errorf("prng: valor %d acima do limite %d", v, max)
+	}
+	r.buf = r.buf[n:]
+	return v, nil
+}
+
+func (r *stateReader) bigInt() (*big.Int, error) {
+	l, err := r.uint(uint64(len(r.buf)) + 1)
+	if err != nil {
+		return nil, err
+	}
+	if l == 0 {
+		return nil, nil
+	}
+	l--
+	if uint64(len(r.buf)) < l {
+		return nil, errTruncatedState
+	}
+	v := new(big.Int).SetBytes(r.buf[:l])
+	r.buf = r.buf[l:]
+	return v, nil
+}
+
+func (r *stateReader) done() error {
+	if len(r.buf) != 0 {
+		return fmt.Errorf("prng: %d bytes sobrando no estado", len(r.buf))
+	}
+	return nil
+}
+
+// MarshalBinary serializa o estado completo do gerador
+func (lfg *LaggedFibonacciGenerator) MarshalBinary() ([]byte, error) {
+	w := newStateWriter(lfgStateTag)
+	w.uint(uint64(lfg.j))
+	w.uint(uint64(lfg.k))
+	w.uint(uint64(lfg.size))
+	w.uint(uint64(lfg.bitSize))
+	// O estado eh gravado do valor mais antigo para o mais recente,
+	// independente da posicao do buffer circular
+	for i := 0; i < lfg.size; i++ {
+		w.bigInt(lfg.at(i))
+	}
+	return w.buf, nil
+}
+
+// UnmarshalBinary restaura um estado gerado por MarshalBinary, validando
+// os parametros antes de alterar o gerador
+func (lfg *LaggedFibonacciGenerator) UnmarshalBinary(data []byte) error {
+	r, err := newStateReader(data, lfgStateTag)
+	if err != nil {
+		return err
+	}
+
+	var fields [4]uint64
+	for i := range fields {
+		max := uint64(maxStateSize)
+		if i == 3 {
+			max = maxStateBitSize
+		}
+		if fields[i], err = r.uint(max); err != nil {
+			return err
+		}
+	}
+	j, k, size, bitSize := int(fields[0]), int(fields[1]), int(fields[2]), int(fields[3])
+	if j < 1 || j >= k || k > size {
+		return fmt.Errorf("prng: parametros do LFG invalidos (j=%d, k=%d, size=%d)", j, k, size)
+	}
+	if bitSize < 1 {
+		return fmt.Errorf("prng: tamanho em bits invalido: %d", bitSize)
+	}
+
+	modValue := new(big.Int).Lsh(big.NewInt(1), uint(bitSize))
+	state := make([]*big.Int, 0, min(size, len(r.buf)))
+	for i := 0; i < size; i++ {
+		v, err := r.bigInt()
+		if err != nil {
+			return err
+		}
+		if v == nil || v.Cmp(modValue) >= 0 {
+			return fmt.Errorf("prng: valor %d do estado fora do intervalo", i)
+		}
+		state = append(state, v)
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+
+	*lfg = LaggedFibonacciGenerator{
+		j:        j,
+		k:        k,
+		state:    state,
+		size:     size,
+		modValue: modValue,
+		bitSize:  bitSize,
+	}
+	return nil
+}
+
+// MarshalBinary serializa o estado completo do gerador, incluindo os
+// fatores p e q de n
+func (bbs *BlumBlumShub) MarshalBinary() ([]byte, error) {
+	w := newStateWriter(bbsStateTag)
+	w.uint(uint64(bbs.bitSize))
+	w.bigInt(bbs.n)
+	w.bigInt(bbs.state)
+	w.bigInt(bbs.p)
+	w.bigInt(bbs.q)
+	return w.buf, nil
+}
+
+// UnmarshalBinary restaura um estado gerado por MarshalBinary, validando
+// os parametros antes de alterar o gerador
+func (bbs *BlumBlumShub) UnmarshalBinary(data []byte) error {
+	r, err := newStateReader(data, bbsStateTag)
+	if err != nil {
+		return err
+	}
+
+	bitSize, err := r.uint(maxStateBitSize)
+	if err != nil {
+		return err
+	}
+	var values [4]*big.Int // n, state, p, q
+	for i := range values {
+		if values[i], err = r.bigInt(); err != nil {
+			return err
+		}
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+
+	n, state, p, q := values[0], values[1], values[2], values[3]
+	if n == nil || n.Cmp(big.NewInt(3)) < 0 {
+		return errors.New("prng: modulo do BBS invalido")
+	}
+	if state == nil || state.Cmp(n) >= 0 {
+		return errors.New("prng: estado do BBS fora do intervalo")
+	}
+	if (p == nil) != (q == nil) {
+		return errors.New("prng: fatores do BBS incompletos")
+	}
+	if p != nil && new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		return errors.New("prng: fatores do BBS nao correspondem ao modulo")
+	}
+
+	*bbs = BlumBlumShub{
+		p:       p,
+		q:       q,
+		n:       n,
+		state:   state,
+		bitSize: int(bitSize),
+		backend: bbs.backend, // o backend nao faz parte do estado
+	}
+	return nil
+}
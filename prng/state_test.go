@@ -0,0 +1,89 @@
+package prng
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLFGStateRoundTrip(t *testing.T) {
+	lfg := NewLFG(10, 7, 10, 128)
+	lfg.Next()
+
+	data, err := lfg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(LaggedFibonacciGenerator)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		if a, b := lfg.Next(), restored.Next(); a.Cmp(b) != 0 {
+			t.Fatalf("saida %d diverge: %s != %s", i, a, b)
+		}
+	}
+}
+
+func TestBBSStateRoundTrip(t *testing.T) {
+	bbs := NewBBS(64)
+	bbs.Next()
+
+	data, err := bbs.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(BlumBlumShub)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if a, b := bbs.Next(), restored.Next(); a.Cmp(b) != 0 {
+			t.Fatalf("saida %d diverge: %s != %s", i, a, b)
+		}
+	}
+}
+
+// FuzzStateUnmarshal garante que estados arbitrarios nunca causam panico e
+// que todo estado aceito pode ser usado e serializado novamente sem perdas
+func FuzzStateUnmarshal(f *testing.F) {
+	lfgState, _ := NewLFG(10, 7, 10, 40).MarshalBinary()
+	bbsState, _ := NewBBS(40).MarshalBinary()
+	f.Add(lfgState)
+	f.Add(bbsState)
+	f.Add([]byte{})
+	f.Add([]byte{lfgStateTag, stateVersion, 0, 0, 0, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		lfg := new(LaggedFibonacciGenerator)
+		if err := lfg.UnmarshalBinary(data); err == nil {
+			lfg.Next()
+			checkReencode(t, lfg, new(LaggedFibonacciGenerator))
+		}
+
+		bbs := new(BlumBlumShub)
+		if err := bbs.UnmarshalBinary(data); err == nil {
+			bbs.NextBit()
+			checkReencode(t, bbs, new(BlumBlumShub))
+		}
+	})
+}
+
+type stateCodec interface {
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary([]byte) error
+}
+
+func checkReencode(t *testing.T, gen, fresh stateCodec) {
+	t.Helper()
+	data, err := gen.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fresh.UnmarshalBinary(data); err != nil {
+		t.Fatalf("estado serializado rejeitado: %v", err)
+	}
+	again, _ := fresh.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Fatal("estado muda apos ida e volta")
+	}
+}
@@ -0,0 +1,132 @@
+// Esse arquivo traz as etapas de pos-processamento (branqueamento) que
+//  corrigem vieses na saida de qualquer gerador.
+
+package prng
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Whitening define o pos-processamento aplicado a saida de um gerador
+// antes de montar o candidato
+type Whitening int
+
+const (
+	// NoWhitening usa a saida do gerador sem alteracoes
+	NoWhitening Whitening = iota
+	// VonNeumann le os bits do gerador aos pares e emite 0 para 01, 1 para
+	// 10 e descarta 00 e 11. Remove o vies de bits independentes, ao custo
+	// de consumir em media 4 bits por bit produzido (ou mais, com vies).
+	VonNeumann
+	// SHA256Whitening passa cada saida do gerador pelo SHA-256 (em modo
+	// contador, para saidas maiores que 256 bits). Espalha a entropia da
+	// entrada por todos os bits, mas nao a aumenta.
+	SHA256Whitening
+)
+
+func (w Whitening) String() string {
+	switch w {
+	case NoWhitening:
+		return "none"
+	case VonNeumann:
+		return "vonneumann"
+	case SHA256Whitening:
+		return "sha256"
+	}
+	return fmt.Sprintf("Whitening(%d)", int(w))
+}
+
+// ParseWhitening converte "none", "vonneumann" ou "sha256" no
+// pos-processamento correspondente
+func ParseWhitening(s string) (Whitening, error) {
+	for _, w := range []Whitening{NoWhitening, VonNeumann, SHA256Whitening} {
+		if s == w.String() {
+			return w, nil
+		}
+	}
+	return 0, fmt.Errorf("prng: pos-processamento desconhecido %q", s)
+}
+
+// Whiten retorna um gerador com as saidas de g pos-processadas por w. As
+// saidas continuam com g.Bits() bits.
+func Whiten(g Generator, w Whitening) Generator {
+	switch w {
+	case VonNeumann:
+		return &vonNeumann{g: g}
+	case SHA256Whitening:
+		return sha256Extractor{g: g}
+	default:
+		return g
+	}
+}
+
+// vonNeumann implementa o pos-processamento VonNeumann
+type vonNeumann struct {
+	g       Generator
+	pending []uint8 // bits lidos de g e ainda nao usados
+}
+
+func (v *vonNeumann) Next() *big.Int {
+	result := new(big.Int)
+	for i := v.Bits() - 1; i >= 0; {
+		if len(v.pending) < 2 {
+			v.fill()
+			continue
+		}
+		a, b := v.pending[0], v.pending[1]
+		v.pending = v.pending[2:]
+		if a == b {
+			continue
+		}
+		// 10 produz 1 e 01 produz 0
+		if a == 1 {
+			result.SetBit(result, i, 1)
+		}
+		i--
+	}
+	return result
+}
+
+// fill acrescenta a pending os bits da proxima saida de g
+func (v *vonNeumann) fill() {
+	x := v.g.Next()
+	for b := v.g.Bits() - 1; b >= 0; b-- {
+		v.pending = append(v.pending, uint8(x.Bit(b)))
+	}
+}
+
+func (v *vonNeumann) Bits() int {
+	return v.g.Bits()
+}
+
+// sha256Extractor implementa o pos-processamento SHA256Whitening
+type sha256Extractor struct {
+	g Generator
+}
+
+func (s sha256Extractor) Next() *big.Int {
+	bits := s.g.Bits()
+	size := (bits + 7) / 8
+	input := s.g.Next().FillBytes(make([]byte, size))
+
+	// Blocos SHA-256(contador || entrada) ate cobrir os bits pedidos
+	out := make([]byte, 0, size+sha256.Size)
+	var counter [4]byte
+	for i := uint32(0); len(out) < size; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write(counter[:])
+		h.Write(input)
+		out = h.Sum(out)
+	}
+	result := new(big.Int).SetBytes(out[:size])
+	// Descartamos os bits excedentes do byte mais significativo
+	return result.Rsh(result, uint(size*8-bits))
+}
+
+func (s sha256Extractor) Bits() int {
+	return s.g.Bits()
+}
@@ -0,0 +1,59 @@
+package prng
+
+import (
+	"math/big"
+	"testing"
+)
+
+// biased eh um gerador com vies forte: cada saida de 8 bits tem 7 uns e a
+// posicao do zero gira a cada chamada
+type biased struct{ i int }
+
+func (b *biased) Next() *big.Int {
+	b.i++
+	return big.NewInt(0xff &^ (1 << (b.i % 8)))
+}
+
+func (b *biased) Bits() int { return 8 }
+
+func ones(g Generator, samples int) int {
+	n := 0
+	for i := 0; i < samples; i++ {
+		v := g.Next()
+		if v.BitLen() > g.Bits() {
+			panic("saida maior que Bits()")
+		}
+		for b := 0; b < g.Bits(); b++ {
+			n += int(v.Bit(b))
+		}
+	}
+	return n
+}
+
+func TestWhitening(t *testing.T) {
+	if got := ones(&biased{}, 100); got != 700 {
+		t.Fatalf("gerador de referencia com %d uns", got)
+	}
+	// Os pares 10 e 01 aparecem na mesma proporcao, entao o Von Neumann
+	// produz aproximadamente metade uns
+	if got := ones(Whiten(&biased{}, VonNeumann), 100); got < 300 || got > 500 {
+		t.Errorf("Von Neumann: %d uns em 800 bits", got)
+	}
+	if got := ones(Whiten(&biased{}, SHA256Whitening), 100); got < 340 || got > 460 {
+		t.Errorf("SHA-256: %d uns em 800 bits", got)
+	}
+	if g := Whiten(NewBBS(300), SHA256Whitening); g.Bits() != 300 {
+		t.Errorf("SHA-256 com 300 bits: Bits() = %d", g.Bits())
+	} else {
+		ones(g, 5)
+	}
+
+	for _, w := range []Whitening{NoWhitening, VonNeumann, SHA256Whitening} {
+		if parsed, err := ParseWhitening(w.String()); err != nil || parsed != w {
+			t.Errorf("ParseWhitening(%q) = %v, %v", w, parsed, err)
+		}
+	}
+	if _, err := ParseWhitening("xor"); err == nil {
+		t.Error("pos-processamento desconhecido aceito")
+	}
+}
@@ -0,0 +1,40 @@
+// Esse arquivo traz as funcoes que apagam da memoria os valores sensiveis
+//  mantidos pelos geradores.
+
+package prng
+
+import "math/big"
+
+// WipeInt zera as palavras internas de x, inclusive a capacidade nao usada,
+// e o define como 0. Copias feitas antes da chamada nao sao afetadas.
+func WipeInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	words = words[:cap(words)]
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
+
+// Wipe apaga o estado interno do gerador. O gerador nao pode mais ser usado.
+func (lfg *LaggedFibonacciGenerator) Wipe() {
+	for i, v := range lfg.state {
+		WipeInt(v)
+		lfg.state[i] = nil
+	}
+	lfg.state = nil
+	lfg.pos = 0
+}
+
+// Wipe apaga o estado interno do gerador, incluindo os fatores p e q.
+// O gerador nao pode mais ser usado.
+func (bbs *BlumBlumShub) Wipe() {
+	WipeInt(bbs.p)
+	WipeInt(bbs.q)
+	WipeInt(bbs.n)
+	WipeInt(bbs.state)
+	bbs.p, bbs.q, bbs.n, bbs.state, bbs.mod, bbs.tried = nil, nil, nil, nil, nil, false
+}
@@ -0,0 +1,52 @@
+package prng
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestWipeInt(t *testing.T) {
+	x := new(big.Int).Lsh(big.NewInt(0xdead), 300)
+	words := x.Bits()
+	WipeInt(x)
+	if x.Sign() != 0 {
+		t.Fatalf("x = %s apos WipeInt", x)
+	}
+	for i, w := range words[:cap(words)] {
+		if w != 0 {
+			t.Fatalf("palavra %d nao foi zerada", i)
+		}
+	}
+}
+
+func TestGeneratorWipe(t *testing.T) {
+	bbs := NewBBS(64)
+	p := bbs.p
+	bbs.Wipe()
+	if p.Sign() != 0 || bbs.p != nil || bbs.state != nil {
+		t.Error("estado do BBS nao foi apagado")
+	}
+
+	lfg := NewLFG(10, 7, 10, 64)
+	first := lfg.state[0]
+	lfg.Wipe()
+	if first.Sign() != 0 || lfg.state != nil {
+		t.Error("estado do LFG nao foi apagado")
+	}
+}
+
+func TestPoolLimit(t *testing.T) {
+	defer SetPoolLimit(0)
+	SetPoolLimit(64)
+	large := GetInt()
+	large.Lsh(large.SetInt64(1), 4096)
+	PutInt(large)
+	// O valor grande foi descartado: nenhum valor do pool tem a sua capacidade
+	for i := 0; i < 100; i++ {
+		x := GetInt()
+		if x == large || x.Sign() != 0 {
+			t.Fatal("valor acima do limite devolvido pelo pool")
+		}
+		defer PutInt(x)
+	}
+}
@@ -0,0 +1,58 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// benchmarkGenerate mede a geracao de um primo de bits bits a partir de
+// candidatos fixos, para que as execucoes sejam comparaveis. O inicio
+// 0b1010...10 tem peso de Hamming medio, para nao cair na auditoria do
+// nivel Strict.
+func benchmarkGenerate(b *testing.B, test PrimalityTest, bits int) {
+	benchmarkGenerateConfig(b, test, bits, Config{Rounds: 20})
+}
+
+func benchmarkGenerateConfig(b *testing.B, test PrimalityTest, bits int, cfg Config) {
+	start := new(big.Int).Lsh(big.NewInt(1), uint(bits+1))
+	start.Div(start, big.NewInt(3))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		candidate := new(big.Int).Add(start, big.NewInt(int64(i%64)*1000))
+		if _, err := Generate(bits, candidate, test, cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateMillerRabin512(b *testing.B) { benchmarkGenerate(b, millerRabin{}, 512) }
+func BenchmarkGenerateFermat512(b *testing.B)      { benchmarkGenerate(b, fermat{}, 512) }
+
+// Compara o pre-filtro por mdc depois de construir o candidato com a
+// construcao pelo Teorema Chines do Resto
+func BenchmarkConstruction(b *testing.B) {
+	for _, bits := range []int{512, 1024} {
+		for _, c := range []Construction{Increment, CRT} {
+			b.Run(fmt.Sprintf("%s-%d", c, bits), func(b *testing.B) {
+				benchmarkGenerateConfig(b, millerRabin{}, bits, Config{Rounds: 20, Construction: c})
+			})
+		}
+	}
+}
+
+// Compara os backends de exponenciacao na geracao de primos pequenos, em que
+// o custo fixo do math/big domina
+func BenchmarkGenerateSmall(b *testing.B) {
+	for _, bits := range []int{64, 128, 256} {
+		for _, backend := range []struct {
+			name string
+			prng.ModExpBackend
+		}{{"big", prng.BigBackend{}}, {"fixed", prng.FixedBackend{}}} {
+			b.Run(fmt.Sprintf("%s-%d", backend.name, bits), func(b *testing.B) {
+				benchmarkGenerateConfig(b, millerRabin{}, bits, Config{Rounds: 20, Backend: backend.ModExpBackend})
+			})
+		}
+	}
+}
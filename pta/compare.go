@@ -0,0 +1,93 @@
+// Esse arquivo traz a comparacao de varios testes de primalidade aplicados
+//  ao mesmo candidato.
+
+package pta
+
+import (
+	"math/big"
+	"slices"
+	"time"
+)
+
+// Comparison reune, para um mesmo candidato, os resultados da geracao com
+// cada teste e os vereditos de todos os numeros avaliados por eles
+type Comparison struct {
+	Bits      int
+	Candidate *big.Int
+	Tests     []string // nomes dos testes, na ordem de Results
+	Results   []Result // primo gerado a partir do candidato com cada teste
+
+	// Numeros avaliados por todos os testes: Agreed conta os vereditos
+	// iguais e Disagreements traz os numeros com vereditos diferentes
+	Agreed        int
+	Disagreements []*big.Int
+}
+
+// SamePrime informa se todos os testes chegaram ao mesmo primo
+func (c Comparison) SamePrime() bool {
+	for _, res := range c.Results[1:] {
+		if res.Number.Cmp(c.Results[0].Number) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Delta retorna a diferenca entre o tempo do teste i e o do primeiro
+func (c Comparison) Delta(i int) time.Duration {
+	return c.Results[i].Duration - c.Results[0].Duration
+}
+
+// Compare gera um primo de bits bits a partir do candidato com cada um dos
+// testes, como Generate, e compara os vereditos: cada teste percorre os
+// mesmos numeros a partir do candidato, entao os numeros avaliados por
+// todos tem de receber o mesmo veredito. Com cfg.Unique, o primo emitido
+// por um teste eh descartado pelos seguintes, e os primos divergem.
+func Compare(bits int, candidate *big.Int, tests []PrimalityTest, cfg Config) (Comparison, error) {
+	c := Comparison{Bits: bits, Candidate: new(big.Int).Set(candidate)}
+	// verdicts[i] guarda, em decimal, os vereditos do teste i
+	verdicts := make([]map[string]bool, len(tests))
+	for i, test := range tests {
+		seen := make(map[string]bool)
+		verdicts[i] = seen
+		run := cfg
+		run.Hooks = append(slices.Clip(cfg.Hooks), Hooks{
+			OnReject: func(e RejectEvent) error {
+				// Pre-filtro e filtros descartam o candidato antes do teste
+				if e.Reason != RejectPrescreen && e.Reason != RejectFilter {
+					seen[e.Candidate.String()] = e.Reason != RejectComposite
+				}
+				return nil
+			},
+			OnAccept: func(e AcceptEvent) error {
+				seen[e.Result.Number.String()] = true
+				return nil
+			},
+		})
+		res, err := Generate(bits, candidate, test, run)
+		if err != nil {
+			return Comparison{}, err
+		}
+		c.Tests = append(c.Tests, test.Name())
+		c.Results = append(c.Results, res)
+	}
+
+	for n, first := range verdicts[0] {
+		agreed, common := true, true
+		for _, other := range verdicts[1:] {
+			v, ok := other[n]
+			common = common && ok
+			agreed = agreed && v == first
+		}
+		switch {
+		case !common:
+		case agreed:
+			c.Agreed++
+		default:
+			number, _ := new(big.Int).SetString(n, 10)
+			c.Disagreements = append(c.Disagreements, number)
+		}
+	}
+	slices.SortFunc(c.Disagreements, (*big.Int).Cmp)
+	return c, nil
+}
@@ -0,0 +1,44 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+// alwaysPrime eh um teste defeituoso que aprova qualquer numero
+type alwaysPrime struct{}
+
+func (alwaysPrime) Name() string { return "always-prime" }
+
+func (alwaysPrime) IsPrime(n *big.Int, cfg Config) Result {
+	return newResult(n, true, nil, 1, nil, fermatConfidence, 0)
+}
+
+func TestCompare(t *testing.T) {
+	cfg := Config{Security: prng.Permissive}
+	candidate, _ := new(big.Int).SetString("0xc0ffee0123456789abcdef0123456789", 0)
+	c, err := Compare(128, candidate, []PrimalityTest{millerRabin{}, fermat{}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Results) != 2 || c.Tests[0] != "miller-rabin" || c.Tests[1] != "fermat" {
+		t.Fatalf("Compare = %+v", c)
+	}
+	if !c.SamePrime() || c.Agreed == 0 || len(c.Disagreements) != 0 {
+		t.Errorf("MR e Fermat: mesmo primo %v, %d vereditos iguais, divergencias %v", c.SamePrime(), c.Agreed, c.Disagreements)
+	}
+	if c.Results[0].Attempts != c.Results[1].Attempts || c.Candidate.Cmp(candidate) != 0 {
+		t.Errorf("tentativas %d e %d", c.Results[0].Attempts, c.Results[1].Attempts)
+	}
+
+	// Um composto sem fatores pequenos separa os dois testes ja no candidato
+	composite := new(big.Int).Mul(big.NewInt(1000003), big.NewInt(1000033))
+	c, err = Compare(composite.BitLen(), composite, []PrimalityTest{alwaysPrime{}, millerRabin{}}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SamePrime() || c.Agreed != 0 || len(c.Disagreements) != 1 || c.Disagreements[0].Cmp(composite) != 0 {
+		t.Errorf("teste defeituoso: mesmo primo %v, %d vereditos iguais, divergencias %v", c.SamePrime(), c.Agreed, c.Disagreements)
+	}
+}
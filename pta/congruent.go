@@ -0,0 +1,112 @@
+// Esse arquivo gera primos em uma classe de congruencia, percorrendo
+//  apenas os candidatos da classe em vez de filtra-los.
+
+package pta
+
+import (
+	"PrimeNumGenerator/clock"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrNoCongruentPrime indica que nenhum numero do tamanho pedido na classe
+// de congruencia eh primo (ou passa pelas verificacoes de Config)
+var ErrNoCongruentPrime = errors.New("pta: nenhum primo na classe de congruencia")
+
+// GeneratePrimeCongruent gera um primo p de bits bits com p ≡ a (mod m),
+// usando o teste de Miller-Rabin com a configuracao padrao. Por exemplo,
+// a = 3 e m = 4 dao os primos do BBS, e a = 1 e m = 2^k os primos dos
+// modulos da NTT. Veja GenerateCongruent.
+func GeneratePrimeCongruent(bits int, a, m *big.Int) (*big.Int, error) {
+	res, err := GenerateCongruent(bits, a, m, millerRabin{}, Config{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Number, nil
+}
+
+// GenerateCongruent gera um primo p de bits bits com p ≡ a (mod m) usando
+// o teste test com a configuracao cfg. Exige m >= 1 e mdc(a, m) = 1.
+//
+// Diferente de Generate com o filtro Congruent, os candidatos ja nascem na
+// classe: o primeiro eh sorteado com a entropia de cfg e os seguintes
+// avancam de mmc(2, m) em mmc(2, m), de modo que nenhum teste eh gasto com
+// numeros pares ou fora da classe. Ao passar de bits bits, a busca volta ao
+// menor numero da classe com bits bits; se percorrer a classe inteira sem
+// encontrar um primo, retorna ErrNoCongruentPrime.
+func GenerateCongruent(bits int, a, m *big.Int, test PrimalityTest, cfg Config) (Result, error) {
+	inicio := clock.Now()
+	if bits < 2 {
+		return Result{}, fmt.Errorf("pta: tamanho em bits invalido: %d", bits)
+	}
+	if m.Sign() <= 0 {
+		return Result{}, fmt.Errorf("pta: modulo invalido: %s", m)
+	}
+	if new(big.Int).GCD(nil, nil, new(big.Int).Abs(a), m).Cmp(big.NewInt(1)) != 0 {
+		return Result{}, fmt.Errorf("pta: %s e %s nao sao coprimos", a, m)
+	}
+	cfg.Rounds = roundsFor(bits, cfg)
+
+	// A classe de p modulo step = mmc(2, m): r ≡ a (mod m) e r impar. Com m
+	// par, a ja eh impar; com m impar, trocamos r por r + m se preciso.
+	step := new(big.Int).Set(m)
+	r := new(big.Int).Mod(a, m)
+	if m.Bit(0) == 1 {
+		step.Lsh(step, 1)
+		if r.Bit(0) == 0 {
+			r.Add(r, m)
+		}
+	}
+
+	// Os numeros de bits bits estao em [lo, hi)
+	lo := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	hi := new(big.Int).Lsh(lo, 1)
+	first := alignUp(lo, r, step)
+	if first.Cmp(hi) >= 0 {
+		return Result{}, fmt.Errorf("pta: nenhum numero de %d bits e congruente a %s modulo %s", bits, a, m)
+	}
+
+	offset, err := cfg.Entropy().Int(lo)
+	if err != nil {
+		return Result{}, err
+	}
+	candidato := alignUp(offset.Add(offset, lo), r, step)
+	if candidato.Cmp(hi) >= 0 {
+		candidato.Set(first)
+	}
+	start := new(big.Int).Set(candidato)
+
+	tentativas := 0
+	for wrapped := false; ; {
+		tentativas++
+		res, err := evaluate(tentativas, candidato, test, cfg)
+		if err != nil {
+			return Result{}, err
+		}
+		if res.Prime {
+			res.Attempts = tentativas
+			res.Duration = clock.Since(inicio)
+			if err := cfg.onAccept(res); err != nil {
+				return Result{}, err
+			}
+			return res, nil
+		}
+
+		candidato.Add(candidato, step)
+		if candidato.Cmp(hi) >= 0 {
+			candidato.Set(first)
+			wrapped = true
+		}
+		if wrapped && candidato.Cmp(start) >= 0 {
+			return Result{}, ErrNoCongruentPrime
+		}
+	}
+}
+
+// alignUp retorna o menor numero x >= n com x ≡ r (mod step)
+func alignUp(n, r, step *big.Int) *big.Int {
+	delta := new(big.Int).Sub(r, n)
+	delta.Mod(delta, step)
+	return delta.Add(delta, n)
+}
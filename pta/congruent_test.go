@@ -0,0 +1,72 @@
+package pta
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestGeneratePrimeCongruent(t *testing.T) {
+	ntt := new(big.Int).Lsh(big.NewInt(1), 20) // primos da NTT: 1 mod 2^20
+	cases := []struct {
+		bits int
+		a, m *big.Int
+	}{
+		{256, big.NewInt(3), big.NewInt(4)},
+		{128, big.NewInt(1), ntt},
+		{96, big.NewInt(-1), big.NewInt(15)}, // m impar e a negativo
+		{64, big.NewInt(2), big.NewInt(3)},   // a par com m impar
+		{3, big.NewInt(1), big.NewInt(4)},    // apenas 5
+	}
+	for _, c := range cases {
+		p, err := GeneratePrimeCongruent(c.bits, c.a, c.m)
+		if err != nil {
+			t.Fatalf("%d bits, %s mod %s: %v", c.bits, c.a, c.m, err)
+		}
+		if p.BitLen() != c.bits || !p.ProbablyPrime(20) {
+			t.Errorf("%s nao eh um primo de %d bits", p, c.bits)
+		}
+		want := new(big.Int).Mod(c.a, c.m)
+		if new(big.Int).Mod(p, c.m).Cmp(want) != 0 {
+			t.Errorf("%s nao eh congruente a %s modulo %s", p, c.a, c.m)
+		}
+	}
+}
+
+func TestGenerateCongruentConfig(t *testing.T) {
+	// As verificacoes de Config valem como em Generate
+	last7, _ := LastDigit(7)
+	res, err := GenerateCongruent(64, big.NewInt(3), big.NewInt(4), fermat{}, Config{Filters: []CandidateFilter{last7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.Number
+	if new(big.Int).Mod(p, big.NewInt(20)).Int64() != 7 {
+		t.Errorf("%s nao eh 3 mod 4 com final 7", p)
+	}
+	if res.Attempts < 1 || res.Rounds != roundsFor(64, Config{}) {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+}
+
+func TestGenerateCongruentInvalid(t *testing.T) {
+	cases := []struct {
+		bits int
+		a, m *big.Int
+	}{
+		{1, big.NewInt(1), big.NewInt(4)},
+		{64, big.NewInt(1), big.NewInt(0)},
+		{64, big.NewInt(2), big.NewInt(4)},                        // nao coprimos
+		{8, big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 10)},   // so 1025, com 11 bits
+		{16, big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 16)}, // so 65535, com 16 bits, mas composto
+	}
+	for _, c := range cases {
+		if p, err := GeneratePrimeCongruent(c.bits, c.a, c.m); err == nil {
+			t.Errorf("%d bits, %s mod %s: gerou %s", c.bits, c.a, c.m, p)
+		}
+	}
+	// 9 eh o unico numero de 4 bits congruente a 1 modulo 8
+	if _, err := GeneratePrimeCongruent(4, big.NewInt(1), big.NewInt(8)); !errors.Is(err, ErrNoCongruentPrime) {
+		t.Errorf("erro %v, esperado ErrNoCongruentPrime", err)
+	}
+}
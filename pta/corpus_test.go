@@ -0,0 +1,115 @@
+// Esse arquivo traz os conjuntos de numeros primos e compostos conhecidos
+//  usados pelos testes do pacote.
+
+package pta
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Primos MODP das RFCs 2409 (grupo 2) e 3526 (grupos 5 e 14)
+const (
+	modp1024 = `FFFFFFFF FFFFFFFF C90FDAA2 2168C234 C4C6628B 80DC1CD1
+		29024E08 8A67CC74 020BBEA6 3B139B22 514A0879 8E3404DD
+		EF9519B3 CD3A431B 302B0A6D F25F1437 4FE1356D 6D51C245
+		E485B576 625E7EC6 F44C42E9 A637ED6B 0BFF5CB6 F406B7ED
+		EE386BFB 5A899FA5 AE9F2411 7C4B1FE6 49286651 ECE65381
+		FFFFFFFF FFFFFFFF`
+	modp1536 = `FFFFFFFF FFFFFFFF C90FDAA2 2168C234 C4C6628B 80DC1CD1
+		29024E08 8A67CC74 020BBEA6 3B139B22 514A0879 8E3404DD
+		EF9519B3 CD3A431B 302B0A6D F25F1437 4FE1356D 6D51C245
+		E485B576 625E7EC6 F44C42E9 A637ED6B 0BFF5CB6 F406B7ED
+		EE386BFB 5A899FA5 AE9F2411 7C4B1FE6 49286651 ECE45B3D
+		C2007CB8 A163BF05 98DA4836 1C55D39A 69163FA8 FD24CF5F
+		83655D23 DCA3AD96 1C62F356 208552BB 9ED52907 7096966D
+		670C354E 4ABC9804 F1746C08 CA237327 FFFFFFFF FFFFFFFF`
+	modp2048 = `FFFFFFFF FFFFFFFF C90FDAA2 2168C234 C4C6628B 80DC1CD1
+		29024E08 8A67CC74 020BBEA6 3B139B22 514A0879 8E3404DD
+		EF9519B3 CD3A431B 302B0A6D F25F1437 4FE1356D 6D51C245
+		E485B576 625E7EC6 F44C42E9 A637ED6B 0BFF5CB6 F406B7ED
+		EE386BFB 5A899FA5 AE9F2411 7C4B1FE6 49286651 ECE45B3D
+		C2007CB8 A163BF05 98DA4836 1C55D39A 69163FA8 FD24CF5F
+		83655D23 DCA3AD96 1C62F356 208552BB 9ED52907 7096966D
+		670C354E 4ABC9804 F1746C08 CA18217C 32905E46 2E36CE3B
+		E39E772C 180E8603 9B2783A2 EC07A28F B5C55DF0 6F4C52C9
+		DE2BCBF6 95581718 3995497C EA956AE5 15D22618 98FA0510
+		15728E5A 8AACAA68 FFFFFFFF FFFFFFFF`
+)
+
+// mersennePrimeExponents sao expoentes p para os quais 2^p - 1 eh primo
+var mersennePrimeExponents = []uint{2, 3, 5, 7, 13, 17, 19, 31, 61, 89, 107, 127, 521, 607, 1279}
+
+// mersenneCompositeExponents sao expoentes primos p para os quais 2^p - 1 eh composto
+var mersenneCompositeExponents = []uint{11, 23, 29, 37, 41, 43, 47, 53, 59, 67}
+
+// carmichaelNumbers enganam o teste de Fermat para toda base coprima com n
+var carmichaelNumbers = []string{
+	"561", "1105", "1729", "2465", "2821", "6601", "8911", "41041", "62745",
+	"294409",    // 37 * 73 * 109
+	"56052361",  // 211 * 421 * 631
+	"118901521", // 271 * 541 * 811
+	// Tambem pseudoprimos fortes para as menores bases primas
+	"3215031751",    // bases 2, 3, 5 e 7
+	"2152302898747", // bases 2 a 11
+}
+
+// strongPseudoprimes passam no Miller-Rabin para as menores bases primas
+var strongPseudoprimes = []string{
+	"2047", "3277", "4033", "4681", "8321", // base 2
+	"1373653",  // bases 2 e 3
+	"25326001", // bases 2, 3 e 5
+}
+
+// primeCase eh uma entrada das tabelas de teste
+type primeCase struct {
+	name  string
+	n     *big.Int
+	prime bool
+}
+
+func mustInt(t testing.TB, s string, base int) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(strings.Join(strings.Fields(s), ""), base)
+	if !ok {
+		t.Fatalf("numero invalido: %q", s)
+	}
+	return n
+}
+
+func mersenne(p uint) *big.Int {
+	n := new(big.Int).Lsh(big.NewInt(1), p)
+	return n.Sub(n, big.NewInt(1))
+}
+
+// knownCases retorna os casos em que tanto o Miller-Rabin quanto o Fermat
+// devem acertar o veredito (os numeros de Carmichael ficam de fora, pois
+// o Fermat pode ser enganado por eles)
+func knownCases(t testing.TB) []primeCase {
+	cases := []primeCase{
+		{"zero", big.NewInt(0), false},
+		{"um", big.NewInt(1), false},
+		{"dois", big.NewInt(2), true},
+		{"tres", big.NewInt(3), true},
+		{"quatro", big.NewInt(4), false},
+		{"cinco", big.NewInt(5), true},
+		{"nove", big.NewInt(9), false},
+		{"negativo", big.NewInt(-7), false},
+		{"par grande", new(big.Int).Lsh(big.NewInt(1), 512), false},
+		{"modp1024", mustInt(t, modp1024, 16), true},
+		{"modp1536", mustInt(t, modp1536, 16), true},
+		{"modp2048", mustInt(t, modp2048, 16), true},
+		{"produto de primos MODP", new(big.Int).Mul(mustInt(t, modp1024, 16), mustInt(t, modp1536, 16)), false},
+	}
+	for _, p := range mersennePrimeExponents {
+		cases = append(cases, primeCase{"M" + big.NewInt(int64(p)).String(), mersenne(p), true})
+	}
+	for _, p := range mersenneCompositeExponents {
+		cases = append(cases, primeCase{"M" + big.NewInt(int64(p)).String(), mersenne(p), false})
+	}
+	for _, s := range strongPseudoprimes {
+		cases = append(cases, primeCase{"spsp " + s, mustInt(t, s, 10), false})
+	}
+	return cases
+}
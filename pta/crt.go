@@ -0,0 +1,131 @@
+// Esse arquivo traz a construcao dos candidatos pelo Teorema Chines do
+//  Resto, que ja nascem sem fatores pequenos em vez de serem filtrados.
+
+package pta
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/numutil"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Construction eh a estrategia de Generate para percorrer os candidatos
+type Construction int
+
+const (
+	// Increment testa o candidato, o candidato + 2, + 4... e descarta com
+	// o pre-filtro por mdc os que tem fatores pequenos. Eh o padrao.
+	Increment Construction = iota
+	// CRT escolhe, para cada um dos primos pequenos do pre-filtro, um
+	// residuo nao nulo, combina os residuos pelo Teorema Chines do Resto e
+	// percorre apenas os numeros com esses residuos: nenhum candidato tem
+	// fator entre esses primos, e o pre-filtro nao os descarta depois
+	CRT
+)
+
+func (c Construction) String() string {
+	switch c {
+	case Increment:
+		return "increment"
+	case CRT:
+		return "crt"
+	}
+	return fmt.Sprintf("Construction(%d)", int(c))
+}
+
+// ParseConstruction converte "increment" ou "crt" na estrategia
+// correspondente
+func ParseConstruction(s string) (Construction, error) {
+	switch s {
+	case "increment":
+		return Increment, nil
+	case "crt":
+		return CRT, nil
+	}
+	return 0, fmt.Errorf("pta: construcao de candidatos desconhecida %q: use increment ou crt", s)
+}
+
+// CRTPrimes retorna os primos usados pela construcao CRT para numeros de
+// bits bits: os primeiros primos do pre-filtro de cfg cujo produto M tenha
+// no maximo bits/2 bits, para que a classe escolhida tenha cerca de
+// 2^(bits/2) numeros de bits bits, muito mais que os candidatos testados
+// ate encontrar um primo. Os primos do pre-filtro que ficam de fora
+// continuam verificados por mdc.
+func CRTPrimes(bits int, cfg Config) []int {
+	k := cfg.PrescreenPrimes()
+	for k > 0 && numutil.Primorial(k).BitLen() > bits/2 {
+		k--
+	}
+	return numutil.FirstPrimes(k)
+}
+
+// generateCRT implementa Generate com a construcao CRT. Os residuos vem do
+// proprio candidato c: o residuo de c modulo p, se nao for nulo, ou senao
+// um dos p - 1 residuos nao nulos escolhido pelo digito seguinte de c na
+// base p. Com c uniforme, o residuo eh uniforme entre os nao nulos, e a
+// busca, como com Increment, depende apenas do candidato. Ela parte do
+// primeiro numero da classe a partir de c, que eh o proprio c se ele nao
+// tiver fatores pequenos, e avanca de M em M; ao passar de bits bits,
+// volta ao menor numero da classe com bits bits.
+func generateCRT(inicio time.Time, bits int, candidato *big.Int, test PrimalityTest, cfg Config) (Result, error) {
+	if bits < 2 {
+		return Result{}, fmt.Errorf("pta: tamanho em bits invalido: %d", bits)
+	}
+	// Os numeros de bits bits estao em [lo, hi)
+	lo := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	hi := new(big.Int).Lsh(lo, 1)
+	if candidato.Cmp(lo) < 0 {
+		candidato.Set(lo)
+	}
+
+	primes := CRTPrimes(bits, cfg)
+	residues := make([]*big.Int, len(primes))
+	moduli := make([]*big.Int, len(primes))
+	for i, p := range primes {
+		moduli[i] = big.NewInt(int64(p))
+		q, r := new(big.Int).QuoRem(candidato, moduli[i], new(big.Int))
+		if r.Sign() == 0 {
+			r.Mod(q, big.NewInt(int64(p-1))).Add(r, big.NewInt(1))
+		}
+		residues[i] = r
+	}
+	r, m, err := numutil.CRT(residues, moduli)
+	if err != nil {
+		return Result{}, err
+	}
+
+	first := alignUp(lo, r, m)
+	candidato = alignUp(candidato, r, m)
+	if candidato.Cmp(hi) >= 0 {
+		candidato.Set(first)
+	}
+	start := new(big.Int).Set(candidato)
+
+	tentativas := 0
+	for wrapped := false; ; {
+		tentativas++
+		res, err := evaluate(tentativas, candidato, test, cfg)
+		if err != nil {
+			return Result{}, err
+		}
+		if res.Prime {
+			res.Attempts = tentativas
+			res.Duration = clock.Since(inicio)
+			if err := cfg.onAccept(res); err != nil {
+				return Result{}, err
+			}
+			return res, nil
+		}
+
+		candidato.Add(candidato, m)
+		if candidato.Cmp(hi) >= 0 {
+			candidato.Set(first)
+			wrapped = true
+		}
+		if wrapped && candidato.Cmp(start) >= 0 {
+			return Result{}, ErrNoCongruentPrime
+		}
+	}
+}
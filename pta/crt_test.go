@@ -0,0 +1,57 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+func TestGenerateCRT(t *testing.T) {
+	for _, bits := range []int{3, 16, 64, 512} {
+		primes := CRTPrimes(bits, Config{})
+		modulus := big.NewInt(1)
+		for _, p := range primes {
+			modulus.Mul(modulus, big.NewInt(int64(p)))
+		}
+		if modulus.BitLen() > bits/2 {
+			t.Errorf("%d bits: produto dos primos com %d bits", bits, modulus.BitLen())
+		}
+
+		cfg := Config{
+			Security:     prng.Permissive,
+			Construction: CRT,
+			Hooks: []Hooks{{
+				OnCandidate: func(ev CandidateEvent) error {
+					if g := new(big.Int).GCD(nil, nil, ev.Candidate, modulus); g.Cmp(big.NewInt(1)) != 0 {
+						t.Errorf("%d bits: candidato %s com fator %s", bits, ev.Candidate, g)
+					}
+					return nil
+				},
+			}},
+		}
+		// Um candidato acima do maior numero de bits bits volta ao inicio
+		start := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		middle := new(big.Int).Div(start, big.NewInt(3))
+		for _, candidate := range []*big.Int{big.NewInt(0), start, middle} {
+			res, err := Generate(bits, candidate, millerRabin{}, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Number.BitLen() != bits || !res.Number.ProbablyPrime(20) {
+				t.Errorf("%d bits: %s nao eh um primo de %d bits", bits, res.Number, bits)
+			}
+			// A busca depende apenas do candidato
+			again, err := Generate(bits, candidate, millerRabin{}, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.Number.Cmp(res.Number) != 0 || again.Attempts != res.Attempts {
+				t.Errorf("%d bits: buscas diferentes a partir de %s", bits, candidate)
+			}
+		}
+	}
+
+	if _, err := ParseConstruction("sieve"); err == nil {
+		t.Error("ParseConstruction aceitou uma construcao desconhecida")
+	}
+}
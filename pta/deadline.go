@@ -0,0 +1,71 @@
+// Esse arquivo traz a geracao de primos com prazo, que prefere um primo um
+//  pouco menor a nenhum.
+
+package pta
+
+import (
+	"PrimeNumGenerator/clock"
+	"errors"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// ErrDeadline indica que o prazo de GenerateBefore acabou antes de algum
+// primo ser encontrado
+var ErrDeadline = errors.New("pta: prazo esgotado sem encontrar um primo")
+
+// GenerateBefore busca, como Generate, um primo de bits bits a partir de um
+// candidato de next(bits), desistindo quando deadline passar. Com minBits
+// entre 2 e bits - 1, a busca comeca por um primo de reserva de minBits
+// bits, que eh retornado se o prazo acabar durante a busca do primo de bits
+// bits: para servicos com limite de latencia, um primo um pouco menor eh
+// melhor que nenhum. Sem reserva, ou se o prazo acabar antes dela, o erro
+// eh ErrDeadline.
+//
+// O prazo eh verificado a cada candidato, entao pode ser ultrapassado pelo
+// tempo de um teste. Attempts e Duration do resultado somam as duas buscas,
+// mesmo quando o primo retornado eh o de reserva. Com cfg.Unique, o primo
+// de reserva fica registrado mesmo quando nao eh usado.
+func GenerateBefore(deadline time.Time, bits, minBits int, next func(bits int) (*big.Int, error), test PrimalityTest, cfg Config) (Result, error) {
+	inicio := clock.Now()
+	errExpired := errors.New("prazo esgotado")
+	tentativas := 0
+	cfg.Hooks = append(slices.Clip(cfg.Hooks), Hooks{
+		OnCandidate: func(CandidateEvent) error {
+			if clock.Now().After(deadline) {
+				return errExpired
+			}
+			tentativas++
+			return nil
+		},
+	})
+	search := func(size int) (Result, error) {
+		candidate, err := next(size)
+		if err != nil {
+			return Result{}, err
+		}
+		res, err := Generate(size, candidate, test, cfg)
+		if errors.Is(err, errExpired) {
+			return Result{}, ErrDeadline
+		}
+		return res, err
+	}
+
+	var reserve Result
+	if minBits >= 2 && minBits < bits {
+		var err error
+		if reserve, err = search(minBits); err != nil {
+			return Result{}, err
+		}
+	}
+	res, err := search(bits)
+	if errors.Is(err, ErrDeadline) && reserve.Number != nil {
+		res, err = reserve, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	res.Attempts, res.Duration = tentativas, clock.Since(inicio)
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// smallOnly reprova, devagar, os numeros com mais de 64 bits, simulando
+// uma busca que nao termina dentro do prazo
+type smallOnly struct{}
+
+func (smallOnly) Name() string { return "small-only" }
+
+func (smallOnly) IsPrime(n *big.Int, cfg Config) Result {
+	if n.BitLen() > 64 {
+		time.Sleep(time.Millisecond)
+		return newResult(n, false, big.NewInt(2), 1, nil, millerRabinConfidence, 0)
+	}
+	return millerRabin{}.IsPrime(n, cfg)
+}
+
+func randomBits(bits int) (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+}
+
+func TestGenerateBefore(t *testing.T) {
+	cfg := Config{Security: prng.Permissive}
+
+	// Com folga no prazo, o primo tem o tamanho pedido
+	res, err := GenerateBefore(time.Now().Add(time.Minute), 256, 128, randomBits, millerRabin{}, cfg)
+	if err != nil || res.Number.BitLen() != 256 || !res.Prime {
+		t.Fatalf("GenerateBefore = %v bits, %v", res.Number.BitLen(), err)
+	}
+	if res.Attempts < 2 {
+		t.Errorf("Attempts = %d, sem contar a reserva", res.Attempts)
+	}
+
+	// Sem tempo para o primo de 256 bits, vem o de reserva
+	start := time.Now()
+	res, err = GenerateBefore(start.Add(50*time.Millisecond), 256, 48, randomBits, smallOnly{}, cfg)
+	if err != nil || res.Number.BitLen() != 48 || !res.Number.ProbablyPrime(20) {
+		t.Fatalf("com reserva: %v, %v", res.Number, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("prazo de 50ms ultrapassado: %s", elapsed)
+	}
+
+	// Sem reserva, o prazo esgotado eh um erro
+	if _, err := GenerateBefore(time.Now().Add(20*time.Millisecond), 256, 0, randomBits, smallOnly{}, cfg); !errors.Is(err, ErrDeadline) {
+		t.Errorf("sem reserva: %v", err)
+	}
+	if _, err := GenerateBefore(time.Now().Add(-time.Second), 256, 48, randomBits, millerRabin{}, cfg); !errors.Is(err, ErrDeadline) {
+		t.Errorf("prazo vencido: %v", err)
+	}
+}
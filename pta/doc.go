@@ -0,0 +1,13 @@
+// O pacote pta implementa os testes de primalidade de Fermat e de
+// Miller-Rabin e a geracao de primos a partir de um candidato.
+//
+// Nenhuma funcao do pacote altera os *big.Int recebidos, e os numeros
+// retornados (como Result.Number) sao sempre valores novos: um mesmo
+// candidato pode ser passado a varios testes, ou guardado, sem copia
+// previa. A excecao sao os geradores de Config.Witnesses e as fontes de
+// entropia, cujo estado avanca a cada base sorteada.
+//
+// A API exportada segue o versionamento semantico, com as mesmas garantias
+// de prng e numutil: nenhuma versao menor remove ou altera um simbolo
+// listado em api/v1.txt.
+package pta
@@ -0,0 +1,112 @@
+// Esse arquivo define os filtros de candidatos, que restringem a forma dos
+//  primos gerados sem alterar o laco de busca.
+
+package pta
+
+import (
+	"PrimeNumGenerator/audit"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// CandidateFilter restringe a forma dos primos gerados: Generate so testa
+// os candidatos aceitos por todos os filtros de Config.Filters e segue a
+// busca a partir dos demais. Como o teste de primalidade vem depois,
+// Accept deve ser barato perto dele.
+type CandidateFilter interface {
+	Accept(n *big.Int) bool
+}
+
+// FilterFunc adapta uma funcao a interface CandidateFilter
+type FilterFunc func(n *big.Int) bool
+
+// Accept implementa CandidateFilter
+func (f FilterFunc) Accept(n *big.Int) bool {
+	return f(n)
+}
+
+// LastDigit aceita os numeros cujo ultimo digito decimal eh d. Apenas 1,
+// 3, 7 e 9 sao aceitos, os unicos finais de primos maiores que 5.
+func LastDigit(d int) (CandidateFilter, error) {
+	if d != 1 && d != 3 && d != 7 && d != 9 {
+		return nil, fmt.Errorf("pta: nenhum primo grande termina em %d", d)
+	}
+	ten, digit := big.NewInt(10), big.NewInt(int64(d))
+	return FilterFunc(func(n *big.Int) bool {
+		return new(big.Int).Mod(n, ten).Cmp(digit) == 0
+	}), nil
+}
+
+// Congruent aceita os numeros n com n ≡ r (mod m). Exige m >= 2 e
+// mdc(r, m) = 1, sem o qual quase nenhum primo estaria na classe.
+func Congruent(r, m *big.Int) (CandidateFilter, error) {
+	if m.Cmp(big.NewInt(2)) < 0 {
+		return nil, fmt.Errorf("pta: modulo invalido: %s", m)
+	}
+	if new(big.Int).GCD(nil, nil, r, m).Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("pta: %s e %s nao sao coprimos", r, m)
+	}
+	m = new(big.Int).Set(m)
+	r = new(big.Int).Mod(r, m)
+	return FilterFunc(func(n *big.Int) bool {
+		return new(big.Int).Mod(n, m).Cmp(r) == 0
+	}), nil
+}
+
+// NotSmooth rejeita os numeros p cujo p - 1 seja suave em relacao a bound,
+// que tornariam um modulo RSA com p fatoravel pelo metodo p - 1 de Pollard
+// (veja audit.SmoothnessReport; Config.SmoothnessBound tambem verifica
+// p + 1)
+func NotSmooth(bound int) CandidateFilter {
+	return FilterFunc(func(n *big.Int) bool {
+		return !audit.SmoothnessReport(n, bound).PollardVulnerable()
+	})
+}
+
+// ParseFilter cria um filtro a partir de sua descricao textual:
+//
+//	last-digit=D    ultimo digito decimal D (veja LastDigit)
+//	mod=R/M         n ≡ R (mod M) (veja Congruent)
+//	not-smooth=B    p - 1 nao suave em relacao a B (veja NotSmooth)
+func ParseFilter(spec string) (CandidateFilter, error) {
+	name, value, ok := strings.Cut(spec, "=")
+	if !ok {
+		return nil, fmt.Errorf("pta: filtro %q sem valor", spec)
+	}
+	switch name {
+	case "last-digit":
+		d, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("pta: digito invalido %q", value)
+		}
+		return LastDigit(d)
+	case "mod":
+		rs, ms, ok := strings.Cut(value, "/")
+		r, okR := new(big.Int).SetString(rs, 0)
+		m, okM := new(big.Int).SetString(ms, 0)
+		if !ok || !okR || !okM {
+			return nil, fmt.Errorf("pta: congruencia invalida %q: use R/M", value)
+		}
+		return Congruent(r, m)
+	case "not-smooth":
+		bound, err := strconv.Atoi(value)
+		if err != nil || bound < 2 {
+			return nil, fmt.Errorf("pta: limite de suavidade invalido %q", value)
+		}
+		return NotSmooth(bound), nil
+	default:
+		return nil, fmt.Errorf("pta: filtro desconhecido %q: use last-digit, mod ou not-smooth", name)
+	}
+}
+
+// accepted informa se n passa por todos os filtros
+func accepted(n *big.Int, filters []CandidateFilter) bool {
+	for _, f := range filters {
+		if !f.Accept(n) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package pta
+
+import (
+	"PrimeNumGenerator/audit"
+	"math/big"
+	"testing"
+)
+
+func TestGenerateWithFilters(t *testing.T) {
+	var filters []CandidateFilter
+	for _, spec := range []string{"last-digit=7", "mod=3/4", "not-smooth=1000"} {
+		f, err := ParseFilter(spec)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", spec, err)
+		}
+		filters = append(filters, f)
+	}
+
+	for i := 0; i < 5; i++ {
+		candidate, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+		candidate.Add(candidate, big.NewInt(int64(1000*i)))
+		res, err := MillerRabin(candidate, 97, Config{Filters: filters})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := res.Number
+		if !p.ProbablyPrime(20) {
+			t.Fatalf("%s nao eh primo", p)
+		}
+		if new(big.Int).Mod(p, big.NewInt(10)).Int64() != 7 || new(big.Int).Mod(p, big.NewInt(4)).Int64() != 3 {
+			t.Errorf("%s nao termina em 7 ou nao eh 3 mod 4", p)
+		}
+		if audit.SmoothnessReport(p, 1000).PollardVulnerable() {
+			t.Errorf("%s tem p - 1 suave", p)
+		}
+	}
+}
+
+func TestFilterFunc(t *testing.T) {
+	// Um filtro proprio: apenas primos com peso de Hamming par
+	even := FilterFunc(func(n *big.Int) bool {
+		ones := 0
+		for i := 0; i < n.BitLen(); i++ {
+			ones += int(n.Bit(i))
+		}
+		return ones%2 == 0
+	})
+	res, err := Fermat(big.NewInt(1<<40), 41, Config{Filters: []CandidateFilter{even}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !even.Accept(res.Number) {
+		t.Errorf("%s desrespeita o filtro", res.Number)
+	}
+}
+
+func TestParseFilterInvalid(t *testing.T) {
+	for _, spec := range []string{"last-digit=5", "last-digit=x", "mod=2/4", "mod=3", "mod=1/1", "not-smooth=1", "prime", "shape=1"} {
+		if _, err := ParseFilter(spec); err == nil {
+			t.Errorf("ParseFilter(%q) aceito", spec)
+		}
+	}
+}
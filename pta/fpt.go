@@ -4,101 +4,108 @@
 package pta
 
 import (
-	"crypto/rand"
-	"fmt"
+	"PrimeNumGenerator/prng"
 	"math/big"
-	"time"
 )
 
 // FermatTest verifica se um numero eh provavelmente primo
 // usando o teste de primalidade de Fermat.
 // k eh o numero de iteracoes para aumentar a confiabilidade
+//
+// Em caso de falha da fonte de entropia, as bases sao escolhidas no nivel
+// prng.Permissive, preservando o comportamento original da funcao.
 func FermatTest(n *big.Int, k int) bool {
+	prime, _, _, _ := fermatTest(n, k, Config{Security: prng.Permissive})
+	return prime
+}
+
+// fermatTest executa o teste de Fermat e retorna, alem do veredito,
+// a testemunha que provou que n eh composto (nil se nao houver) e o numero
+// de iteracoes efetivamente executadas. As bases sao sorteadas a partir da
+// entropia de cfg; se a fonte de entropia falhar, o erro eh retornado.
+func fermatTest(n *big.Int, k int, cfg Config) (bool, *big.Int, int, error) {
+	prime, witness, rounds, err := runFermat(n, k, cfg)
+	if err == nil && validationEnabled() {
+		crossValidate("fermat", n, prime)
+	}
+	return prime, witness, rounds, err
+}
+
+// runFermat contem a implementacao do teste usada por fermatTest
+func runFermat(n *big.Int, k int, cfg Config) (bool, *big.Int, int, error) {
 	// Tratamento de casos especiais
 	if n.Cmp(big.NewInt(2)) == 0 || n.Cmp(big.NewInt(3)) == 0 {
-		return true
+		return true, nil, 0, nil
 	}
 	if n.Cmp(big.NewInt(2)) < 0 || new(big.Int).Mod(n, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		return false
+		return false, nil, 0, nil
 	}
 
 	one := big.NewInt(1)
 	nMinus1 := new(big.Int).Sub(n, one)
+	backend := prng.Prepare(cfg.Backend, n)
+
+	if cfg.ConstantTime {
+		// Executamos todas as iteracoes, sem sair cedo, para que o tempo
+		// gasto nao revele em qual base n foi reprovado. Como todas as
+		// bases sao usadas, as exponenciacoes sao calculadas em um lote.
+		bases := make([]*big.Int, k)
+		exps := make([]*big.Int, k)
+		for i := range bases {
+			a, err := randomBase(n, cfg) // Garante que 2 <= a <= n-2
+			if err != nil {
+				return false, nil, i, err
+			}
+			bases[i], exps[i] = a, nMinus1
+		}
+		var witness *big.Int
+		for i, result := range backend.ExpBatch(bases, exps, n) {
+			passed := result.Cmp(one) == 0
+			prng.WipeInt(result)
+			if !passed && witness == nil {
+				witness = bases[i]
+			} else {
+				prng.WipeInt(bases[i])
+			}
+		}
+		if witness != nil {
+			return false, witness, k, nil
+		}
+		return true, nil, k, nil
+	}
 
 	for i := 0; i < k; i++ {
-		nMinus2 := new(big.Int).Sub(n, big.NewInt(2))
-		a, err := rand.Int(rand.Reader, nMinus2)
+		a, err := randomBase(n, cfg) // Garante que 2 <= a <= n-2
 		if err != nil {
-			t := time.Now().UnixNano()
-			a = big.NewInt(t % nMinus2.Int64())
+			return false, nil, i, err
 		}
-		a.Add(a, big.NewInt(2)) // Garante que a >= 2
 
 		// Calculamos a^(n-1) mod n
-		result := new(big.Int).Exp(a, nMinus1, n)
+		result := backend.Exp(a, nMinus1, n)
+
+		passed := result.Cmp(one) == 0
+		if err := cfg.onRound("fermat", n, i+1, a, passed); err != nil {
+			return false, nil, i + 1, err
+		}
 
 		// Se o resultado != 1, entao definitivamente  eh composto
-		if result.Cmp(one) != 0 {
-			return false
+		if !passed {
+			return false, a, i + 1, nil
 		}
 	}
-
-	return true // Provavelmente primo
+	return true, nil, k, nil // Provavelmente primo
 }
 
 // GeneratePrimeNumberFermat gera um numero primo com o tamanho de bits especificado
-// usando o Teste de Primalidade de Fermat.
+// usando o Teste de Primalidade de Fermat, no nivel prng.Permissive
 func GeneratePrimeNumberFemart(bits int, candidato *big.Int) (*big.Int, int) {
-	tentativas := 0
-	for {
-		tentativas++
-
-		for candidato.BitLen() < bits {
-			candidato.SetBit(candidato, bits-1, 1)
-		}
-
-		if candidato.Bit(0) == 0 {
-			candidato.SetBit(candidato, 0, 1)
-		}
-
-		iteracoes := 20
-		if bits > 256 {
-			iteracoes = 30
-		}
-		if bits > 1024 {
-			iteracoes = 40
-		}
-
-		if FermatTest(candidato, iteracoes) {
-			return candidato, tentativas
-		}
-
-		candidato.Add(candidato, big.NewInt(2))
-	}
+	res, _ := Generate(bits, candidato, fermat{}, Config{Security: prng.Permissive})
+	return res.Number, res.Attempts
 }
 
 // ======= Funcao chamada externamente =======
-func Fermat(candidate *big.Int, bits int) *big.Int {
-	fmt.Println("\nGerando número primo usando Fermat")
-	fmt.Println("===================================")
-
-	inicio := time.Now()
-
-	prime, tentativas := GeneratePrimeNumberFemart(bits, candidate)
-
-	duracao := time.Since(inicio)
-
-	fmt.Printf("- Número gerado: %d bits\n", bits)
-	fmt.Printf("- Tempo de execução: %s\n", duracao)
-	fmt.Printf("- Tentativas: %d\n", tentativas)
-	fmt.Printf("- Tamanho do número gerado: %d dígitos\n", len(prime.String()))
-
-	bitLength := prime.BitLen()
-	fmt.Printf("- Tamanho real: %d bits\n", bitLength)
-	fmt.Printf("- Valor decimal: %s\n", prime.String())
-
-	binStr := fmt.Sprintf("%b", prime)
-	fmt.Printf("- Binário: %s\n", binStr)
-
-	return prime
+// Fermat gera um numero primo de bits bits a partir do candidato
+// usando o teste de Fermat e retorna o resultado da geracao.
+func Fermat(candidate *big.Int, bits int, cfg Config) (Result, error) {
+	return Generate(bits, candidate, fermat{}, cfg)
 }
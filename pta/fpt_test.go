@@ -0,0 +1,55 @@
+package pta
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestFermatKnownNumbers(t *testing.T) {
+	for _, tc := range knownCases(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FermatTest(tc.n, 20); got != tc.prime {
+				t.Errorf("FermatTest(%s) = %t, esperado %t", tc.n, got, tc.prime)
+			}
+		})
+	}
+}
+
+// Os numeros de Carmichael satisfazem a^(n-1) = 1 (mod n) para toda base
+// coprima com n; o teste de Fermat so os rejeita quando sorteia uma base
+// com fator comum. Verificamos aqui a propriedade que explica essa fraqueza.
+func TestFermatCarmichaelWeakness(t *testing.T) {
+	one := big.NewInt(1)
+	for _, s := range carmichaelNumbers {
+		n := mustInt(t, s, 10)
+		nMinus1 := new(big.Int).Sub(n, one)
+		for _, a := range []int64{2, 5, 13, 101} {
+			base := big.NewInt(a)
+			if new(big.Int).GCD(nil, nil, base, n).Cmp(one) != 0 {
+				continue
+			}
+			if new(big.Int).Exp(base, nMinus1, n).Cmp(one) != 0 {
+				t.Errorf("%s nao se comporta como numero de Carmichael na base %d", s, a)
+			}
+		}
+	}
+}
+
+func TestGeneratePrimeNumberFermatProperty(t *testing.T) {
+	for _, bits := range []int{40, 56, 80, 128, 256, 512} {
+		for i := 0; i < 5; i++ {
+			candidate, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			prime, _ := GeneratePrimeNumberFemart(bits, candidate)
+			if !prime.ProbablyPrime(64) {
+				t.Errorf("%d bits: %s nao passa em ProbablyPrime", bits, prime)
+			}
+			if prime.BitLen() < bits {
+				t.Errorf("%d bits: primo gerado tem %d bits", bits, prime.BitLen())
+			}
+		}
+	}
+}
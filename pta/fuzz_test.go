@@ -0,0 +1,60 @@
+package pta
+
+import (
+	"math/big"
+	"testing"
+)
+
+// maxFuzzBytes limita o tamanho dos numeros testados para manter o fuzzing rapido
+const maxFuzzBytes = 128
+
+func fuzzSeeds(f *testing.F) {
+	for _, s := range []string{"0", "1", "2", "3", "4", "561", "2047", "1373653", "25326001"} {
+		n, _ := new(big.Int).SetString(s, 10)
+		f.Add(n.Bytes(), false)
+	}
+	f.Add(mersenne(127).Bytes(), false)
+	f.Add(mersenne(67).Bytes(), true)
+}
+
+func fuzzInput(data []byte, negative bool) *big.Int {
+	if len(data) > maxFuzzBytes {
+		data = data[:maxFuzzBytes]
+	}
+	n := new(big.Int).SetBytes(data)
+	if negative {
+		n.Neg(n)
+	}
+	return n
+}
+
+// FuzzMillerRabin verifica que o Miller-Rabin concorda com ProbablyPrime
+func FuzzMillerRabin(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte, negative bool) {
+		n := fuzzInput(data, negative)
+		got := MillerRabinTest(n, 20)
+		if want := n.Sign() > 0 && n.ProbablyPrime(20); got != want {
+			t.Errorf("MillerRabinTest(%s) = %t, ProbablyPrime = %t", n, got, want)
+		}
+	})
+}
+
+// FuzzFermat verifica que o teste de Fermat nunca rejeita um primo. O
+// contrario nao eh exigido, pois numeros de Carmichael podem engana-lo.
+func FuzzFermat(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte, negative bool) {
+		n := fuzzInput(data, negative)
+		got := FermatTest(n, 20)
+		if n.Sign() <= 0 {
+			if got {
+				t.Errorf("FermatTest(%s) = true para numero nao positivo", n)
+			}
+			return
+		}
+		if !got && n.ProbablyPrime(20) {
+			t.Errorf("FermatTest rejeitou o primo %s", n)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+// Esse arquivo traz o laco de geracao de primos compartilhado por todos os
+//  testes de primalidade.
+
+package pta
+
+import (
+	"PrimeNumGenerator/audit"
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"math/big"
+)
+
+// Generate gera um numero primo com o tamanho de bits especificado a partir
+// do candidato, usando o teste test com a configuracao cfg. A busca parte
+// de uma copia do candidato, que nao eh alterado, e o primo de res.Number
+// eh sempre um valor novo. No nivel prng.Strict, os primos reprovados por
+// audit.Audit sao descartados. Retorna erro se o teste falhar, por exemplo por
+// falta de entropia no nivel prng.Strict.
+//
+// Por padrao, a busca percorre o candidato, o candidato + 2, + 4...; com
+// cfg.Construction igual a CRT, percorre apenas os numeros sem fatores
+// entre os primos pequenos, a partir do candidato (veja Construction).
+func Generate(bits int, candidato *big.Int, test PrimalityTest, cfg Config) (Result, error) {
+	inicio := clock.Now()
+	candidato = new(big.Int).Set(candidato)
+
+	// O numero de iteracoes varia conforme o tamanho para aumentar a confiabilidade
+	cfg.Rounds = roundsFor(bits, cfg)
+	if cfg.Construction == CRT {
+		return generateCRT(inicio, bits, candidato, test, cfg)
+	}
+
+	tentativas := 0
+	for {
+		tentativas++
+
+		// Garantindo que o candidato tenha a quantidade de bits correto
+		for candidato.BitLen() < bits {
+			candidato.SetBit(candidato, bits-1, 1)
+		}
+
+		// Garantindo que o numero eh impar (um requisito para primos > 2)
+		if candidato.Bit(0) == 0 {
+			candidato.SetBit(candidato, 0, 1)
+		}
+
+		res, err := evaluate(tentativas, candidato, test, cfg)
+		if err != nil {
+			return Result{}, err
+		}
+		if res.Prime {
+			res.Attempts = tentativas
+			res.Duration = clock.Since(inicio)
+			if err := cfg.onAccept(res); err != nil {
+				return Result{}, err
+			}
+			return res, nil
+		}
+
+		// Se nao for primo, incrementa por 2 e tentar novamente
+		// Isto eh mais eficiente que gerar um novo numero aleatorio a cada tentativa
+		// E como estamos usando o BBS e o LFG para gerar o candidato,
+		// opto por nao "resetar" o gerador de numeros aleatorios
+		candidato.Add(candidato, big.NewInt(2))
+	}
+}
+
+// evaluate avalia o candidato de numero tentativa da busca, chamando os
+// ganchos de cfg: res.Prime eh true se ele for o primo procurado
+func evaluate(tentativa int, candidato *big.Int, test PrimalityTest, cfg Config) (Result, error) {
+	if err := cfg.onCandidate(tentativa, candidato); err != nil {
+		return Result{}, err
+	}
+	res, reason := Result{}, screened(candidato, cfg)
+	if reason == "" {
+		var err error
+		if res, reason, err = check(candidato, test, cfg); err != nil {
+			return Result{}, err
+		}
+	}
+	if reason != "" {
+		return Result{}, cfg.onReject(tentativa, candidato, reason, res.Witness)
+	}
+	return res, nil
+}
+
+// screened verifica se o candidato passa pelas verificacoes baratas que
+// antecedem o teste, o pre-filtro e os filtros de cfg.Filters, e retorna o
+// motivo da rejeicao ("" se passar)
+func screened(candidato *big.Int, cfg Config) RejectReason {
+	// Pre-filtro: um unico mdc com o primorial descarta os candidatos com
+	// fatores pequenos sem gastar as iteracoes do teste
+	if numutil.HasSmallFactor(candidato, cfg.PrescreenPrimes()) {
+		return RejectPrescreen
+	}
+	// Os filtros de forma tambem vem antes do teste
+	if !accepted(candidato, cfg.Filters) {
+		return RejectFilter
+	}
+	return ""
+}
+
+// check aplica o teste ao candidato e as verificacoes de cfg que so valem
+// para primos. Um candidato descartado tem res.Prime false e o motivo em
+// reason.
+func check(candidato *big.Int, test PrimalityTest, cfg Config) (res Result, reason RejectReason, err error) {
+	res = test.IsPrime(candidato, cfg)
+	switch {
+	case res.Err != nil:
+		return Result{}, "", res.Err
+	case !res.Prime:
+		reason = RejectComposite
+	case cfg.SmoothnessBound > 0 && audit.SmoothnessReport(candidato, cfg.SmoothnessBound).Vulnerable():
+		// Primo fraco contra os ataques p - 1 e p + 1: seguimos a busca
+		reason = RejectSmooth
+	case cfg.Security == prng.Strict && audit.Audit(candidato) != nil:
+		// No nivel Strict tambem descartamos os primos com padroes fracos
+		// conhecidos, como a estrutura do ROCA
+		reason = RejectAudit
+	case cfg.Unique != nil:
+		// Por ultimo, para registrar apenas o primo que sera emitido
+		added, err := cfg.Unique.Add(candidato)
+		if err != nil {
+			return Result{}, "", err
+		}
+		if !added {
+			reason = RejectDuplicate
+		}
+	}
+	if reason != "" {
+		res.Prime = false
+	}
+	return res, reason, nil
+}
+
+// randomBase sorteia uma base a, com 2 <= a <= n-2, para os testes de
+// primalidade, a partir de cfg.Witnesses ou, se nil, da entropia de cfg.
+// Exige n >= 5. A base n-1 fica de fora: como (n-1)^2 = 1 (mod n), ela
+// passa nos testes com qualquer n impar e nao serve de testemunha.
+func randomBase(n *big.Int, cfg Config) (*big.Int, error) {
+	// As bases sao 2 + [0, n-3), isto eh, [2, n-2]
+	bound := prng.GetInt()
+	defer prng.PutInt(bound)
+	bound.Sub(n, big.NewInt(3))
+	if cfg.Witnesses != nil {
+		// Amostragem por rejeicao, sem o vies de reduzir a saida mod bound
+		a := prng.NextBelow(cfg.Witnesses, bound)
+		return a.Add(a, big.NewInt(2)), nil
+	}
+	a, err := cfg.Entropy().Int(bound)
+	if err != nil {
+		return nil, err
+	}
+	return a.Add(a, big.NewInt(2)), nil // a esta agora entre 2 e n-2
+}
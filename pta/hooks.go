@@ -0,0 +1,109 @@
+// Esse arquivo define os ganchos da geracao de primos, chamados a cada
+//  candidato, rejeicao, aceitacao e iteracao dos testes.
+
+package pta
+
+import "math/big"
+
+// RejectReason eh o motivo pelo qual um candidato foi descartado
+type RejectReason string
+
+// Motivos de rejeicao, na ordem em que as verificacoes sao feitas
+const (
+	RejectPrescreen RejectReason = "prescreen" // fator pequeno no pre-filtro
+	RejectFilter    RejectReason = "filter"    // reprovado por Config.Filters
+	RejectComposite RejectReason = "composite" // reprovado pelo teste
+	RejectSmooth    RejectReason = "smooth"    // p - 1 ou p + 1 suave
+	RejectAudit     RejectReason = "audit"     // padrao fraco no nivel Strict
+	RejectDuplicate RejectReason = "duplicate" // ja registrado em Config.Unique
+)
+
+// CandidateEvent eh emitido quando um candidato comeca a ser avaliado
+type CandidateEvent struct {
+	Attempt   int // numero do candidato na busca, a partir de 1
+	Candidate *big.Int
+}
+
+// RejectEvent eh emitido quando um candidato eh descartado
+type RejectEvent struct {
+	Attempt   int
+	Candidate *big.Int
+	Reason    RejectReason
+	Witness   *big.Int // base que provou que o candidato eh composto, se houver
+}
+
+// AcceptEvent eh emitido quando a busca encontra o primo
+type AcceptEvent struct {
+	Result Result
+}
+
+// RoundEvent eh emitido apos cada iteracao de um teste de primalidade
+type RoundEvent struct {
+	Test   string // nome do teste, como em PrimalityTest.Name
+	Number *big.Int
+	Round  int // iteracao, a partir de 1
+	Base   *big.Int
+	Passed bool // false se Base provou que Number eh composto
+}
+
+// Hooks sao funcoes chamadas nos pontos da geracao de primos, para
+// registro, metricas ou interrupcao da busca sem alterar o seu laco.
+// Qualquer campo pode ser nil. Se uma funcao retornar erro, a geracao (ou
+// o teste, no caso de OnRound) para e retorna esse erro.
+//
+// Os numeros dos eventos sao copias, e podem ser guardados. No modo
+// ConstantTime, OnRound nao eh chamado, para nao revelar em que iteracao
+// o candidato foi reprovado.
+type Hooks struct {
+	OnCandidate func(CandidateEvent) error
+	OnReject    func(RejectEvent) error
+	OnAccept    func(AcceptEvent) error
+	OnRound     func(RoundEvent) error
+}
+
+// runHooks chama, em ordem, a funcao escolhida por pick de cada Hooks,
+// parando no primeiro erro. event so eh montado se houver ganchos.
+func runHooks[E any](hooks []Hooks, pick func(Hooks) func(E) error, event func() E) error {
+	for _, h := range hooks {
+		if f := pick(h); f != nil {
+			if err := f(event()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// onCandidate chama os ganchos OnCandidate de cfg
+func (cfg Config) onCandidate(attempt int, candidate *big.Int) error {
+	return runHooks(cfg.Hooks, func(h Hooks) func(CandidateEvent) error { return h.OnCandidate }, func() CandidateEvent {
+		return CandidateEvent{Attempt: attempt, Candidate: new(big.Int).Set(candidate)}
+	})
+}
+
+// onReject chama os ganchos OnReject de cfg
+func (cfg Config) onReject(attempt int, candidate *big.Int, reason RejectReason, witness *big.Int) error {
+	return runHooks(cfg.Hooks, func(h Hooks) func(RejectEvent) error { return h.OnReject }, func() RejectEvent {
+		ev := RejectEvent{Attempt: attempt, Candidate: new(big.Int).Set(candidate), Reason: reason}
+		if witness != nil {
+			ev.Witness = new(big.Int).Set(witness)
+		}
+		return ev
+	})
+}
+
+// onAccept chama os ganchos OnAccept de cfg
+func (cfg Config) onAccept(res Result) error {
+	return runHooks(cfg.Hooks, func(h Hooks) func(AcceptEvent) error { return h.OnAccept }, func() AcceptEvent {
+		// Result.Number ja eh um valor novo, mas cada gancho recebe o seu
+		res.Number = new(big.Int).Set(res.Number)
+		return AcceptEvent{Result: res}
+	})
+}
+
+// onRound chama os ganchos OnRound de cfg
+func (cfg Config) onRound(test string, n *big.Int, round int, base *big.Int, passed bool) error {
+	return runHooks(cfg.Hooks, func(h Hooks) func(RoundEvent) error { return h.OnRound }, func() RoundEvent {
+		return RoundEvent{Test: test, Number: new(big.Int).Set(n), Round: round, Base: new(big.Int).Set(base), Passed: passed}
+	})
+}
@@ -0,0 +1,122 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestHooksEvents(t *testing.T) {
+	var candidates, rejects, accepts, rounds int
+	reasons := make(map[RejectReason]int)
+	var order []string
+	var accepted Result
+	last := new(big.Int)
+	digit, err := LastDigit(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{
+		Security: prng.Permissive,
+		Filters:  []CandidateFilter{digit},
+		Hooks: []Hooks{{
+			OnCandidate: func(ev CandidateEvent) error {
+				candidates++
+				if ev.Attempt != candidates {
+					t.Errorf("candidato %d com Attempt %d", candidates, ev.Attempt)
+				}
+				order = append(order, "primeiro")
+				return nil
+			},
+			OnReject: func(ev RejectEvent) error {
+				rejects++
+				reasons[ev.Reason]++
+				if ev.Reason == RejectComposite && ev.Witness == nil {
+					t.Errorf("%s rejeitado sem testemunha", ev.Candidate)
+				}
+				return nil
+			},
+			OnAccept: func(ev AcceptEvent) error {
+				accepts++
+				accepted = ev.Result
+				return nil
+			},
+			OnRound: func(ev RoundEvent) error {
+				rounds++
+				if ev.Test != "miller-rabin" || ev.Round < 1 || ev.Base == nil {
+					t.Errorf("evento de iteracao invalido: %+v", ev)
+				}
+				last = ev.Number
+				return nil
+			},
+		}, {
+			OnCandidate: func(CandidateEvent) error {
+				order = append(order, "segundo")
+				return nil
+			},
+		}},
+	}
+
+	res, err := Generate(128, big.NewInt(1000), millerRabin{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidates != res.Attempts || rejects != res.Attempts-1 || accepts != 1 {
+		t.Errorf("%d candidatos, %d rejeicoes e %d aceitacoes para %d tentativas", candidates, rejects, accepts, res.Attempts)
+	}
+	if reasons[RejectFilter] == 0 || reasons[RejectPrescreen] == 0 {
+		t.Errorf("motivos das rejeicoes: %v", reasons)
+	}
+	if accepted.Number.Cmp(res.Number) != 0 || accepted.Number == res.Number {
+		t.Errorf("aceito %s, gerado %s (ou o mesmo ponteiro)", accepted.Number, res.Number)
+	}
+	if last.Cmp(res.Number) != 0 || rounds < res.Rounds {
+		t.Errorf("ultima iteracao com %s, %d iteracoes no total", last, rounds)
+	}
+	for i := 0; i < len(order); i += 2 {
+		if order[i] != "primeiro" || order[i+1] != "segundo" {
+			t.Fatalf("ganchos fora de ordem: %v", order)
+		}
+	}
+}
+
+func TestHooksAbort(t *testing.T) {
+	stop := errors.New("chega")
+	second := false
+	cfg := Config{Security: prng.Permissive, Hooks: []Hooks{{
+		OnCandidate: func(ev CandidateEvent) error {
+			if ev.Attempt == 3 {
+				return stop
+			}
+			return nil
+		},
+		OnReject: func(RejectEvent) error { return nil },
+	}, {
+		OnCandidate: func(ev CandidateEvent) error {
+			second = second || ev.Attempt == 3
+			return nil
+		},
+	}}}
+	// 2^128 + 1 e 2^128 + 3 sao compostos, entao a busca chega ao terceiro
+	candidate := new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err := Generate(129, candidate, millerRabin{}, cfg); !errors.Is(err, stop) {
+		t.Errorf("Generate retornou %v, esperado %v", err, stop)
+	}
+	if second {
+		t.Error("segundo gancho chamado apos o erro do primeiro")
+	}
+
+	// Um erro em OnRound interrompe o teste
+	cfg = Config{Security: prng.Permissive, Hooks: []Hooks{{
+		OnRound: func(RoundEvent) error { return stop },
+	}}}
+	if res := (fermat{}).IsPrime(mersenne(127), cfg); !errors.Is(res.Err, stop) || res.Prime {
+		t.Errorf("IsPrime retornou %+v", res)
+	}
+	// No modo ConstantTime, OnRound nao eh chamado
+	cfg.ConstantTime = true
+	if res := (millerRabin{}).IsPrime(mersenne(127), cfg); res.Err != nil || !res.Prime {
+		t.Errorf("IsPrime constant-time retornou %+v", res)
+	}
+}
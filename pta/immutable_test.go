@@ -0,0 +1,111 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+// TestInputsUnchanged verifica que as funcoes do pacote nao alteram os
+// *big.Int recebidos e nao devolvem um deles como resultado
+func TestInputsUnchanged(t *testing.T) {
+	permissive := Config{Security: prng.Permissive}
+	constantTime := Config{Security: prng.Permissive, ConstantTime: true}
+	// Um candidato par, com menos bits que o pedido: Generate precisa
+	// ajusta-lo antes de testar
+	candidate := func() *big.Int { return big.NewInt(1000) }
+	mustFilter := func(f CandidateFilter, err error) CandidateFilter {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	cases := []struct {
+		name   string
+		inputs []*big.Int
+		call   func(in []*big.Int) *big.Int // retorna o numero produzido, se houver
+	}{
+		{"Generate", []*big.Int{candidate()}, func(in []*big.Int) *big.Int {
+			res, err := Generate(64, in[0], millerRabin{}, permissive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res.Number
+		}},
+		{"MillerRabin constant-time", []*big.Int{candidate()}, func(in []*big.Int) *big.Int {
+			res, err := MillerRabin(in[0], 64, constantTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res.Number
+		}},
+		{"Fermat com filtros", []*big.Int{candidate()}, func(in []*big.Int) *big.Int {
+			cfg := permissive
+			cfg.Filters = []CandidateFilter{mustFilter(LastDigit(7)), NotSmooth(1000)}
+			res, err := Fermat(in[0], 64, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res.Number
+		}},
+		{"GeneratePrimeNumber", []*big.Int{candidate()}, func(in []*big.Int) *big.Int {
+			p, _ := GeneratePrimeNumber(64, in[0])
+			return p
+		}},
+		{"GeneratePrimeNumberFemart", []*big.Int{candidate()}, func(in []*big.Int) *big.Int {
+			p, _ := GeneratePrimeNumberFemart(64, in[0])
+			return p
+		}},
+		{"IsPrime primo", []*big.Int{mersenne(127)}, func(in []*big.Int) *big.Int {
+			return millerRabin{}.IsPrime(in[0], permissive).Number
+		}},
+		{"IsPrime composto constant-time", []*big.Int{big.NewInt(561)}, func(in []*big.Int) *big.Int {
+			return fermat{}.IsPrime(in[0], constantTime).Number
+		}},
+		{"MillerRabinTest e FermatTest", []*big.Int{big.NewInt(1000003)}, func(in []*big.Int) *big.Int {
+			MillerRabinTest(in[0], 10)
+			FermatTest(in[0], 10)
+			return nil
+		}},
+		{"StrongProbablePrime", []*big.Int{big.NewInt(2047), big.NewInt(2)}, func(in []*big.Int) *big.Int {
+			StrongProbablePrime(in[0], in[1])
+			return nil
+		}},
+		{"GenerateCongruent", []*big.Int{big.NewInt(-1), big.NewInt(15)}, func(in []*big.Int) *big.Int {
+			res, err := GenerateCongruent(64, in[0], in[1], millerRabin{}, permissive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res.Number
+		}},
+		{"Congruent", []*big.Int{big.NewInt(-1), big.NewInt(4), big.NewInt(1003)}, func(in []*big.Int) *big.Int {
+			mustFilter(Congruent(in[0], in[1])).Accept(in[2])
+			return nil
+		}},
+		{"RootOfUnity", []*big.Int{big.NewInt(65537)}, func(in []*big.Int) *big.Int {
+			w, err := RootOfUnity(in[0], 16, prng.Entropy{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return w
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			before := make([]*big.Int, len(c.inputs))
+			for i, n := range c.inputs {
+				before[i] = new(big.Int).Set(n)
+			}
+			out := c.call(c.inputs)
+			for i, n := range c.inputs {
+				if n.Cmp(before[i]) != 0 {
+					t.Errorf("entrada %d alterada de %s para %s", i, before[i], n)
+				}
+				if out == n {
+					t.Errorf("o resultado eh a propria entrada %d", i)
+				}
+			}
+		})
+	}
+}
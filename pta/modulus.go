@@ -0,0 +1,122 @@
+// Esse arquivo gera modulos RSA n = p*q cujos bytes mais significativos
+//  formam um padrao escolhido, para testes de protocolo e modulos de
+//  demonstracao.
+
+package pta
+
+import (
+	"PrimeNumGenerator/audit"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrPrefixTooLong indica que o prefixo pedido ocupa bits demais do modulo
+var ErrPrefixTooLong = errors.New("pta: prefixo longo demais para o modulo")
+
+// Limites do prefixo de GeneratePrefixedModulus, em bits: no nivel
+// prng.Strict, um quarto do modulo; no nivel prng.Permissive, metade menos
+// prefixMargin, a folga para o ajuste de q nao alcancar o prefixo
+const prefixMargin = 32
+
+// prefixAttempts limita as tentativas de GeneratePrefixedModulus
+const prefixAttempts = 64
+
+// MaxPrefixBits retorna o maior prefixo, em bits, aceito por
+// GeneratePrefixedModulus para modulos de bits bits no nivel informado
+func MaxPrefixBits(bits int, level prng.SecurityLevel) int {
+	if level == prng.Strict {
+		return bits / 4
+	}
+	return max(bits/2-prefixMargin, 0)
+}
+
+// GenerateModulusWithPrefix gera um modulo RSA n = p*q de bits bits cujos
+// bytes mais significativos sao prefix, usando o teste de Miller-Rabin com
+// a configuracao padrao (nivel prng.Strict). Veja GeneratePrefixedModulus.
+func GenerateModulusWithPrefix(bits int, prefix []byte) (n, p, q *big.Int, err error) {
+	return GeneratePrefixedModulus(bits, prefix, millerRabin{}, Config{})
+}
+
+// GeneratePrefixedModulus gera um modulo RSA n = p*q de bits bits cujos
+// len(prefix) bytes mais significativos sao prefix, com p de bits/2 bits e
+// q de bits - bits/2 bits gerados com o teste test e a configuracao cfg. O
+// primeiro byte do prefixo precisa ter o bit mais alto ligado, pois ele eh
+// o bit bits - 1 de n.
+//
+// O modulo eh montado por ajuste iterativo: um alvo T com o prefixo seguido
+// de bits sorteados, um primo p sorteado e q o primeiro primo a partir de
+// ceil(T/p). Como q passa de T/p apenas pela distancia ate o proximo primo,
+// n = p*q excede T em cerca de p*ln(q), o que altera so os bits baixos de
+// T, e o prefixo se mantem; quando nao se mantem, a busca recomeca.
+//
+// AVISO: use esses modulos apenas em testes e demonstracoes. O prefixo nao
+// revela a fatoracao, mas q deixa de ser independente de p, o padrao torna
+// as chaves reconheciveis e relacionaveis entre si, e a seguranca de
+// modulos com partes escolhidas depende de analises especificas. Por isso,
+// no nivel prng.Strict (o padrao), o prefixo fica limitado a um quarto do
+// modulo e n precisa passar por audit.AuditModulus; apenas no nivel
+// prng.Permissive ele pode chegar perto da metade (veja MaxPrefixBits).
+func GeneratePrefixedModulus(bits int, prefix []byte, test PrimalityTest, cfg Config) (n, p, q *big.Int, err error) {
+	if bits < 64 {
+		return nil, nil, nil, fmt.Errorf("pta: modulo de %d bits pequeno demais", bits)
+	}
+	if len(prefix) == 0 || prefix[0]&0x80 == 0 {
+		return nil, nil, nil, errors.New("pta: o prefixo deve comecar por um byte com o bit mais alto ligado")
+	}
+	prefixBits := 8 * len(prefix)
+	if limit := MaxPrefixBits(bits, cfg.Security); prefixBits > limit {
+		return nil, nil, nil, fmt.Errorf("%w: %d bits, o limite no nivel %s eh %d", ErrPrefixTooLong, prefixBits, cfg.Security, limit)
+	}
+
+	half := bits / 2
+	want := new(big.Int).SetBytes(prefix)
+	low := uint(bits - prefixBits)
+	pBound := new(big.Int).Lsh(big.NewInt(1), uint(half))
+	for range prefixAttempts {
+		// Alvo T: o prefixo seguido de bits sorteados
+		target, err := cfg.Entropy().Int(new(big.Int).Lsh(big.NewInt(1), low))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		target.Or(target, new(big.Int).Lsh(want, low))
+
+		// p > T/2^(bits-half) garante q = T/p abaixo de 2^(bits-half), com
+		// bits - half bits
+		pMin := new(big.Int).Rsh(target, uint(bits-half))
+		pMin.Add(pMin, big.NewInt(1))
+		offset, err := cfg.Entropy().Int(new(big.Int).Sub(pBound, pMin))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		resP, err := Generate(half, offset.Add(offset, pMin), test, cfg)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		p = resP.Number
+
+		q0, rem := new(big.Int).QuoRem(target, p, new(big.Int))
+		if rem.Sign() != 0 {
+			q0.Add(q0, big.NewInt(1))
+		}
+		resQ, err := Generate(bits-half, q0, test, cfg)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		q = resQ.Number
+
+		n = new(big.Int).Mul(p, q)
+		if p.BitLen() != half || q.BitLen() != bits-half || n.BitLen() != bits || p.Cmp(q) == 0 {
+			continue
+		}
+		if new(big.Int).Rsh(n, low).Cmp(want) != 0 {
+			continue
+		}
+		if cfg.Security == prng.Strict && len(audit.AuditModulus(n)) > 0 {
+			continue
+		}
+		return n, p, q, nil
+	}
+	return nil, nil, nil, fmt.Errorf("pta: nenhum modulo com o prefixo %x em %d tentativas", prefix, prefixAttempts)
+}
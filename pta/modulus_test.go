@@ -0,0 +1,50 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGenerateModulusWithPrefix(t *testing.T) {
+	cases := []struct {
+		bits   int
+		prefix []byte
+		level  prng.SecurityLevel
+	}{
+		{512, []byte{0xde, 0xad, 0xbe, 0xef}, prng.Strict},
+		{1024, []byte("\xcafe babe, safe"), prng.Strict},
+		{512, bytes.Repeat([]byte{0xff}, 28), prng.Permissive},
+	}
+	for _, c := range cases {
+		n, p, q, err := GeneratePrefixedModulus(c.bits, c.prefix, millerRabin{}, Config{Security: c.level})
+		if err != nil {
+			t.Fatalf("%d bits, prefixo %x: %v", c.bits, c.prefix, err)
+		}
+		if n.BitLen() != c.bits || p.BitLen() != c.bits/2 || q.BitLen() != c.bits-c.bits/2 {
+			t.Errorf("tamanhos errados: n %d, p %d, q %d bits", n.BitLen(), p.BitLen(), q.BitLen())
+		}
+		if !bytes.HasPrefix(n.Bytes(), c.prefix) {
+			t.Errorf("n = %x nao comeca por %x", n, c.prefix)
+		}
+		if !p.ProbablyPrime(20) || !q.ProbablyPrime(20) || n.Cmp(p.Mul(p, q)) != 0 {
+			t.Errorf("n = %x nao eh o produto de dois primos", n)
+		}
+	}
+}
+
+func TestGenerateModulusWithPrefixLimits(t *testing.T) {
+	// 17 bytes passam de um quarto de 512 bits no nivel Strict, mas nao de
+	// metade menos a folga no Permissive
+	prefix := bytes.Repeat([]byte{0xaa}, 17)
+	if _, _, _, err := GenerateModulusWithPrefix(512, prefix); !errors.Is(err, ErrPrefixTooLong) {
+		t.Errorf("prefixo longo no nivel Strict: erro %v", err)
+	}
+	if _, _, _, err := GeneratePrefixedModulus(512, prefix, millerRabin{}, Config{Security: prng.Permissive}); err != nil {
+		t.Errorf("prefixo no limite do nivel Permissive: %v", err)
+	}
+	if _, _, _, err := GenerateModulusWithPrefix(512, []byte{0x7f}); err == nil {
+		t.Error("prefixo sem o bit mais alto deveria falhar")
+	}
+}
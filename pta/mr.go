@@ -4,75 +4,143 @@
 package pta
 
 import (
-	"crypto/rand"
-	"fmt"
+	"PrimeNumGenerator/prng"
 	"math/big"
-	"time"
 )
 
 // MillerRabinTest verifica se um numero eh provavelmente primo
 // usando o teste de primalidade de Miller-Rabin
 // k eh o numero de iteracoes para aumentar a confiabilidade
+//
+// Em caso de falha da fonte de entropia, as bases sao escolhidas no nivel
+// prng.Permissive, preservando o comportamento original da funcao.
 func MillerRabinTest(n *big.Int, k int) bool {
+	prime, _, _, _ := millerRabinTest(n, k, Config{Security: prng.Permissive})
+	return prime
+}
+
+// millerRabinTest executa o teste de Miller-Rabin e retorna, alem do veredito,
+// a testemunha que provou que n eh composto (nil se nao houver) e o numero
+// de iteracoes efetivamente executadas. As bases sao sorteadas a partir da
+// entropia de cfg; se a fonte de entropia falhar, o erro eh retornado.
+func millerRabinTest(n *big.Int, k int, cfg Config) (bool, *big.Int, int, error) {
+	prime, witness, rounds, err := runMillerRabin(n, k, cfg)
+	if err == nil && validationEnabled() {
+		crossValidate("miller-rabin", n, prime)
+	}
+	return prime, witness, rounds, err
+}
+
+// runMillerRabin contem a implementacao do teste usada por millerRabinTest
+func runMillerRabin(n *big.Int, k int, cfg Config) (bool, *big.Int, int, error) {
 	// Tratamento de casos especiais
 	if n.Cmp(big.NewInt(2)) == 0 || n.Cmp(big.NewInt(3)) == 0 {
-		return true
+		return true, nil, 0, nil
 	}
 	if n.Cmp(big.NewInt(2)) < 0 || new(big.Int).Mod(n, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		return false
+		return false, nil, 0, nil
 	}
 
 	// Escreve n-1 como 2^r * d onde d é ímpar
-	r := 0
 	d := new(big.Int).Sub(n, big.NewInt(1)) // d = n-1 inicialmente
 
-	// Enquanto d eh par, dividir por 2
-	for new(big.Int).Mod(d, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		d.Rsh(d, 1) // d = d/2
-		r++
+	// Dividimos d por 2 tantas vezes quantos forem os zeros finais
+	r := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(r))
+	// O modulo n eh preparado uma vez para todas as iteracoes
+	backend := prng.Prepare(cfg.Backend, n)
+	if cfg.ConstantTime {
+		defer prng.WipeInt(d)
+		return millerRabinConstantTime(n, d, r, k, cfg, backend)
 	}
 
 	// Principal loop do Miller-Rabin
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1))
 	for i := 0; i < k; i++ {
-		if !millerRabinIteration(n, d, r) {
-			return false // Definitivamente composto
+		a, err := randomBase(n, cfg)
+		if err != nil {
+			return false, nil, i, err
+		}
+		passed := millerRabinIteration(n, nMinus1, d, r, a, backend)
+		if err := cfg.onRound("miller-rabin", n, i+1, a, passed); err != nil {
+			return false, nil, i + 1, err
+		}
+		if !passed {
+			return false, a, i + 1, nil // Definitivamente composto
 		}
 	}
-
-	return true // Provavelmente primo
+	return true, nil, k, nil // Provavelmente primo
 }
 
-// millerRabinIteration realiza uma unica iteracao do teste
-func millerRabinIteration(n, d *big.Int, r int) bool {
-	nMinus2 := new(big.Int).Sub(n, big.NewInt(2))
-	a, err := rand.Int(rand.Reader, nMinus2)
-	if err != nil {
-		// Fallback se rand.Int falhar
-		t := time.Now().UnixNano()
-		a = big.NewInt(t % nMinus2.Int64())
+// millerRabinConstantTime executa as k iteracoes sem sair cedo, para que o
+// tempo gasto nao revele em qual base n foi reprovado. Como todas as bases
+// sao usadas, as exponenciacoes a^d sao calculadas em um unico lote.
+func millerRabinConstantTime(n, d *big.Int, r, k int, cfg Config, backend prng.ModExpBackend) (bool, *big.Int, int, error) {
+	bases := make([]*big.Int, k)
+	exps := make([]*big.Int, k)
+	for i := range bases {
+		a, err := randomBase(n, cfg)
+		if err != nil {
+			return false, nil, i, err
+		}
+		bases[i], exps[i] = a, d
 	}
-	a.Add(a, big.NewInt(2)) // a esta agora entre 2 e n-2
 
+	var witness *big.Int
+	for i, x := range backend.ExpBatch(bases, exps, n) {
+		if !millerRabinIterationConstantTime(n, x, r) && witness == nil {
+			witness = bases[i]
+		} else {
+			prng.WipeInt(bases[i])
+		}
+	}
+	if witness != nil {
+		return false, witness, k, nil
+	}
+	return true, nil, k, nil // Provavelmente primo
+}
+
+// millerRabinIteration realiza uma unica iteracao do teste com a base a.
+// nMinus1 eh n-1, calculado uma vez para todas as iteracoes.
+func millerRabinIteration(n, nMinus1, d *big.Int, r int, a *big.Int, backend prng.ModExpBackend) bool {
 	// Calcula x = a^d mod n
-	x := new(big.Int).Exp(a, d, n)
+	x := backend.Exp(a, d, n)
 
 	// Se x = 1 ou x = n-1, provavelmente eh primo
-	one := big.NewInt(1)
-	nMinus1 := new(big.Int).Sub(n, one)
-
-	if x.Cmp(one) == 0 || x.Cmp(nMinus1) == 0 {
+	if x.IsInt64() && x.Int64() == 1 || x.Cmp(nMinus1) == 0 {
 		return true
 	}
 
+	// Com os backends do pacote prng, os quadrados sao feitos no lugar: com
+	// o modulo preparado, pelo pacote modexp, e nos demais casos com
+	// valores temporarios do pool
+	prepared, isPrepared := backend.(prng.PreparedBackend)
+	inPlace := false
+	switch backend.(type) {
+	case prng.BigBackend, prng.FixedBackend:
+		inPlace = true
+	}
+	square, quo := prng.GetInt(), prng.GetInt()
+	defer prng.PutInt(square)
+	defer prng.PutInt(quo)
+
 	// Continua elevando ao quadrado x enquanto:
 	// - r-1 > 0
 	// - x != n-1
 	// - x != 1
 	for j := 0; j < r-1; j++ {
 		// x = x^2 mod n
-		x.Exp(x, big.NewInt(2), n)
+		switch {
+		case isPrepared:
+			prepared.Square(x, n)
+		case inPlace:
+			square.Mul(x, x)
+			quo.QuoRem(square, n, x)
+		default:
+			x = backend.Exp(x, big.NewInt(2), n)
+		}
 
-		if x.Cmp(one) == 0 {
+		if x.IsInt64() && x.Int64() == 1 {
 			// Encontramos uma raiz nao-trivial da unidade,
 			// 	n é composto
 			return false
@@ -88,72 +156,39 @@ func millerRabinIteration(n, d *big.Int, r int) bool {
 	return false
 }
 
-// GeneratePrimeNumber gera um numero primo com o tamanho de bits especificado
-// usando o teste de Miller-Rabin
-func GeneratePrimeNumber(bits int, candidato *big.Int) (*big.Int, int) {
-	tentativas := 0
-	for {
-		tentativas++
-
-		// Garantindo que o candidato tenha a quantidade de bits correto
-		for candidato.BitLen() < bits {
-			candidato.SetBit(candidato, bits-1, 1)
-		}
-
-		// Garantindo que o numero eh impar (um requisito para primos > 2)
-		if candidato.Bit(0) == 0 {
-			candidato.SetBit(candidato, 0, 1)
-		}
-
-		// Verificando se eh primo usando Miller-Rabin
-		// O numero de iteracoes varia conforme o tamanho para aumentar a confiabilidade
-		iteracoes := 20
-		if bits > 256 {
-			iteracoes = 30
-		}
-		if bits > 1024 {
-			iteracoes = 40
-		}
-
-		if MillerRabinTest(candidato, iteracoes) {
-			return candidato, tentativas
-		}
+// millerRabinIterationConstantTime realiza uma iteracao do teste a partir
+// de x = a^d mod n sempre executando os r-1 quadrados, em vez de parar no
+// primeiro valor conclusivo. O math/big nao garante tempo constante nas
+// operacoes aritmeticas; aqui evitamos apenas as saidas antecipadas do
+// algoritmo. x eh apagado ao final.
+func millerRabinIterationConstantTime(n, x *big.Int, r int) bool {
+	one := big.NewInt(1)
+	nMinus1 := new(big.Int).Sub(n, one)
+	square, quo := prng.GetInt(), prng.GetInt()
+	defer prng.WipeInt(x)
+	defer prng.PutInt(square)
+	defer prng.PutInt(quo)
 
-		// Se nao for primo, incrementa por 2 e tentar novamente
-		// Isto eh mais eficiente que gerar um novo numero aleatorio a cada tentativa
-		// E como estamos usando o BBS e o LFG para gerar o candidato,
-		// opto por nao "resetar" o gerador de numeros aleatorios
-		candidato.Add(candidato, big.NewInt(2))
+	// n passa se a^d = 1 ou se algum a^(2^j * d) = n-1, para 0 <= j < r
+	passed := x.Cmp(one) == 0 || x.Cmp(nMinus1) == 0
+	for j := 0; j < r-1; j++ {
+		square.Mul(x, x)
+		quo.QuoRem(square, n, x)
+		passed = x.Cmp(nMinus1) == 0 || passed
 	}
+	return passed
 }
 
-// ======= Funcao chamada externamente =======
-func MillerRabin(candidate *big.Int, bits int) *big.Int {
-	fmt.Println("\nGerando número primo usando Miller-Rabin")
-	fmt.Println("=======================================")
-
-	// Medindo o execution time
-	inicio := time.Now()
-
-	// Gerando o primo
-	prime, tentativas := GeneratePrimeNumber(bits, candidate)
-
-	// Calculando o tempo decorrido
-	duracao := time.Since(inicio)
-
-	fmt.Printf("- Número gerado: %d bits\n", bits)
-	fmt.Printf("- Tempo de execução: %s\n", duracao)
-	fmt.Printf("- Tentativas: %d\n", tentativas)
-	fmt.Printf("- Tamanho do número gerado: %d dígitos\n", len(prime.String()))
-
-	// Verificando o tamanho real do numero gerado
-	bitLength := prime.BitLen()
-
-	fmt.Printf("- Tamanho real: %d bits\n", bitLength)
-	fmt.Printf("- Valor decimal: %s\n", prime.String())
-
-	binStr := fmt.Sprintf("%b", prime)
-	fmt.Printf("- Binário: %s\n", binStr)
+// GeneratePrimeNumber gera um numero primo com o tamanho de bits especificado
+// usando o teste de Miller-Rabin, no nivel prng.Permissive
+func GeneratePrimeNumber(bits int, candidato *big.Int) (*big.Int, int) {
+	res, _ := Generate(bits, candidato, millerRabin{}, Config{Security: prng.Permissive})
+	return res.Number, res.Attempts
+}
 
-	return prime
+// ======= Funcao chamada externamente =======
+// MillerRabin gera um numero primo de bits bits a partir do candidato
+// usando o teste de Miller-Rabin e retorna o resultado da geracao.
+func MillerRabin(candidate *big.Int, bits int, cfg Config) (Result, error) {
+	return Generate(bits, candidate, millerRabin{}, cfg)
 }
@@ -0,0 +1,167 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestMillerRabinKnownNumbers(t *testing.T) {
+	for _, tc := range knownCases(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := MillerRabinTest(tc.n, 20); got != tc.prime {
+				t.Errorf("MillerRabinTest(%s) = %t, esperado %t", tc.n, got, tc.prime)
+			}
+		})
+	}
+}
+
+func TestMillerRabinRejectsCarmichael(t *testing.T) {
+	for _, s := range carmichaelNumbers {
+		n := mustInt(t, s, 10)
+		if MillerRabinTest(n, 20) {
+			t.Errorf("MillerRabinTest(%s) = true para numero de Carmichael", s)
+		}
+	}
+}
+
+func TestMillerRabinWitness(t *testing.T) {
+	n := mustInt(t, "561", 10)
+	prime, witness, rounds, _ := millerRabinTest(n, 20, Config{})
+	if prime {
+		t.Fatal("561 considerado primo")
+	}
+	if witness == nil || rounds < 1 {
+		t.Fatalf("esperada testemunha e ao menos uma iteracao, obtido %v e %d", witness, rounds)
+	}
+	if witness.Cmp(big.NewInt(2)) < 0 || witness.Cmp(big.NewInt(559)) > 0 {
+		t.Errorf("testemunha %s fora do intervalo [2, n-2]", witness)
+	}
+}
+
+func TestGeneratePrimeNumberProperty(t *testing.T) {
+	for _, bits := range []int{40, 56, 80, 128, 256, 512} {
+		for i := 0; i < 5; i++ {
+			candidate, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			prime, attempts := GeneratePrimeNumber(bits, candidate)
+			if !prime.ProbablyPrime(64) {
+				t.Errorf("%d bits: %s nao passa em ProbablyPrime", bits, prime)
+			}
+			if prime.BitLen() < bits {
+				t.Errorf("%d bits: primo gerado tem %d bits", bits, prime.BitLen())
+			}
+			if attempts < 1 {
+				t.Errorf("%d bits: %d tentativas", bits, attempts)
+			}
+		}
+	}
+}
+
+// countingBackend conta as chamadas feitas ao backend de exponenciacao
+type countingBackend struct {
+	prng.BigBackend
+	exps, batches int
+}
+
+func (b *countingBackend) Exp(base, exp, mod *big.Int) *big.Int {
+	b.exps++
+	return b.BigBackend.Exp(base, exp, mod)
+}
+
+func (b *countingBackend) ExpBatch(bases, exps []*big.Int, mod *big.Int) []*big.Int {
+	b.batches++
+	return b.BigBackend.ExpBatch(bases, exps, mod)
+}
+
+func TestModExpBackend(t *testing.T) {
+	p := mustInt(t, "170141183460469231731687303715884105727", 10) // 2^127 - 1
+	b := &countingBackend{}
+	if res := (millerRabin{}).IsPrime(p, Config{Rounds: 8, Backend: b}); !res.Prime {
+		t.Fatal("2^127 - 1 reprovado com backend externo")
+	}
+	if b.exps < 8 || b.batches != 0 {
+		t.Errorf("modo normal: %d exponenciacoes e %d lotes", b.exps, b.batches)
+	}
+
+	// No modo de tempo constante, todas as bases vao em um unico lote
+	*b = countingBackend{}
+	for _, test := range []PrimalityTest{millerRabin{}, fermat{}} {
+		if res := test.IsPrime(p, Config{Rounds: 8, Backend: b, ConstantTime: true}); !res.Prime {
+			t.Fatalf("%s: 2^127 - 1 reprovado no modo de tempo constante", test.Name())
+		}
+	}
+	if b.batches != 2 {
+		t.Errorf("modo de tempo constante: %d lotes, esperado 2", b.batches)
+	}
+}
+
+func TestRandomBaseRange(t *testing.T) {
+	// Com n = 7, as bases validas sao 2, 3, 4 e 5; 6 = n-1 passaria em
+	// qualquer teste
+	n := big.NewInt(7)
+	for _, cfg := range []Config{{}, {Witnesses: prng.NewLFG(10, 7, 10, 64)}} {
+		seen := make(map[int64]bool)
+		for i := 0; i < 400; i++ {
+			a, err := randomBase(n, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Cmp(big.NewInt(2)) < 0 || a.Cmp(big.NewInt(5)) > 0 {
+				t.Fatalf("base %s fora de [2, n-2]", a)
+			}
+			seen[a.Int64()] = true
+		}
+		if len(seen) != 4 {
+			t.Errorf("bases sorteadas %v, esperado 2 a 5", seen)
+		}
+	}
+}
+
+func TestWitnessesFromGenerator(t *testing.T) {
+	seeded := func() prng.Generator {
+		seed := make([]*big.Int, 10)
+		for i := range seed {
+			seed[i] = new(big.Int).SetUint64(0x9e3779b97f4a7c15 * uint64(i+1))
+		}
+		g, err := prng.NewLFGFromState(7, 10, 64, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	// Com geradores de mesma semente, as testemunhas se repetem
+	n := mustInt(t, "3215031751", 10) // pseudoprimo forte nas bases 2, 3, 5 e 7
+	for _, constantTime := range []bool{false, true} {
+		_, a, roundsA, err := millerRabinTest(n, 20, Config{Witnesses: seeded(), ConstantTime: constantTime})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, b, roundsB, _ := millerRabinTest(n, 20, Config{Witnesses: seeded(), ConstantTime: constantTime})
+		if a == nil || a.Cmp(b) != 0 || roundsA != roundsB {
+			t.Errorf("ConstantTime=%t: testemunhas %v e %v, iteracoes %d e %d", constantTime, a, b, roundsA, roundsB)
+		}
+		if a != nil && (a.Cmp(big.NewInt(2)) < 0 || a.Cmp(n) >= 0) {
+			t.Errorf("testemunha %s fora do intervalo", a)
+		}
+	}
+
+	// O gerador substitui a fonte de entropia, que nem chega a ser lida
+	cfg := Config{Witnesses: seeded(), Source: failingSource{}, Security: prng.Strict}
+	for _, test := range []PrimalityTest{millerRabin{}, fermat{}} {
+		if res := test.IsPrime(big.NewInt(1000003), cfg); res.Err != nil || !res.Prime {
+			t.Errorf("%s(1000003) = %+v", test.Name(), res)
+		}
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Read(p []byte) (int, error) {
+	return 0, errors.New("fonte indisponivel")
+}
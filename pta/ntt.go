@@ -0,0 +1,88 @@
+// Esse arquivo gera os primos usados nas transformadas teoricas dos numeros
+//  (NTT), da forma k*2^n + 1, e suas raizes primitivas da unidade.
+
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// rootAttempts limita os sorteios de RootOfUnity. Para p primo, metade das
+// bases da uma raiz primitiva, entao a chance de esgotar as tentativas eh
+// 2^-rootAttempts.
+const rootAttempts = 128
+
+// GenerateNTTPrime gera um primo p = k*2^twoAdicity + 1 de bits bits e uma
+// raiz primitiva 2^twoAdicity-esima da unidade modulo p, com a qual uma NTT
+// de tamanho ate 2^twoAdicity pode ser calculada modulo p. Veja GenerateNTT.
+//
+// Como os primos da NTT sao publicos e de forma especial por construcao, a
+// busca usa o nivel prng.Permissive, sem a auditoria do nivel Strict, que
+// descartaria os primos com poucos bits ligados.
+func GenerateNTTPrime(bits, twoAdicity int) (p, root *big.Int, err error) {
+	return GenerateNTT(bits, twoAdicity, Config{Security: prng.Permissive})
+}
+
+// GenerateNTT eh como GenerateNTTPrime, mas usa a configuracao cfg: os
+// candidatos e as bases da raiz sao sorteados com a entropia de cfg, e o
+// primo passa pelas mesmas verificacoes de GenerateCongruent. O primo pode
+// ter mais que twoAdicity fatores 2 em p - 1.
+//
+// No nivel prng.Strict, audit.Audit descarta os primos de forma especial,
+// entao com twoAdicity perto de bits (k com poucos bits) a busca pode
+// terminar em ErrNoCongruentPrime, com uma mensagem que aponta a causa;
+// como os primos da NTT sao publicos, o nivel prng.Permissive pode ser
+// usado nesse caso.
+func GenerateNTT(bits, twoAdicity int, cfg Config) (p, root *big.Int, err error) {
+	if twoAdicity < 1 || twoAdicity >= bits {
+		return nil, nil, fmt.Errorf("pta: %d fatores 2 em p - 1 nao cabem em %d bits", twoAdicity, bits)
+	}
+	m := new(big.Int).Lsh(big.NewInt(1), uint(twoAdicity))
+	res, err := GenerateCongruent(bits, big.NewInt(1), m, millerRabin{}, cfg)
+	if errors.Is(err, ErrNoCongruentPrime) && cfg.Security == prng.Strict {
+		return nil, nil, fmt.Errorf("%w: no nivel %s, a auditoria descarta os primos k*2^%d + 1 com k pequeno; use o nivel %s", err, prng.Strict, twoAdicity, prng.Permissive)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err = RootOfUnity(res.Number, twoAdicity, cfg.Entropy())
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Number, root, nil
+}
+
+// RootOfUnity retorna uma raiz primitiva 2^twoAdicity-esima da unidade
+// modulo o primo p, isto eh, w com w^(2^twoAdicity) = 1 e
+// w^(2^(twoAdicity-1)) = -1 (mod p). Exige que 2^twoAdicity divida p - 1.
+//
+// Com p - 1 = k*2^twoAdicity, w = g^k para uma base g sorteada tem ordem
+// 2^twoAdicity exatamente quando g nao eh um resto quadratico de ordem
+// menor, o que acontece com metade das bases.
+func RootOfUnity(p *big.Int, twoAdicity int, e prng.Entropy) (*big.Int, error) {
+	one := big.NewInt(1)
+	pMinus1 := new(big.Int).Sub(p, one)
+	if twoAdicity < 1 || p.Cmp(big.NewInt(3)) < 0 || pMinus1.TrailingZeroBits() < uint(twoAdicity) {
+		return nil, fmt.Errorf("pta: 2^%d nao divide %s - 1", twoAdicity, p)
+	}
+	k := new(big.Int).Rsh(pMinus1, uint(twoAdicity))
+	half := new(big.Int).Lsh(one, uint(twoAdicity-1))
+
+	bound := new(big.Int).Sub(p, big.NewInt(2)) // bases entre 1 e p - 2
+	check := new(big.Int)
+	for i := 0; i < rootAttempts; i++ {
+		g, err := e.Int(bound)
+		if err != nil {
+			return nil, err
+		}
+		g.Add(g, one)
+		w := g.Exp(g, k, p)
+		if check.Exp(w, half, p).Cmp(pMinus1) == 0 {
+			return w, nil
+		}
+	}
+	return nil, errors.New("pta: nenhuma raiz primitiva encontrada; p eh primo?")
+}
@@ -0,0 +1,67 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestGenerateNTTPrime(t *testing.T) {
+	for _, c := range []struct{ bits, n int }{{64, 32}, {128, 20}, {31, 27}} {
+		p, root, err := GenerateNTTPrime(c.bits, c.n)
+		if err != nil {
+			t.Fatalf("%d bits, 2^%d: %v", c.bits, c.n, err)
+		}
+		if p.BitLen() != c.bits || !p.ProbablyPrime(20) {
+			t.Fatalf("%s nao eh um primo de %d bits", p, c.bits)
+		}
+		pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+		if pMinus1.TrailingZeroBits() < uint(c.n) {
+			t.Errorf("2^%d nao divide %s - 1", c.n, p)
+		}
+		// root^(2^n) = 1 e root^(2^(n-1)) = -1
+		half := new(big.Int).Lsh(big.NewInt(1), uint(c.n-1))
+		if new(big.Int).Exp(root, half, p).Cmp(pMinus1) != 0 {
+			t.Errorf("%s nao eh uma raiz primitiva 2^%d-esima modulo %s", root, c.n, p)
+		}
+		full := new(big.Int).Lsh(half, 1)
+		if new(big.Int).Exp(root, full, p).Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("%s^(2^%d) != 1 modulo %s", root, c.n, p)
+		}
+	}
+}
+
+func TestRootOfUnity(t *testing.T) {
+	// 998244353 = 119*2^23 + 1, o primo mais usado em NTTs; 3 gera o grupo
+	p := big.NewInt(998244353)
+	root, err := RootOfUnity(p, 23, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), 22)
+	if new(big.Int).Exp(root, half, p).Int64() != 998244352 {
+		t.Errorf("%s nao tem ordem 2^23", root)
+	}
+
+	if _, err := RootOfUnity(p, 24, prng.Entropy{}); err == nil {
+		t.Error("aceitou 2^24, que nao divide p - 1")
+	}
+	// 65537 = 2^16 + 1 eh o unico candidato de 17 bits, de forma especial
+	// demais para o nivel Strict
+	p, root, err = GenerateNTT(17, 16, Config{Security: prng.Permissive})
+	if err != nil || p.Int64() != 65537 || new(big.Int).Exp(root, big.NewInt(1<<15), p).Int64() != 65536 {
+		t.Errorf("GenerateNTT(17, 16) = %v, %v, %v", p, root, err)
+	}
+	if _, _, err := GenerateNTT(17, 16, Config{}); !errors.Is(err, ErrNoCongruentPrime) || !strings.Contains(err.Error(), prng.Permissive.String()) {
+		t.Errorf("65537 no nivel Strict: %v; esperado ErrNoCongruentPrime sugerindo o nivel Permissive", err)
+	}
+	// GenerateNTTPrime usa o nivel Permissive
+	if p, _, err := GenerateNTTPrime(17, 16); err != nil || p.Int64() != 65537 {
+		t.Errorf("GenerateNTTPrime(17, 16) = %v, %v", p, err)
+	}
+	if _, _, err := GenerateNTTPrime(16, 16); err == nil {
+		t.Error("aceitou 2^16 em 16 bits")
+	}
+}
@@ -0,0 +1,174 @@
+// Esse arquivo constroi pseudoprimos fortes: numeros compostos aprovados
+//  pelo Miller-Rabin com um conjunto fixo de bases, que mostram por que as
+//  bases devem ser sorteadas (ou o teste trocado pelo Baillie-PSW).
+
+package pta
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// Limites da busca de StrongPseudoprime
+const (
+	pseudoprimeMaxK      = 2000    // maior coeficiente k2, k3 considerado
+	pseudoprimeMaxTrials = 1 << 22 // candidatos p1 sorteados por par k2, k3
+)
+
+// StrongProbablePrime informa se o numero impar n > 3 passa pela iteracao
+// do Miller-Rabin com a base a, isto eh, se n eh um provavel primo forte na
+// base a. Um composto aprovado eh um pseudoprimo forte na base a.
+func StrongProbablePrime(n, a *big.Int) bool {
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1))
+	r := nMinus1.TrailingZeroBits()
+	d := new(big.Int).Rsh(nMinus1, r)
+	return millerRabinIteration(n, nMinus1, d, int(r), a, prng.BigBackend{})
+}
+
+// StrongPseudoprime constroi um numero composto n = p1*p2*p3 que eh um
+// pseudoprimo forte em todas as bases de bases, que devem ser primos, pela
+// construcao de Arnault. p1 tem factorBits bits, entao n tem cerca de
+// 3*factorBits bits. Retorna n e seus fatores p1 < p2 < p3.
+//
+// Os fatores sao p_i = k_i*(p1 - 1) + 1, com k1 = 1 e k2, k3 primos maiores
+// que as bases. Com p_i ≡ 3 (mod 4), (a/p_i) = -1 para cada base a e
+// p_i - 1 dividindo n - 1, a^((n-1)/2) ≡ -1 (mod n), e n passa pelo teste
+// com a base a. As condicoes sobre os simbolos de Legendre dependem apenas
+// de p1 mod 4a; as de divisibilidade, de p1 mod k2 e mod k3. p1 eh sorteado
+// com a entropia e na classe que satisfaz todas elas, ate que os tres
+// fatores sejam primos.
+func StrongPseudoprime(bases []int64, factorBits int, e prng.Entropy) (n *big.Int, factors []*big.Int, err error) {
+	if len(bases) == 0 {
+		return nil, nil, errors.New("pta: nenhuma base informada")
+	}
+	bases = slices.Clone(bases)
+	slices.Sort(bases)
+	bases = slices.Compact(bases)
+	for _, a := range bases {
+		if a < 2 || !big.NewInt(a).ProbablyPrime(0) {
+			return nil, nil, fmt.Errorf("pta: a base %d nao eh um primo", a)
+		}
+	}
+
+	biggest := bases[len(bases)-1]
+	ks := numutil.PrimesUpTo(pseudoprimeMaxK)
+	for i, k2 := range ks {
+		if int64(k2) <= biggest {
+			continue
+		}
+		for _, k3 := range ks[i+1:] {
+			residue, modulus, ok := arnaultClass(bases, int64(k2), int64(k3), e)
+			if !ok {
+				continue
+			}
+			if modulus.BitLen()+16 > factorBits {
+				return nil, nil, fmt.Errorf("pta: fatores de %d bits sao pequenos demais para essas bases; use ao menos %d", factorBits, modulus.BitLen()+16)
+			}
+			return arnaultSearch(bases, int64(k2), int64(k3), residue, modulus, factorBits, e)
+		}
+	}
+	return nil, nil, errors.New("pta: nenhum par de coeficientes k2, k3 serve para essas bases")
+}
+
+// arnaultClass retorna a classe de p1 modulo 4*prod(bases impares)*k2*k3
+// (8 no lugar de 4 se 2 for uma das bases) que satisfaz as condicoes de
+// StrongPseudoprime, sorteando uma das possibilidades para cada base, ou
+// ok = false se alguma base nao tiver nenhuma
+func arnaultClass(bases []int64, k2, k3 int64, e prng.Entropy) (residue, modulus *big.Int, ok bool) {
+	// Com k3 ≡ -1 (mod k2), k2 dividiria p1 - 1 (e vice-versa)
+	if (k3+1)%k2 == 0 || (k2+1)%k3 == 0 {
+		return nil, nil, false
+	}
+	ks := []int64{1, k2, k3}
+	residues := []*big.Int{big.NewInt(3)}
+	moduli := []*big.Int{big.NewInt(4)}
+	for _, a := range bases {
+		m := 4 * a
+		var class []int64
+		for x := int64(3); x < m; x += 4 { // p1 ≡ 3 (mod 4)
+			good := true
+			for _, k := range ks {
+				y := (k*(x-1) + 1) % m
+				if y%a == 0 || numutil.Jacobi(big.NewInt(a), big.NewInt(y)) != -1 {
+					good = false
+					break
+				}
+			}
+			if good {
+				class = append(class, x)
+			}
+		}
+		if len(class) == 0 {
+			return nil, nil, false
+		}
+		pick, err := e.Int(big.NewInt(int64(len(class))))
+		if err != nil {
+			return nil, nil, false
+		}
+		x := class[pick.Int64()]
+		if a == 2 {
+			// A classe mod 8 substitui a classe mod 4
+			residues[0], moduli[0] = big.NewInt(x), big.NewInt(8)
+		} else {
+			residues = append(residues, big.NewInt(x%a))
+			moduli = append(moduli, big.NewInt(a))
+		}
+	}
+	// p1 ≡ -k3^-1 (mod k2) e p1 ≡ -k2^-1 (mod k3) fazem n ≡ 1 (mod k2) e
+	// (mod k3)
+	for _, pair := range [][2]int64{{k2, k3}, {k3, k2}} {
+		inv, err := numutil.ModInverse(big.NewInt(pair[1]), big.NewInt(pair[0]))
+		if err != nil {
+			return nil, nil, false
+		}
+		residues = append(residues, inv.Neg(inv).Mod(inv, big.NewInt(pair[0])))
+		moduli = append(moduli, big.NewInt(pair[0]))
+	}
+	residue, modulus, err := numutil.CRT(residues, moduli)
+	return residue, modulus, err == nil
+}
+
+// arnaultSearch sorteia p1 na classe residue mod modulus, com factorBits
+// bits, ate que p1, p2 e p3 sejam primos
+func arnaultSearch(bases []int64, k2, k3 int64, residue, modulus *big.Int, factorBits int, e prng.Entropy) (*big.Int, []*big.Int, error) {
+	one := big.NewInt(1)
+	lo := new(big.Int).Lsh(one, uint(factorBits-1))
+	steps := new(big.Int).Div(lo, modulus) // p1 = residue + modulus*(t + lo/modulus)
+	offset := new(big.Int).Set(steps)
+
+	factor := func(p1 *big.Int, k int64) *big.Int {
+		p := new(big.Int).Sub(p1, one)
+		return p.Mul(p, big.NewInt(k)).Add(p, one)
+	}
+	prime := func(p *big.Int) bool {
+		return !numutil.HasSmallFactor(p, DefaultPrescreen) && p.ProbablyPrime(20)
+	}
+	for trial := 0; trial < pseudoprimeMaxTrials; trial++ {
+		t, err := e.Int(steps)
+		if err != nil {
+			return nil, nil, err
+		}
+		p1 := t.Add(t, offset).Mul(t, modulus).Add(t, residue)
+		if !prime(p1) {
+			continue
+		}
+		p2, p3 := factor(p1, k2), factor(p1, k3)
+		if !prime(p2) || !prime(p3) {
+			continue
+		}
+		n := new(big.Int).Mul(p1, p2)
+		n.Mul(n, p3)
+		passes := true
+		for _, a := range bases {
+			passes = passes && StrongProbablePrime(n, big.NewInt(a))
+		}
+		if passes {
+			return n, []*big.Int{p1, p2, p3}, nil
+		}
+	}
+	return nil, nil, errors.New("pta: nenhum pseudoprimo encontrado")
+}
@@ -0,0 +1,63 @@
+package pta
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+func TestStrongPseudoprime(t *testing.T) {
+	for _, bases := range [][]int64{{2}, {7, 5, 3, 2, 3}, {2, 3, 5, 7, 11, 13, 17}} {
+		n, factors, err := StrongPseudoprime(bases, 96, prng.Entropy{})
+		if err != nil {
+			t.Fatalf("bases %v: %v", bases, err)
+		}
+		product := big.NewInt(1)
+		for _, p := range factors {
+			if !p.ProbablyPrime(20) {
+				t.Fatalf("fator %s nao eh primo", p)
+			}
+			product.Mul(product, p)
+		}
+		if product.Cmp(n) != 0 || factors[0].BitLen() != 96 {
+			t.Fatalf("%s nao eh o produto de %v", n, factors)
+		}
+		for _, a := range bases {
+			if !StrongProbablePrime(n, big.NewInt(a)) {
+				t.Errorf("%s reprovado na base %d", n, a)
+			}
+		}
+		// Bases sorteadas e o Baillie-PSW nao se deixam enganar
+		if MillerRabinTest(n, 20) {
+			t.Errorf("%s aprovado pelo Miller-Rabin com bases sorteadas", n)
+		}
+		if n.ProbablyPrime(0) {
+			t.Errorf("%s aprovado pelo Baillie-PSW", n)
+		}
+	}
+}
+
+func TestStrongPseudoprimeInvalid(t *testing.T) {
+	for _, bases := range [][]int64{nil, {4}, {2, 9}} {
+		if _, _, err := StrongPseudoprime(bases, 96, prng.Entropy{}); err == nil {
+			t.Errorf("bases %v aceitas", bases)
+		}
+	}
+	// Primos demais para fatores de 32 bits
+	if _, _, err := StrongPseudoprime([]int64{2, 3, 5, 7, 11, 13, 17}, 32, prng.Entropy{}); err == nil {
+		t.Error("fatores pequenos demais aceitos")
+	}
+}
+
+func TestStrongProbablePrime(t *testing.T) {
+	// 2047 = 23 * 89 eh o menor pseudoprimo forte na base 2
+	if !StrongProbablePrime(big.NewInt(2047), big.NewInt(2)) {
+		t.Error("2047 reprovado na base 2")
+	}
+	if StrongProbablePrime(big.NewInt(2047), big.NewInt(3)) {
+		t.Error("2047 aprovado na base 3")
+	}
+	if !StrongProbablePrime(big.NewInt(1000003), big.NewInt(5)) {
+		t.Error("primo 1000003 reprovado")
+	}
+}
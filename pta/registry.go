@@ -0,0 +1,168 @@
+// Esse arquivo define a interface comum dos testes de primalidade e o
+//  registro que permite seleciona-los pelo nome.
+
+package pta
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/prng"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// PrimalityTest eh a interface implementada por todos os testes de primalidade.
+// Name retorna o nome usado no registro (ex.: "miller-rabin") e IsPrime
+// aplica o teste ao numero n usando a configuracao cfg.
+type PrimalityTest interface {
+	Name() string
+	IsPrime(n *big.Int, cfg Config) Result
+}
+
+// Config agrupa os parametros usados pelos testes de primalidade.
+// Rounds eh o numero de iteracoes do teste; se for <= 0, o numero de
+// iteracoes eh escolhido a partir do tamanho em bits de n. As bases sao
+// sorteadas de Source (crypto/rand se nil) e Security define o que fazer se
+// essa fonte falhar. Com ConstantTime, os testes executam todas as iteracoes
+// mesmo apos encontrar uma testemunha e zeram os valores temporarios, para
+// nao revelar pelo tempo em que ponto o candidato foi reprovado.
+// SmoothnessBound, se positivo, faz Generate rejeitar os primos p cujo
+// p - 1 ou p + 1 seja suave em relacao a esse limite (veja
+// audit.SmoothnessReport). Unique, se nao for nil, registra os primos
+// emitidos por Generate, que descarta os ja registrados. Backend calcula
+// as exponenciacoes modulares dos testes (prng.FixedBackend se nil).
+// Prescreen eh o numero de primos pequenos do pre-filtro por mdc aplicado
+// aos candidatos antes do teste (DefaultPrescreen se <= 0).
+// Filters restringem a forma dos primos de Generate (veja CandidateFilter).
+// Hooks sao chamados, em ordem, a cada candidato, rejeicao, aceitacao e
+// iteracao dos testes (veja Hooks).
+// Witnesses, se nao for nil, substitui Source no sorteio das bases: com um
+// gerador de semente conhecida, candidatos e bases podem ser reproduzidos.
+// Construction escolhe como Generate percorre os candidatos (veja
+// Construction).
+// Os geradores nao sao seguros para uso concorrente, entao uma Config com
+// Witnesses nao deve ser usada por varias goroutines ao mesmo tempo.
+type Config struct {
+	Rounds          int
+	Security        prng.SecurityLevel
+	Source          prng.EntropySource
+	ConstantTime    bool
+	SmoothnessBound int
+	Unique          UniqueStore
+	Backend         prng.ModExpBackend
+	Prescreen       int
+	Filters         []CandidateFilter
+	Hooks           []Hooks
+	Witnesses       prng.Generator
+	Construction    Construction
+}
+
+// UniqueStore registra os primos ja emitidos, como o dedupe.DB. Add
+// registra n e retorna false se ele ja estava registrado.
+type UniqueStore interface {
+	Add(n *big.Int) (bool, error)
+}
+
+// DefaultPrescreen eh o numero padrao de primos pequenos do pre-filtro
+const DefaultPrescreen = 256
+
+// PrescreenPrimes retorna o numero de primos pequenos do pre-filtro
+func (cfg Config) PrescreenPrimes() int {
+	if cfg.Prescreen > 0 {
+		return cfg.Prescreen
+	}
+	return DefaultPrescreen
+}
+
+// Entropy retorna a entropia descrita por Source e Security
+func (cfg Config) Entropy() prng.Entropy {
+	return prng.Entropy{Source: cfg.Source, Level: cfg.Security}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]PrimalityTest{}
+	// Mantemos a ordem de registro para listar os testes de forma previsivel
+	registryOrder []string
+)
+
+// Register adiciona um teste ao registro. Entra em panico se o teste for nil
+// ou se ja existir um teste registrado com o mesmo nome.
+func Register(t PrimalityTest) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if t == nil {
+		panic("pta: Register com teste nil")
+	}
+	name := t.Name()
+	if _, dup := registry[name]; dup {
+		panic("pta: Register chamado duas vezes para o teste " + name)
+	}
+	registry[name] = t
+	registryOrder = append(registryOrder, name)
+}
+
+// Get retorna o teste registrado com o nome informado.
+func Get(name string) (PrimalityTest, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	t, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("pta: teste de primalidade desconhecido %q", name)
+	}
+	return t, nil
+}
+
+// Names retorna os nomes dos testes registrados, na ordem de registro.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]string(nil), registryOrder...)
+}
+
+// roundsFor retorna o numero de iteracoes a ser usado para um numero de
+// bits bits: o valor de cfg.Rounds, ou um valor que cresce com o tamanho
+// para aumentar a confiabilidade.
+func roundsFor(bits int, cfg Config) int {
+	if cfg.Rounds > 0 {
+		return cfg.Rounds
+	}
+	iteracoes := 20
+	if bits > 256 {
+		iteracoes = 30
+	}
+	if bits > 1024 {
+		iteracoes = 40
+	}
+	return iteracoes
+}
+
+// millerRabin adapta MillerRabinTest a interface PrimalityTest
+type millerRabin struct{}
+
+func (millerRabin) Name() string { return "miller-rabin" }
+
+func (millerRabin) IsPrime(n *big.Int, cfg Config) Result {
+	inicio := clock.Now()
+	prime, witness, rounds, err := millerRabinTest(n, roundsFor(n.BitLen(), cfg), cfg)
+	return newResult(n, prime, witness, rounds, err, millerRabinConfidence, clock.Since(inicio))
+}
+
+// fermat adapta FermatTest a interface PrimalityTest
+type fermat struct{}
+
+func (fermat) Name() string { return "fermat" }
+
+func (fermat) IsPrime(n *big.Int, cfg Config) Result {
+	inicio := clock.Now()
+	prime, witness, rounds, err := fermatTest(n, roundsFor(n.BitLen(), cfg), cfg)
+	return newResult(n, prime, witness, rounds, err, fermatConfidence, clock.Since(inicio))
+}
+
+func init() {
+	Register(millerRabin{})
+	Register(fermat{})
+}
@@ -0,0 +1,139 @@
+package pta
+
+import (
+	"PrimeNumGenerator/audit"
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	names := Names()
+	if len(names) < 2 || names[0] != "miller-rabin" || names[1] != "fermat" {
+		t.Fatalf("Names() = %v", names)
+	}
+	if _, err := Get("inexistente"); err == nil {
+		t.Error("Get de teste inexistente nao retornou erro")
+	}
+}
+
+func TestRegisteredTestsKnownNumbers(t *testing.T) {
+	for _, name := range Names() {
+		test, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range knownCases(t) {
+			res := test.IsPrime(tc.n, Config{})
+			if res.Prime != tc.prime {
+				t.Errorf("%s: IsPrime(%s) = %t, esperado %t", name, tc.n, res.Prime, tc.prime)
+			}
+			if !res.Prime && res.Confidence != 1 {
+				t.Errorf("%s: veredito de composto com confiabilidade %g", name, res.Confidence)
+			}
+		}
+	}
+}
+
+func TestResultConfidence(t *testing.T) {
+	test, _ := Get("miller-rabin")
+	res := test.IsPrime(mersenne(127), Config{Rounds: 10})
+	if !res.Prime || res.Rounds != 10 || res.Witness != nil {
+		t.Fatalf("resultado inesperado: %+v", res)
+	}
+	if res.Confidence < 1-1e-6 || res.Confidence >= 1 {
+		t.Errorf("confiabilidade %g fora do esperado", res.Confidence)
+	}
+
+	res = test.IsPrime(big.NewInt(91), Config{Rounds: 10})
+	if res.Prime || res.Witness == nil {
+		t.Errorf("91 deveria ser composto com testemunha: %+v", res)
+	}
+
+	// 2 e 3 sao decididos sem iteracoes: o veredito eh certo
+	for _, name := range Names() {
+		test, _ := Get(name)
+		for _, n := range []int64{2, 3} {
+			res := test.IsPrime(big.NewInt(n), Config{})
+			if !res.Prime || res.Confidence != 1 {
+				t.Errorf("%s: IsPrime(%d) = %t com confiabilidade %g", name, n, res.Prime, res.Confidence)
+			}
+		}
+	}
+}
+
+func TestConstantTimeKnownNumbers(t *testing.T) {
+	for _, name := range Names() {
+		test, _ := Get(name)
+		for _, tc := range knownCases(t) {
+			res := test.IsPrime(tc.n, Config{Rounds: 8, ConstantTime: true})
+			if res.Prime != tc.prime {
+				t.Errorf("%s: IsPrime(%s) = %t, esperado %t", name, tc.n, res.Prime, tc.prime)
+			}
+			// Sem saidas antecipadas, todas as iteracoes sao executadas
+			if tc.n.Cmp(big.NewInt(3)) > 0 && tc.n.Bit(0) == 1 && res.Rounds != 8 {
+				t.Errorf("%s: %d iteracoes para %s, esperado 8", name, res.Rounds, tc.n)
+			}
+		}
+	}
+}
+
+func TestGenerateSmoothnessBound(t *testing.T) {
+	// 527281 eh o primeiro primo a partir de 527279, mas 527280 = 2^4 * 3 *
+	// 5 * 13^3 eh suave
+	res, err := Generate(20, big.NewInt(527279), millerRabin{}, Config{})
+	if err != nil || res.Number.Int64() != 527281 {
+		t.Fatalf("Generate = %v, %v", res.Number, err)
+	}
+	res, err = Generate(20, big.NewInt(527279), millerRabin{}, Config{SmoothnessBound: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Number.Int64() == 527281 || !res.Number.ProbablyPrime(20) || res.Attempts < 2 {
+		t.Fatalf("Generate com SmoothnessBound = %v em %d tentativas", res.Number, res.Attempts)
+	}
+}
+
+func TestGenerateStrictAudit(t *testing.T) {
+	// 2^63 + 29 eh o primeiro primo apos 2^63, mas tem apenas 5 bits ligados
+	start := new(big.Int).Lsh(big.NewInt(1), 63)
+	sparse := new(big.Int).Add(start, big.NewInt(29))
+	res, err := Generate(64, new(big.Int).Set(start), millerRabin{}, Config{Security: prng.Permissive})
+	if err != nil || res.Number.Cmp(sparse) != 0 {
+		t.Fatalf("Generate no nivel Permissive = %v, %v", res.Number, err)
+	}
+	res, err = Generate(64, new(big.Int).Set(start), millerRabin{}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Number.Cmp(sparse) <= 0 || audit.Audit(res.Number) != nil {
+		t.Fatalf("Generate no nivel Strict = %v", res.Number)
+	}
+}
+
+// memoryStore eh um UniqueStore em memoria
+type memoryStore map[string]bool
+
+func (m memoryStore) Add(n *big.Int) (bool, error) {
+	if m[n.String()] {
+		return false, nil
+	}
+	m[n.String()] = true
+	return true, nil
+}
+
+func TestGenerateUnique(t *testing.T) {
+	store := memoryStore{}
+	cfg := Config{Unique: store}
+	first, err := Generate(20, big.NewInt(527279), millerRabin{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Generate(20, big.NewInt(527279), millerRabin{}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Number.Cmp(second.Number) >= 0 || len(store) != 2 {
+		t.Fatalf("primos repetidos: %s e %s", first.Number, second.Number)
+	}
+}
@@ -0,0 +1,94 @@
+// Esse arquivo define o resultado retornado pelos testes de primalidade
+//  e pelas funcoes de geracao de primos.
+
+package pta
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Result descreve o resultado de um teste de primalidade ou de uma geracao
+// de numero primo, permitindo que quem chama decida como exibi-lo.
+type Result struct {
+	Prime      bool          // true se Number eh provavelmente primo
+	Number     *big.Int      // numero testado ou primo gerado
+	Rounds     int           // iteracoes executadas no (ultimo) teste
+	Confidence float64       // probabilidade de o veredito estar correto
+	Attempts   int           // candidatos testados ate encontrar o primo
+	Duration   time.Duration // tempo total gasto
+	Witness    *big.Int      // base que provou que Number eh composto, se houver
+	Err        error         // erro que impediu o teste; Prime eh false nesse caso
+}
+
+// millerRabinConfidence retorna 1 - 4^-k, o limite inferior da
+// confiabilidade de k iteracoes do Miller-Rabin
+func millerRabinConfidence(k int) float64 {
+	return 1 - math.Pow(4, -float64(k))
+}
+
+// fermatConfidence retorna 1 - 2^-k, a confiabilidade de k iteracoes do
+// teste de Fermat para numeros que nao sao de Carmichael
+func fermatConfidence(k int) float64 {
+	return 1 - math.Pow(2, -float64(k))
+}
+
+// newResult monta o Result de um teste isolado. Um veredito de composto
+// eh sempre certo, entao sua confiabilidade eh 1, assim como a de um primo
+// sem iteracoes (rounds = 0), que so acontece nos casos especiais 2 e 3,
+// decididos sem sorteio. Number recebe uma copia
+// de n, para que alterar o resultado nao altere o numero testado.
+func newResult(n *big.Int, prime bool, witness *big.Int, rounds int, err error, confidence func(int) float64, d time.Duration) Result {
+	res := Result{
+		Prime:      prime,
+		Number:     new(big.Int).Set(n),
+		Rounds:     rounds,
+		Confidence: 1,
+		Attempts:   1,
+		Duration:   d,
+		Witness:    witness,
+		Err:        err,
+	}
+	if err != nil {
+		res.Confidence = 0
+	} else if prime && rounds > 0 {
+		res.Confidence = confidence(rounds)
+	}
+	return res
+}
+
+// resultJSON eh a representacao de Result em JSON. Os numeros sao escritos
+// em decimal, como strings, para nao perder precisao.
+type resultJSON struct {
+	Prime      bool    `json:"prime"`
+	Number     string  `json:"number,omitempty"`
+	Rounds     int     `json:"rounds"`
+	Confidence float64 `json:"confidence"`
+	Attempts   int     `json:"attempts"`
+	DurationNS int64   `json:"duration_ns"`
+	Witness    string  `json:"witness,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// MarshalJSON implementa json.Marshaler
+func (r Result) MarshalJSON() ([]byte, error) {
+	out := resultJSON{
+		Prime:      r.Prime,
+		Rounds:     r.Rounds,
+		Confidence: r.Confidence,
+		Attempts:   r.Attempts,
+		DurationNS: r.Duration.Nanoseconds(),
+	}
+	if r.Number != nil {
+		out.Number = r.Number.String()
+	}
+	if r.Witness != nil {
+		out.Witness = r.Witness.String()
+	}
+	if r.Err != nil {
+		out.Error = r.Err.Error()
+	}
+	return json.Marshal(out)
+}
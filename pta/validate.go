@@ -0,0 +1,55 @@
+// Esse arquivo implementa o modo de validacao cruzada, que compara os
+//  vereditos dos testes deste pacote com (*big.Int).ProbablyPrime.
+
+package pta
+
+import (
+	"log"
+	"math/big"
+	"sync/atomic"
+)
+
+// validationRounds eh o numero de iteracoes usado em ProbablyPrime
+// na validacao cruzada
+const validationRounds = 64
+
+var (
+	validation    atomic.Bool
+	discrepancies atomic.Uint64
+)
+
+// SetValidation liga ou desliga a validacao cruzada. Com ela ligada, todo
+// veredito do Miller-Rabin e do Fermat eh comparado com
+// (*big.Int).ProbablyPrime(64) e as divergencias sao registradas no log.
+// Em builds com a tag debug a validacao esta sempre ligada.
+func SetValidation(on bool) {
+	validation.Store(on)
+}
+
+// Discrepancies retorna quantas divergencias a validacao cruzada encontrou
+// desde o inicio do programa.
+func Discrepancies() uint64 {
+	return discrepancies.Load()
+}
+
+func validationEnabled() bool {
+	return debugValidation || validation.Load()
+}
+
+// crossValidate compara o veredito prime do teste name para n com o de
+// ProbablyPrime e registra a divergencia, se houver
+func crossValidate(name string, n *big.Int, prime bool) {
+	if n.Sign() < 0 {
+		// ProbablyPrime entra em panico para numeros negativos
+		if prime {
+			discrepancies.Add(1)
+			log.Printf("pta: validacao: %s considerou primo o numero negativo %s", name, n)
+		}
+		return
+	}
+	if expected := n.ProbablyPrime(validationRounds); expected != prime {
+		discrepancies.Add(1)
+		log.Printf("pta: validacao: %s retornou primo=%t para %s, ProbablyPrime(%d) retornou %t",
+			name, prime, n, validationRounds, expected)
+	}
+}
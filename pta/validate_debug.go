@@ -0,0 +1,6 @@
+//go:build debug
+
+package pta
+
+// Em builds de debug a validacao cruzada esta sempre ligada
+const debugValidation = true
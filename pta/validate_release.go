@@ -0,0 +1,5 @@
+//go:build !debug
+
+package pta
+
+const debugValidation = false
@@ -0,0 +1,62 @@
+package recreational
+
+import (
+	"iter"
+	"math/big"
+)
+
+// LucasLehmer informa se o numero de Mersenne M_p = 2^p - 1 eh primo, pelo
+// teste de Lucas-Lehmer, que eh deterministico: com s_0 = 4 e
+// s_(i+1) = s_i^2 - 2 (mod M_p), M_p eh primo se e somente se
+// s_(p-2) ≡ 0 (mod M_p). Se p for composto, M_p tambem eh.
+func LucasLehmer(p uint) bool {
+	switch {
+	case p == 2:
+		return true
+	case p < 2 || !new(big.Int).SetUint64(uint64(p)).ProbablyPrime(0):
+		return false
+	}
+	m := mersenne(p)
+	s := big.NewInt(4)
+	hi := new(big.Int)
+	for i := uint(0); i < p-2; i++ {
+		s.Mul(s, s).Sub(s, big.NewInt(2))
+		// x mod (2^p - 1) = (x mod 2^p) + (x >> p), repetido ate x < 2^p
+		for s.BitLen() > int(p) {
+			hi.Rsh(s, p)
+			s.And(s, m).Add(s, hi)
+		}
+		if s.Cmp(m) == 0 {
+			s.SetInt64(0)
+		}
+	}
+	return s.Sign() == 0
+}
+
+// PerfectFromMersenne retorna o numero perfeito par 2^(p-1) * (2^p - 1),
+// se o teste de Lucas-Lehmer confirmar que 2^p - 1 eh primo. Pelo teorema
+// de Euclides-Euler, todo numero perfeito par tem essa forma.
+func PerfectFromMersenne(p uint) (*big.Int, bool) {
+	if !LucasLehmer(p) {
+		return nil, false
+	}
+	return new(big.Int).Lsh(mersenne(p), p-1), true
+}
+
+// PerfectNumbers enumera, em ordem crescente, os numeros perfeitos pares
+// 2^(p-1) * (2^p - 1) com p <= maxExponent, junto com o expoente p
+func PerfectNumbers(maxExponent uint) iter.Seq2[uint, *big.Int] {
+	return func(yield func(uint, *big.Int) bool) {
+		for p := uint(2); p <= maxExponent; p++ {
+			if n, ok := PerfectFromMersenne(p); ok && !yield(p, n) {
+				return
+			}
+		}
+	}
+}
+
+// mersenne retorna M_p = 2^p - 1
+func mersenne(p uint) *big.Int {
+	m := new(big.Int).Lsh(big.NewInt(1), p)
+	return m.Sub(m, big.NewInt(1))
+}
@@ -0,0 +1,121 @@
+// O pacote recreational busca primos de formas curiosas, por diversao e
+// como demonstracao dos testes de primalidade: primos palindromos (que se
+// leem igualmente nos dois sentidos, como 10301), repunits primos (so com
+// o digito 1, como 1111111111111111111), primos de Fibonacci e primos
+// "personalizados", que terminam com ou contem um padrao escolhido (veja
+// Vanity).
+//
+// Cada forma tem um enumerador que ja descarta os candidatos que nao podem
+// ser primos, e Primes aplica a eles um teste do pacote pta.
+package recreational
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/pta"
+	"iter"
+	"math/big"
+)
+
+// Palindromes enumera, em ordem crescente, os palindromos decimais de
+// digits digitos que podem ser primos. Com mais de um digito, o primeiro
+// (igual ao ultimo) precisa ser 1, 3, 7 ou 9. Todo palindromo com numero
+// par de digitos eh multiplo de 11, entao para digits par so 11 eh
+// enumerado, com digits = 2.
+func Palindromes(digits int) iter.Seq[*big.Int] {
+	return func(yield func(*big.Int) bool) {
+		switch {
+		case digits == 1:
+			for _, p := range []int64{2, 3, 5, 7} {
+				if !yield(big.NewInt(p)) {
+					return
+				}
+			}
+			return
+		case digits == 2:
+			yield(big.NewInt(11))
+			return
+		case digits < 1 || digits%2 == 0:
+			return
+		}
+
+		// O palindromo eh definido pela sua metade mais significativa, de
+		// half digitos, espelhada sem repetir o digito central
+		half := (digits + 1) / 2
+		rest := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(half-1)), nil)
+		prefix := new(big.Int)
+		for _, first := range []int64{1, 3, 7, 9} {
+			end := new(big.Int).Mul(big.NewInt(first+1), rest)
+			for prefix.Mul(big.NewInt(first), rest); prefix.Cmp(end) < 0; prefix.Add(prefix, big.NewInt(1)) {
+				if !yield(mirror(prefix)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// mirror retorna o palindromo de digitos impares cuja metade mais
+// significativa eh prefix
+func mirror(prefix *big.Int) *big.Int {
+	s := []byte(prefix.String())
+	for i := len(s) - 2; i >= 0; i-- {
+		s = append(s, s[i])
+	}
+	n, _ := new(big.Int).SetString(string(s), 10)
+	return n
+}
+
+// Repunits enumera os repunits R_n = (10^n - 1) / 9, o numero formado por
+// n digitos 1, com n primo e n <= maxDigits. Se n = ab, R_a divide R_n,
+// entao os demais repunits sao compostos.
+func Repunits(maxDigits int) iter.Seq[*big.Int] {
+	return func(yield func(*big.Int) bool) {
+		ten := big.NewInt(10)
+		r := big.NewInt(1) // R_n
+		for n := 2; n <= maxDigits; n++ {
+			r.Mul(r, ten).Add(r, big.NewInt(1))
+			if big.NewInt(int64(n)).ProbablyPrime(0) && !yield(new(big.Int).Set(r)) {
+				return
+			}
+		}
+	}
+}
+
+// Fibonacci enumera os numeros de Fibonacci F_k com k <= maxIndex que podem
+// ser primos, junto com o indice k. Se d divide k, F_d divide F_k, entao
+// alem de F_4 = 3 so os F_k com k primo sao enumerados (F_2 = 1 nao eh
+// primo). Cada F_k eh calculado pela duplicacao de numutil.Fibonacci.
+func Fibonacci(maxIndex int) iter.Seq2[int, *big.Int] {
+	return func(yield func(int, *big.Int) bool) {
+		for k := 3; k <= maxIndex; k++ {
+			if k != 4 && !big.NewInt(int64(k)).ProbablyPrime(0) {
+				continue
+			}
+			if !yield(k, numutil.Fibonacci(big.NewInt(int64(k)), nil)) {
+				return
+			}
+		}
+	}
+}
+
+// Primes aplica o teste test, com a configuracao cfg, aos candidatos e
+// enumera os aprovados, na ordem dos candidatos, junto com o erro que
+// interrompeu a busca, se houver. Os candidatos passam antes pelo mesmo
+// pre-filtro por mdc da geracao de primos (veja pta.Config.Prescreen).
+func Primes(candidates iter.Seq[*big.Int], test pta.PrimalityTest, cfg pta.Config) iter.Seq2[*big.Int, error] {
+	return func(yield func(*big.Int, error) bool) {
+		for n := range candidates {
+			if numutil.HasSmallFactor(n, cfg.PrescreenPrimes()) {
+				continue
+			}
+			res := test.IsPrime(n, cfg)
+			if res.Err != nil {
+				yield(nil, res.Err)
+				return
+			}
+			if res.Prime && !yield(n, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package recreational
+
+import (
+	"PrimeNumGenerator/pta"
+	"errors"
+	"iter"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// collect retorna os primos entre os candidatos, como strings
+func collect(t *testing.T, candidates iter.Seq[*big.Int]) []string {
+	t.Helper()
+	test, err := pta.Get("miller-rabin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var primes []string
+	for p, err := range Primes(candidates, test, pta.Config{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		primes = append(primes, p.String())
+	}
+	return primes
+}
+
+func TestPalindromicPrimes(t *testing.T) {
+	var got []string
+	for digits := 1; digits <= 4; digits++ {
+		got = append(got, collect(t, Palindromes(digits))...)
+	}
+	want := []string{"2", "3", "5", "7", "11", "101", "131", "151", "181", "191",
+		"313", "353", "373", "383", "727", "757", "787", "797", "919", "929"}
+	if !slices.Equal(got, want) {
+		t.Errorf("primos palindromos ate 4 digitos = %v, esperados %v", got, want)
+	}
+
+	// 5 digitos: 93 primos palindromos, o primeiro 10301 e o ultimo 98689
+	five := collect(t, Palindromes(5))
+	if len(five) != 93 || five[0] != "10301" || five[92] != "98689" {
+		t.Errorf("%d primos palindromos de 5 digitos, de %s a %s", len(five), five[0], five[len(five)-1])
+	}
+}
+
+func TestPalindromesOrder(t *testing.T) {
+	var prev *big.Int
+	count := 0
+	for n := range Palindromes(7) {
+		s := n.String()
+		for i := 0; i < len(s)/2; i++ {
+			if s[i] != s[len(s)-1-i] {
+				t.Fatalf("%s nao eh palindromo", s)
+			}
+		}
+		if len(s) != 7 || (prev != nil && prev.Cmp(n) >= 0) {
+			t.Fatalf("%s fora de ordem ou com tamanho errado", s)
+		}
+		prev = n
+		count++
+	}
+	// 4 digitos iniciais, 10 * 10 * 10 para os demais da metade
+	if count != 4000 {
+		t.Errorf("%d palindromos de 7 digitos, esperados 4000", count)
+	}
+}
+
+func TestRepunitPrimes(t *testing.T) {
+	var got []int
+	for _, p := range collect(t, Repunits(100)) {
+		got = append(got, len(p))
+	}
+	if want := []int{2, 19, 23}; !slices.Equal(got, want) {
+		t.Errorf("repunits primos ate 100 digitos: R%v, esperados R%v", got, want)
+	}
+}
+
+func TestPerfectNumbers(t *testing.T) {
+	var exponents []uint
+	var perfect []string
+	for p, n := range PerfectNumbers(700) {
+		exponents = append(exponents, p)
+		if len(perfect) < 5 {
+			perfect = append(perfect, n.String())
+		}
+	}
+	if want := []uint{2, 3, 5, 7, 13, 17, 19, 31, 61, 89, 107, 127, 521, 607}; !slices.Equal(exponents, want) {
+		t.Errorf("expoentes de Mersenne ate 700 = %v, esperados %v", exponents, want)
+	}
+	if want := []string{"6", "28", "496", "8128", "33550336"}; !slices.Equal(perfect, want) {
+		t.Errorf("primeiros numeros perfeitos = %v, esperados %v", perfect, want)
+	}
+	// 2^11 - 1 = 23 * 89
+	if n, ok := PerfectFromMersenne(11); ok {
+		t.Errorf("PerfectFromMersenne(11) = %s", n)
+	}
+}
+
+func TestFibonacciPrimes(t *testing.T) {
+	index := make(map[string]int)
+	candidates := func(yield func(*big.Int) bool) {
+		for k, f := range Fibonacci(600) {
+			index[f.String()] = k
+			if !yield(f) {
+				return
+			}
+		}
+	}
+	var got []int
+	for _, p := range collect(t, candidates) {
+		got = append(got, index[p])
+	}
+	want := []int{3, 4, 5, 7, 11, 13, 17, 23, 29, 43, 47, 83, 131, 137, 359, 431, 433, 449, 509, 569, 571}
+	if !slices.Equal(got, want) {
+		t.Errorf("indices dos primos de Fibonacci ate 600 = %v, esperados %v", got, want)
+	}
+}
+
+func TestVanity(t *testing.T) {
+	test, err := pta.Get("miller-rabin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		pattern string
+		base    int
+		mode    VanityMode
+	}{
+		{"2027", 10, Contains},
+		{"777", 10, Suffix},
+		{"C0FFEE", 16, Contains},
+		{"beef", 16, Contains},
+		{"deadbeef", 16, Suffix},
+	}
+	for _, c := range cases {
+		p, err := Vanity(128, c.pattern, c.base, c.mode, test, pta.Config{})
+		if err != nil {
+			t.Fatalf("padrao %q: %v", c.pattern, err)
+		}
+		s, want := p.Text(c.base), strings.ToLower(c.pattern)
+		if p.BitLen() != 128 || !p.ProbablyPrime(20) {
+			t.Errorf("padrao %q: %s nao eh um primo de 128 bits", c.pattern, s)
+		}
+		if c.mode == Suffix && !strings.HasSuffix(s, want) || !strings.Contains(s, want) {
+			t.Errorf("padrao %q: %s nao tem o padrao", c.pattern, s)
+		}
+	}
+
+	// Finais pares (ou 5, em decimal) e padroes grandes demais nao tem primos
+	for _, pattern := range []string{"12", "15", "123456789012345678901234567890123456789"} {
+		if _, err := Vanity(128, pattern, 10, Suffix, test, pta.Config{}); !errors.Is(err, ErrVanityPattern) {
+			t.Errorf("padrao %q: erro %v", pattern, err)
+		}
+	}
+	if _, err := Vanity(128, "12x", 10, Contains, test, pta.Config{}); err == nil {
+		t.Error("padrao com digito invalido deveria falhar")
+	}
+}
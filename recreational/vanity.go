@@ -0,0 +1,88 @@
+package recreational
+
+import (
+	"PrimeNumGenerator/pta"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// VanityMode diz onde o padrao de Vanity aparece no primo
+type VanityMode int
+
+const (
+	// Suffix exige que o primo termine com o padrao
+	Suffix VanityMode = iota
+	// Contains exige que o primo contenha o padrao
+	Contains
+)
+
+// vanityDigits sao os digitos aceitos nos padroes, na ordem dos valores
+const vanityDigits = "0123456789abcdef"
+
+// ErrVanityPattern indica que o padrao eh invalido ou que nenhum primo com
+// o tamanho pedido pode te-lo na posicao pedida
+var ErrVanityPattern = errors.New("recreational: padrao impossivel")
+
+// Vanity busca um primo provavel de bits bits cuja representacao na base
+// (10 ou 16) termina com pattern ou, com mode = Contains, contem pattern.
+//
+// A busca nao filtra os primos gerados: os candidatos ja nascem com o
+// padrao. Com Suffix, p ≡ pattern (mod base^k), onde k eh o numero de
+// digitos do padrao, e pta.GenerateCongruent percorre so os numeros dessa
+// classe, sorteando o primeiro com a entropia de cfg. Por isso o padrao
+// precisa terminar em um digito coprimo com a base (1, 3, 7 ou 9 em
+// decimal; um digito impar em hexadecimal): os demais finais so permitem
+// primos pequenos. Com Contains, o padrao eh seguido de um digito sorteado
+// entre esses, e a busca eh a mesma.
+func Vanity(bits int, pattern string, base int, mode VanityMode, test pta.PrimalityTest, cfg pta.Config) (*big.Int, error) {
+	if base != 10 && base != 16 {
+		return nil, fmt.Errorf("recreational: base %d nao suportada (use 10 ou 16)", base)
+	}
+	pattern = strings.ToLower(pattern)
+	if pattern == "" || strings.Trim(pattern, vanityDigits[:base]) != "" {
+		return nil, fmt.Errorf("%w: %q nao eh um numero na base %d", ErrVanityPattern, pattern, base)
+	}
+	residue, _ := new(big.Int).SetString(pattern, base)
+	b := big.NewInt(int64(base))
+	modulus := new(big.Int).Exp(b, big.NewInt(int64(len(pattern))), nil)
+
+	if mode == Contains {
+		digit, err := unitDigit(base, cfg)
+		if err != nil {
+			return nil, err
+		}
+		residue.Mul(residue, b).Add(residue, big.NewInt(digit))
+		modulus.Mul(modulus, b)
+	}
+	last := new(big.Int).Mod(residue, b)
+	if new(big.Int).GCD(nil, nil, last, b).Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("%w: nenhum primo grande termina em %c na base %d", ErrVanityPattern, vanityDigits[last.Int64()], base)
+	}
+	// A classe precisa de numeros de sobra entre os de bits bits; 8 bits
+	// deixam ao menos 2^7 candidatos
+	if modulus.BitLen() > bits-8 {
+		return nil, fmt.Errorf("%w: padrao longo demais para primos de %d bits", ErrVanityPattern, bits)
+	}
+
+	res, err := pta.GenerateCongruent(bits, residue, modulus, test, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res.Number, nil
+}
+
+// unitDigit sorteia um digito coprimo com a base
+func unitDigit(base int, cfg pta.Config) (int64, error) {
+	b := big.NewInt(int64(base))
+	for {
+		d, err := cfg.Entropy().Int(b)
+		if err != nil {
+			return 0, err
+		}
+		if new(big.Int).GCD(nil, nil, d, b).Cmp(big.NewInt(1)) == 0 {
+			return d.Int64(), nil
+		}
+	}
+}
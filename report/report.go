@@ -0,0 +1,199 @@
+// O pacote report executa uma matriz de experimentos (geradores x tamanhos
+// em bits x testes de primalidade) e monta um relatorio em Markdown com
+// tabelas e graficos de barras em texto, comparando o tempo e as tentativas
+// da geracao de primos e os testes estatisticos de cada gerador, como no
+// relatorio original do trabalho.
+package report
+
+import (
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"PrimeNumGenerator/stats"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// chartWidth eh a largura, em caracteres, da maior barra dos graficos
+const chartWidth = 40
+
+// Matrix descreve os experimentos do relatorio. Para cada gerador e
+// tamanho, Runs candidatos sao sorteados e cada teste gera um primo a
+// partir de cada um deles; Samples saidas do gerador passam pelos testes
+// estatisticos. NewGenerator cria os geradores pelo nome.
+type Matrix struct {
+	Generators   []string
+	Bits         []int
+	Tests        []string
+	Runs         int
+	Samples      int
+	Config       pta.Config
+	NewGenerator func(name string, bits int) (prng.Generator, error)
+}
+
+// Cell eh o resultado de um teste com um gerador e um tamanho
+type Cell struct {
+	Generator    string
+	Bits         int
+	Test         string
+	Runs         int
+	MeanTime     time.Duration
+	MeanAttempts float64
+	MaxAttempts  int
+}
+
+// Score eh o resultado dos testes estatisticos de um gerador e um tamanho
+type Score struct {
+	Generator string
+	Bits      int
+	Stats     stats.Report
+}
+
+// Report eh o resultado da matriz
+type Report struct {
+	Time     time.Time
+	Duration time.Duration
+	Runs     int
+	Cells    []Cell
+	Scores   []Score
+}
+
+// Run executa os experimentos de m
+func Run(m Matrix) (*Report, error) {
+	if len(m.Generators) == 0 || len(m.Bits) == 0 || len(m.Tests) == 0 {
+		return nil, errors.New("report: matriz vazia")
+	}
+	if m.Runs < 1 {
+		return nil, fmt.Errorf("report: numero de execucoes invalido: %d", m.Runs)
+	}
+	if m.NewGenerator == nil {
+		return nil, errors.New("report: NewGenerator nao definido")
+	}
+	tests := make([]pta.PrimalityTest, len(m.Tests))
+	for i, name := range m.Tests {
+		var err error
+		if tests[i], err = pta.Get(name); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Report{Time: clock.Now().UTC(), Runs: m.Runs}
+	for _, name := range m.Generators {
+		for _, bits := range m.Bits {
+			g, err := m.NewGenerator(name, bits)
+			if err != nil {
+				return nil, err
+			}
+			cells := make([]Cell, len(tests))
+			for i, t := range tests {
+				cells[i] = Cell{Generator: name, Bits: bits, Test: t.Name(), Runs: m.Runs}
+			}
+			for run := 0; run < m.Runs; run++ {
+				// Todos os testes partem do mesmo candidato, como nas
+				// demonstracoes fibonacci e bbs
+				candidate := g.Next()
+				for i, t := range tests {
+					res, err := pta.Generate(bits, candidate, t, m.Config)
+					if err != nil {
+						return nil, err
+					}
+					cells[i].MeanTime += res.Duration
+					cells[i].MeanAttempts += float64(res.Attempts)
+					cells[i].MaxAttempts = max(cells[i].MaxAttempts, res.Attempts)
+				}
+			}
+			for i := range cells {
+				cells[i].MeanTime /= time.Duration(m.Runs)
+				cells[i].MeanAttempts /= float64(m.Runs)
+			}
+			r.Cells = append(r.Cells, cells...)
+
+			if m.Samples > 0 {
+				s, err := stats.Evaluate(g, m.Samples)
+				if err != nil {
+					return nil, err
+				}
+				r.Scores = append(r.Scores, Score{Generator: name, Bits: bits, Stats: s})
+			}
+		}
+	}
+	r.Duration = clock.Since(r.Time)
+	return r, nil
+}
+
+// WriteMarkdown escreve o relatorio em Markdown
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Relatório de geração de números primos")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "Gerado em %s, em %s, com %d execução(ões) por combinação.\n",
+		r.Time.Format(time.RFC3339), r.Duration.Round(time.Millisecond), r.Runs)
+
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Geração de primos")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "| Gerador | Bits | Teste | Tempo médio | Tentativas (média) | Tentativas (máx.) |")
+	fmt.Fprintln(bw, "|---|---:|---|---:|---:|---:|")
+	for _, c := range r.Cells {
+		fmt.Fprintf(bw, "| %s | %d | %s | %s | %.1f | %d |\n", c.Generator, c.Bits, c.Test, c.MeanTime, c.MeanAttempts, c.MaxAttempts)
+	}
+
+	labels := make([]string, len(r.Cells))
+	times := make([]float64, len(r.Cells))
+	attempts := make([]float64, len(r.Cells))
+	for i, c := range r.Cells {
+		labels[i] = fmt.Sprintf("%s %d %s", c.Generator, c.Bits, c.Test)
+		times[i] = float64(c.MeanTime)
+		attempts[i] = c.MeanAttempts
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "### Tempo médio")
+	fmt.Fprintln(bw)
+	writeChart(bw, labels, times, func(v float64) string { return time.Duration(v).String() })
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "### Tentativas (média)")
+	fmt.Fprintln(bw)
+	writeChart(bw, labels, attempts, func(v float64) string { return fmt.Sprintf("%.1f", v) })
+
+	if len(r.Scores) > 0 {
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "## Testes estatísticos")
+		fmt.Fprintln(bw)
+		fmt.Fprintf(bw, "Valores-p dos testes de frequência e de corridas (aprovado se ambos forem pelo menos %.2f).\n", stats.Alpha)
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "| Gerador | Bits | Bits avaliados | Proporção de uns | Frequência (p) | Corridas (p) | Resultado |")
+		fmt.Fprintln(bw, "|---|---:|---:|---:|---:|---:|---|")
+		for _, s := range r.Scores {
+			verdict := "reprovado"
+			if s.Stats.Pass() {
+				verdict = "aprovado"
+			}
+			fmt.Fprintf(bw, "| %s | %d | %d | %.4f | %.4f | %.4f | %s |\n", s.Generator, s.Bits, s.Stats.Bits,
+				float64(s.Stats.Ones)/float64(s.Stats.Bits), s.Stats.Monobit, s.Stats.Runs, verdict)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeChart escreve um grafico de barras horizontais em texto, em um
+// bloco de codigo para manter o alinhamento
+func writeChart(w io.Writer, labels []string, values []float64, format func(float64) string) {
+	width, most := 0, 0.0
+	for i, l := range labels {
+		width = max(width, len(l))
+		most = max(most, values[i])
+	}
+	fmt.Fprintln(w, "```")
+	for i, l := range labels {
+		n := 0
+		if most > 0 {
+			n = int(values[i] / most * chartWidth)
+		}
+		fmt.Fprintf(w, "%-*s %s %s\n", width, l, strings.Repeat("█", max(n, 1)), format(values[i]))
+	}
+	fmt.Fprintln(w, "```")
+}
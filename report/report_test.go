@@ -0,0 +1,65 @@
+package report
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	m := Matrix{
+		Generators: []string{"lfg", "bbs"},
+		Bits:       []int{32, 64},
+		Tests:      []string{"miller-rabin", "fermat"},
+		Runs:       2,
+		Samples:    50,
+		NewGenerator: func(name string, bits int) (prng.Generator, error) {
+			if name == "bbs" {
+				return prng.NewBBSWithSecurity(bits, prng.Strict)
+			}
+			return prng.NewLFGWithSecurity(10, 7, 10, bits, prng.Strict)
+		},
+	}
+	r, err := Run(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Cells) != 8 || len(r.Scores) != 4 {
+		t.Fatalf("%d celulas e %d avaliacoes, esperadas 8 e 4", len(r.Cells), len(r.Scores))
+	}
+	for _, c := range r.Cells {
+		if c.Runs != 2 || c.MeanAttempts < 1 || c.MaxAttempts < 1 || c.MeanTime <= 0 {
+			t.Errorf("celula inesperada: %+v", c)
+		}
+	}
+	if s := r.Scores[3]; s.Generator != "bbs" || s.Bits != 64 || s.Stats.Bits != 50*64 {
+		t.Errorf("avaliacao inesperada: %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	for _, want := range []string{"| lfg | 32 | miller-rabin |", "| bbs | 64 | fermat |", "## Testes estatísticos", "### Tempo médio", "█"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("relatorio sem %q", want)
+		}
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	newGen := func(string, int) (prng.Generator, error) { return prng.NewLFG(10, 7, 10, 32), nil }
+	for _, m := range []Matrix{
+		{Bits: []int{32}, Tests: []string{"fermat"}, Runs: 1, NewGenerator: newGen},
+		{Generators: []string{"lfg"}, Bits: []int{32}, Tests: []string{"fermat"}, NewGenerator: newGen},
+		{Generators: []string{"lfg"}, Bits: []int{32}, Tests: []string{"aks"}, Runs: 1, NewGenerator: newGen},
+		{Generators: []string{"lfg"}, Bits: []int{32}, Tests: []string{"fermat"}, Runs: 1, Config: pta.Config{}},
+	} {
+		if _, err := Run(m); err == nil {
+			t.Errorf("matriz invalida aceita: %+v", m)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// O pacote rsa monta um par de chaves RSA a partir de dois primos gerados
+// pelo projeto e cifra e decifra mensagens com ele, passo a passo, para
+// fins didaticos.
+//
+// Eh o RSA "de livro-texto": a mensagem vira um inteiro m < n e a cifra eh
+// apenas c = m^e mod n, sem preenchimento (OAEP). Isso o torna
+// deterministico e maleavel; para proteger dados de verdade use
+// crypto/rsa.
+package rsa
+
+import (
+	"PrimeNumGenerator/numutil"
+	"errors"
+	"math/big"
+)
+
+// DefaultE eh o expoente publico usual, 2^16 + 1: primo, e com apenas dois
+// bits ligados, o que deixa a cifragem barata
+const DefaultE = 65537
+
+// ErrMessageTooLong indica uma mensagem que nao cabe em um inteiro menor
+// que o modulo
+var ErrMessageTooLong = errors.New("rsa: mensagem grande demais para o modulo")
+
+var one = big.NewInt(1)
+
+// Key eh um par de chaves RSA. A chave publica eh (N, E); D e os valores
+// do CRT (Dp, Dq e Qinv) formam a chave privada, junto com P e Q.
+type Key struct {
+	P, Q   *big.Int
+	N      *big.Int // modulo, P*Q
+	E      *big.Int // expoente publico
+	Lambda *big.Int // funcao de Carmichael, mmc(P-1, Q-1)
+	D      *big.Int // expoente privado, E^-1 mod Lambda
+	Dp, Dq *big.Int // D mod (P-1) e D mod (Q-1)
+	Qinv   *big.Int // Q^-1 mod P
+}
+
+// Compatible informa se o primo p pode ser fator de um modulo com o
+// expoente publico e, isto eh, se mdc(e, p-1) = 1. Caso contrario, e nao
+// tem inverso e nao ha expoente privado.
+func Compatible(p, e *big.Int) bool {
+	g, _, _ := numutil.ExtGCD(e, new(big.Int).Sub(p, one))
+	return g.Cmp(one) == 0
+}
+
+// NewKey monta o par de chaves dos primos p e q com o expoente publico e.
+// O expoente privado eh o inverso de e modulo lambda(n) = mmc(p-1, q-1),
+// como no PKCS #1, o menor que funciona; o phi(n) = (p-1)(q-1) dos livros
+// tambem serviria, com um d maior. Os primos nao sao testados: cabe a quem
+// chama gera-los com um teste de primalidade.
+func NewKey(p, q, e *big.Int) (*Key, error) {
+	switch {
+	case p.Cmp(big.NewInt(3)) < 0 || q.Cmp(big.NewInt(3)) < 0:
+		return nil, errors.New("rsa: os primos devem ser impares")
+	case p.Cmp(q) == 0:
+		return nil, errors.New("rsa: p e q devem ser distintos")
+	case e.Cmp(big.NewInt(3)) < 0 || e.Bit(0) == 0:
+		return nil, errors.New("rsa: o expoente publico deve ser impar e maior que 1")
+	case !Compatible(p, e) || !Compatible(q, e):
+		return nil, errors.New("rsa: o expoente publico nao eh coprimo com p-1 e q-1")
+	}
+	pm1, qm1 := new(big.Int).Sub(p, one), new(big.Int).Sub(q, one)
+	g, _, _ := numutil.ExtGCD(pm1, qm1)
+	lambda := new(big.Int).Mul(pm1, qm1)
+	lambda.Div(lambda, g)
+	d, err := numutil.ModInverse(e, lambda)
+	if err != nil {
+		return nil, err
+	}
+	qinv, err := numutil.ModInverse(q, p)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		P:      new(big.Int).Set(p),
+		Q:      new(big.Int).Set(q),
+		N:      new(big.Int).Mul(p, q),
+		E:      new(big.Int).Set(e),
+		Lambda: lambda,
+		D:      d,
+		Dp:     new(big.Int).Mod(d, pm1),
+		Dq:     new(big.Int).Mod(d, qm1),
+		Qinv:   qinv,
+	}, nil
+}
+
+// Encrypt cifra m, que deve estar em [0, N), com a chave publica:
+// c = m^E mod N
+func (k *Key) Encrypt(m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(k.N) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+	return new(big.Int).Exp(m, k.E, k.N), nil
+}
+
+// Decrypt decifra c com o expoente privado: m = c^D mod N
+func (k *Key) Decrypt(c *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k.D, k.N)
+}
+
+// DecryptCRT decifra c pelo teorema chines do resto, com duas
+// exponenciacoes de metade do tamanho: m1 = c^Dp mod P, m2 = c^Dq mod Q e
+// m = m2 + Q*(Qinv*(m1 - m2) mod P), a recombinacao de Garner. Como o custo
+// da exponenciacao cresce com o cubo do tamanho, fica cerca de 4 vezes mais
+// rapido que Decrypt.
+func (k *Key) DecryptCRT(c *big.Int) *big.Int {
+	m1 := new(big.Int).Exp(c, k.Dp, k.P)
+	m2 := new(big.Int).Exp(c, k.Dq, k.Q)
+	h := m1.Sub(m1, m2)
+	h.Mul(h, k.Qinv)
+	h.Mod(h, k.P)
+	return h.Mul(h, k.Q).Add(h, m2)
+}
+
+// EncodeMessage converte msg no inteiro big-endian que o representa, que
+// deve ser menor que n
+func EncodeMessage(msg []byte, n *big.Int) (*big.Int, error) {
+	m := new(big.Int).SetBytes(msg)
+	if m.Cmp(n) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+	return m, nil
+}
+
+// DecodeMessage desfaz EncodeMessage. Os bytes nulos do inicio da
+// mensagem original se perdem na conversao, como em todo RSA sem
+// preenchimento.
+func DecodeMessage(m *big.Int) []byte {
+	return m.Bytes()
+}
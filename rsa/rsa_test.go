@@ -0,0 +1,74 @@
+package rsa
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNewKey(t *testing.T) {
+	// O exemplo classico: p = 61, q = 53, e = 17
+	k, err := NewKey(big.NewInt(61), big.NewInt(53), big.NewInt(17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want *big.Int
+	}{
+		{"N", k.N, big.NewInt(3233)},
+		{"Lambda", k.Lambda, big.NewInt(780)},
+		{"D", k.D, big.NewInt(413)},
+		{"Dp", k.Dp, big.NewInt(53)},
+		{"Dq", k.Dq, big.NewInt(49)},
+		{"Qinv", k.Qinv, big.NewInt(38)},
+	} {
+		if c.got.Cmp(c.want) != 0 {
+			t.Errorf("%s = %s, esperado %s", c.name, c.got, c.want)
+		}
+	}
+
+	for _, c := range [][3]int64{{61, 61, 17}, {61, 53, 4}, {61, 53, 1}, {61, 53, 3}, {2, 53, 17}} {
+		if _, err := NewKey(big.NewInt(c[0]), big.NewInt(c[1]), big.NewInt(c[2])); err == nil {
+			t.Errorf("NewKey(%d, %d, %d) aceito", c[0], c[1], c[2])
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	p, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127 - 1
+	q, _ := new(big.Int).SetString("618970019642690137449562111", 10)             // 2^89 - 1
+	k, err := NewKey(p, q, big.NewInt(DefaultE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("Olá, RSA!")
+	m, err := EncodeMessage(msg, k.N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := k.Encrypt(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Cmp(m) == 0 {
+		t.Fatal("cifra igual a mensagem")
+	}
+	for name, got := range map[string]*big.Int{"Decrypt": k.Decrypt(c), "DecryptCRT": k.DecryptCRT(c)} {
+		if string(DecodeMessage(got)) != string(msg) {
+			t.Errorf("%s = %q, esperado %q", name, DecodeMessage(got), msg)
+		}
+	}
+
+	if _, err := EncodeMessage(make([]byte, 28), k.N); err != nil {
+		t.Errorf("mensagem nula recusada: %v", err)
+	}
+	long := make([]byte, 28)
+	long[0] = 1
+	if _, err := EncodeMessage(long, k.N); !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("mensagem de 28 bytes = %v, esperado ErrMessageTooLong", err)
+	}
+	if _, err := k.Encrypt(k.N); !errors.Is(err, ErrMessageTooLong) {
+		t.Errorf("Encrypt(N) = %v, esperado ErrMessageTooLong", err)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Scope restringe o que um cliente autenticado pode fazer
+type Scope struct {
+	MaxBits   int      `json:"max_bits"`  // maior tamanho aceito por /generate e /check; 0 usa o do servidor
+	Endpoints []string `json:"endpoints"` // rotas permitidas (ex.: "/generate", "/beacon"); vazio permite todas
+}
+
+// Auth define os clientes aceitos pelo servidor. Um cliente se autentica
+// pela chave de API do cabecalho X-API-Key ou, com TLS mutuo, pelo nome
+// comum (CN) do seu certificado.
+type Auth struct {
+	Keys    map[string]Scope `json:"keys"`    // escopo de cada chave de API
+	Clients map[string]Scope `json:"clients"` // escopo de cada CN de certificado
+	hashes  map[[sha256.Size]byte]Scope
+}
+
+// LoadAuth le a configuracao de autenticacao de um arquivo JSON no formato
+// {"keys": {"chave": {"max_bits": 1024, "endpoints": ["/generate"]}}, "clients": {...}}
+func LoadAuth(path string) (*Auth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a := new(Auth)
+	if err := json.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	if len(a.Keys) == 0 && len(a.Clients) == 0 {
+		return nil, errors.New("server: nenhuma chave ou cliente na configuracao de autenticacao")
+	}
+	return a, nil
+}
+
+// scope retorna o escopo do cliente de r, ou false se ele nao for aceito.
+// As chaves sao comparadas pelo hash, para que o tempo da busca nao
+// dependa de quantos bytes da chave enviada estao corretos.
+func (a *Auth) scope(r *http.Request) (Scope, bool) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		s, ok := a.hashes[sha256.Sum256([]byte(key))]
+		return s, ok
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		s, ok := a.Clients[r.TLS.VerifiedChains[0][0].Subject.CommonName]
+		return s, ok
+	}
+	return Scope{}, false
+}
+
+// allows informa se o escopo permite a rota path
+func (s Scope) allows(path string) bool {
+	if len(s.Endpoints) == 0 {
+		return true
+	}
+	for _, e := range s.Endpoints {
+		if path == e || strings.HasPrefix(path, strings.TrimSuffix(e, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type scopeKey struct{}
+
+// requestScope retorna o escopo do cliente autenticado em r
+func requestScope(r *http.Request) Scope {
+	s, _ := r.Context().Value(scopeKey{}).(Scope)
+	return s
+}
+
+// authenticate rejeita as requisicoes de clientes nao aceitos (401) ou fora
+// do seu escopo (403). /healthz nao exige autenticacao.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if s.cfg.Auth == nil || r.URL.Path == "/healthz" {
+		return r, true
+	}
+	scope, ok := s.cfg.Auth.scope(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.New("chave de API ou certificado invalido"))
+		return nil, false
+	}
+	if !scope.allows(r.URL.Path) {
+		writeError(w, http.StatusForbidden, errors.New("rota fora do escopo da chave"))
+		return nil, false
+	}
+	return r.WithContext(context.WithValue(r.Context(), scopeKey{}, scope)), true
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"PrimeNumGenerator/clock"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// APIKeyHeader eh o cabecalho com a chave de API do cliente
+const APIKeyHeader = "X-API-Key"
+
+// maxBuckets limita o numero de clientes acompanhados pelo limitador;
+// acima dele, os baldes cheios (clientes inativos) sao descartados
+const maxBuckets = 10000
+
+// Limit eh um limite de taxa no formato balde de fichas: o cliente pode
+// fazer ate Burst requisicoes seguidas, e as fichas sao repostas a Rate
+// por segundo
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Limits define os limites de uso das rotas que geram ou testam numeros
+type Limits struct {
+	Default Limit            // limite por cliente; Rate 0 o desativa
+	Keys    map[string]Limit // limites por chave de API, no lugar de Default
+	// MaxConcurrent eh o numero de requisicoes atendidas ao mesmo tempo
+	// (0 = sem limite) e MaxQueue o de requisicoes aguardando vaga; alem
+	// disso, o servidor responde 429
+	MaxConcurrent int
+	MaxQueue      int
+}
+
+// bucket eh o balde de fichas de um cliente
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter aplica Limits as requisicoes
+type limiter struct {
+	limits  Limits
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+func newLimiter(limits Limits) *limiter {
+	l := &limiter{limits: limits, now: clock.Now, buckets: make(map[string]*bucket)}
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// client identifica o cliente pela chave de API ou, sem ela, pelo IP. So
+// as chaves conhecidas (com limite proprio em Limits.Keys ou aceitas pela
+// autenticacao) ganham um balde: com qualquer outra, o cliente eh
+// identificado pelo IP, para que trocar de chave inventada a cada
+// requisicao nao renove as fichas.
+func (l *limiter) client(r *http.Request) (id string, key string) {
+	if key = r.Header.Get(APIKeyHeader); key != "" {
+		_, limited := l.limits.Keys[key]
+		_, authenticated := r.Context().Value(scopeKey{}).(Scope)
+		if limited || authenticated {
+			return "key:" + key, key
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host, ""
+}
+
+// allow consome uma ficha do cliente de r. Se nao houver fichas, retorna
+// quanto tempo falta para a proxima.
+func (l *limiter) allow(r *http.Request) (bool, time.Duration) {
+	id, key := l.client(r)
+	limit, ok := l.limits.Keys[key]
+	if !ok || key == "" {
+		limit = l.limits.Default
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	burst := float64(max(limit.Burst, 1))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets[id]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.evict(now)
+		}
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[id] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// evict descarta os baldes que ja estariam cheios, considerando o limite
+// padrao; chamado com l.mu travado
+func (l *limiter) evict(now time.Time) {
+	for id, b := range l.buckets {
+		if now.Sub(b.last).Seconds()*l.limits.Default.Rate >= float64(l.limits.Default.Burst) || now.Sub(b.last) > time.Hour {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+// errQueueFull indica que a fila de trabalho esta cheia
+var errQueueFull = errors.New("servidor ocupado, tente novamente mais tarde")
+
+// acquire reserva uma vaga de trabalho, aguardando na fila se necessario.
+// Retorna a funcao que libera a vaga.
+func (l *limiter) acquire(r *http.Request) (func(), error) {
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+	if l.waiting.Add(1) > int64(l.limits.MaxQueue) {
+		l.waiting.Add(-1)
+		return nil, errQueueFull
+	}
+	defer l.waiting.Add(-1)
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	}
+}
+
+func (l *limiter) release() {
+	<-l.slots
+}
+
+// limited envolve h com o limite de taxa e a fila de trabalho
+func (s *Server) limited(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := s.limiter.allow(r); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			writeError(w, http.StatusTooManyRequests, errors.New("limite de requisicoes excedido"))
+			return
+		}
+		release, err := s.limiter.acquire(r)
+		if err != nil {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, err)
+			return
+		}
+		defer release()
+		h(w, r)
+	}
+}
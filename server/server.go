@@ -0,0 +1,276 @@
+// O pacote server expoe os geradores e testes de primalidade por HTTP.
+package server
+
+import (
+	"PrimeNumGenerator/auditlog"
+	"PrimeNumGenerator/beacon"
+	"PrimeNumGenerator/clock"
+	"PrimeNumGenerator/history"
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Config define o comportamento do servidor
+type Config struct {
+	Tests   pta.Config     // configuracao dos testes de primalidade
+	MaxBits int            // maior tamanho, em bits, aceito por /generate e /check
+	Beacon  *beacon.Beacon // farol exposto em /beacon; nil o desativa
+	History *history.Store // historico das geracoes de /generate; nil o desativa
+	// AuditLog registra cada primo emitido por /generate em um log
+	// encadeado por hashes; nil o desativa
+	AuditLog *auditlog.Log
+	Limits   Limits // limites de uso de /generate, /check e /beacon/next
+	Auth     *Auth  // clientes aceitos e seus escopos; nil dispensa autenticacao
+	// Candidates, se nao for nil, fornece os candidatos de /generate no
+	// lugar da entropia de Tests. Deve ser seguro para uso concorrente,
+	// como prng.Concurrent ou prng.Reseeding.
+	Candidates prng.Generator
+	// Deadline, se positivo, eh o prazo de cada /generate, que o parametro
+	// deadline so pode encurtar (veja pta.GenerateBefore)
+	Deadline time.Duration
+}
+
+// Server atende as requisicoes HTTP
+type Server struct {
+	cfg     Config
+	mux     *http.ServeMux
+	limiter *limiter
+}
+
+// testResponse eh a resposta de /generate e /check
+type testResponse struct {
+	Test   string     `json:"test"`
+	Result pta.Result `json:"result"`
+}
+
+// beaconResponse eh a resposta de /beacon/next
+type beaconResponse struct {
+	Output beacon.Output `json:"output"`
+	Proof  *beacon.Proof `json:"proof,omitempty"`
+}
+
+// New cria um servidor com a configuracao cfg
+func New(cfg Config) *Server {
+	s := &Server{cfg: cfg, mux: http.NewServeMux(), limiter: newLimiter(cfg.Limits)}
+	if cfg.Auth != nil {
+		cfg.Auth.hashes = make(map[[sha256.Size]byte]Scope, len(cfg.Auth.Keys))
+		for key, scope := range cfg.Auth.Keys {
+			cfg.Auth.hashes[sha256.Sum256([]byte(key))] = scope
+		}
+	}
+	s.mux.HandleFunc("GET /generate", s.limited(s.handleGenerate))
+	s.mux.HandleFunc("GET /check", s.limited(s.handleCheck))
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	if cfg.Beacon != nil {
+		s.mux.HandleFunc("GET /beacon", s.handleBeacon)
+		s.mux.HandleFunc("GET /beacon/next", s.limited(s.handleBeaconNext))
+		s.mux.HandleFunc("GET /beacon/proof", s.handleBeaconProof)
+	}
+	return s
+}
+
+// ServeHTTP implementa http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// test retorna o teste pedido no parametro test (miller-rabin por padrao)
+func test(r *http.Request) (pta.PrimalityTest, error) {
+	name := r.URL.Query().Get("test")
+	if name == "" {
+		name = "miller-rabin"
+	}
+	return pta.Get(name)
+}
+
+// handleHealth informa que o servidor esta de pe, para sondas de
+// orquestradores e o HEALTHCHECK do container
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// maxBits eh o maior tamanho aceito para a requisicao: o do servidor ou,
+// se menor, o do escopo do cliente
+func (s *Server) maxBits(r *http.Request) int {
+	maxBits := s.cfg.MaxBits
+	if scope := requestScope(r); scope.MaxBits > 0 {
+		maxBits = min(maxBits, scope.MaxBits)
+	}
+	return maxBits
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	maxBits := s.maxBits(r)
+	bits, err := strconv.Atoi(r.URL.Query().Get("bits"))
+	if err != nil || bits < 2 || bits > maxBits {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("bits deve estar entre 2 e %d", maxBits))
+		return
+	}
+	t, err := test(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	deadline, minBits, err := s.deadline(r, bits)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// candidates guarda o candidato de cada tamanho, para o historico
+	candidates := make(map[int]*big.Int)
+	next := func(size int) (*big.Int, error) {
+		if s.cfg.Candidates != nil {
+			candidates[size] = prng.ExactBits(s.cfg.Candidates, size)
+			return candidates[size], nil
+		}
+		candidate, err := s.cfg.Tests.Entropy().Bits(size)
+		candidates[size] = candidate
+		return candidate, err
+	}
+	var res pta.Result
+	if deadline.IsZero() {
+		var candidate *big.Int
+		if candidate, err = next(bits); err == nil {
+			res, err = pta.Generate(bits, candidate, t, s.cfg.Tests)
+		}
+	} else {
+		res, err = pta.GenerateBefore(deadline, bits, minBits, next, t, s.cfg.Tests)
+	}
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if res.Number.BitLen() < bits {
+		// Prazo esgotado: o primo eh o de reserva
+		bits = minBits
+	}
+	if s.cfg.History != nil {
+		// Um primo que nao pode ser registrado nao eh emitido
+		rec := history.NewRecord("server", t.Name(), bits, s.cfg.Tests, res).WithCandidate(candidates[bits], nil)
+		if err := s.cfg.History.Append(rec); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if s.cfg.AuditLog != nil {
+		entry := auditlog.Entry{
+			Event:  "generate",
+			Test:   t.Name(),
+			Bits:   bits,
+			Number: res.Number.String(),
+			Params: fmt.Sprintf("rounds=%d attempts=%d security=%s", res.Rounds, res.Attempts, s.cfg.Tests.Security),
+		}
+		if _, err := s.cfg.AuditLog.Append(entry); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, testResponse{Test: t.Name(), Result: res})
+}
+
+// deadline retorna o prazo de /generate, do parametro deadline (uma duracao
+// como 500ms) limitado por Config.Deadline, e o tamanho do primo de reserva
+// do parametro min_bits. Sem prazo, o instante retornado eh zero.
+func (s *Server) deadline(r *http.Request, bits int) (time.Time, int, error) {
+	query := r.URL.Query()
+	budget := s.cfg.Deadline
+	if text := query.Get("deadline"); text != "" {
+		d, err := time.ParseDuration(text)
+		if err != nil || d <= 0 {
+			return time.Time{}, 0, errors.New("deadline deve ser uma duracao positiva, como 500ms")
+		}
+		if budget <= 0 || d < budget {
+			budget = d
+		}
+	}
+	minBits := 0
+	if text := query.Get("min_bits"); text != "" {
+		var err error
+		if minBits, err = strconv.Atoi(text); err != nil || minBits < 2 || minBits >= bits {
+			return time.Time{}, 0, fmt.Errorf("min_bits deve estar entre 2 e %d", bits-1)
+		}
+		if budget <= 0 {
+			return time.Time{}, 0, errors.New("min_bits exige um prazo")
+		}
+	}
+	if budget <= 0 {
+		return time.Time{}, 0, nil
+	}
+	return clock.Now().Add(budget), minBits, nil
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	// A base 0 aceita decimal e hexadecimal com prefixo 0x
+	n, ok := new(big.Int).SetString(r.URL.Query().Get("n"), 0)
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.New("n deve ser um inteiro decimal ou hexadecimal (0x...)"))
+		return
+	}
+	if maxBits := s.maxBits(r); n.BitLen() > maxBits {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("n deve ter no maximo %d bits", maxBits))
+		return
+	}
+	t, err := test(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := t.IsPrime(n, s.cfg.Tests)
+	if res.Err != nil {
+		writeError(w, http.StatusServiceUnavailable, res.Err)
+		return
+	}
+	writeJSON(w, http.StatusOK, testResponse{Test: t.Name(), Result: res})
+}
+
+func (s *Server) handleBeacon(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.cfg.Beacon.Commitment())
+}
+
+func (s *Server) handleBeaconNext(w http.ResponseWriter, r *http.Request) {
+	out, proof, err := s.cfg.Beacon.Next()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, beaconResponse{Output: out, Proof: proof})
+}
+
+func (s *Server) handleBeaconProof(w http.ResponseWriter, r *http.Request) {
+	segment, err := strconv.ParseUint(r.URL.Query().Get("segment"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("segment invalido"))
+		return
+	}
+	proof, ok := s.cfg.Beacon.Proof(segment)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("segmento nao encerrado ou descartado"))
+		return
+	}
+	writeJSON(w, http.StatusOK, proof)
+}
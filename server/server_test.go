@@ -0,0 +1,294 @@
+package server
+
+import (
+	"PrimeNumGenerator/auditlog"
+	"PrimeNumGenerator/beacon"
+	"PrimeNumGenerator/prng"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, h http.Handler, url string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code
+}
+
+func TestGenerateAndCheck(t *testing.T) {
+	s := New(Config{MaxBits: 256})
+
+	var res struct {
+		Test   string
+		Result struct {
+			Prime  bool
+			Number string
+		}
+	}
+	if code := get(t, s, "/generate?bits=64", &res); code != http.StatusOK || !res.Result.Prime {
+		t.Fatalf("/generate: %d %+v", code, res)
+	}
+	if code := get(t, s, "/check?n=0x1fffffffffffffff&test=fermat", &res); code != http.StatusOK || !res.Result.Prime || res.Test != "fermat" {
+		t.Fatalf("/check: %d %+v", code, res)
+	}
+	if code := get(t, s, "/check?n=91", &res); code != http.StatusOK || res.Result.Prime {
+		t.Fatalf("/check de composto: %d %+v", code, res)
+	}
+
+	if code := get(t, s, "/generate?bits=512", nil); code != http.StatusBadRequest {
+		t.Errorf("bits acima do limite: status %d", code)
+	}
+	if code := get(t, s, "/check?n=abc", nil); code != http.StatusBadRequest {
+		t.Errorf("numero invalido: status %d", code)
+	}
+	var health struct{ Status string }
+	if code := get(t, s, "/healthz", &health); code != http.StatusOK || health.Status != "ok" {
+		t.Errorf("/healthz: %d %+v", code, health)
+	}
+	if code := get(t, s, "/beacon", nil); code != http.StatusNotFound {
+		t.Errorf("farol desativado: status %d", code)
+	}
+}
+
+func TestGenerateWithCandidates(t *testing.T) {
+	g, err := prng.NewReseeding(func() (prng.Generator, error) {
+		return prng.NewLFGWithEntropy(10, 7, 10, 64, prng.Entropy{})
+	}, prng.ReseedPolicy{MaxBytes: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(Config{MaxBits: 256, Candidates: g})
+	var res struct{ Result struct{ Number string } }
+	for i := 0; i < 5; i++ {
+		if code := get(t, s, "/generate?bits=128", &res); code != http.StatusOK || len(res.Result.Number) < 38 {
+			t.Fatalf("/generate: %d %+v", code, res)
+		}
+	}
+}
+
+func TestGenerateDeadline(t *testing.T) {
+	s := New(Config{MaxBits: 256, Deadline: time.Minute})
+	var res struct{ Result struct{ Number string } }
+	if code := get(t, s, "/generate?bits=256&deadline=30s&min_bits=64", &res); code != http.StatusOK || len(res.Result.Number) < 70 {
+		t.Fatalf("/generate com prazo: %d %+v", code, res)
+	}
+	// Um prazo que ja venceu nao encontra nem a reserva
+	if code := get(t, s, "/generate?bits=256&deadline=1ns&min_bits=64", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("prazo vencido: status %d", code)
+	}
+	for _, query := range []string{"deadline=abc", "deadline=-1s", "min_bits=256", "min_bits=1", "deadline=1s&min_bits=x"} {
+		if code := get(t, s, "/generate?bits=256&"+query, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d", query, code)
+		}
+	}
+	if code := get(t, New(Config{MaxBits: 256}), "/generate?bits=128&min_bits=64", nil); code != http.StatusBadRequest {
+		t.Errorf("min_bits sem prazo: status %d", code)
+	}
+}
+
+func TestBeaconEndpoints(t *testing.T) {
+	b, err := beacon.New(128, 32, 2, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(Config{MaxBits: 256, Beacon: b})
+
+	var c beacon.Commitment
+	get(t, s, "/beacon", &c)
+
+	var outputs []beacon.Output
+	var next beaconResponse
+	for next.Proof == nil {
+		if code := get(t, s, "/beacon/next", &next); code != http.StatusOK {
+			t.Fatalf("/beacon/next: status %d", code)
+		}
+		outputs = append(outputs, next.Output)
+	}
+
+	var proof beacon.Proof
+	if code := get(t, s, "/beacon/proof?segment=0", &proof); code != http.StatusOK {
+		t.Fatalf("/beacon/proof: status %d", code)
+	}
+	if err := beacon.Verify(c, proof, outputs, 32); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := auditlog.Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(Config{MaxBits: 256, AuditLog: l})
+	for i := 0; i < 3; i++ {
+		if code := get(t, s, "/generate?bits=64", nil); code != http.StatusOK {
+			t.Fatalf("/generate: status %d", code)
+		}
+	}
+	l.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if n, err := auditlog.Verify(f, pub); n != 3 || err != nil {
+		t.Fatalf("Verify = %d, %v", n, err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := New(Config{MaxBits: 256, Limits: Limits{
+		Default: Limit{Rate: 1, Burst: 2},
+		Keys:    map[string]Limit{"vip": {Rate: 100, Burst: 100}},
+	}})
+	now := time.Unix(0, 0)
+	s.limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if code := get(t, s, "/check?n=7", nil); code != http.StatusOK {
+			t.Fatalf("requisicao %d: status %d", i, code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/check?n=7", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("terceira requisicao: status %d", rec.Code)
+	}
+	if code := get(t, s, "/healthz", nil); code != http.StatusOK {
+		t.Errorf("/healthz limitado: status %d", code)
+	}
+
+	// Outra chave tem seu proprio balde
+	req := httptest.NewRequest(http.MethodGet, "/check?n=7", nil)
+	req.Header.Set(APIKeyHeader, "vip")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("chave vip: status %d", rec.Code)
+	}
+
+	// Chaves inventadas nao ganham baldes proprios: o cliente continua
+	// limitado pelo IP
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/check?n=7", nil)
+		req.Header.Set(APIKeyHeader, fmt.Sprintf("falsa-%d", i))
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("chave inventada %d: status %d", i, rec.Code)
+		}
+	}
+
+	now = now.Add(time.Second)
+	if code := get(t, s, "/check?n=7", nil); code != http.StatusOK {
+		t.Errorf("ficha reposta: status %d", code)
+	}
+}
+
+func TestRateLimitAuthenticatedKey(t *testing.T) {
+	s := New(Config{MaxBits: 256, Limits: Limits{Default: Limit{Rate: 0.001, Burst: 1}},
+		Auth: &Auth{Keys: map[string]Scope{"a": {}, "b": {}}}})
+	now := time.Unix(0, 0)
+	s.limiter.now = func() time.Time { return now }
+	request := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/check?n=7", nil)
+		req.Header.Set(APIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// Cada chave aceita pela autenticacao tem o seu balde
+	for _, key := range []string{"a", "b"} {
+		if code := request(key); code != http.StatusOK {
+			t.Errorf("chave %s: status %d", key, code)
+		}
+	}
+	if code := request("a"); code != http.StatusTooManyRequests {
+		t.Errorf("segunda requisicao da chave a: status %d", code)
+	}
+}
+
+func TestWorkQueue(t *testing.T) {
+	l := newLimiter(Limits{MaxConcurrent: 1, MaxQueue: 0})
+	req := httptest.NewRequest(http.MethodGet, "/generate", nil)
+	release, err := l.acquire(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.acquire(req); err != errQueueFull {
+		t.Fatalf("fila cheia: %v", err)
+	}
+	release()
+	if _, err := l.acquire(req); err != nil {
+		t.Fatalf("vaga liberada: %v", err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	s := New(Config{MaxBits: 256, Auth: &Auth{
+		Keys: map[string]Scope{
+			"admin":   {},
+			"limited": {MaxBits: 64, Endpoints: []string{"/generate", "/check"}},
+		},
+		Clients: map[string]Scope{"worker": {Endpoints: []string{"/check"}}},
+	}})
+	request := func(url, key string, state *tls.ConnectionState) int {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		req.TLS = state
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	cases := []struct {
+		url, key string
+		want     int
+	}{
+		{"/check?n=7", "", http.StatusUnauthorized},
+		{"/check?n=7", "errada", http.StatusUnauthorized},
+		{"/healthz", "", http.StatusOK},
+		{"/check?n=7", "admin", http.StatusOK},
+		{"/generate?bits=128", "admin", http.StatusOK},
+		{"/generate?bits=64", "limited", http.StatusOK},
+		{"/generate?bits=128", "limited", http.StatusBadRequest},
+		{"/check?n=7", "limited", http.StatusOK},
+		{"/check?n=0x10000000000000001", "limited", http.StatusBadRequest},
+		{"/check?n=0x10000000000000001", "admin", http.StatusOK},
+		{"/beacon", "limited", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		if code := request(c.url, c.key, nil); code != c.want {
+			t.Errorf("%s com chave %q: status %d, esperado %d", c.url, c.key, code, c.want)
+		}
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "worker"}}
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	if code := request("/check?n=7", "", state); code != http.StatusOK {
+		t.Errorf("certificado do worker: status %d", code)
+	}
+	if code := request("/generate?bits=64", "", state); code != http.StatusForbidden {
+		t.Errorf("worker fora do escopo: status %d", code)
+	}
+}
@@ -0,0 +1,203 @@
+// O pacote shamir implementa o compartilhamento de segredos de Shamir
+// (t de n) sobre um corpo primo gerado pelo proprio projeto.
+//
+// O segredo eh o termo constante de um polinomio aleatorio de grau t-1
+// modulo um primo p; cada parte eh um ponto (x, f(x)) do polinomio.
+// Quaisquer t partes reconstroem o segredo por interpolacao de Lagrange,
+// enquanto t-1 partes nao revelam nada sobre ele.
+package shamir
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// fieldMargin eh a quantidade de bits a mais do primo do corpo em relacao
+// ao segredo
+const fieldMargin = 64
+
+// Share eh uma parte do segredo: o ponto (X, Y) do polinomio modulo Prime
+type Share struct {
+	X     int
+	Y     *big.Int
+	Prime *big.Int
+}
+
+// String codifica a parte como "x:primo:y", com primo e y em hexadecimal
+func (s Share) String() string {
+	return fmt.Sprintf("%d:%s:%s", s.X, s.Prime.Text(16), s.Y.Text(16))
+}
+
+// ParseShare decodifica uma parte no formato de Share.String
+func ParseShare(s string) (Share, error) {
+	fields := strings.Split(strings.TrimSpace(s), ":")
+	if len(fields) != 3 {
+		return Share{}, fmt.Errorf("shamir: parte invalida %q", s)
+	}
+	var share Share
+	if _, err := fmt.Sscanf(fields[0], "%d", &share.X); err != nil || share.X < 1 {
+		return Share{}, fmt.Errorf("shamir: indice invalido %q", fields[0])
+	}
+	var ok bool
+	if share.Prime, ok = new(big.Int).SetString(fields[1], 16); !ok {
+		return Share{}, fmt.Errorf("shamir: primo invalido %q", fields[1])
+	}
+	if share.Y, ok = new(big.Int).SetString(fields[2], 16); !ok {
+		return Share{}, fmt.Errorf("shamir: valor invalido %q", fields[2])
+	}
+	if err := share.check(); err != nil {
+		return Share{}, err
+	}
+	return share, nil
+}
+
+// check recusa as partes com que a interpolacao nao faz sentido: um corpo
+// de primo menor que 2 ou um indice fora de [1, Prime). Um indice multiplo
+// do primo equivaleria a x = 0, o proprio segredo.
+func (s Share) check() error {
+	if s.Prime == nil || s.Prime.Cmp(big.NewInt(2)) < 0 {
+		return fmt.Errorf("shamir: primo do corpo invalido %v", s.Prime)
+	}
+	if s.X < 1 || big.NewInt(int64(s.X)).Cmp(s.Prime) >= 0 {
+		return fmt.Errorf("shamir: indice %d fora do corpo", s.X)
+	}
+	if s.Y == nil {
+		return fmt.Errorf("shamir: parte %d sem valor", s.X)
+	}
+	return nil
+}
+
+// FieldFor gera, com o Miller-Rabin, um primo maior que secret para servir
+// de corpo ao compartilhamento
+func FieldFor(secret *big.Int, e prng.Entropy) (*big.Int, error) {
+	bits := secret.BitLen() + fieldMargin
+	candidate, err := e.Bits(bits)
+	if err != nil {
+		return nil, err
+	}
+	res, err := pta.MillerRabin(candidate, bits, pta.Config{Security: e.Level, Source: e.Source})
+	if err != nil {
+		return nil, err
+	}
+	return res.Number, nil
+}
+
+// Split divide secret em n partes, das quais quaisquer t o reconstroem.
+// A aritmetica eh feita modulo o primo p, que deve ser maior que secret e
+// que n; os coeficientes do polinomio sao sorteados da entropia e.
+func Split(secret *big.Int, t, n int, p *big.Int, e prng.Entropy) ([]Share, error) {
+	if t < 1 || t > n {
+		return nil, errors.New("shamir: o limiar t deve estar entre 1 e n")
+	}
+	if secret.Sign() < 0 || secret.Cmp(p) >= 0 {
+		return nil, errors.New("shamir: o segredo deve estar em [0, p)")
+	}
+	if big.NewInt(int64(n)).Cmp(p) >= 0 {
+		return nil, errors.New("shamir: o primo deve ser maior que o numero de partes")
+	}
+
+	// f(x) = secret + a_1 x + ... + a_(t-1) x^(t-1)
+	coefficients := []*big.Int{new(big.Int).Set(secret)}
+	for i := 1; i < t; i++ {
+		a, err := e.Int(p)
+		if err != nil {
+			return nil, err
+		}
+		coefficients = append(coefficients, a)
+	}
+	defer func() {
+		for _, a := range coefficients {
+			prng.WipeInt(a)
+		}
+	}()
+
+	shares := make([]Share, n)
+	for i := range shares {
+		x := big.NewInt(int64(i + 1))
+		// Avaliamos f(x) pelo metodo de Horner
+		y := new(big.Int)
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			y.Mul(y, x)
+			y.Add(y, coefficients[j])
+			y.Mod(y, p)
+		}
+		shares[i] = Share{X: i + 1, Y: y, Prime: new(big.Int).Set(p)}
+	}
+	return shares, nil
+}
+
+// Combine reconstroi o segredo a partir das partes por interpolacao de
+// Lagrange em x = 0. Com menos partes que o limiar, o resultado eh um
+// valor sem relacao com o segredo.
+func Combine(shares []Share) (*big.Int, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("shamir: nenhuma parte informada")
+	}
+	p := shares[0].Prime
+	seen := make(map[int]bool)
+	for _, s := range shares {
+		if err := s.check(); err != nil {
+			return nil, err
+		}
+		if s.Prime.Cmp(p) != 0 {
+			return nil, errors.New("shamir: partes de corpos diferentes")
+		}
+		if seen[s.X] {
+			return nil, fmt.Errorf("shamir: parte %d repetida", s.X)
+		}
+		seen[s.X] = true
+	}
+
+	secret := new(big.Int)
+	for i, si := range shares {
+		// L_i(0) = prod_(j != i) x_j / (x_j - x_i)
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(sj.X)))
+			den.Mul(den, big.NewInt(int64(sj.X-si.X)))
+		}
+		den.Mod(den, p)
+		inv := new(big.Int).ModInverse(den, p)
+		if inv == nil {
+			return nil, errors.New("shamir: primo do corpo invalido")
+		}
+		term := num.Mul(num, inv)
+		term.Mul(term, si.Y)
+		secret.Add(secret, term)
+		secret.Mod(secret, p)
+	}
+	return secret, nil
+}
+
+// SplitBytes divide um segredo binario qualquer (por exemplo, uma chave
+// privada RSA em PEM). Um byte 0x01 eh prefixado para preservar zeros a
+// esquerda; o primo do corpo eh gerado com FieldFor.
+func SplitBytes(secret []byte, t, n int, e prng.Entropy) ([]Share, error) {
+	s := new(big.Int).SetBytes(append([]byte{1}, secret...))
+	defer prng.WipeInt(s)
+	p, err := FieldFor(s, e)
+	if err != nil {
+		return nil, err
+	}
+	return Split(s, t, n, p, e)
+}
+
+// CombineBytes reconstroi um segredo dividido com SplitBytes
+func CombineBytes(shares []Share) ([]byte, error) {
+	s, err := Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+	b := s.Bytes()
+	if len(b) == 0 || b[0] != 1 {
+		return nil, errors.New("shamir: partes nao correspondem a um segredo binario")
+	}
+	return b[1:], nil
+}
@@ -0,0 +1,104 @@
+// Esse arquivo traz os destinos em texto e em JSON Lines, na saida padrao
+//  ou em arquivos.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+func init() {
+	Register("stdout", func(arg string) (OutputSink, error) {
+		if arg != "" {
+			return nil, errors.New("sink: stdout nao aceita argumento; use text:ARQUIVO")
+		}
+		return NewText(os.Stdout), nil
+	})
+	Register("text", func(arg string) (OutputSink, error) {
+		w, err := openFile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewText(w), nil
+	})
+	Register("json", func(arg string) (OutputSink, error) {
+		w, err := openFile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewJSON(w), nil
+	})
+}
+
+// openFile abre o arquivo path para acrescentar registros; "-" eh a saida
+// padrao, que nao eh fechada
+func openFile(path string) (io.Writer, error) {
+	switch path {
+	case "":
+		return nil, errors.New("sink: informe o arquivo, ou - para a saida padrao")
+	case "-":
+		return os.Stdout, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
+
+// writerSink grava uma linha por registro em w, formatada por encode
+type writerSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	encode func(r Record) ([]byte, error)
+}
+
+// NewText cria um destino que grava cada primo em decimal, um por linha,
+// em w: o formato mais simples de encadear com outros programas. Close
+// fecha w se ele implementar io.Closer e nao for a saida padrao.
+func NewText(w io.Writer) OutputSink {
+	return &writerSink{w: w, encode: func(r Record) ([]byte, error) {
+		return []byte(r.Number + "\n"), nil
+	}}
+}
+
+// NewJSON cria um destino que grava cada registro como uma linha JSON em w
+// (JSON Lines). Close fecha w como em NewText.
+func NewJSON(w io.Writer) OutputSink {
+	return &writerSink{w: w, encode: func(r Record) ([]byte, error) {
+		line, err := json.Marshal(r)
+		return append(line, '\n'), err
+	}}
+}
+
+func (s *writerSink) Write(ctx context.Context, r Record) error {
+	line, err := s.encode(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.w == nil {
+		return errors.New("sink: destino fechado")
+	}
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	// Cada registro fica em disco antes do proximo, como no historico
+	if f, ok := s.w.(*os.File); ok && f != os.Stdout {
+		return f.Sync()
+	}
+	return nil
+}
+
+func (s *writerSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.w
+	s.w = nil
+	if c, ok := w.(io.Closer); ok && w != io.Writer(os.Stdout) {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Esse arquivo traz os destinos HTTP: webhooks, que recebem cada registro
+//  em JSON, e topicos do Kafka, por meio do REST Proxy.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPTimeout eh o prazo de cada envio dos destinos HTTP
+const HTTPTimeout = 10 * time.Second
+
+// kafkaContentType eh o formato da API v2 do REST Proxy do Kafka para
+// mensagens em JSON
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+func init() {
+	Register("webhook", func(arg string) (OutputSink, error) {
+		return NewWebhook(arg)
+	})
+	Register("kafka", func(arg string) (OutputSink, error) {
+		return NewKafkaREST(arg)
+	})
+}
+
+// httpSink envia cada registro em uma requisicao POST para url, no corpo
+// montado por body
+type httpSink struct {
+	client      *http.Client
+	url         string
+	contentType string
+	body        func(r Record) any
+}
+
+// checkURL exige uma URL http ou https absoluta
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("sink: URL http ou https invalida %q", raw)
+	}
+	return nil
+}
+
+// NewWebhook cria um destino que envia cada registro, em JSON, para a URL
+// por POST. Uma resposta fora da faixa 2xx eh um erro.
+func NewWebhook(rawURL string) (OutputSink, error) {
+	if err := checkURL(rawURL); err != nil {
+		return nil, err
+	}
+	return &httpSink{
+		client:      &http.Client{Timeout: HTTPTimeout},
+		url:         rawURL,
+		contentType: "application/json",
+		body:        func(r Record) any { return r },
+	}, nil
+}
+
+// NewKafkaREST cria um destino que publica cada registro como uma mensagem
+// JSON no topico do Kafka da URL do REST Proxy, como
+// http://proxy:8082/topics/primos. A chave da mensagem eh o tamanho em
+// bits, para que os primos de um mesmo tamanho fiquem na mesma particao.
+func NewKafkaREST(rawURL string) (OutputSink, error) {
+	if err := checkURL(rawURL); err != nil {
+		return nil, err
+	}
+	type message struct {
+		Key   string `json:"key"`
+		Value Record `json:"value"`
+	}
+	return &httpSink{
+		client:      &http.Client{Timeout: HTTPTimeout},
+		url:         rawURL,
+		contentType: kafkaContentType,
+		body: func(r Record) any {
+			return map[string][]message{"records": {{Key: fmt.Sprint(r.Bits), Value: r}}}
+		},
+	}, nil
+}
+
+func (s *httpSink) Write(ctx context.Context, r Record) error {
+	body, err := json.Marshal(s.body(r))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.contentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.New("sink: " + s.url + " respondeu " + resp.Status + ": " + string(bytes.TrimSpace(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
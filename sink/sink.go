@@ -0,0 +1,114 @@
+// O pacote sink publica os primos gerados pelos subcomandos em destinos
+// externos: a saida padrao ou arquivos em texto, arquivos JSON Lines, bancos
+// de dados SQL (como o SQLite), webhooks HTTP e topicos do Kafka, por meio
+// do REST Proxy. Cada destino implementa OutputSink e eh aberto a partir de
+// uma especificacao "tipo:argumento", como as da opcao -out da linha de
+// comando; novos tipos podem ser acrescentados com Register.
+package sink
+
+import (
+	"PrimeNumGenerator/clock"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record eh um primo gerado, como publicado nos destinos
+type Record struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`             // subcomando que gerou o primo
+	Generator  string    `json:"generator,omitempty"` // gerador dos candidatos, se houver
+	Test       string    `json:"test,omitempty"`      // teste de primalidade usado
+	Bits       int       `json:"bits"`
+	Number     string    `json:"number"` // primo em decimal
+	Attempts   int       `json:"attempts,omitempty"`
+	DurationNS int64     `json:"duration_ns,omitempty"`
+}
+
+// NewRecord cria o registro do primo p gerado pelo subcomando command,
+// com o horario do relogio do processo
+func NewRecord(command string, p *big.Int) Record {
+	return Record{Time: clock.Now().UTC(), Command: command, Bits: p.BitLen(), Number: p.String()}
+}
+
+// OutputSink eh um destino dos primos gerados. Write publica um registro e
+// Close libera os recursos do destino, gravando o que estiver pendente. As
+// implementacoes do pacote podem ser usadas por varias goroutines ao mesmo
+// tempo.
+type OutputSink interface {
+	Write(ctx context.Context, r Record) error
+	Close() error
+}
+
+// Opener abre um destino a partir do argumento da especificacao, o texto
+// depois de "tipo:"
+type Opener func(arg string) (OutputSink, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Opener{}
+)
+
+// Register registra o tipo de destino name, para que Open o aceite. Entra
+// em panico se o nome ja estiver registrado, como database/sql.Register.
+func Register(name string, open Opener) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("sink: tipo de destino registrado duas vezes: " + name)
+	}
+	registry[name] = open
+}
+
+// Names retorna os tipos de destino registrados, em ordem alfabetica
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Open abre o destino descrito por spec, no formato "tipo:argumento" (por
+// exemplo "json:primos.jsonl" ou "webhook:https://exemplo/primos"). O tipo
+// "stdout" dispensa o argumento.
+func Open(spec string) (OutputSink, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+	registryMu.RLock()
+	open, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("sink: tipo de destino desconhecido %q (use %s)", name, strings.Join(Names(), ", "))
+	}
+	return open(arg)
+}
+
+// Tee eh um OutputSink que publica cada registro em todos os destinos
+type Tee []OutputSink
+
+// Write publica r em todos os destinos, mesmo que algum falhe, e retorna
+// os erros reunidos
+func (t Tee) Write(ctx context.Context, r Record) error {
+	var errs []error
+	for _, s := range t {
+		errs = append(errs, s.Write(ctx, r))
+	}
+	return errors.Join(errs...)
+}
+
+// Close fecha todos os destinos
+func (t Tee) Close() error {
+	var errs []error
+	for _, s := range t {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,197 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func record() Record {
+	r := NewRecord("vanity", big.NewInt(1_000_003))
+	r.Test = "miller-rabin"
+	return r
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	text, jsonPath := filepath.Join(dir, "primos.txt"), filepath.Join(dir, "primos.jsonl")
+	s, err := Open("text:" + text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := Open("json:" + jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tee := Tee{s, j}
+	for range 2 {
+		if err := tee.Write(context.Background(), record()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tee.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(context.Background(), record()); err == nil {
+		t.Error("escrita aceita depois de Close")
+	}
+
+	if data, _ := os.ReadFile(text); string(data) != "1000003\n1000003\n" {
+		t.Errorf("texto gravado = %q", data)
+	}
+	f, err := os.Open(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	for sc := bufio.NewScanner(f); sc.Scan(); lines++ {
+		var got Record
+		if err := json.Unmarshal(sc.Bytes(), &got); err != nil || got.Number != "1000003" || got.Bits != 20 || got.Command != "vanity" {
+			t.Fatalf("registro JSON = %+v, %v", got, err)
+		}
+	}
+	if lines != 2 {
+		t.Errorf("%d linhas JSON, esperadas 2", lines)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	for _, spec := range []string{"", "ftp:x", "stdout:x", "json:", "webhook:ftp://x", "kafka:/topics/x", "sql:sqlite", "sqlite:"} {
+		if _, err := Open(spec); err == nil {
+			t.Errorf("Open(%q) aceito", spec)
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got []Record
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rec Record
+		if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&rec) != nil {
+			http.Error(w, "corpo invalido", http.StatusBadRequest)
+			return
+		}
+		if rec.Bits > 100 {
+			http.Error(w, "grande demais", http.StatusUnprocessableEntity)
+			return
+		}
+		got = append(got, rec)
+	}))
+	defer srv.Close()
+
+	s, err := Open("webhook:" + srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Write(context.Background(), record()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Number != "1000003" {
+		t.Fatalf("webhook recebeu %+v", got)
+	}
+	large := record()
+	large.Bits = 128
+	if err := s.Write(context.Background(), large); err == nil || !strings.Contains(err.Error(), "grande demais") {
+		t.Errorf("resposta 422 = %v; esperado erro com a mensagem do servidor", err)
+	}
+}
+
+func TestKafkaREST(t *testing.T) {
+	var body struct {
+		Records []struct {
+			Key   string `json:"key"`
+			Value Record `json:"value"`
+		} `json:"records"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/primos" || r.Header.Get("Content-Type") != kafkaContentType {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	s, err := Open("kafka:" + srv.URL + "/topics/primos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Write(context.Background(), record()); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Records) != 1 || body.Records[0].Key != "20" || body.Records[0].Value.Number != "1000003" {
+		t.Fatalf("mensagem publicada = %+v", body)
+	}
+}
+
+// fakeDriver eh um driver do database/sql que guarda os comandos e os
+// argumentos recebidos, no lugar de um banco de verdade
+type fakeDriver struct {
+	mu    sync.Mutex
+	execs []string
+	args  [][]driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("sem transacoes") }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, strings.Fields(s.query)[0])
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(1), nil
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("sem consultas")
+}
+
+func TestSQL(t *testing.T) {
+	d := &fakeDriver{}
+	sql.Register("sqlite", d)
+	s, err := Open("sqlite:primos.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(context.Background(), record()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.execs) != 2 || d.execs[0] != "CREATE" || d.execs[1] != "INSERT" {
+		t.Fatalf("comandos = %v; esperados CREATE e INSERT", d.execs)
+	}
+	if args := d.args[1]; len(args) != 8 || args[1] != "vanity" || args[5] != "1000003" {
+		t.Errorf("argumentos do INSERT = %v", args)
+	}
+	if _, err := OpenSQL("sqlite", "primos.db", "primos; DROP TABLE x"); err == nil {
+		t.Error("nome de tabela invalido aceito")
+	}
+}
@@ -0,0 +1,91 @@
+// Esse arquivo traz o destino em bancos de dados SQL, pelo database/sql.
+//  O programa nao inclui drivers: o SQLite e os demais bancos funcionam nas
+//  compilacoes que importarem o driver correspondente.
+
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTable eh a tabela usada pelos destinos sql e sqlite
+const DefaultTable = "primes"
+
+// sqliteDrivers sao os nomes com que os drivers do SQLite costumam se
+// registrar no database/sql
+var sqliteDrivers = []string{"sqlite", "sqlite3"}
+
+// tableName valida os nomes de tabela, que nao podem ir como parametro
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func init() {
+	Register("sql", func(arg string) (OutputSink, error) {
+		driver, dsn, ok := strings.Cut(arg, ":")
+		if !ok || driver == "" || dsn == "" {
+			return nil, errors.New("sink: use sql:DRIVER:DSN")
+		}
+		return OpenSQL(driver, dsn, DefaultTable)
+	})
+	Register("sqlite", func(arg string) (OutputSink, error) {
+		if arg == "" {
+			return nil, errors.New("sink: informe o arquivo do banco")
+		}
+		for _, driver := range sqliteDrivers {
+			if slices.Contains(sql.Drivers(), driver) {
+				return OpenSQL(driver, arg, DefaultTable)
+			}
+		}
+		return nil, errors.New("sink: nenhum driver do SQLite registrado no database/sql; compile o programa importando um, como modernc.org/sqlite")
+	})
+}
+
+// sqlSink grava os registros em uma tabela, criada se preciso
+type sqlSink struct {
+	db     *sql.DB
+	insert *sql.Stmt
+}
+
+// OpenSQL abre o banco dsn com o driver do database/sql e cria, se nao
+// existir, a tabela em que cada registro vira uma linha. O primo eh
+// gravado como texto, pois nenhum tipo inteiro dos bancos comporta
+// centenas de digitos. A insercao usa parametros "?", aceitos pelo SQLite
+// e pelo MySQL.
+func OpenSQL(driver, dsn, table string) (OutputSink, error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("sink: nome de tabela invalido %q", table)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	create := `CREATE TABLE IF NOT EXISTS ` + table + ` (
+		time TEXT NOT NULL, command TEXT NOT NULL, generator TEXT, test TEXT,
+		bits INTEGER NOT NULL, number TEXT NOT NULL, attempts INTEGER, duration_ns INTEGER)`
+	if _, err := db.Exec(create); err != nil {
+		db.Close()
+		return nil, err
+	}
+	insert, err := db.Prepare(`INSERT INTO ` + table + ` (time, command, generator, test, bits, number, attempts, duration_ns)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqlSink{db: db, insert: insert}, nil
+}
+
+func (s *sqlSink) Write(ctx context.Context, r Record) error {
+	_, err := s.insert.ExecContext(ctx, r.Time.Format(time.RFC3339Nano), r.Command, r.Generator, r.Test, r.Bits, r.Number, r.Attempts, r.DurationNS)
+	return err
+}
+
+func (s *sqlSink) Close() error {
+	return errors.Join(s.insert.Close(), s.db.Close())
+}
@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// Parametros dos testes de saude continuos do NIST SP 800-90B (secao 4.4)
+const (
+	// HealthAlphaLog2 eh log2 da probabilidade de falso alarme de cada teste
+	HealthAlphaLog2 = -20
+	// HealthWindow eh o tamanho da janela do teste de proporcao adaptativa
+	// para amostras de 8 bits
+	HealthWindow = 512
+)
+
+// HealthError indica que a sequencia falhou em um teste de saude: Count
+// repeticoes (ou ocorrencias na janela) atingiram o limite Cutoff
+type HealthError struct {
+	Test   string // "repetition-count" ou "adaptive-proportion"
+	Sample uint64 // numero da amostra (byte) em que a falha ocorreu
+	Count  int
+	Cutoff int
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("stats: teste de saude %s reprovado na amostra %d (%d >= %d)", e.Test, e.Sample, e.Count, e.Cutoff)
+}
+
+// Health aplica continuamente, amostra a amostra, os testes de saude do
+// SP 800-90B a uma sequencia de bytes: o de contagem de repeticoes, que
+// detecta um valor que se repete demais seguidamente, e o de proporcao
+// adaptativa, que detecta um valor frequente demais em uma janela de
+// HealthWindow amostras. Os limites sao calculados a partir da entropia
+// minima suposta por amostra. Depois de uma falha, o teste reprovado
+// recomeca, de modo que o monitoramento pode continuar.
+type Health struct {
+	rctCutoff, aptCutoff int
+	samples              uint64
+
+	last byte // contagem de repeticoes
+	run  int
+
+	first byte // proporcao adaptativa
+	count int
+	seen  int
+
+	partial byte // bits ainda nao completam um byte em AddBits
+	nbits   int
+}
+
+// NewHealth cria o monitor para amostras com minEntropy bits de entropia
+// minima, entre 0 (exclusive) e 8
+func NewHealth(minEntropy float64) (*Health, error) {
+	if !(minEntropy > 0 && minEntropy <= 8) {
+		return nil, fmt.Errorf("stats: entropia minima invalida %g: use um valor em (0, 8]", minEntropy)
+	}
+	return &Health{
+		// C = 1 + ceil(-log2(alpha) / H)
+		rctCutoff: 1 + int(math.Ceil(-HealthAlphaLog2/minEntropy)),
+		// C = 1 + CRITBINOM(W, 2^-H, 1 - alpha)
+		aptCutoff: 1 + critBinom(HealthWindow, math.Exp2(-minEntropy), 1-math.Exp2(HealthAlphaLog2)),
+	}, nil
+}
+
+// Cutoffs retorna os limites dos testes de contagem de repeticoes e de
+// proporcao adaptativa
+func (h *Health) Cutoffs() (repetition, proportion int) {
+	return h.rctCutoff, h.aptCutoff
+}
+
+// Samples retorna o numero de amostras avaliadas
+func (h *Health) Samples() uint64 {
+	return h.samples
+}
+
+// Add avalia a proxima amostra e retorna um *HealthError se algum teste
+// falhar
+func (h *Health) Add(s byte) error {
+	h.samples++
+
+	if h.run > 0 && s == h.last {
+		h.run++
+	} else {
+		h.last, h.run = s, 1
+	}
+	if h.run >= h.rctCutoff {
+		err := &HealthError{Test: "repetition-count", Sample: h.samples, Count: h.run, Cutoff: h.rctCutoff}
+		h.run = 0
+		return err
+	}
+
+	if h.seen == 0 {
+		h.first, h.count = s, 0
+	}
+	if s == h.first {
+		h.count++
+	}
+	if h.seen++; h.seen == HealthWindow {
+		h.seen = 0
+	}
+	if h.count >= h.aptCutoff {
+		err := &HealthError{Test: "adaptive-proportion", Sample: h.samples, Count: h.count, Cutoff: h.aptCutoff}
+		h.seen = 0
+		return err
+	}
+	return nil
+}
+
+// AddBits agrupa os bits (como os de Bits), do mais significativo ao
+// menos, em amostras de 8 bits e as avalia com Add. Os bits que nao
+// completam uma amostra ficam para a proxima chamada. Retorna a primeira
+// falha, mas avalia todos os bits.
+func (h *Health) AddBits(bits []uint8) error {
+	var first error
+	for _, b := range bits {
+		h.partial = h.partial<<1 | b&1
+		if h.nbits++; h.nbits == 8 {
+			if err := h.Add(h.partial); err != nil && first == nil {
+				first = err
+			}
+			h.partial, h.nbits = 0, 0
+		}
+	}
+	return first
+}
+
+// critBinom retorna o menor k com P(X <= k) >= q, para X binomial com n
+// tentativas e probabilidade p
+func critBinom(n int, p, q float64) int {
+	cdf := 0.0
+	for k := 0; k < n; k++ {
+		// Probabilidade de k sucessos, calculada em escala logaritmica
+		lg, _ := math.Lgamma(float64(n + 1))
+		lk, _ := math.Lgamma(float64(k + 1))
+		lnk, _ := math.Lgamma(float64(n - k + 1))
+		cdf += math.Exp(lg - lk - lnk + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+		if cdf >= q {
+			return k
+		}
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+// O pacote stats traz testes estatisticos simples para as sequencias dos
+// geradores, seguindo os testes de frequencia (monobit) e de corridas (runs)
+// do NIST SP 800-22. Eles nao provam que um gerador eh seguro, mas
+// detectam vieses grosseiros, como bits fixos ou alternancia excessiva.
+// Health aplica continuamente os testes de saude do SP 800-90B, para
+// monitorar um gerador em funcionamento.
+package stats
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math"
+)
+
+// Alpha eh o nivel de significancia: um teste falha se o valor-p for menor
+const Alpha = 0.01
+
+// Bits concatena samples saidas de g, cada uma com exatamente g.Bits()
+// bits (zeros a esquerda incluidos), do bit mais significativo ao menos
+func Bits(g prng.Generator, samples int) []uint8 {
+	width := g.Bits()
+	bits := make([]uint8, 0, samples*width)
+	for i := 0; i < samples; i++ {
+		v := g.Next()
+		for b := width - 1; b >= 0; b-- {
+			bits = append(bits, uint8(v.Bit(b)))
+		}
+	}
+	return bits
+}
+
+// Monobit retorna o valor-p do teste de frequencia: a proporcao de uns
+// deve ser proxima de 1/2
+func Monobit(bits []uint8) float64 {
+	n := float64(len(bits))
+	if n == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, b := range bits {
+		sum += 2*float64(b) - 1
+	}
+	return math.Erfc(math.Abs(sum) / math.Sqrt(n) / math.Sqrt2)
+}
+
+// Runs retorna o valor-p do teste de corridas: o numero de sequencias
+// maximas de bits iguais deve ser o esperado para bits independentes.
+// Se a proporcao de uns ja for muito distante de 1/2, retorna 0.
+func Runs(bits []uint8) float64 {
+	n := float64(len(bits))
+	if n == 0 {
+		return 0
+	}
+	ones := 0.0
+	for _, b := range bits {
+		ones += float64(b)
+	}
+	pi := ones / n
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return 0
+	}
+	runs := 1.0
+	for i := 1; i < len(bits); i++ {
+		if bits[i] != bits[i-1] {
+			runs++
+		}
+	}
+	expected := 2 * n * pi * (1 - pi)
+	return math.Erfc(math.Abs(runs-expected) / (2 * math.Sqrt(2*n) * pi * (1 - pi)))
+}
+
+// Report eh o resultado de Evaluate
+type Report struct {
+	Bits    int     `json:"bits"` // bits avaliados
+	Ones    int     `json:"ones"`
+	Monobit float64 `json:"monobit"` // valor-p do teste de frequencia
+	Runs    float64 `json:"runs"`    // valor-p do teste de corridas
+}
+
+// Pass informa se a sequencia passou nos dois testes
+func (r Report) Pass() bool {
+	return r.Monobit >= Alpha && r.Runs >= Alpha
+}
+
+// Evaluate avalia samples saidas de g
+func Evaluate(g prng.Generator, samples int) (Report, error) {
+	if samples < 1 || g.Bits() < 1 {
+		return Report{}, errors.New("stats: nenhum bit para avaliar")
+	}
+	bits := Bits(g, samples)
+	r := Report{Bits: len(bits), Monobit: Monobit(bits), Runs: Runs(bits)}
+	for _, b := range bits {
+		r.Ones += int(b)
+	}
+	return r, nil
+}
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"PrimeNumGenerator/prng"
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+// fixed eh um gerador que sempre retorna o mesmo valor
+type fixed struct {
+	v    int64
+	bits int
+}
+
+func (f fixed) Next() *big.Int { return big.NewInt(f.v) }
+func (f fixed) Bits() int      { return f.bits }
+
+func TestEvaluateDetectsBias(t *testing.T) {
+	// So zeros falha no teste de frequencia
+	if r, _ := Evaluate(fixed{0, 64}, 100); r.Pass() || r.Ones != 0 {
+		t.Errorf("sequencia de zeros aprovada: %+v", r)
+	}
+	// 0101... tem frequencia perfeita, mas corridas demais
+	if r, _ := Evaluate(fixed{0x5555, 16}, 100); r.Monobit < Alpha || r.Runs >= Alpha {
+		t.Errorf("sequencia alternada: %+v", r)
+	}
+}
+
+func TestEvaluateHybrid(t *testing.T) {
+	lfg := prng.NewLFG(10, 7, 10, 128)
+	bbs := prng.NewBBS(128)
+	h, err := prng.NewHybrid(lfg, bbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Evaluate(h, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Com alpha = 0.01, uma sequencia boa falha por acaso 1 a cada ~50 vezes;
+	// exigimos apenas que nao seja um vies grosseiro
+	if r.Bits != 200*128 || r.Monobit < 1e-6 || r.Runs < 1e-6 {
+		t.Errorf("gerador hibrido com vies: %+v", r)
+	}
+}
+
+func TestHealthCutoffs(t *testing.T) {
+	// Com alpha = 2^-20 e W = 512; para H = 8, os valores do SP 800-90B
+	for _, c := range []struct {
+		h        float64
+		rct, apt int
+	}{{8, 4, 13}, {4, 6, 62}, {1, 21, 311}} {
+		health, err := NewHealth(c.h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rct, apt := health.Cutoffs(); rct != c.rct || apt != c.apt {
+			t.Errorf("H = %g: limites %d e %d, esperados %d e %d", c.h, rct, apt, c.rct, c.apt)
+		}
+	}
+	for _, h := range []float64{0, -1, 8.5} {
+		if _, err := NewHealth(h); err == nil {
+			t.Errorf("entropia minima %g aceita", h)
+		}
+	}
+}
+
+func TestHealthFailures(t *testing.T) {
+	random := make([]byte, 1<<20)
+	rand.NewChaCha8([32]byte{1}).Read(random)
+
+	health, _ := NewHealth(6)
+	for i, b := range random {
+		if err := health.Add(b); err != nil {
+			t.Fatalf("amostra aleatoria %d reprovada: %v", i, err)
+		}
+	}
+
+	// Um byte travado falha na contagem de repeticoes
+	var herr *HealthError
+	health, _ = NewHealth(6)
+	err := health.AddBits(Bits(fixed{0x7f7f7f7f, 32}, 10))
+	if !errors.As(err, &herr) || herr.Test != "repetition-count" || herr.Sample != 5 {
+		t.Errorf("byte travado: %v", err)
+	}
+
+	// Um valor frequente demais, sem repeticoes seguidas, falha na
+	// proporcao adaptativa
+	health, err = NewHealth(6)
+	for i := 0; err == nil && i < HealthWindow; i++ {
+		s := random[i]
+		if i%4 == 0 {
+			s = 0
+		} else if s == 0 {
+			s = 1
+		}
+		err = health.Add(s)
+	}
+	if !errors.As(err, &herr) || herr.Test != "adaptive-proportion" {
+		t.Errorf("valor frequente: %v", err)
+	}
+}
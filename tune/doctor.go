@@ -0,0 +1,175 @@
+// Esse arquivo traz o diagnostico da maquina do subcomando doctor: a vazao
+//  das fontes de entropia, os geradores de hardware disponiveis, o numero
+//  de CPUs e o custo das operacoes com inteiros grandes, com as
+//  recomendacoes de configuracao que decorrem deles.
+
+package tune
+
+import (
+	"PrimeNumGenerator/prng"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Abaixo dessa vazao, o crypto/rand eh considerado lento, em bytes/s
+const slowEntropy = 1 << 20
+
+// Acima desse tempo estimado por primo, recomendamos dividir a busca
+const slowSearch = 10 * time.Second
+
+// entropyBlock eh o tamanho das leituras medidas em cada fonte
+const entropyBlock = 4096
+
+// DoctorBits sao os tamanhos medidos por padrao pelo diagnostico
+var DoctorBits = []int{1024, 2048, 4096}
+
+// EntropyProbe eh a medicao de uma fonte de entropia
+type EntropyProbe struct {
+	Source    string  // nome usado em -entropy
+	Available bool    // a fonte pode ser usada nesta maquina
+	Rate      float64 // vazao em bytes/s, se disponivel
+	Note      string  // origem dos bytes ou motivo da indisponibilidade
+}
+
+// Diagnosis eh o diagnostico da maquina feito por Diagnose
+type Diagnosis struct {
+	OS, Arch  string
+	GoVersion string
+	CPUs      int
+	HWRNG     string // dispositivo de gerador de hardware do kernel, se houver
+	Entropy   []EntropyProbe
+	Machines  []Machine // medicoes de Probe para cada tamanho
+}
+
+// osEntropy descreve de onde o crypto/rand le em cada sistema
+var osEntropy = map[string]string{
+	"linux":   "getrandom(2) do kernel",
+	"android": "getrandom(2) do kernel",
+	"darwin":  "arc4random_buf do sistema",
+	"ios":     "arc4random_buf do sistema",
+	"windows": "ProcessPrng do Windows",
+	"freebsd": "getrandom(2) do kernel",
+	"openbsd": "arc4random_buf do sistema",
+}
+
+// Diagnose mede as fontes de entropia e, com Probe, o custo das operacoes
+// para cada tamanho de bitSizes. Leva cerca de meio segundo por tamanho.
+func Diagnose(bitSizes []int) (Diagnosis, error) {
+	d := Diagnosis{OS: runtime.GOOS, Arch: runtime.GOARCH, GoVersion: runtime.Version(), CPUs: runtime.GOMAXPROCS(0)}
+
+	note, ok := osEntropy[runtime.GOOS]
+	if !ok {
+		note = "fonte do sistema operacional"
+	}
+	d.Entropy = append(d.Entropy, probeEntropy("crypto", note, func() (io.Reader, error) { return rand.Reader, nil }))
+	d.Entropy = append(d.Entropy, probeEntropy("rdrand", "instrução RDRAND combinada com o crypto/rand", func() (io.Reader, error) {
+		return prng.NewHardwareSource(false)
+	}))
+	d.Entropy = append(d.Entropy, probeEntropy("rdseed", "instrução RDSEED combinada com o crypto/rand", func() (io.Reader, error) {
+		return prng.NewHardwareSource(true)
+	}))
+	d.Entropy = append(d.Entropy, probeEntropy("jitter", "variação do tempo de execução da CPU", func() (io.Reader, error) {
+		return prng.NewJitterSource()
+	}))
+
+	// O kernel do Linux mistura o gerador de hardware, quando ha, no pool
+	// do getrandom
+	if _, err := os.Stat("/dev/hwrng"); err == nil {
+		d.HWRNG = "/dev/hwrng"
+	}
+
+	for _, bits := range bitSizes {
+		m, err := Probe(bits)
+		if err != nil {
+			return Diagnosis{}, err
+		}
+		d.Machines = append(d.Machines, m)
+	}
+	return d, nil
+}
+
+// probeEntropy mede a vazao da fonte criada por open
+func probeEntropy(name, note string, open func() (io.Reader, error)) EntropyProbe {
+	p := EntropyProbe{Source: name, Note: note}
+	r, err := open()
+	if err != nil {
+		if errors.Is(err, prng.ErrNoHardwareRNG) {
+			p.Note = "indisponível neste processador"
+		} else {
+			p.Note = err.Error()
+		}
+		return p
+	}
+	buf := make([]byte, entropyBlock)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		p.Note = err.Error()
+		return p
+	}
+	var readErr error
+	perBlock := measure(func() {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			readErr = err
+		}
+	})
+	if readErr != nil {
+		p.Note = readErr.Error()
+		return p
+	}
+	p.Available = true
+	p.Rate = entropyBlock / max(perBlock.Seconds(), 1e-9)
+	return p
+}
+
+// Recommendations retorna as configuracoes recomendadas para a maquina
+// diagnosticada, com a probabilidade de erro de 2^-errorBits. Nao mede
+// nada: as recomendacoes vem de d e de Choose.
+func (d Diagnosis) Recommendations(errorBits int) []string {
+	var recs []string
+
+	sources := make(map[string]EntropyProbe, len(d.Entropy))
+	for _, p := range d.Entropy {
+		sources[p.Source] = p
+	}
+	switch crypto := sources["crypto"]; {
+	case !crypto.Available && sources["jitter"].Available:
+		recs = append(recs, "o crypto/rand falhou: use -security permissive, que recorre à fonte jitter, até corrigir o sistema")
+	case !crypto.Available:
+		recs = append(recs, "nenhuma fonte de entropia funcionou: use -seed apenas para testes reproduzíveis")
+	case crypto.Rate < slowEntropy:
+		recs = append(recs, fmt.Sprintf("o crypto/rand está lento (%s): a geração é limitada pelo sistema, e não pelos testes; prefira o gerador lfg, que consome menos entropia", FormatRate(crypto.Rate)))
+	default:
+		recs = append(recs, "-entropy crypto (padrão): o crypto/rand é rápido o bastante")
+	}
+	if sources["rdseed"].Available || sources["rdrand"].Available {
+		recs = append(recs, "-entropy rdseed ou rdrand acrescenta o gerador do processador ao crypto/rand, sem ganho de velocidade, para quem exige uma fonte de hardware")
+	}
+
+	if d.CPUs <= 1 {
+		recs = append(recs, "uma única CPU: os workers locais de auto não ajudam; para buscas grandes, distribua com worker e coordinator em outras máquinas")
+	}
+	for _, m := range d.Machines {
+		p := Choose(m.Bits, errorBits, m)
+		perPrime := p.Estimate / time.Duration(p.Workers)
+		recs = append(recs, fmt.Sprintf("%d bits: cerca de %s por primo; use auto -bits %d ou -prescreen %d, gerador %s", m.Bits, perPrime.Round(time.Millisecond), m.Bits, p.Prescreen, p.Generator))
+		if perPrime > slowSearch {
+			recs = append(recs, fmt.Sprintf("%d bits é lento nesta máquina: use -timeout com -state e -resume para continuar a busca depois, ou coordinator com mais workers", m.Bits))
+		}
+	}
+	return recs
+}
+
+// FormatRate formata uma vazao em bytes/s com o prefixo binario adequado
+func FormatRate(rate float64) string {
+	units := []string{"B/s", "KiB/s", "MiB/s", "GiB/s"}
+	i := 0
+	for rate >= 1024 && i < len(units)-1 {
+		rate /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", rate, units[i])
+}
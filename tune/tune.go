@@ -0,0 +1,167 @@
+// O pacote tune escolhe os parametros da geracao de primos a partir de
+// medicoes da maquina: o gerador dos candidatos, o tamanho do pre-filtro,
+// o numero de iteracoes do Miller-Rabin e quantos workers usar. Probe faz
+// as medicoes e Choose, que nao mede nada, monta o plano a partir delas.
+package tune
+
+import (
+	"PrimeNumGenerator/numutil"
+	"PrimeNumGenerator/prng"
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"math"
+	"math/big"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// PrescreenOptions sao os tamanhos de pre-filtro (em numero de primos
+// pequenos) medidos por Probe e considerados por Choose
+var PrescreenOptions = []int{32, 64, 128, 256, 512, 1024, 2048}
+
+// Tempos gastos por Probe em cada medicao
+const (
+	probeBudget = 20 * time.Millisecond
+	probeMax    = 1000
+)
+
+// Abaixo desse tempo estimado, a busca eh curta demais para compensar o
+// custo de coordenar varios workers
+const parallelThreshold = 20 * time.Millisecond
+
+// Machine guarda as medicoes de Probe para numeros de Bits bits
+type Machine struct {
+	Bits      int
+	CPUs      int
+	ModExp    time.Duration         // a^(n-1) mod n, o custo de uma iteracao do teste
+	Square    time.Duration         // x^2 mod n, o custo de um bit do BBS
+	Prescreen map[int]time.Duration // mdc com o primorial dos k primeiros primos
+}
+
+// Probe mede a maquina para numeros de bits bits. Cada medicao repete a
+// operacao ate somar cerca de 20ms, entao Probe leva alguns decimos de
+// segundo.
+func Probe(bits int) (Machine, error) {
+	if bits < 2 {
+		return Machine{}, fmt.Errorf("tune: tamanho em bits invalido: %d", bits)
+	}
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	if err != nil {
+		return Machine{}, err
+	}
+	n.SetBit(n, bits-1, 1).SetBit(n, 0, 1)
+	a := new(big.Int).Rsh(n, 1)
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1))
+	r := new(big.Int)
+
+	m := Machine{
+		Bits:      bits,
+		CPUs:      runtime.GOMAXPROCS(0),
+		Prescreen: make(map[int]time.Duration, len(PrescreenOptions)),
+	}
+	m.ModExp = measure(func() { r.Exp(a, nMinus1, n) })
+	m.Square = measure(func() { r.Mul(a, a).Mod(r, n) })
+	for _, k := range PrescreenOptions {
+		numutil.HasSmallFactor(n, k) // fora da medicao: calcula o primorial
+		m.Prescreen[k] = measure(func() { numutil.HasSmallFactor(n, k) })
+	}
+	return m, nil
+}
+
+// measure retorna o tempo medio de f
+func measure(f func()) time.Duration {
+	start := time.Now()
+	runs := 0
+	for runs < probeMax && (runs == 0 || time.Since(start) < probeBudget) {
+		f()
+		runs++
+	}
+	return time.Since(start) / time.Duration(runs)
+}
+
+// Plan eh a configuracao escolhida por Choose
+type Plan struct {
+	Bits      int
+	ErrorBits int    // probabilidade de erro de no maximo 2^-ErrorBits
+	Generator string // gerador dos candidatos: bbs ou lfg
+	Prescreen int    // primos pequenos do pre-filtro (pta.Config.Prescreen)
+	Rounds    int    // iteracoes do Miller-Rabin (pta.Config.Rounds)
+	Workers   int    // workers da busca paralela
+	Window    int    // candidatos impares por janela de cada worker
+	// Estimate eh o tempo esperado da busca com um unico worker
+	Estimate time.Duration
+	Reasons  []string // justificativa de cada escolha
+}
+
+// Choose monta o plano para gerar um primo de bits bits com probabilidade
+// de erro de no maximo 2^-errorBits, a partir das medicoes m.
+//
+// As iteracoes usam o limite de pior caso do Miller-Rabin, 4^-t por
+// iteracao. O pre-filtro escolhido minimiza o custo esperado por candidato,
+// mdc + (fracao que sobrevive ao filtro) * ModExp. O tempo da busca vem do
+// numero esperado de impares ate encontrar um primo, bits * ln 2 / 2.
+func Choose(bits, errorBits int, m Machine) Plan {
+	p := Plan{Bits: bits, ErrorBits: max(errorBits, 1)}
+	p.Rounds = (p.ErrorBits + 1) / 2
+	p.Reasons = append(p.Reasons, fmt.Sprintf("%d iterações: o Miller-Rabin erra com probabilidade de no máximo 4^-t", p.Rounds))
+
+	// Pre-filtro
+	odds := float64(bits) * math.Ln2 / 2 // impares testados ate o primo
+	best := math.Inf(1)
+	for _, k := range slices.Sorted(maps.Keys(m.Prescreen)) {
+		cost := float64(m.Prescreen[k]) + Survival(k)*float64(m.ModExp)
+		if cost < best {
+			best, p.Prescreen = cost, k
+		}
+	}
+	if p.Prescreen == 0 {
+		best = float64(m.ModExp)
+	} else {
+		p.Reasons = append(p.Reasons, fmt.Sprintf("pré-filtro de %d primos: deixa passar %.1f%% dos candidatos ímpares, com o menor custo por candidato", p.Prescreen, 100*Survival(p.Prescreen)))
+	}
+	p.Estimate = time.Duration(odds*best) + time.Duration(p.Rounds-1)*m.ModExp
+
+	// Workers e janelas
+	p.Workers = 1
+	switch {
+	case m.CPUs <= 1:
+		p.Reasons = append(p.Reasons, "1 worker: a máquina tem uma única CPU")
+	case p.Estimate < parallelThreshold:
+		p.Reasons = append(p.Reasons, "1 worker: a busca é curta demais para compensar a coordenação")
+	default:
+		p.Workers = m.CPUs
+		p.Reasons = append(p.Reasons, fmt.Sprintf("%d workers: um por CPU", p.Workers))
+	}
+	// Cada worker recebe, por janela, sua parte dos impares esperados
+	p.Window = max(16, int(math.Ceil(odds/float64(p.Workers))))
+
+	// Gerador: o BBS eh o mais seguro, mas custa um quadrado por bit, alem
+	// de gerar dois primos de bits/2 bits, cerca de 1/8 da busca cada
+	bbsCost := time.Duration(bits)*m.Square + p.Estimate/4
+	switch {
+	case bits < prng.MinSecureBBSBits:
+		p.Generator = "lfg"
+		p.Reasons = append(p.Reasons, fmt.Sprintf("gerador lfg: um BBS de %d bits não é seguro", bits))
+	case bbsCost > p.Estimate/2:
+		p.Generator = "lfg"
+		p.Reasons = append(p.Reasons, "gerador lfg: o BBS aumentaria o tempo total em mais de 50%")
+	default:
+		p.Generator = "bbs"
+		p.Reasons = append(p.Reasons, "gerador bbs: o custo extra do BBS é pequeno perto da busca")
+	}
+	return p
+}
+
+// Survival retorna a fracao dos impares sem fatores entre os k primeiros
+// primos, o produto de (1 - 1/p) para os primos impares entre eles
+func Survival(k int) float64 {
+	s := 1.0
+	for _, p := range numutil.FirstPrimes(k) {
+		if p != 2 {
+			s *= 1 - 1/float64(p)
+		}
+	}
+	return s
+}
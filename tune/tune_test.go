@@ -0,0 +1,101 @@
+package tune
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// machine simula uma maquina em que o mdc custa gcd por primo pequeno
+func machine(cpus int, modExp, square, gcd time.Duration) Machine {
+	m := Machine{Bits: 2048, CPUs: cpus, ModExp: modExp, Square: square, Prescreen: map[int]time.Duration{}}
+	for _, k := range PrescreenOptions {
+		m.Prescreen[k] = time.Duration(k) * gcd
+	}
+	return m
+}
+
+func TestChoose(t *testing.T) {
+	p := Choose(2048, 80, machine(8, 10*time.Millisecond, time.Microsecond, time.Nanosecond))
+	if p.Rounds != 40 {
+		t.Errorf("Rounds = %d, esperado 40", p.Rounds)
+	}
+	// Com o mdc quase de graca, o maior pre-filtro eh o melhor
+	if p.Prescreen != 2048 {
+		t.Errorf("Prescreen = %d, esperado 2048", p.Prescreen)
+	}
+	if p.Workers != 8 || p.Window < 16 {
+		t.Errorf("Workers = %d, Window = %d", p.Workers, p.Window)
+	}
+	if p.Generator != "bbs" {
+		t.Errorf("Generator = %s, esperado bbs", p.Generator)
+	}
+	if len(p.Reasons) == 0 {
+		t.Error("plano sem justificativas")
+	}
+
+	// Com o mdc caro, o menor pre-filtro eh o melhor; a busca fica curta
+	// e o BBS, de 128 bits, inseguro
+	p = Choose(128, 81, machine(8, time.Microsecond, time.Microsecond, time.Microsecond))
+	if p.Rounds != 41 || p.Prescreen != 32 || p.Workers != 1 || p.Generator != "lfg" {
+		t.Errorf("plano inesperado: %+v", p)
+	}
+
+	// Quadrados lentos tornam o BBS caro demais
+	p = Choose(2048, 80, machine(1, time.Millisecond, time.Millisecond, time.Nanosecond))
+	if p.Generator != "lfg" || p.Workers != 1 {
+		t.Errorf("plano inesperado: %+v", p)
+	}
+}
+
+func TestSurvival(t *testing.T) {
+	// Entre os impares, 2/3 nao sao multiplos de 3 e 4/5 nao sao de 5
+	if got := Survival(3); got < 0.5333 || got > 0.5334 {
+		t.Errorf("Survival(3) = %f, esperado 8/15", got)
+	}
+	if Survival(2048) >= Survival(256) {
+		t.Error("um pre-filtro maior deveria deixar passar menos candidatos")
+	}
+}
+
+func TestProbe(t *testing.T) {
+	m, err := Probe(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.CPUs < 1 || m.ModExp <= 0 || m.Square <= 0 || len(m.Prescreen) != len(PrescreenOptions) {
+		t.Errorf("medicoes incompletas: %+v", m)
+	}
+	if _, err := Probe(1); err == nil {
+		t.Error("Probe(1) aceito")
+	}
+}
+
+func TestRecommendations(t *testing.T) {
+	slow := machine(1, time.Second, time.Millisecond, time.Nanosecond)
+	slow.Bits = 4096
+	d := Diagnosis{
+		CPUs:     1,
+		Entropy:  []EntropyProbe{{Source: "crypto", Available: true, Rate: 1000}, {Source: "jitter", Available: true, Rate: 100}},
+		Machines: []Machine{slow},
+	}
+	recs := strings.Join(d.Recommendations(80), "\n")
+	for _, want := range []string{"crypto/rand está lento", "uma única CPU", "4096 bits: cerca de", "-state e -resume"} {
+		if !strings.Contains(recs, want) {
+			t.Errorf("recomendacoes sem %q:\n%s", want, recs)
+		}
+	}
+
+	d = Diagnosis{CPUs: 8, Entropy: []EntropyProbe{{Source: "crypto"}, {Source: "jitter", Available: true}}}
+	if recs := d.Recommendations(80); len(recs) != 1 || !strings.Contains(recs[0], "-security permissive") {
+		t.Errorf("recomendacoes = %q; esperado recorrer a fonte jitter", recs)
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	for rate, want := range map[float64]string{512: "512.0 B/s", 3 << 20: "3.0 MiB/s", 1 << 40: "1024.0 GiB/s"} {
+		if got := FormatRate(rate); got != want {
+			t.Errorf("FormatRate(%v) = %q, esperado %q", rate, got, want)
+		}
+	}
+}
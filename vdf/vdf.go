@@ -0,0 +1,172 @@
+// O pacote vdf implementa uma funcao de atraso verificavel (VDF) baseada
+//
+//	em quadrados sucessivos modulo n, como no quebra-cabeca de Rivest,
+//	Shamir e Wagner.
+//
+// Calcular y = x^(2^T) mod n exige T quadrados sequenciais quando a
+// fatoracao de n eh desconhecida, que eh exatamente o que o Blum Blum Shub
+// faz a cada passo. As provas de Wesolowski e de Pietrzak permitem conferir
+// y com muito menos trabalho do que recalcula-lo. O modulo deve ser gerado
+// sem que ninguem retenha p e q, como em NewModulus.
+package vdf
+
+import (
+	"PrimeNumGenerator/prng"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// challengeBits eh o tamanho dos desafios derivados por hash
+const challengeBits = 128
+
+// NewModulus gera um modulo RSA de bits bits e descarta seus fatores
+func NewModulus(bits int, e prng.Entropy) (*big.Int, error) {
+	bbs, err := prng.NewBBSPublicOnly(bits, e)
+	if err != nil {
+		return nil, err
+	}
+	return bbs.Modulus(), nil
+}
+
+// Evaluate calcula y = x^(2^T) mod n usando os quadrados sucessivos do BBS.
+// x deve estar no intervalo [1, n).
+func Evaluate(n, x *big.Int, T uint64) (*big.Int, error) {
+	bbs, err := prng.NewBBSFromPublic(n, x, 0)
+	if err != nil {
+		return nil, err
+	}
+	return bbs.Advance(T), nil
+}
+
+// hashInts calcula o SHA-256 dos valores, cada um precedido do seu tamanho
+func hashInts(values ...*big.Int) []byte {
+	h := sha256.New()
+	var buf [8]byte
+	for _, v := range values {
+		b := v.Bytes()
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// hashToPrime deriva dos valores um primo de challengeBits bits
+func hashToPrime(values ...*big.Int) *big.Int {
+	l := new(big.Int).SetBytes(hashInts(values...)[:challengeBits/8])
+	l.SetBit(l, challengeBits-1, 1)
+	l.SetBit(l, 0, 1)
+	for !l.ProbablyPrime(20) {
+		l.Add(l, big.NewInt(2))
+	}
+	return l
+}
+
+// ProveWesolowski gera a prova de Wesolowski pi = x^floor(2^T / l) mod n,
+// em que l eh um primo derivado de (n, x, y, T). O quociente de 2^T por l
+// tem T bits, entao nao eh montado: a divisao eh feita bit a bit, como a
+// divisao longa, e cada bit do quociente entra direto na exponenciacao.
+// Assim a memoria fica constante e o custo eh de T quadrados, como o da
+// avaliacao.
+func ProveWesolowski(n, x, y *big.Int, T uint64) *big.Int {
+	l := hashToPrime(n, x, y, new(big.Int).SetUint64(T))
+	pi := big.NewInt(1)
+	r := big.NewInt(1) // resto parcial, sempre menor que l
+	for i := uint64(0); i < T; i++ {
+		// Proximo bit do quociente: floor(2r / l), que eh 0 ou 1
+		r.Lsh(r, 1)
+		pi.Mul(pi, pi).Mod(pi, n)
+		if r.Cmp(l) >= 0 {
+			r.Sub(r, l)
+			pi.Mul(pi, x).Mod(pi, n)
+		}
+	}
+	return pi
+}
+
+// VerifyWesolowski confere a prova pi verificando se pi^l * x^r = y (mod n),
+// com r = 2^T mod l
+func VerifyWesolowski(n, x, y, pi *big.Int, T uint64) bool {
+	if !inGroup(n, x) || !inGroup(n, y) || !inGroup(n, pi) {
+		return false
+	}
+	l := hashToPrime(n, x, y, new(big.Int).SetUint64(T))
+	r := new(big.Int).Exp(big.NewInt(2), new(big.Int).SetUint64(T), l)
+
+	lhs := new(big.Int).Exp(pi, l, n)
+	lhs.Mul(lhs, new(big.Int).Exp(x, r, n))
+	lhs.Mod(lhs, n)
+	return lhs.Cmp(y) == 0
+}
+
+// pietrzakChallenge deriva o desafio de uma rodada da prova de Pietrzak
+func pietrzakChallenge(x, y, mu *big.Int, T uint64) *big.Int {
+	return new(big.Int).SetBytes(hashInts(x, y, mu, new(big.Int).SetUint64(T))[:challengeBits/8])
+}
+
+// pietrzakStep reduz a afirmacao y = x^(2^T) a y' = x'^(2^(T/2)) usando o
+// ponto intermediario mu = x^(2^(T/2)). T deve ser par.
+func pietrzakStep(n, x, y, mu *big.Int, T uint64) (*big.Int, *big.Int) {
+	r := pietrzakChallenge(x, y, mu, T)
+	nx := new(big.Int).Exp(x, r, n)
+	nx.Mul(nx, mu).Mod(nx, n)
+	ny := new(big.Int).Exp(mu, r, n)
+	ny.Mul(ny, y).Mod(ny, n)
+	return nx, ny
+}
+
+// ProvePietrzak gera a prova de Pietrzak: a lista dos pontos intermediarios
+// de cada rodada de reducao pela metade
+func ProvePietrzak(n, x, y *big.Int, T uint64) ([]*big.Int, error) {
+	var proof []*big.Int
+	x, y = new(big.Int).Set(x), new(big.Int).Set(y)
+	for T > 1 {
+		if T%2 == 1 {
+			// Com T impar, passamos a provar y^2 = x^(2^(T+1))
+			y.Mul(y, y).Mod(y, n)
+			T++
+		}
+		mu, err := Evaluate(n, x, T/2)
+		if err != nil {
+			return nil, err
+		}
+		x, y = pietrzakStep(n, x, y, mu, T)
+		T /= 2
+		proof = append(proof, mu)
+	}
+	return proof, nil
+}
+
+// VerifyPietrzak confere a prova de Pietrzak repetindo as reducoes com os
+// pontos intermediarios da prova
+func VerifyPietrzak(n, x, y *big.Int, T uint64, proof []*big.Int) bool {
+	if !inGroup(n, x) || !inGroup(n, y) {
+		return false
+	}
+	x, y = new(big.Int).Set(x), new(big.Int).Set(y)
+	for T > 1 {
+		if len(proof) == 0 || !inGroup(n, proof[0]) {
+			return false
+		}
+		if T%2 == 1 {
+			y.Mul(y, y).Mod(y, n)
+			T++
+		}
+		x, y = pietrzakStep(n, x, y, proof[0], T)
+		T /= 2
+		proof = proof[1:]
+	}
+	if len(proof) != 0 {
+		return false
+	}
+	if T == 1 {
+		x.Mul(x, x).Mod(x, n)
+	}
+	return x.Cmp(y) == 0
+}
+
+// inGroup verifica se v esta em [1, n)
+func inGroup(n, v *big.Int) bool {
+	return v != nil && v.Sign() > 0 && v.Cmp(n) < 0
+}
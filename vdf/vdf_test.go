@@ -0,0 +1,61 @@
+package vdf
+
+import (
+	"PrimeNumGenerator/prng"
+	"math/big"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	n := big.NewInt(3 * 7 * 11 * 19) // pequeno o bastante para conferir a mao
+	x := big.NewInt(5)
+	y, err := Evaluate(n, x, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Exp(x, new(big.Int).Lsh(big.NewInt(1), 10), n)
+	if y.Cmp(want) != 0 {
+		t.Fatalf("Evaluate = %s, esperado %s", y, want)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	n, err := NewModulus(256, prng.Entropy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := big.NewInt(12345)
+
+	for _, T := range []uint64{0, 1, 2, 7, 100, 1023} {
+		y, err := Evaluate(n, x, T)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wrong := new(big.Int).Add(y, big.NewInt(1))
+
+		pi := ProveWesolowski(n, x, y, T)
+		// A divisao bit a bit da o mesmo quociente que floor(2^T / l)
+		l := hashToPrime(n, x, y, new(big.Int).SetUint64(T))
+		q := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), uint(T)), l)
+		if want := new(big.Int).Exp(x, q, n); pi.Cmp(want) != 0 {
+			t.Errorf("T=%d: prova %s, esperado %s", T, pi, want)
+		}
+		if !VerifyWesolowski(n, x, y, pi, T) {
+			t.Errorf("T=%d: prova de Wesolowski rejeitada", T)
+		}
+		if VerifyWesolowski(n, x, wrong, pi, T) {
+			t.Errorf("T=%d: prova de Wesolowski aceita para y incorreto", T)
+		}
+
+		proof, err := ProvePietrzak(n, x, y, T)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyPietrzak(n, x, y, T, proof) {
+			t.Errorf("T=%d: prova de Pietrzak rejeitada", T)
+		}
+		if VerifyPietrzak(n, x, wrong, T, proof) {
+			t.Errorf("T=%d: prova de Pietrzak aceita para y incorreto", T)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//go:build js && wasm
+
+// O programa wasm expoe os geradores e os testes de primalidade para o
+// JavaScript quando compilado para WebAssembly:
+//
+//	GOOS=js GOARCH=wasm go build -o wasm/primegen.wasm ./wasm
+//
+// As funcoes registradas no objeto global sao:
+//
+//	generatePrime(bits)    primo de bits bits, em hexadecimal
+//	isProbablePrime(hex)   true se o numero em hexadecimal eh provavelmente primo
+//	bbsNext(bits)          proximo numero do Blum Blum Shub, em hexadecimal;
+//	                       bits deve ser pelo menos prng.MinBBSBits
+//
+// Em caso de erro, as funcoes retornam um objeto Error do JavaScript em vez
+// de lancar uma excecao.
+package main
+
+import (
+	"PrimeNumGenerator/prng"
+	"PrimeNumGenerator/pta"
+	"fmt"
+	"math/big"
+	"syscall/js"
+)
+
+// maxBits limita o tamanho dos numeros pedidos pelo JavaScript
+const maxBits = 4096
+
+// O navegador fornece o crypto.getRandomValues, usado pelo crypto/rand.
+// Nao usamos o nivel Permissive: a fonte jitter depende de temporizadores
+// de alta resolucao, que os navegadores deliberadamente degradam.
+var cfg = pta.Config{Security: prng.Strict}
+
+// bbs eh o gerador usado por bbsNext, recriado quando o tamanho muda
+var (
+	bbs     *prng.BlumBlumShub
+	bbsBits int
+)
+
+func jsError(format string, args ...any) js.Value {
+	return js.Global().Get("Error").New(fmt.Sprintf(format, args...))
+}
+
+// bitsArg le e valida o tamanho em bits passado pelo JavaScript, que deve
+// estar entre min e maxBits
+func bitsArg(args []js.Value, min int) (int, error) {
+	if len(args) < 1 || args[0].Type() != js.TypeNumber {
+		return 0, fmt.Errorf("informe o tamanho em bits")
+	}
+	bits := args[0].Int()
+	if bits < min || bits > maxBits {
+		return 0, fmt.Errorf("bits deve estar entre %d e %d", min, maxBits)
+	}
+	return bits, nil
+}
+
+func generatePrime(this js.Value, args []js.Value) any {
+	bits, err := bitsArg(args, 2)
+	if err != nil {
+		return jsError("%v", err)
+	}
+	candidate, err := cfg.Entropy().Bits(bits)
+	if err != nil {
+		return jsError("%v", err)
+	}
+	res, err := pta.MillerRabin(candidate, bits, cfg)
+	if err != nil {
+		return jsError("%v", err)
+	}
+	return res.Number.Text(16)
+}
+
+func isProbablePrime(this js.Value, args []js.Value) any {
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		return jsError("informe o numero em hexadecimal")
+	}
+	n, ok := new(big.Int).SetString(args[0].String(), 16)
+	if !ok {
+		return jsError("numero hexadecimal invalido: %q", args[0].String())
+	}
+	test, _ := pta.Get("miller-rabin")
+	res := test.IsPrime(n, cfg)
+	if res.Err != nil {
+		return jsError("%v", res.Err)
+	}
+	return res.Prime
+}
+
+func bbsNext(this js.Value, args []js.Value) any {
+	bits := 256
+	if len(args) > 0 {
+		var err error
+		if bits, err = bitsArg(args, prng.MinBBSBits); err != nil {
+			return jsError("%v", err)
+		}
+	}
+	if bbs == nil || bbsBits != bits {
+		var err error
+		if bbs, err = prng.NewBBSWithEntropy(bits, cfg.Entropy()); err != nil {
+			return jsError("%v", err)
+		}
+		bbsBits = bits
+	}
+	return bbs.Next().Text(16)
+}
+
+func main() {
+	js.Global().Set("generatePrime", js.FuncOf(generatePrime))
+	js.Global().Set("isProbablePrime", js.FuncOf(isProbablePrime))
+	js.Global().Set("bbsNext", js.FuncOf(bbsNext))
+	// Mantemos o programa vivo para atender as chamadas do JavaScript
+	select {}
+}